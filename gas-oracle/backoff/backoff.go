@@ -0,0 +1,152 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy describes a sequence of delays. The first delay is Initial, every
+// following delay is Multiplier times the previous one, capped to Max.
+// Each delay is then randomized by up to Jitter times its value in either
+// direction, so that a Jitter of 0.1 spreads a 10s delay over 9s to 11s.
+type Policy struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+}
+
+// Constant returns a policy that always waits for delay
+func Constant(delay time.Duration) Policy {
+	return Policy{Initial: delay, Max: delay, Multiplier: 1}
+}
+
+// Validate checks that the policy describes a valid sequence of delays
+func (p Policy) Validate() error {
+	if p.Initial <= 0 {
+		return errors.New("initial delay must be positive")
+	}
+	if p.Max < p.Initial {
+		return fmt.Errorf("max delay %s is below the initial delay %s", p.Max, p.Initial)
+	}
+	if p.Multiplier < 1 {
+		return fmt.Errorf("multiplier must be at least 1, got %v", p.Multiplier)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1, got %v", p.Jitter)
+	}
+	return nil
+}
+
+// Delay returns the delay before the given attempt, counting from 0,
+// without jitter
+func (p Policy) Delay(attempt int) time.Duration {
+	delay := float64(p.Initial) * math.Pow(p.Multiplier, float64(attempt))
+	if delay > float64(p.Max) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		return p.Max
+	}
+	return time.Duration(delay)
+}
+
+// Bounds returns the shortest and the longest delay before the given
+// attempt once jitter is applied
+func (p Policy) Bounds(attempt int) (time.Duration, time.Duration) {
+	delay := float64(p.Delay(attempt))
+	return time.Duration(delay * (1 - p.Jitter)), time.Duration(delay * (1 + p.Jitter))
+}
+
+// String formats the policy the way that Parse reads it
+func (p Policy) String() string {
+	return fmt.Sprintf("initial=%s,max=%s,multiplier=%s,jitter=%s", p.Initial, p.Max,
+		strconv.FormatFloat(p.Multiplier, 'f', -1, 64), strconv.FormatFloat(p.Jitter, 'f', -1, 64))
+}
+
+// Parse reads a policy from a comma separated list of key=value pairs,
+// e.g. "initial=1s,max=30s,multiplier=2,jitter=0.1". Keys that are not
+// given keep their value from defaults.
+func Parse(spec string, defaults Policy) (Policy, error) {
+	p := defaults
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return Policy{}, fmt.Errorf("invalid backoff option %q", field)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		var err error
+		switch key {
+		case "initial":
+			p.Initial, err = time.ParseDuration(value)
+		case "max":
+			p.Max, err = time.ParseDuration(value)
+		case "multiplier":
+			p.Multiplier, err = strconv.ParseFloat(value, 64)
+		case "jitter":
+			p.Jitter, err = strconv.ParseFloat(value, 64)
+		default:
+			return Policy{}, fmt.Errorf("unknown backoff option %q", key)
+		}
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid backoff option %q: %w", key, err)
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return Policy{}, err
+	}
+	return p, nil
+}
+
+// Backoff walks the sequence of delays of a policy
+type Backoff struct {
+	policy  Policy
+	attempt int
+	rand    func() float64
+}
+
+var (
+	randMu  sync.Mutex
+	randSrc = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func random() float64 {
+	randMu.Lock()
+	defer randMu.Unlock()
+	return randSrc.Float64()
+}
+
+// New returns a Backoff at the start of the sequence of the policy
+func (p Policy) New() *Backoff {
+	return &Backoff{policy: p, rand: random}
+}
+
+// Next returns the delay before the next attempt
+func (b *Backoff) Next() time.Duration {
+	delay := b.policy.Delay(b.attempt)
+	b.attempt++
+	if b.policy.Jitter == 0 {
+		return delay
+	}
+	// Spread the delay evenly over [1-jitter, 1+jitter] times its value
+	factor := 1 + b.policy.Jitter*(2*b.rand()-1)
+	return time.Duration(float64(delay) * factor)
+}
+
+// Attempt returns the number of delays that were handed out
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+// Reset goes back to the start of the sequence, for example after a
+// success
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
@@ -0,0 +1,90 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequence(t *testing.T) {
+	p := Policy{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2}
+	b := p.New()
+	expect := []time.Duration{1, 2, 4, 8, 10, 10}
+	for i, e := range expect {
+		require.Equal(t, e*time.Second, b.Next(), "attempt %d", i)
+	}
+	require.Equal(t, len(expect), b.Attempt())
+
+	b.Reset()
+	require.Equal(t, time.Second, b.Next())
+}
+
+func TestConstant(t *testing.T) {
+	b := Constant(300 * time.Millisecond).New()
+	for i := 0; i < 5; i++ {
+		require.Equal(t, 300*time.Millisecond, b.Next())
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	p := Policy{Initial: time.Second, Max: 8 * time.Second, Multiplier: 2, Jitter: 0.25}
+	min, max := p.Bounds(3)
+	require.Equal(t, 6*time.Second, min)
+	require.Equal(t, 10*time.Second, max)
+
+	// The extremes of the random source land on the bounds
+	for _, r := range []float64{0, 0.5, 0.999999} {
+		b := p.New()
+		b.rand = func() float64 { return r }
+		for attempt := 0; attempt < 6; attempt++ {
+			min, max := p.Bounds(attempt)
+			delay := b.Next()
+			require.GreaterOrEqual(t, delay, min, "attempt %d", attempt)
+			require.LessOrEqual(t, delay, max, "attempt %d", attempt)
+		}
+	}
+
+	// With real randomness every delay stays within the bounds
+	b := p.New()
+	for attempt := 0; attempt < 1000; attempt++ {
+		min, max := p.Bounds(attempt)
+		delay := b.Next()
+		require.GreaterOrEqual(t, delay, min)
+		require.LessOrEqual(t, delay, max)
+	}
+}
+
+func TestDelayDoesNotOverflow(t *testing.T) {
+	p := Policy{Initial: time.Second, Max: time.Hour, Multiplier: 10}
+	require.Equal(t, time.Hour, p.Delay(10_000))
+}
+
+func TestParse(t *testing.T) {
+	defaults := Constant(time.Second)
+
+	p, err := Parse("", defaults)
+	require.NoError(t, err)
+	require.Equal(t, defaults, p)
+
+	p, err = Parse("max=30s, multiplier=2,jitter=0.1", defaults)
+	require.NoError(t, err)
+	require.Equal(t, Policy{Initial: time.Second, Max: 30 * time.Second, Multiplier: 2, Jitter: 0.1}, p)
+
+	// String and Parse round trip
+	parsed, err := Parse(p.String(), Policy{})
+	require.NoError(t, err)
+	require.Equal(t, p, parsed)
+
+	for _, spec := range []string{
+		"initial",
+		"initial=soon",
+		"delay=1s",
+		"max=100ms",
+		"multiplier=0.5",
+		"jitter=2",
+	} {
+		_, err := Parse(spec, defaults)
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,188 @@
+package flags
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteExampleConfig writes a YAML config file to w that holds every flag
+// set to its default value, documented by the usage of the flag. Options
+// without a default are written commented out, since setting them to the
+// zero value is not the same as leaving them unset, and so are the inline
+// secrets, which would conflict with their file flag.
+func WriteExampleConfig(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("# gas-oracle configuration\n")
+	buf.WriteString("#\n")
+	buf.WriteString("# Every option can also be set by its command line flag or its\n")
+	buf.WriteString("# environment variable.\n")
+	for _, flag := range Flags {
+		value, envVar, usage, err := describe(flag)
+		if err != nil {
+			return err
+		}
+		encoded, err := yaml.Marshal(map[string]interface{}{flag.GetName(): value})
+		if err != nil {
+			return fmt.Errorf("cannot encode %s: %w", flag.GetName(), err)
+		}
+		buf.WriteString("\n")
+		fmt.Fprintf(&buf, "# %s\n", usage)
+		if envVar != "" {
+			fmt.Fprintf(&buf, "# env: %s\n", envVar)
+		}
+		if isInlineSecret(flag.GetName()) || isZero(value) {
+			buf.WriteString("# ")
+		}
+		buf.Write(encoded)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// describe returns the default value, environment variable and usage of
+// a flag
+func describe(flag cli.Flag) (interface{}, string, string, error) {
+	switch f := flag.(type) {
+	case cli.StringFlag:
+		return f.Value, f.EnvVar, f.Usage, nil
+	case cli.Uint64Flag:
+		return f.Value, f.EnvVar, f.Usage, nil
+	case cli.IntFlag:
+		return f.Value, f.EnvVar, f.Usage, nil
+	case cli.Float64Flag:
+		return f.Value, f.EnvVar, f.Usage, nil
+	case cli.BoolFlag:
+		return false, f.EnvVar, f.Usage, nil
+	case cli.BoolTFlag:
+		return true, f.EnvVar, f.Usage, nil
+	default:
+		return nil, "", "", fmt.Errorf("unsupported flag type %T for %s", flag, flag.GetName())
+	}
+}
+
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case uint64:
+		return v == 0
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	}
+	return false
+}
+
+// profilesSection is the key of the config file that holds the custom
+// profiles, see LoadProfiles
+const profilesSection = "profiles"
+
+// LoadConfig parses a YAML config file into the string values of the flags
+// that it sets. Keys are the names of the flags, unknown keys are an error.
+// The profiles section is checked but left to ApplyProfile.
+func LoadConfig(r io.Reader) (map[string]string, error) {
+	raw, err := decodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := profileValues(raw[profilesSection]); err != nil {
+		return nil, err
+	}
+	delete(raw, profilesSection)
+	return optionValues(raw)
+}
+
+func decodeConfig(r io.Reader) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("cannot parse config: %w", err)
+	}
+	return raw, nil
+}
+
+// optionValues converts the options of a decoded config into the string
+// values of the flags that they set
+func optionValues(raw map[string]interface{}) (map[string]string, error) {
+	known := make(map[string]bool, len(Flags))
+	for _, flag := range Flags {
+		known[flag.GetName()] = true
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown config option: %s", key)
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("config option %s must be a scalar", key)
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = strings.TrimSpace(fmt.Sprint(value))
+		}
+	}
+	return values, nil
+}
+
+// LoadConfigFile parses the YAML config file at path, see LoadConfig
+func LoadConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadConfig(file)
+}
+
+// ApplyConfig sets the flags to the values of the config file of --config.
+// Flags set on the command line or through their environment variable are
+// left untouched, and the config file takes precedence over the profile
+// when it is applied first.
+func ApplyConfig(ctx *cli.Context) error {
+	path := ctx.GlobalString(ConfigFlag.Name)
+	if path == "" {
+		return nil
+	}
+	values, err := LoadConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot load --%s: %w", ConfigFlag.Name, err)
+	}
+
+	options := make([]string, 0, len(values))
+	for option := range values {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	for _, option := range options {
+		if option == ConfigFlag.Name {
+			return fmt.Errorf("config %s cannot set %s", path, option)
+		}
+		if ctx.GlobalIsSet(option) {
+			continue
+		}
+		if err := ctx.GlobalSet(option, values[option]); err != nil {
+			return fmt.Errorf("config %s: invalid %s: %w", path, option, err)
+		}
+	}
+	return nil
+}
+
+// MetricsEnabled reports whether --metrics, --metrics.prometheus or
+// --metrics.influxdb collect the metrics, from the command line, the
+// environment, the config file or the profile alike
+func MetricsEnabled(ctx *cli.Context) bool {
+	return ctx.GlobalBool(MetricsEnabledFlag.Name) ||
+		ctx.GlobalBool(MetricsPrometheusFlag.Name) ||
+		ctx.GlobalBool(MetricsEnableInfluxDBFlag.Name)
+}
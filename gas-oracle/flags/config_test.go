@@ -0,0 +1,143 @@
+package flags
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleConfigParses(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteExampleConfig(&buf))
+
+	values, err := LoadConfig(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	for _, flag := range Flags {
+		value, _, usage, err := describe(flag)
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "# "+usage+"\n")
+
+		name := flag.GetName()
+		parsed, ok := values[name]
+		if isInlineSecret(name) || isZero(value) {
+			require.False(t, ok, "%s should be commented out", name)
+			require.Contains(t, buf.String(), "# "+name+":")
+			continue
+		}
+		require.True(t, ok, "%s missing", name)
+		switch v := value.(type) {
+		case string:
+			require.Equal(t, v, parsed, name)
+		case uint64:
+			require.Equal(t, strconv.FormatUint(v, 10), parsed, name)
+		case int:
+			require.Equal(t, strconv.Itoa(v), parsed, name)
+		case float64:
+			f, err := strconv.ParseFloat(parsed, 64)
+			require.NoError(t, err, name)
+			require.Equal(t, v, f, name)
+		}
+	}
+}
+
+func TestExampleConfigSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	require.NoError(t, WriteExampleConfig(&buf))
+	config := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(config, buf.Bytes(), 0o600))
+
+	// Every secret of the generated config can be read from its file
+	args := []string{"--config", config}
+	for _, secret := range secretFlags {
+		path := filepath.Join(dir, secret.file.Name)
+		require.NoError(t, os.WriteFile(path, []byte(secret.file.Name+"\n"), 0o600))
+		args = append(args, "--"+secret.file.Name, path)
+	}
+	ctx := newProfileContext(t, args...)
+	require.NoError(t, ApplyConfig(ctx))
+	for _, secret := range secretFlags {
+		value, err := Secret(ctx, secret.inline, secret.file)
+		require.NoError(t, err, secret.inline.Name)
+		require.Equal(t, secret.file.Name, value)
+	}
+}
+
+func TestLoadConfigUnknownOption(t *testing.T) {
+	_, err := LoadConfig(strings.NewReader("no-such-option: 1\n"))
+	require.Error(t, err)
+}
+
+func TestApplyConfigPrecedence(t *testing.T) {
+	t.Setenv(LayerTwoHttpUrlFlag.EnvVar, "http://env:9545")
+	ctx := newProfileContext(t, "--config", "testdata/config.yaml", "--l2-chain-id", "5001")
+	require.NoError(t, ApplyConfig(ctx))
+	require.NoError(t, ApplyProfile(ctx))
+
+	// The command line wins over the config file
+	require.Equal(t, uint64(5001), ctx.GlobalUint64(L2ChainIDFlag.Name))
+	// and so does the environment
+	require.Equal(t, "http://env:9545", ctx.GlobalString(LayerTwoHttpUrlFlag.Name))
+	// The config file wins over the profile
+	require.Equal(t, "http://config:8545", ctx.GlobalString(EthereumHttpUrlFlag.Name))
+	require.Equal(t, uint64(20), ctx.GlobalUint64(EpochLengthSecondsFlag.Name))
+	require.True(t, ctx.GlobalBool(WaitForReceiptFlag.Name))
+	// and the profile that it selects over the defaults
+	require.Equal(t, uint64(31337), ctx.GlobalUint64(L1ChainIDFlag.Name))
+	require.True(t, ctx.GlobalBool(EnableDaFeeFlag.Name))
+	// Options that nothing sets keep their default
+	require.Equal(t, 0.05, ctx.GlobalFloat64(L2GasPriceSignificanceFactorFlag.Name))
+}
+
+func TestApplyConfigErrors(t *testing.T) {
+	require.NoError(t, ApplyConfig(newProfileContext(t)))
+	require.Error(t, ApplyConfig(newProfileContext(t, "--config", "testdata/missing.yaml")))
+
+	dir := t.TempDir()
+	for _, config := range []string{
+		"config: other.yaml\n",
+		"no-such-option: 1\n",
+		"l2-chain-id: five\n",
+	} {
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+		require.Error(t, ApplyConfig(newProfileContext(t, "--config", path)), config)
+	}
+}
+
+func TestConfigEnablesMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("metrics.prometheus: true\n"), 0o600))
+	ctx := newProfileContext(t, "--config", path)
+	require.False(t, MetricsEnabled(ctx))
+	require.NoError(t, ApplyConfig(ctx))
+	require.NoError(t, ApplyProfile(ctx))
+	require.True(t, MetricsEnabled(ctx))
+
+	// The metrics created once they are enabled are scraped with their value
+	ometrics.Enable()
+	metrics.GetOrRegisterCounter("config_test/updates", ometrics.DefaultRegistry).Inc(3)
+	mux := http.NewServeMux()
+	ometrics.Prometheus(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Contains(t, string(body), "config_test_updates 3\n")
+}
@@ -0,0 +1,87 @@
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+// The names of the contracts in a deployments file, as in
+// packages/contracts/addresses.json
+const (
+	GasPriceOracleContract = "BVM_GasPriceOracle"
+	DaFeeContract          = "BVM_EigenDataLayrFee"
+)
+
+var (
+	// ErrMissingDeployment is returned when a contract is not in the
+	// deployments file
+	ErrMissingDeployment = errors.New("contract missing from the deployments")
+	// ErrInvalidDeployment is returned when the address of a contract in
+	// the deployments file is not a hex address
+	ErrInvalidDeployment = errors.New("invalid contract address in the deployments")
+)
+
+// Deployments are the addresses of the deployed contracts, keyed by the
+// names of the contracts
+type Deployments map[string]string
+
+// LoadDeployments parses the deployments JSON file at path, an object of
+// the contract names to their addresses
+func LoadDeployments(path string) (Deployments, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read --%s: %w", DeploymentsFlag.Name, err)
+	}
+	var deployments Deployments
+	if err := json.Unmarshal(data, &deployments); err != nil {
+		return nil, fmt.Errorf("cannot parse --%s: %w", DeploymentsFlag.Name, err)
+	}
+	return deployments, nil
+}
+
+// Address returns the address of the contract, which must be present and a
+// hex address
+func (d Deployments) Address(contract string) (common.Address, error) {
+	value, ok := d[contract]
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrMissingDeployment, contract)
+	}
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("%w: %s is %q", ErrInvalidDeployment, contract, value)
+	}
+	return common.HexToAddress(value), nil
+}
+
+// ContractAddress returns the address of a contract. The address flag
+// takes precedence when it is set, or else the address is read from the
+// deployments. Without deployments the default of the flag is used.
+func ContractAddress(ctx *cli.Context, flag cli.StringFlag, deployments Deployments, contract string) (common.Address, error) {
+	if ctx.GlobalIsSet(flag.Name) || deployments == nil {
+		return common.HexToAddress(ctx.GlobalString(flag.Name)), nil
+	}
+	return deployments.Address(contract)
+}
+
+// ContractAddresses returns the addresses of a contract whose address flag
+// accepts a comma separated list, see ContractAddress. The deployments only
+// hold the one address.
+func ContractAddresses(ctx *cli.Context, flag cli.StringFlag, deployments Deployments, contract string) ([]common.Address, error) {
+	if ctx.GlobalIsSet(flag.Name) || deployments == nil {
+		var addresses []common.Address
+		for _, address := range strings.Split(ctx.GlobalString(flag.Name), ",") {
+			addresses = append(addresses, common.HexToAddress(strings.TrimSpace(address)))
+		}
+		return addresses, nil
+	}
+	address, err := deployments.Address(contract)
+	if err != nil {
+		return nil, err
+	}
+	return []common.Address{address}, nil
+}
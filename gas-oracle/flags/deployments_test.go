@@ -0,0 +1,59 @@
+package flags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDeployments(t *testing.T) {
+	deployments, err := LoadDeployments("testdata/deployments.json")
+	require.NoError(t, err)
+
+	address, err := deployments.Address(GasPriceOracleContract)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x420000000000000000000000000000000000000F"), address)
+	address, err = deployments.Address(DaFeeContract)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x1Edd37fc504513bAa00D782E52478d0a3f675553"), address)
+
+	_, err = deployments.Address("BVM_SequencerFeeVault")
+	require.ErrorIs(t, err, ErrMissingDeployment)
+	_, err = deployments.Address("TestBitToken")
+	require.ErrorIs(t, err, ErrInvalidDeployment)
+
+	_, err = LoadDeployments(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["BVM_GasPriceOracle"]`), 0o600))
+	_, err = LoadDeployments(path)
+	require.Error(t, err)
+}
+
+func TestContractAddress(t *testing.T) {
+	deployments, err := LoadDeployments("testdata/deployments.json")
+	require.NoError(t, err)
+
+	// The deployments take precedence over the default of the flag
+	ctx := newProfileContext(t)
+	address, err := ContractAddress(ctx, DaFeeContractAddressFlag, deployments, DaFeeContract)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x1Edd37fc504513bAa00D782E52478d0a3f675553"), address)
+
+	// and the flag over the deployments
+	ctx = newProfileContext(t, "--da-fee-contract-address", "0x0000000000000000000000000000000000000001")
+	address, err = ContractAddress(ctx, DaFeeContractAddressFlag, deployments, DaFeeContract)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x1"), address)
+
+	// Without deployments the default of the flag is used
+	address, err = ContractAddress(newProfileContext(t), DaFeeContractAddressFlag, nil, DaFeeContract)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress(DaFeeContractAddressFlag.Value), address)
+
+	_, err = ContractAddress(newProfileContext(t), DaFeeContractAddressFlag, Deployments{}, DaFeeContract)
+	require.ErrorIs(t, err, ErrMissingDeployment)
+}
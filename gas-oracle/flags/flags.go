@@ -5,6 +5,16 @@ import (
 )
 
 var (
+	ConfigFlag = cli.StringFlag{
+		Name:   "config",
+		Usage:  "YAML config file of option names to values, flags and environment variables take precedence",
+		EnvVar: "GAS_PRICE_ORACLE_CONFIG",
+	}
+	ProfileFlag = cli.StringFlag{
+		Name:   "profile",
+		Usage:  "bundle of defaults to apply: mainnet, testnet, devnet or a profile of the profiles section of the config file, explicit flags take precedence",
+		EnvVar: "GAS_PRICE_ORACLE_PROFILE",
+	}
 	EthereumHttpUrlFlag = cli.StringFlag{
 		Name:   "ethereum-http-url",
 		Value:  "http://127.0.0.1:8545",
@@ -17,6 +27,36 @@ var (
 		Usage:  "Sequencer HTTP Endpoint",
 		EnvVar: "GAS_PRICE_ORACLE_LAYER_TWO_HTTP_URL",
 	}
+	L1BaseFeeReadHttpUrlFlag = cli.StringFlag{
+		Name:   "l1-base-fee-read-http-url",
+		Usage:  "L1 HTTP Endpoint to read the L1 base fee from, defaults to ethereum-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_READ_HTTP_URL",
+	}
+	L1BaseFeeWriteHttpUrlFlag = cli.StringFlag{
+		Name:   "l1-base-fee-write-http-url",
+		Usage:  "Sequencer HTTP Endpoint to send L1 base fee updates to, defaults to layer-two-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_WRITE_HTTP_URL",
+	}
+	L2GasPriceReadHttpUrlFlag = cli.StringFlag{
+		Name:   "l2-gas-price-read-http-url",
+		Usage:  "Sequencer HTTP Endpoint to read L2 blocks from, defaults to layer-two-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_L2_GAS_PRICE_READ_HTTP_URL",
+	}
+	L2GasPriceWriteHttpUrlFlag = cli.StringFlag{
+		Name:   "l2-gas-price-write-http-url",
+		Usage:  "Sequencer HTTP Endpoint to send L2 gas price updates to, defaults to layer-two-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_L2_GAS_PRICE_WRITE_HTTP_URL",
+	}
+	DaFeeReadHttpUrlFlag = cli.StringFlag{
+		Name:   "da-fee-read-http-url",
+		Usage:  "L1 HTTP Endpoint to read the da fee from, defaults to ethereum-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_READ_HTTP_URL",
+	}
+	DaFeeWriteHttpUrlFlag = cli.StringFlag{
+		Name:   "da-fee-write-http-url",
+		Usage:  "Sequencer HTTP Endpoint to send da fee updates to, defaults to layer-two-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_WRITE_HTTP_URL",
+	}
 	L1ChainIDFlag = cli.Uint64Flag{
 		Name:   "l1-chain-id",
 		Usage:  "L1 Chain ID",
@@ -29,7 +69,7 @@ var (
 	}
 	GasPriceOracleAddressFlag = cli.StringFlag{
 		Name:   "gas-price-oracle-address",
-		Usage:  "Address of BVM_GasPriceOracle",
+		Usage:  "Address of BVM_GasPriceOracle, or a comma separated list whose first address is read from and every address is updated",
 		Value:  "0x420000000000000000000000000000000000000F",
 		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_ORACLE_ADDRESS",
 	}
@@ -39,16 +79,95 @@ var (
 		Value:  "0x9109811E8eEe02520219612bB5D47C60c382F4aa",
 		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_CONTRACT_ADDRESS",
 	}
+	DeploymentsFlag = cli.StringFlag{
+		Name:   "deployments",
+		Usage:  "deployments JSON file of the contract names to their addresses, read for the contract addresses that are not set by their flags",
+		EnvVar: "GAS_PRICE_ORACLE_DEPLOYMENTS",
+	}
+	SubmissionPathFlag = cli.StringFlag{
+		Name:   "submission-path",
+		Value:  "owner",
+		Usage:  "how updates are sent: owner calls the contract on L2, deposit sends them through the portal on L1 as system transactions",
+		EnvVar: "GAS_PRICE_ORACLE_SUBMISSION_PATH",
+	}
+	DepositPortalAddressFlag = cli.StringFlag{
+		Name:   "deposit-portal-address",
+		Usage:  "address of the L1 portal that updates are deposited through with the deposit submission path",
+		EnvVar: "GAS_PRICE_ORACLE_DEPOSIT_PORTAL_ADDRESS",
+	}
+	DepositGasLimitFlag = cli.Uint64Flag{
+		Name:   "deposit-gas-limit",
+		Usage:  "L2 gas limit of deposited updates, 0 uses the L2 estimate of the update",
+		EnvVar: "GAS_PRICE_ORACLE_DEPOSIT_GAS_LIMIT",
+	}
 	PrivateKeyFlag = cli.StringFlag{
 		Name:   "private-key",
-		Usage:  "Private Key corresponding to BVM_GasPriceOracle Owner",
+		Usage:  "Private Key corresponding to BVM_GasPriceOracle Owner, optional with --dry-run",
 		EnvVar: "GAS_PRICE_ORACLE_PRIVATE_KEY",
 	}
+	SignerEndpointFlag = cli.StringFlag{
+		Name:   "signer-endpoint",
+		Usage:  "endpoint of an external signer, such as clef, that signs the transactions in place of --private-key",
+		EnvVar: "GAS_PRICE_ORACLE_SIGNER_ENDPOINT",
+	}
+	SignerAddressFlag = cli.StringFlag{
+		Name:   "signer-address",
+		Usage:  "address of the account of the external signer that signs the transactions",
+		EnvVar: "GAS_PRICE_ORACLE_SIGNER_ADDRESS",
+	}
+	KMSKeyIDFlag = cli.StringFlag{
+		Name:   "kms-key-id",
+		Usage:  "ID or ARN of the AWS KMS key that signs the transactions in place of --private-key",
+		EnvVar: "GAS_PRICE_ORACLE_KMS_KEY_ID",
+	}
+	KMSRegionFlag = cli.StringFlag{
+		Name:   "kms-region",
+		Usage:  "AWS region of the KMS key",
+		EnvVar: "GAS_PRICE_ORACLE_KMS_REGION",
+	}
+	PrivateKeyFileFlag = cli.StringFlag{
+		Name:   "private-key-file",
+		Usage:  "File holding the private key, instead of --private-key",
+		EnvVar: "GAS_PRICE_ORACLE_PRIVATE_KEY_FILE",
+	}
 	TransactionGasPriceFlag = cli.Uint64Flag{
 		Name:   "transaction-gas-price",
 		Usage:  "Hardcoded tx.gasPrice, not setting it uses gas estimation",
 		EnvVar: "GAS_PRICE_ORACLE_TRANSACTION_GAS_PRICE",
 	}
+	GasPriceSourceFlag = cli.StringFlag{
+		Name:   "gas-price-source",
+		Usage:  "source of the tx.gasPrice of updates: node, history or fixed, defaults to fixed when transaction-gas-price is set and node otherwise",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_SOURCE",
+	}
+	GasPriceHistoryBlocksFlag = cli.Uint64Flag{
+		Name:   "gas-price-history-blocks",
+		Value:  20,
+		Usage:  "number of recent blocks that the history gas price source reads tips from",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_HISTORY_BLOCKS",
+	}
+	GasPriceHistoryPercentileFlag = cli.Float64Flag{
+		Name:   "gas-price-history-percentile",
+		Value:  50,
+		Usage:  "percentile of the tips paid in recent blocks that the history gas price source uses",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_HISTORY_PERCENTILE",
+	}
+	TxTypeFlag = cli.StringFlag{
+		Name:   "tx-type",
+		Value:  "legacy",
+		Usage:  "type of the update transactions: legacy, dynamic for EIP-1559 or auto to detect whether the chain has a base fee",
+		EnvVar: "GAS_PRICE_ORACLE_TX_TYPE",
+	}
+	TxMaxFeePerGasFlag = cli.Uint64Flag{
+		Name:   "tx-max-fee-per-gas",
+		Usage:  "Hardcoded fee cap in wei of EIP-1559 update transactions, not setting it allows twice the base fee plus the tip",
+		EnvVar: "GAS_PRICE_ORACLE_TX_MAX_FEE_PER_GAS",
+	}
+	TxMaxPriorityFeePerGasFlag = cli.Uint64Flag{
+		Name:   "tx-max-priority-fee-per-gas",
+		Usage:  "Hardcoded tip cap in wei of EIP-1559 update transactions, not setting it uses eth_maxPriorityFeePerGas",
+		EnvVar: "GAS_PRICE_ORACLE_TX_MAX_PRIORITY_FEE_PER_GAS",
+	}
 	EnableL1BaseFeeFlag = cli.BoolFlag{
 		Name:   "enable-l1-base-fee",
 		Usage:  "Enable updating the L1 base fee",
@@ -64,24 +183,68 @@ var (
 		Usage:  "Enable updating the da gas price",
 		EnvVar: "GAS_PRICE_ORACLE_ENABLE_DA_FEE",
 	}
+	EnableOverheadFlag = cli.BoolFlag{
+		Name:   "enable-overhead",
+		Usage:  "Enable updating the overhead of the L1 fee to --overhead",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_OVERHEAD",
+	}
+	EnableScalarFlag = cli.BoolFlag{
+		Name:   "enable-scalar",
+		Usage:  "Enable updating the scalar of the L1 fee to --scalar",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_SCALAR",
+	}
 	LogLevelFlag = cli.IntFlag{
 		Name:   "loglevel",
 		Value:  3,
 		Usage:  "log level to emit to the screen",
 		EnvVar: "GAS_PRICE_ORACLE_LOG_LEVEL",
 	}
+	LogFormatFlag = cli.StringFlag{
+		Name:   "log-format",
+		Value:  "text",
+		Usage:  "format of the logs: text, or json to emit every event as a structured record",
+		EnvVar: "GAS_PRICE_ORACLE_LOG_FORMAT",
+	}
 	FloorPriceFlag = cli.Uint64Flag{
 		Name:   "floor-price",
 		Value:  1,
 		Usage:  "gas price floor",
 		EnvVar: "GAS_PRICE_ORACLE_FLOOR_PRICE",
 	}
+	CeilingPriceFlag = cli.Uint64Flag{
+		Name:   "ceiling-price",
+		Usage:  "gas price ceiling, 0 is unlimited",
+		EnvVar: "GAS_PRICE_ORACLE_CEILING_PRICE",
+	}
 	TargetGasPerSecondFlag = cli.Uint64Flag{
 		Name:   "target-gas-per-second",
 		Value:  11_000_000,
 		Usage:  "target gas per second",
 		EnvVar: "GAS_PRICE_ORACLE_TARGET_GAS_PER_SECOND",
 	}
+	L2GasPriceModeFlag = cli.StringFlag{
+		Name:   "l2-gas-price-mode",
+		Value:  "gas-per-second",
+		Usage:  "how the L2 gas price is computed: gas-per-second or inclusion-time",
+		EnvVar: "GAS_PRICE_ORACLE_L2_GAS_PRICE_MODE",
+	}
+	TargetInclusionSecondsFlag = cli.Float64Flag{
+		Name:   "target-inclusion-seconds",
+		Usage:  "seconds within which the transactions should be included, used by the inclusion-time mode",
+		EnvVar: "GAS_PRICE_ORACLE_TARGET_INCLUSION_SECONDS",
+	}
+	InclusionLatencyPercentileFlag = cli.Float64Flag{
+		Name:   "inclusion-latency-percentile",
+		Value:  0.9,
+		Usage:  "share of the transactions, between 0 and 1, that should be included within the target inclusion seconds",
+		EnvVar: "GAS_PRICE_ORACLE_INCLUSION_LATENCY_PERCENTILE",
+	}
+	InclusionPollSecondsFlag = cli.Uint64Flag{
+		Name:   "inclusion-poll-seconds",
+		Value:  1,
+		Usage:  "interval at which the L2 transaction pool is polled to measure the inclusion latencies",
+		EnvVar: "GAS_PRICE_ORACLE_INCLUSION_POLL_SECONDS",
+	}
 	MaxPercentChangePerEpochFlag = cli.Float64Flag{
 		Name:   "max-percent-change-per-epoch",
 		Value:  0.1,
@@ -112,46 +275,511 @@ var (
 		Usage:  "polling time for updating the Da fee",
 		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_EPOCH_LENGTH_SECONDS",
 	}
+	OverheadEpochLengthSecondsFlag = cli.Uint64Flag{
+		Name:   "overhead-epoch-length-seconds",
+		Value:  60,
+		Usage:  "polling time for updating the overhead",
+		EnvVar: "GAS_PRICE_ORACLE_OVERHEAD_EPOCH_LENGTH_SECONDS",
+	}
+	ScalarEpochLengthSecondsFlag = cli.Uint64Flag{
+		Name:   "scalar-epoch-length-seconds",
+		Value:  60,
+		Usage:  "polling time for updating the scalar",
+		EnvVar: "GAS_PRICE_ORACLE_SCALAR_EPOCH_LENGTH_SECONDS",
+	}
+	EpochInputBudgetMsFlag = cli.Uint64Flag{
+		Name:   "epoch-input-budget-ms",
+		Usage:  "abort an epoch when collecting its inputs takes longer than this many milliseconds, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_EPOCH_INPUT_BUDGET_MS",
+	}
 	L1BaseFeeSignificanceFactorFlag = cli.Float64Flag{
 		Name:   "l1-base-fee-significant-factor",
 		Value:  0.10,
 		Usage:  "only update when the L1 base fee changes by more than this factor",
 		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_SIGNIFICANT_FACTOR",
 	}
+	L1BaseFeeModeFlag = cli.StringFlag{
+		Name:   "l1-base-fee-mode",
+		Value:  "tip",
+		Usage:  "how the L1 base fee is computed: tip follows the latest block, epoch averages the last complete L1 epoch",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_MODE",
+	}
+	L1BaseFeeWindowFlag = cli.Uint64Flag{
+		Name:   "l1-base-fee-window",
+		Usage:  "write the median of the last this many sampled L1 base fees in place of the latest one, below 2 does not filter",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_WINDOW",
+	}
+	L1EpochBlocksFlag = cli.Uint64Flag{
+		Name:   "l1-epoch-blocks",
+		Value:  32,
+		Usage:  "number of L1 blocks in an epoch for the epoch L1 base fee mode",
+		EnvVar: "GAS_PRICE_ORACLE_L1_EPOCH_BLOCKS",
+	}
+	DualComputeL1HttpUrlFlag = cli.StringFlag{
+		Name:   "dual-compute-l1-http-url",
+		Usage:  "second, independent L1 HTTP endpoint to compute the L1 base fee from, which must agree with the first before it is written",
+		EnvVar: "GAS_PRICE_ORACLE_DUAL_COMPUTE_L1_HTTP_URL",
+	}
+	DualComputeToleranceFlag = cli.Float64Flag{
+		Name:   "dual-compute-tolerance",
+		Value:  0.01,
+		Usage:  "largest relative difference between the two dual-compute pipelines that still counts as agreement",
+		EnvVar: "GAS_PRICE_ORACLE_DUAL_COMPUTE_TOLERANCE",
+	}
+	L1BaseFeeMaxFreezeSecondsFlag = cli.Uint64Flag{
+		Name:   "l1-base-fee-max-freeze-seconds",
+		Value:  3600,
+		Usage:  "longest that the L1 base fee can be frozen for through POST /freeze, 0 is unlimited",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_MAX_FREEZE_SECONDS",
+	}
 	DaFeeSignificanceFactorFlag = cli.Float64Flag{
 		Name:   "da-fee-significant-factor",
 		Value:  0.10,
 		Usage:  "only update when the L1 base fee changes by more than this factor",
 		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_SIGNIFICANT_FACTOR",
 	}
+	OverheadFlag = cli.Uint64Flag{
+		Name:   "overhead",
+		Usage:  "overhead of the L1 fee written to the contract when --enable-overhead is set",
+		EnvVar: "GAS_PRICE_ORACLE_OVERHEAD",
+	}
+	OverheadSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "overhead-significant-factor",
+		Value:  0.05,
+		Usage:  "only update when the overhead differs from its target by more than this factor",
+		EnvVar: "GAS_PRICE_ORACLE_OVERHEAD_SIGNIFICANT_FACTOR",
+	}
+	ScalarFlag = cli.Uint64Flag{
+		Name:   "scalar",
+		Usage:  "scalar of the L1 fee written to the contract when --enable-scalar is set, scaled by the decimals of the contract",
+		EnvVar: "GAS_PRICE_ORACLE_SCALAR",
+	}
+	ScalarSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "scalar-significant-factor",
+		Value:  0.05,
+		Usage:  "only update when the scalar differs from its target by more than this factor",
+		EnvVar: "GAS_PRICE_ORACLE_SCALAR_SIGNIFICANT_FACTOR",
+	}
+	DaFeeMinFlag = cli.Uint64Flag{
+		Name:   "da-fee-min",
+		Usage:  "lowest DA fee written to the contract, lower values are clamped, 0 is unbounded",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_MIN",
+	}
+	DaFeeMaxFlag = cli.Uint64Flag{
+		Name:   "da-fee-max",
+		Usage:  "highest DA fee written to the contract, higher values are clamped, 0 is unbounded",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_MAX",
+	}
+	GasPriceRoundToFlag = cli.Uint64Flag{
+		Name:   "gas-price-round-to",
+		Usage:  "round the computed L2 gas price to the nearest multiple of this many wei before the significance check, 0 does not round",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_ROUND_TO",
+	}
+	L1BaseFeeRoundToFlag = cli.Uint64Flag{
+		Name:   "l1-base-fee-round-to",
+		Usage:  "round the computed L1 base fee to the nearest multiple of this many wei before the significance check, 0 does not round",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_ROUND_TO",
+	}
+	DaFeeRoundToFlag = cli.Uint64Flag{
+		Name:   "da-fee-round-to",
+		Usage:  "round the computed DA fee to the nearest multiple of this many wei before the bounds and the significance check, 0 does not round",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_ROUND_TO",
+	}
+	DaFeeModelFlag = cli.StringFlag{
+		Name:   "da-fee-model",
+		Value:  "rollup",
+		Usage:  "model of the DA fee: rollup, calldata, blob or expression, can be switched at runtime with POST /da-fee-model",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_MODEL",
+	}
+	DaFeeExpressionFlag = cli.StringFlag{
+		Name:   "da-fee-expression",
+		Usage:  "expression of the DA fee over rollup_fee, l1_base_fee and blob_base_fee, used by the expression model",
+		EnvVar: "GAS_PRICE_ORACLE_DA_FEE_EXPRESSION",
+	}
 	L2GasPriceSignificanceFactorFlag = cli.Float64Flag{
 		Name:   "significant-factor",
 		Value:  0.05,
 		Usage:  "only update when the gas price changes by more than this factor",
 		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR",
 	}
+	L2GasPriceSignificanceFactorUpFlag = cli.Float64Flag{
+		Name:   "significant-factor-up",
+		Usage:  "only increase the gas price when it changes by more than this factor, in place of --significant-factor",
+		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR_UP",
+	}
+	L2GasPriceSignificanceFactorDownFlag = cli.Float64Flag{
+		Name:   "significant-factor-down",
+		Usage:  "only decrease the gas price when it changes by more than this factor, in place of --significant-factor",
+		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR_DOWN",
+	}
+	L2GasPriceEMAAlphaFlag = cli.Float64Flag{
+		Name:   "l2-gas-price-ema-alpha",
+		Usage:  "smooth the computed L2 gas price with an exponential moving average of this weight in (0, 1], 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_L2_GAS_PRICE_EMA_ALPHA",
+	}
+	EmergencyUpdateThresholdFlag = cli.Uint64Flag{
+		Name:   "emergency-update-threshold",
+		Usage:  "switch a channel to the emergency significance factor when it sends this many updates within the emergency window, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_EMERGENCY_UPDATE_THRESHOLD",
+	}
+	EmergencyWindowSecondsFlag = cli.Uint64Flag{
+		Name:   "emergency-window-seconds",
+		Value:  600,
+		Usage:  "window that the updates of a channel are counted over to engage or revert the emergency mode",
+		EnvVar: "GAS_PRICE_ORACLE_EMERGENCY_WINDOW_SECONDS",
+	}
+	EmergencySignificanceFactorFlag = cli.Float64Flag{
+		Name:   "emergency-significance-factor",
+		Value:  0.50,
+		Usage:  "only update when the value changes by more than this factor while a channel is in the emergency mode",
+		EnvVar: "GAS_PRICE_ORACLE_EMERGENCY_SIGNIFICANCE_FACTOR",
+	}
+	ReversalWindowWritesFlag = cli.Uint64Flag{
+		Name:   "reversal-window-writes",
+		Value:  10,
+		Usage:  "number of recent writes of a channel that the reversals of its direction are counted over, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_REVERSAL_WINDOW_WRITES",
+	}
+	ReversalThresholdFlag = cli.Uint64Flag{
+		Name:   "reversal-threshold",
+		Usage:  "dampen the reversals of a channel once this many are counted within the reversal window, 0 only reports them",
+		EnvVar: "GAS_PRICE_ORACLE_REVERSAL_THRESHOLD",
+	}
+	ReversalSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "reversal-significance-factor",
+		Value:  0.20,
+		Usage:  "only reverse the direction of a channel when the value changes by more than this factor while its reversals are dampened",
+		EnvVar: "GAS_PRICE_ORACLE_REVERSAL_SIGNIFICANCE_FACTOR",
+	}
+	MaxDropPerPeriodFlag = cli.Float64Flag{
+		Name:   "max-drop-per-period",
+		Usage:  "max fraction that the value of a channel may fall below its highest value within the drop period, e.g. 0.5, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_MAX_DROP_PER_PERIOD",
+	}
+	MaxDropPeriodSecondsFlag = cli.Uint64Flag{
+		Name:   "max-drop-period-seconds",
+		Value:  3600,
+		Usage:  "rolling period that the drop of a channel is bounded over",
+		EnvVar: "GAS_PRICE_ORACLE_MAX_DROP_PERIOD_SECONDS",
+	}
+	StuckWriteThresholdFlag = cli.Uint64Flag{
+		Name:   "stuck-write-threshold",
+		Value:  3,
+		Usage:  "report a channel as stuck after this many consecutive writes that did not change the contract state, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_STUCK_WRITE_THRESHOLD",
+	}
+	NonceReconcileIntervalSecondsFlag = cli.Uint64Flag{
+		Name:   "nonce-reconcile-interval-seconds",
+		Usage:  "keep the signer nonce locally and reconcile it with the chain at this interval, 0 leaves the nonces to the node",
+		EnvVar: "GAS_PRICE_ORACLE_NONCE_RECONCILE_INTERVAL_SECONDS",
+	}
+	TxResubmitTimeoutFlag = cli.Uint64Flag{
+		Name:   "tx-resubmit-timeout",
+		Usage:  "seconds after which an update that was not mined is replaced at the same nonce with a gas price 10% higher, 0 disables the replacements",
+		EnvVar: "GAS_PRICE_ORACLE_TX_RESUBMIT_TIMEOUT",
+	}
+	TxMaxBumpsFlag = cli.Uint64Flag{
+		Name:   "tx-max-bumps",
+		Value:  5,
+		Usage:  "maximum number of times that an update that was not mined is replaced",
+		EnvVar: "GAS_PRICE_ORACLE_TX_MAX_BUMPS",
+	}
+	SimulateBeforeSendFlag = cli.BoolTFlag{
+		Name:   "simulate-before-send",
+		Usage:  "call every update against the latest block before sending it, and skip the ones that revert",
+		EnvVar: "GAS_PRICE_ORACLE_SIMULATE_BEFORE_SEND",
+	}
+	MaxInflightUpdatesFlag = cli.Uint64Flag{
+		Name:   "max-inflight-updates",
+		Usage:  "number of updates that the channels can send at once through their shared queue, 1 sends them serially, 0 does not queue them",
+		EnvVar: "GAS_PRICE_ORACLE_MAX_INFLIGHT_UPDATES",
+	}
+	MaxPendingTransactionsFlag = cli.Uint64Flag{
+		Name:   "max-pending-transactions",
+		Usage:  "hold back updates while more than this many transactions of the signer are pending, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_MAX_PENDING_TRANSACTIONS",
+	}
+	SubmissionTimeoutSecondsFlag = cli.Uint64Flag{
+		Name:   "submission-timeout-seconds",
+		Value:  300,
+		Usage:  "time after which an update that was sent without a receipt is recorded as dropped, 0 disables recording the outcomes of the updates",
+		EnvVar: "GAS_PRICE_ORACLE_SUBMISSION_TIMEOUT_SECONDS",
+	}
+	SubmissionPollSecondsFlag = cli.Uint64Flag{
+		Name:   "submission-poll-seconds",
+		Value:  5,
+		Usage:  "interval at which the receipts of the updates that were sent are polled",
+		EnvVar: "GAS_PRICE_ORACLE_SUBMISSION_POLL_SECONDS",
+	}
+	SpendBudgetGweiFlag = cli.Uint64Flag{
+		Name:   "spend-budget-gwei",
+		Usage:  "gas cost in gwei that the updates may spend within the spend budget window before they are paused, 0 disables the budget",
+		EnvVar: "GAS_PRICE_ORACLE_SPEND_BUDGET_GWEI",
+	}
+	SpendBudgetWindowSecondsFlag = cli.Uint64Flag{
+		Name:   "spend-budget-window-seconds",
+		Value:  86400,
+		Usage:  "rolling window of the spend budget",
+		EnvVar: "GAS_PRICE_ORACLE_SPEND_BUDGET_WINDOW_SECONDS",
+	}
+	ChannelPriorityFlag = cli.StringFlag{
+		Name:   "channel-priority",
+		Usage:  "priorities of the channels in the shared queue, e.g. l1-base-fee=2,l2-gas-price=1,da-fee=0, higher is sent first",
+		EnvVar: "GAS_PRICE_ORACLE_CHANNEL_PRIORITY",
+	}
+	ObserveOnlyFlag = cli.StringFlag{
+		Name:   "observe-only",
+		Usage:  "channels that report what they would write but never send a transaction, e.g. da-fee,l1-base-fee",
+		EnvVar: "GAS_PRICE_ORACLE_OBSERVE_ONLY",
+	}
+	DryRunFlag = cli.BoolFlag{
+		Name:   "dry-run",
+		Usage:  "compute and log the updates along with the calldata that they would submit without ever signing a transaction, the private key is optional",
+		EnvVar: "GAS_PRICE_ORACLE_DRY_RUN",
+	}
+	EnableGraceFlag = cli.StringFlag{
+		Name:   "enable-grace",
+		Usage:  "how channels catch up with the value on chain once enabled, ease in steps of max-percent-change-per-epoch or immediate, e.g. l1-base-fee=ease,da-fee=immediate, channels left out are immediate",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_GRACE",
+	}
+	L2GasPriceSpreadBlocksFlag = cli.Uint64Flag{
+		Name:   "l2-gas-price-spread-blocks",
+		Value:  0,
+		Usage:  "number of recent L2 blocks to compare the gas price to their realized base fee, 0 disables. Needs eth_feeHistory on the L2 node, which l2geth does not serve",
+		EnvVar: "GAS_PRICE_ORACLE_L2_GAS_PRICE_SPREAD_BLOCKS",
+	}
 	BybitBackendURL = cli.StringFlag{
 		Name:   "bybitBackendURL",
 		Value:  "https://api.bybit.com",
 		Usage:  "bybit exchange backend url",
 		EnvVar: "BYBIT_BACKEND_URL",
 	}
+	BinanceBackendURLFlag = cli.StringFlag{
+		Name:   "binance-backend-url",
+		Value:  "https://api.binance.com",
+		Usage:  "binance exchange backend url",
+		EnvVar: "GAS_PRICE_ORACLE_BINANCE_BACKEND_URL",
+	}
+	TokenPricerBackendFlag = cli.StringFlag{
+		Name:   "token-pricer-backend",
+		Value:  "bybit",
+		Usage:  "comma separated exchanges that the token prices are read from, bybit or binance",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_BACKEND",
+	}
+	TokenPricerQuorumFlag = cli.IntFlag{
+		Name:   "token-pricer-quorum",
+		Value:  1,
+		Usage:  "minimum number of exchanges that must return a price for the token price to be used",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_QUORUM",
+	}
+	TokenPricerAggregationFlag = cli.StringFlag{
+		Name:   "token-pricer-aggregation",
+		Value:  "median",
+		Usage:  "how the prices of several exchanges are combined, median or mean",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_AGGREGATION",
+	}
+	TokenPricerMaxRetriesFlag = cli.IntFlag{
+		Name:   "token-pricer-max-retries",
+		Value:  3,
+		Usage:  "number of times a request to an exchange is retried after a network error, a 429 or a 5xx, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_MAX_RETRIES",
+	}
+	TokenPricerRetryBackoffFlag = cli.StringFlag{
+		Name:   "token-pricer-retry-backoff",
+		Usage:  "backoff between the retries of a request to an exchange as initial=,max=,multiplier=,jitter=, defaults to doubling from 250ms up to 4s with a jitter of 0.2",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_RETRY_BACKOFF",
+	}
+	TokenPricerDeadlineSecondsFlag = cli.Uint64Flag{
+		Name:   "token-pricer-deadline-seconds",
+		Value:  10,
+		Usage:  "deadline of a token price update, all of its requests and retries included, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_DEADLINE_SECONDS",
+	}
+	TokenPricerRateLimitFlag = cli.Float64Flag{
+		Name:   "token-pricer-rate-limit",
+		Usage:  "requests per second sent to the exchanges, all backends and retries included, requests above it wait for their turn, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICER_RATE_LIMIT",
+	}
+	TokenPriceAPIKeyFileFlag = cli.StringFlag{
+		Name:   "token-price-api-key-file",
+		Usage:  "File holding the API key that signs the requests of the price source, unsigned requests use the public endpoints",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_API_KEY_FILE",
+	}
+	TokenPriceAPISecretFileFlag = cli.StringFlag{
+		Name:   "token-price-api-secret-file",
+		Usage:  "File holding the API secret that signs the requests of the price source",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_API_SECRET_FILE",
+	}
 	TokenPricerUpdateFrequencySecond = cli.Uint64Flag{
 		Name:   "tokenPricerUpdateFrequencySecond",
 		Value:  3,
 		Usage:  "token pricer update frequency",
 		EnvVar: "TOKEN_PRICER_UPDATE_FREQUENCY",
 	}
+	TokenPriceSymbolsFlag = cli.StringFlag{
+		Name:   "token-price-symbols",
+		Usage:  "comma separated symbols to track the prices of, e.g. ETHUSDT,MNTUSDT, each refreshed on its own",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_SYMBOLS",
+	}
+	TokenPriceMaxStaleSecondsFlag = cli.Uint64Flag{
+		Name:   "token-price-max-stale-seconds",
+		Usage:  "keep using the last token price for this long while the price source is failing, 0 fails right away",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_MAX_STALE_SECONDS",
+	}
+	TokenPriceMaxAgeFlag = cli.Uint64Flag{
+		Name:   "token-price-max-age",
+		Usage:  "seconds after which the token price polled from the exchanges is too old for an update, which is skipped, 0 polls the exchanges on every update instead",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_MAX_AGE",
+	}
+	TokenPriceStaleMarginPerMinuteFlag = cli.Float64Flag{
+		Name:   "token-price-stale-margin-per-minute",
+		Usage:  "conservative margin added to a stale token price for every minute that the price source is failing",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_STALE_MARGIN_PER_MINUTE",
+	}
+	TokenPriceStrictFlag = cli.BoolFlag{
+		Name:   "token-price-strict",
+		Usage:  "fail on a token price response that does not match the ticker schema instead of coercing it",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_STRICT",
+	}
+	TokenPriceDecimalsFlag = cli.Uint64Flag{
+		Name:   "token-price-decimals",
+		Value:  6,
+		Usage:  "decimals of the fee contract scalar that the token price ratio is scaled for, checked against the contract",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_DECIMALS",
+	}
+	TokenPriceAdaptiveMinSecondsFlag = cli.Uint64Flag{
+		Name:   "token-price-adaptive-min-seconds",
+		Usage:  "shortest token price polling interval while the price is volatile, 0 polls at the update frequency",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_ADAPTIVE_MIN_SECONDS",
+	}
+	TokenPriceAdaptiveMaxSecondsFlag = cli.Uint64Flag{
+		Name:   "token-price-adaptive-max-seconds",
+		Usage:  "longest token price polling interval while the price is stable",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_ADAPTIVE_MAX_SECONDS",
+	}
+	TokenPriceMaxDeviationFlag = cli.Float64Flag{
+		Name:   "token-price-max-deviation",
+		Usage:  "largest change of the token price from the last accepted one, in percent, above which the new price is rejected, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_MAX_DEVIATION",
+	}
+	TokenPriceBreakerResetFlag = cli.IntFlag{
+		Name:   "token-price-breaker-reset",
+		Value:  3,
+		Usage:  "number of consecutive rejected token prices after which the new price is accepted, 0 never accepts it",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_BREAKER_RESET",
+	}
+	TokenPriceVolatilityThresholdFlag = cli.Float64Flag{
+		Name:   "token-price-volatility-threshold",
+		Value:  0.01,
+		Usage:  "relative change of the token price between two polls above which it is considered volatile",
+		EnvVar: "GAS_PRICE_ORACLE_TOKEN_PRICE_VOLATILITY_THRESHOLD",
+	}
 	WaitForReceiptFlag = cli.BoolFlag{
 		Name:   "wait-for-receipt",
 		Usage:  "wait for receipts when sending transactions",
 		EnvVar: "GAS_PRICE_ORACLE_WAIT_FOR_RECEIPT",
 	}
+	ReceiptBackoffFlag = cli.StringFlag{
+		Name:   "receipt-backoff",
+		Usage:  "backoff between polls for a receipt as initial=,max=,multiplier=,jitter=, defaults to polling every 300ms",
+		EnvVar: "GAS_PRICE_ORACLE_RECEIPT_BACKOFF",
+	}
+	ConnectBackoffFlag = cli.StringFlag{
+		Name:   "connect-backoff",
+		Usage:  "backoff between attempts to connect to an endpoint as initial=,max=,multiplier=,jitter=, defaults to retrying every second",
+		EnvVar: "GAS_PRICE_ORACLE_CONNECT_BACKOFF",
+	}
+	HeartbeatIntervalSecondsFlag = cli.Uint64Flag{
+		Name:   "heartbeat-interval-seconds",
+		Usage:  "send a zero value transfer to self when no update was sent for this long, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_HEARTBEAT_INTERVAL_SECONDS",
+	}
+	HeartbeatMaxCostFlag = cli.Uint64Flag{
+		Name:   "heartbeat-max-cost",
+		Value:  1_000_000_000_000_000,
+		Usage:  "maximum cost of a heartbeat transaction in wei, more expensive heartbeats are skipped",
+		EnvVar: "GAS_PRICE_ORACLE_HEARTBEAT_MAX_COST",
+	}
+	WatchdogTimeoutSecondsFlag = cli.Uint64Flag{
+		Name:   "watchdog-timeout-seconds",
+		Usage:  "restart a loop that did not finish an iteration for this long past its interval, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_WATCHDOG_TIMEOUT_SECONDS",
+	}
+	HealthStalenessEpochsFlag = cli.Uint64Flag{
+		Name:   "health-staleness-epochs",
+		Value:  3,
+		Usage:  "report the service as not ready when a channel did not finish an epoch for this many epochs, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_HEALTH_STALENESS_EPOCHS",
+	}
+	StateFileFlag = cli.StringFlag{
+		Name:   "state-file",
+		Usage:  "path of a JSON file that persists the values last sent, which the first decision of every channel after a restart is made against",
+		EnvVar: "GAS_PRICE_ORACLE_STATE_FILE",
+	}
+	ShutdownReferenceURLFlag = cli.StringFlag{
+		Name:   "shutdown-reference-url",
+		Usage:  "URL of trusted channel values, as a JSON object of channel names to decimal values, to compare the on-chain values with at shutdown",
+		EnvVar: "GAS_PRICE_ORACLE_SHUTDOWN_REFERENCE_URL",
+	}
+	ShutdownDivergenceToleranceFlag = cli.Float64Flag{
+		Name:   "shutdown-divergence-tolerance",
+		Value:  0.1,
+		Usage:  "relative difference between an on-chain value and its reference at shutdown that raises an alert",
+		EnvVar: "GAS_PRICE_ORACLE_SHUTDOWN_DIVERGENCE_TOLERANCE",
+	}
+	AlertWebhookURLFlag = cli.StringFlag{
+		Name:   "alert-webhook-url",
+		Usage:  "URL to POST a JSON alert to once a channel fails --alert-failure-threshold consecutive epochs, and once it recovers",
+		EnvVar: "GAS_PRICE_ORACLE_ALERT_WEBHOOK_URL",
+	}
+	AlertFailureThresholdFlag = cli.Uint64Flag{
+		Name:   "alert-failure-threshold",
+		Value:  5,
+		Usage:  "number of consecutive failed epochs of a channel that posts an alert to --alert-webhook-url",
+		EnvVar: "GAS_PRICE_ORACLE_ALERT_FAILURE_THRESHOLD",
+	}
+	ShutdownTimeoutSecondsFlag = cli.Uint64Flag{
+		Name:   "shutdown-timeout-seconds",
+		Value:  30,
+		Usage:  "time to wait for the updates in flight, and then for the reconciliation, at shutdown",
+		EnvVar: "GAS_PRICE_ORACLE_SHUTDOWN_TIMEOUT_SECONDS",
+	}
+	HistorySizeFlag = cli.Uint64Flag{
+		Name:   "history-size",
+		Value:  1000,
+		Usage:  "number of recent decisions kept per channel for GET /series, 0 disables",
+		EnvVar: "GAS_PRICE_ORACLE_HISTORY_SIZE",
+	}
+	AuditStdoutFlag = cli.BoolFlag{
+		Name:   "audit-stdout",
+		Usage:  "write the decision of every epoch of every channel to stdout as a line of JSON, logs go to stderr",
+		EnvVar: "GAS_PRICE_ORACLE_AUDIT_STDOUT",
+	}
+	OnceFlag = cli.BoolFlag{
+		Name:   "once",
+		Usage:  "run a single update of every enabled channel and exit, with a non-zero status when one fails",
+		EnvVar: "GAS_PRICE_ORACLE_ONCE",
+	}
+	ReplayFileFlag = cli.StringFlag{
+		Name:   "replay-file",
+		Usage:  "replay the samples of timestamp, baseFee and gasUsedPerSecond of this CSV or JSON lines file in place of the RPC endpoints, writing the decision of every epoch without sending transactions",
+		EnvVar: "GAS_PRICE_ORACLE_REPLAY_FILE",
+	}
+	ReplayOutFlag = cli.StringFlag{
+		Name:   "replay-out",
+		Usage:  "file to write the decisions of --replay-file to, in place of stdout",
+		EnvVar: "GAS_PRICE_ORACLE_REPLAY_OUT",
+	}
 	MetricsEnabledFlag = cli.BoolFlag{
 		Name:   "metrics",
 		Usage:  "Enable metrics collection and reporting",
 		EnvVar: "GAS_PRICE_ORACLE_METRICS_ENABLE",
 	}
+	MetricsPrometheusFlag = cli.BoolFlag{
+		Name:   "metrics.prometheus",
+		Usage:  "Enable metrics collection and serve them to Prometheus under /metrics on the metrics HTTP server",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_PROMETHEUS",
+	}
 	MetricsHTTPFlag = cli.StringFlag{
 		Name:   "metrics.addr",
 		Usage:  "Enable stand-alone metrics HTTP server listening interface",
@@ -164,6 +792,41 @@ var (
 		Value:  6060,
 		EnvVar: "GAS_PRICE_ORACLE_METRICS_PORT",
 	}
+	MetricsAuthUsernameFlag = cli.StringFlag{
+		Name:   "metrics.auth.username",
+		Usage:  "Username required by the metrics HTTP server using basic auth, disabled when empty",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_AUTH_USERNAME",
+	}
+	MetricsAuthPasswordFlag = cli.StringFlag{
+		Name:   "metrics.auth.password",
+		Usage:  "Password required by the metrics HTTP server using basic auth",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_AUTH_PASSWORD",
+	}
+	MetricsAuthPasswordFileFlag = cli.StringFlag{
+		Name:   "metrics.auth.password-file",
+		Usage:  "File holding the password of the metrics HTTP server, instead of --metrics.auth.password",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_AUTH_PASSWORD_FILE",
+	}
+	MetricsAuthTokenFlag = cli.StringFlag{
+		Name:   "metrics.auth.token",
+		Usage:  "Bearer token accepted by the metrics HTTP server, disabled when empty",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_AUTH_TOKEN",
+	}
+	MetricsAuthTokenFileFlag = cli.StringFlag{
+		Name:   "metrics.auth.token-file",
+		Usage:  "File holding the bearer token of the metrics HTTP server, instead of --metrics.auth.token",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_AUTH_TOKEN_FILE",
+	}
+	MetricsGzipFlag = cli.BoolFlag{
+		Name:   "metrics.gzip",
+		Usage:  "Gzip compress metrics HTTP server responses when the client accepts it",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_GZIP",
+	}
+	PprofFlag = cli.BoolFlag{
+		Name:   "pprof",
+		Usage:  "Serve the pprof profiling endpoints under /debug/pprof/ on the metrics HTTP server",
+		EnvVar: "GAS_PRICE_ORACLE_PPROF",
+	}
 	MetricsEnableInfluxDBFlag = cli.BoolFlag{
 		Name:   "metrics.influxdb",
 		Usage:  "Enable metrics export/push to an external InfluxDB database",
@@ -193,40 +856,187 @@ var (
 		Value:  "test",
 		EnvVar: "GAS_PRICE_ORACLE_METRICS_INFLUX_DB_PASSWORD",
 	}
+	MetricsInfluxDBPasswordFileFlag = cli.StringFlag{
+		Name:   "metrics.influxdb.password-file",
+		Usage:  "File holding the password of the database, instead of --metrics.influxdb.password",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_INFLUX_DB_PASSWORD_FILE",
+	}
+	WatchURLFlag = cli.StringFlag{
+		Name:  "url",
+		Value: "http://127.0.0.1:6060",
+		Usage: "metrics server of the running instance to watch",
+	}
+	WatchIntervalSecondsFlag = cli.Uint64Flag{
+		Name:  "interval-seconds",
+		Value: 3,
+		Usage: "seconds between refreshes of the summary",
+	}
 )
 
+// WatchFlags are the flags of the watch command. It authenticates with the
+// metrics.auth flags of the instance.
+var WatchFlags = []cli.Flag{
+	WatchURLFlag,
+	WatchIntervalSecondsFlag,
+}
+
 var Flags = []cli.Flag{
+	ConfigFlag,
+	ProfileFlag,
 	EthereumHttpUrlFlag,
 	LayerTwoHttpUrlFlag,
+	L1BaseFeeReadHttpUrlFlag,
+	L1BaseFeeWriteHttpUrlFlag,
+	L2GasPriceReadHttpUrlFlag,
+	L2GasPriceWriteHttpUrlFlag,
+	DaFeeReadHttpUrlFlag,
+	DaFeeWriteHttpUrlFlag,
 	L1ChainIDFlag,
 	L2ChainIDFlag,
 	L1BaseFeeSignificanceFactorFlag,
+	L1BaseFeeModeFlag,
+	L1BaseFeeWindowFlag,
+	L1EpochBlocksFlag,
+	DualComputeL1HttpUrlFlag,
+	DualComputeToleranceFlag,
+	L1BaseFeeMaxFreezeSecondsFlag,
 	DaFeeSignificanceFactorFlag,
+	DaFeeMinFlag,
+	OverheadFlag,
+	OverheadSignificanceFactorFlag,
+	ScalarFlag,
+	ScalarSignificanceFactorFlag,
+	DaFeeMaxFlag,
+	GasPriceRoundToFlag,
+	L1BaseFeeRoundToFlag,
+	DaFeeRoundToFlag,
+	DaFeeModelFlag,
+	DaFeeExpressionFlag,
 	GasPriceOracleAddressFlag,
 	DaFeeContractAddressFlag,
+	DeploymentsFlag,
+	SubmissionPathFlag,
+	DepositPortalAddressFlag,
+	DepositGasLimitFlag,
 	PrivateKeyFlag,
+	PrivateKeyFileFlag,
+	SignerEndpointFlag,
+	SignerAddressFlag,
+	KMSKeyIDFlag,
+	KMSRegionFlag,
 	TransactionGasPriceFlag,
+	GasPriceSourceFlag,
+	GasPriceHistoryBlocksFlag,
+	GasPriceHistoryPercentileFlag,
+	TxTypeFlag,
+	TxMaxFeePerGasFlag,
+	TxMaxPriorityFeePerGasFlag,
 	LogLevelFlag,
+	LogFormatFlag,
 	FloorPriceFlag,
+	CeilingPriceFlag,
 	TargetGasPerSecondFlag,
+	L2GasPriceModeFlag,
+	TargetInclusionSecondsFlag,
+	InclusionLatencyPercentileFlag,
+	InclusionPollSecondsFlag,
 	MaxPercentChangePerEpochFlag,
 	AverageBlockGasLimitPerEpochFlag,
 	EpochLengthSecondsFlag,
 	L1BaseFeeEpochLengthSecondsFlag,
 	DaFeeEpochLengthSecondsFlag,
+	OverheadEpochLengthSecondsFlag,
+	ScalarEpochLengthSecondsFlag,
+	EpochInputBudgetMsFlag,
 	L2GasPriceSignificanceFactorFlag,
+	L2GasPriceSignificanceFactorUpFlag,
+	L2GasPriceSignificanceFactorDownFlag,
+	L2GasPriceEMAAlphaFlag,
+	EmergencyUpdateThresholdFlag,
+	EmergencyWindowSecondsFlag,
+	EmergencySignificanceFactorFlag,
+	ReversalWindowWritesFlag,
+	ReversalThresholdFlag,
+	ReversalSignificanceFactorFlag,
+	MaxDropPerPeriodFlag,
+	MaxDropPeriodSecondsFlag,
+	StuckWriteThresholdFlag,
+	NonceReconcileIntervalSecondsFlag,
+	TxResubmitTimeoutFlag,
+	TxMaxBumpsFlag,
+	SimulateBeforeSendFlag,
+	MaxInflightUpdatesFlag,
+	MaxPendingTransactionsFlag,
+	SubmissionTimeoutSecondsFlag,
+	SubmissionPollSecondsFlag,
+	SpendBudgetGweiFlag,
+	SpendBudgetWindowSecondsFlag,
+	ChannelPriorityFlag,
+	ObserveOnlyFlag,
+	DryRunFlag,
+	EnableGraceFlag,
+	L2GasPriceSpreadBlocksFlag,
 	BybitBackendURL,
+	BinanceBackendURLFlag,
+	TokenPricerBackendFlag,
+	TokenPricerQuorumFlag,
+	TokenPricerAggregationFlag,
+	TokenPricerMaxRetriesFlag,
+	TokenPricerRetryBackoffFlag,
+	TokenPricerDeadlineSecondsFlag,
+	TokenPricerRateLimitFlag,
+	TokenPriceAPIKeyFileFlag,
+	TokenPriceAPISecretFileFlag,
 	TokenPricerUpdateFrequencySecond,
+	TokenPriceSymbolsFlag,
+	TokenPriceMaxStaleSecondsFlag,
+	TokenPriceMaxAgeFlag,
+	TokenPriceStaleMarginPerMinuteFlag,
+	TokenPriceStrictFlag,
+	TokenPriceDecimalsFlag,
+	TokenPriceAdaptiveMinSecondsFlag,
+	TokenPriceAdaptiveMaxSecondsFlag,
+	TokenPriceVolatilityThresholdFlag,
+	TokenPriceMaxDeviationFlag,
+	TokenPriceBreakerResetFlag,
 	WaitForReceiptFlag,
+	ReceiptBackoffFlag,
+	ConnectBackoffFlag,
+	HeartbeatIntervalSecondsFlag,
+	HeartbeatMaxCostFlag,
+	WatchdogTimeoutSecondsFlag,
+	HealthStalenessEpochsFlag,
+	StateFileFlag,
+	ShutdownReferenceURLFlag,
+	ShutdownDivergenceToleranceFlag,
+	ShutdownTimeoutSecondsFlag,
+	AlertWebhookURLFlag,
+	AlertFailureThresholdFlag,
 	EnableL1BaseFeeFlag,
 	EnableL2GasPriceFlag,
 	EnableDaFeeFlag,
+	EnableOverheadFlag,
+	EnableScalarFlag,
+	HistorySizeFlag,
+	AuditStdoutFlag,
+	OnceFlag,
+	ReplayFileFlag,
+	ReplayOutFlag,
 	MetricsEnabledFlag,
+	MetricsPrometheusFlag,
 	MetricsHTTPFlag,
 	MetricsPortFlag,
+	MetricsAuthUsernameFlag,
+	MetricsAuthPasswordFlag,
+	MetricsAuthPasswordFileFlag,
+	MetricsAuthTokenFlag,
+	MetricsAuthTokenFileFlag,
+	MetricsGzipFlag,
+	PprofFlag,
 	MetricsEnableInfluxDBFlag,
 	MetricsInfluxDBEndpointFlag,
 	MetricsInfluxDBDatabaseFlag,
 	MetricsInfluxDBUsernameFlag,
 	MetricsInfluxDBPasswordFlag,
+	MetricsInfluxDBPasswordFileFlag,
 }
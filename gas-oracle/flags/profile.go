@@ -0,0 +1,124 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/urfave/cli"
+)
+
+// Profiles are the built in bundles of defaults of the environments that
+// the oracle runs in, keyed by the names of the flags
+var Profiles = map[string]map[string]string{
+	"mainnet": {
+		L1ChainIDFlag.Name:      "1",
+		L2ChainIDFlag.Name:      "5000",
+		WaitForReceiptFlag.Name: "true",
+	},
+	"testnet": {
+		L1ChainIDFlag.Name: "5",
+		L2ChainIDFlag.Name: "5001",
+	},
+	"devnet": {
+		EthereumHttpUrlFlag.Name:     "http://l1_chain:8545",
+		LayerTwoHttpUrlFlag.Name:     "http://l2geth:8545",
+		L1ChainIDFlag.Name:           "31337",
+		L2ChainIDFlag.Name:           "17",
+		TransactionGasPriceFlag.Name: "0",
+		EnableL1BaseFeeFlag.Name:     "true",
+		EnableL2GasPriceFlag.Name:    "false",
+		EnableDaFeeFlag.Name:         "true",
+	},
+}
+
+// LoadProfiles parses the custom profiles of the profiles section of a YAML
+// config file. Every key of the section names a profile that holds options
+// the same way as the config file.
+func LoadProfiles(r io.Reader) (map[string]map[string]string, error) {
+	raw, err := decodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	return profileValues(raw[profilesSection])
+}
+
+// profileValues converts the decoded profiles section of a config into the
+// string values of the flags that every profile sets
+func profileValues(section interface{}) (map[string]map[string]string, error) {
+	if section == nil {
+		return map[string]map[string]string{}, nil
+	}
+	raw, ok := section.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config %s must be a map of profiles", profilesSection)
+	}
+	profiles := make(map[string]map[string]string, len(raw))
+	for name, options := range raw {
+		var values map[string]string
+		switch options := options.(type) {
+		case map[string]interface{}:
+			parsed, err := optionValues(options)
+			if err != nil {
+				return nil, fmt.Errorf("profile %s: %w", name, err)
+			}
+			values = parsed
+		case nil:
+			values = map[string]string{}
+		default:
+			return nil, fmt.Errorf("profile %s must be a map of options", name)
+		}
+		profiles[name] = values
+	}
+	return profiles, nil
+}
+
+// ApplyProfile sets the flags of the profile selected by --profile to the
+// values of the profile. Flags set on the command line or through their
+// environment variable are left untouched. A profile of the profiles
+// section of the --config file takes precedence over the built in profile
+// of the same name.
+func ApplyProfile(ctx *cli.Context) error {
+	name := ctx.GlobalString(ProfileFlag.Name)
+	if name == "" {
+		return nil
+	}
+
+	profile, ok := Profiles[name]
+	if path := ctx.GlobalString(ConfigFlag.Name); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("cannot load --%s: %w", ConfigFlag.Name, err)
+		}
+		defer file.Close()
+		custom, err := LoadProfiles(file)
+		if err != nil {
+			return fmt.Errorf("cannot load --%s: %w", ConfigFlag.Name, err)
+		}
+		if values, found := custom[name]; found {
+			profile, ok = values, true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	options := make([]string, 0, len(profile))
+	for option := range profile {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	for _, option := range options {
+		if option == ProfileFlag.Name || option == ConfigFlag.Name {
+			return fmt.Errorf("profile %s cannot set %s", name, option)
+		}
+		if ctx.GlobalIsSet(option) {
+			continue
+		}
+		if err := ctx.GlobalSet(option, profile[option]); err != nil {
+			return fmt.Errorf("profile %s: invalid %s: %w", name, option, err)
+		}
+	}
+	return nil
+}
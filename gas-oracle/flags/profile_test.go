@@ -0,0 +1,95 @@
+package flags
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+// newProfileContext returns a cli.Context of the app with all of the
+// flags registered and parsed from args
+func newProfileContext(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+	app := cli.NewApp()
+	app.Flags = Flags
+	set := flag.NewFlagSet("gas-oracle", flag.ContinueOnError)
+	for _, f := range Flags {
+		f.Apply(set)
+	}
+	require.NoError(t, set.Parse(args))
+	return cli.NewContext(app, set, nil)
+}
+
+func TestProfileSetsDefaults(t *testing.T) {
+	ctx := newProfileContext(t, "--profile", "devnet")
+	require.NoError(t, ApplyProfile(ctx))
+
+	require.Equal(t, "http://l1_chain:8545", ctx.GlobalString(EthereumHttpUrlFlag.Name))
+	require.Equal(t, uint64(17), ctx.GlobalUint64(L2ChainIDFlag.Name))
+	require.True(t, ctx.GlobalBool(EnableDaFeeFlag.Name))
+	require.False(t, ctx.GlobalBool(EnableL2GasPriceFlag.Name))
+	// Options that the profile does not set keep their default
+	require.Equal(t, uint64(10), ctx.GlobalUint64(EpochLengthSecondsFlag.Name))
+}
+
+func TestExplicitFlagsOverrideProfile(t *testing.T) {
+	t.Setenv(L1ChainIDFlag.EnvVar, "11155111")
+	ctx := newProfileContext(t,
+		"--profile", "mainnet",
+		"--l2-chain-id", "5003",
+	)
+	require.NoError(t, ApplyProfile(ctx))
+
+	require.Equal(t, uint64(5003), ctx.GlobalUint64(L2ChainIDFlag.Name))
+	require.Equal(t, uint64(11155111), ctx.GlobalUint64(L1ChainIDFlag.Name))
+	require.True(t, ctx.GlobalBool(WaitForReceiptFlag.Name))
+}
+
+func TestCustomProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join([]string{
+		"l1-chain-id: 11155111",
+		"profiles:",
+		"  staging:",
+		"    l2-chain-id: 5003",
+		"    da-fee-significant-factor: 0.2",
+		"  testnet:",
+		"    l2-chain-id: 5002",
+	}, "\n")), 0o600))
+
+	ctx := newProfileContext(t, "--profile", "staging", "--config", path)
+	require.NoError(t, ApplyProfile(ctx))
+	require.Equal(t, uint64(5003), ctx.GlobalUint64(L2ChainIDFlag.Name))
+	require.Equal(t, 0.2, ctx.GlobalFloat64(DaFeeSignificanceFactorFlag.Name))
+
+	// A custom profile replaces the built in profile of the same name
+	ctx = newProfileContext(t, "--profile", "testnet", "--config", path)
+	require.NoError(t, ApplyProfile(ctx))
+	require.Equal(t, uint64(5002), ctx.GlobalUint64(L2ChainIDFlag.Name))
+	require.Equal(t, uint64(0), ctx.GlobalUint64(L1ChainIDFlag.Name))
+
+	// The options of the config are applied apart from its profiles and
+	// take precedence over the profile, as they do in main
+	ctx = newProfileContext(t, "--profile", "testnet", "--config", path)
+	require.NoError(t, ApplyConfig(ctx))
+	require.NoError(t, ApplyProfile(ctx))
+	require.Equal(t, uint64(11155111), ctx.GlobalUint64(L1ChainIDFlag.Name))
+	require.Equal(t, uint64(5002), ctx.GlobalUint64(L2ChainIDFlag.Name))
+
+	ctx = newProfileContext(t, "--profile", "unknown", "--config", path)
+	require.Error(t, ApplyProfile(ctx))
+}
+
+func TestLoadProfilesUnknownOption(t *testing.T) {
+	_, err := LoadProfiles(strings.NewReader("profiles:\n  staging:\n    no-such-option: 1\n"))
+	require.Error(t, err)
+
+	// The config rejects the same profiles section
+	_, err = LoadConfig(strings.NewReader("profiles:\n  staging:\n    no-such-option: 1\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// ErrSecretConflict is returned when a secret is set both inline and from
+// a file
+var ErrSecretConflict = errors.New("secret set both inline and from a file")
+
+// secretFlags pairs the inline flag of every secret with its file flag
+var secretFlags = []struct{ inline, file cli.StringFlag }{
+	{PrivateKeyFlag, PrivateKeyFileFlag},
+	{MetricsAuthPasswordFlag, MetricsAuthPasswordFileFlag},
+	{MetricsAuthTokenFlag, MetricsAuthTokenFileFlag},
+	{MetricsInfluxDBPasswordFlag, MetricsInfluxDBPasswordFileFlag},
+}
+
+// isInlineSecret reports whether the flag sets a secret that can also be
+// read from a file
+func isInlineSecret(name string) bool {
+	for _, secret := range secretFlags {
+		if secret.inline.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Secret returns the value of a sensitive option. The value is read from
+// the file that the file flag names, so that it appears in neither the
+// arguments nor the environment of the process, or else from the inline
+// flag. Setting both is an error. Surrounding whitespace, like the
+// trailing newline of the file, is trimmed.
+func Secret(ctx *cli.Context, inline, file cli.StringFlag) (string, error) {
+	path := ctx.GlobalString(file.Name)
+	if path == "" {
+		return ctx.GlobalString(inline.Name), nil
+	}
+	if ctx.GlobalIsSet(inline.Name) {
+		return "", fmt.Errorf("%w: --%s and --%s", ErrSecretConflict, inline.Name, file.Name)
+	}
+	return readSecret(file, path)
+}
+
+// SecretFile returns the value of a secret that is only read from a file,
+// or an empty value when the file flag is not set
+func SecretFile(ctx *cli.Context, file cli.StringFlag) (string, error) {
+	path := ctx.GlobalString(file.Name)
+	if path == "" {
+		return "", nil
+	}
+	return readSecret(file, path)
+}
+
+func readSecret(file cli.StringFlag, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read --%s: %w", file.Name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,43 @@
+package flags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("s3cret\n"), 0o600))
+
+	ctx := newProfileContext(t, "--metrics.auth.token-file", path)
+	token, err := Secret(ctx, MetricsAuthTokenFlag, MetricsAuthTokenFileFlag)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", token)
+
+	// Without a file the inline value is used
+	ctx = newProfileContext(t, "--metrics.auth.token", "inline")
+	token, err = Secret(ctx, MetricsAuthTokenFlag, MetricsAuthTokenFileFlag)
+	require.NoError(t, err)
+	require.Equal(t, "inline", token)
+
+	ctx = newProfileContext(t, "--metrics.auth.token-file", filepath.Join(t.TempDir(), "missing"))
+	_, err = Secret(ctx, MetricsAuthTokenFlag, MetricsAuthTokenFileFlag)
+	require.Error(t, err)
+}
+
+func TestSecretInlineAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte("0x01"), 0o600))
+
+	ctx := newProfileContext(t, "--private-key", "0x02", "--private-key-file", path)
+	_, err := Secret(ctx, PrivateKeyFlag, PrivateKeyFileFlag)
+	require.ErrorIs(t, err, ErrSecretConflict)
+
+	// The conflict holds for a secret with a default value as well
+	ctx = newProfileContext(t, "--metrics.influxdb.password", "test", "--metrics.influxdb.password-file", path)
+	_, err = Secret(ctx, MetricsInfluxDBPasswordFlag, MetricsInfluxDBPasswordFileFlag)
+	require.ErrorIs(t, err, ErrSecretConflict)
+}
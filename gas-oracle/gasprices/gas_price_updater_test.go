@@ -21,7 +21,7 @@ func makeTestGasPricerAndUpdater(curPrice uint64) (*GasPricer, *GasPriceUpdater,
 	averageBlockGasLimit := uint64(11000000)
 	tokenPricer := tokenprice.NewClient("https://api.bybit.com", 3)
 	// Based on our 10 second epoch, we are targeting 3 blocks per epoch.
-	gasPricer, err := NewGasPricer(curPrice, 1, tokenPricer, getGasTarget, 10)
+	gasPricer, err := NewGasPricer(curPrice, 1, 0, tokenPricer, getGasTarget, 10)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -0,0 +1,155 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// GetInclusionLatenciesFn returns the seconds that the transactions
+// included in the blocks after from up to to waited to be included
+type GetInclusionLatenciesFn func(from, to uint64) ([]float64, error)
+
+// InclusionPricer moves the L2 gas price towards the price at which the
+// transactions are included within a target time. Once per epoch the
+// latency percentile of the transactions included in the epoch is compared
+// with the target: a slower inclusion raises the price and a faster one
+// lowers it, by at most the max change per epoch.
+type InclusionPricer struct {
+	mu                      sync.RWMutex
+	curPrice                uint64
+	floorPrice              uint64
+	ceilingPrice            uint64
+	targetSeconds           float64
+	percentile              float64
+	maxChangePerEpoch       float64
+	epochStartBlockNumber   uint64
+	getLatestBlockNumberFn  GetLatestBlockNumberFn
+	getInclusionLatenciesFn GetInclusionLatenciesFn
+	updateL2GasPriceFn      UpdateL2GasPriceFn
+}
+
+// NewInclusionPricer creates an InclusionPricer and checks its config
+// beforehand. The percentile is between 0 and 1, and a ceilingPrice of zero
+// does not cap the gas price.
+func NewInclusionPricer(
+	curPrice, floorPrice, ceilingPrice uint64,
+	targetSeconds, percentile, maxPercentChangePerEpoch float64,
+	epochStartBlockNumber uint64,
+	getLatestBlockNumberFn GetLatestBlockNumberFn,
+	getInclusionLatenciesFn GetInclusionLatenciesFn,
+	updateL2GasPriceFn UpdateL2GasPriceFn,
+) (*InclusionPricer, error) {
+	if floorPrice < 1 {
+		return nil, errors.New("floorPrice must be greater than or equal to 1")
+	}
+	if ceilingPrice != 0 && ceilingPrice < floorPrice {
+		return nil, errors.New("ceilingPrice must be greater than or equal to floorPrice")
+	}
+	if targetSeconds <= 0 {
+		return nil, errors.New("targetSeconds must be greater than 0")
+	}
+	if percentile <= 0 || percentile > 1 {
+		return nil, errors.New("percentile must be between (0,1]")
+	}
+	if maxPercentChangePerEpoch <= 0 {
+		return nil, errors.New("maxPercentChangePerEpoch must be between (0,100]")
+	}
+	return &InclusionPricer{
+		curPrice:                max(curPrice, floorPrice),
+		floorPrice:              floorPrice,
+		ceilingPrice:            ceilingPrice,
+		targetSeconds:           targetSeconds,
+		percentile:              percentile,
+		maxChangePerEpoch:       maxPercentChangePerEpoch,
+		epochStartBlockNumber:   epochStartBlockNumber,
+		getLatestBlockNumberFn:  getLatestBlockNumberFn,
+		getInclusionLatenciesFn: getInclusionLatenciesFn,
+		updateL2GasPriceFn:      updateL2GasPriceFn,
+	}, nil
+}
+
+// CalcNextEpochGasPrice calculates the next gas price given the inclusion
+// latencies of the last epoch. Without latencies nothing was observed
+// waiting, and the price is kept.
+func (p *InclusionPricer) CalcNextEpochGasPrice(latencies []float64) (uint64, error) {
+	if len(latencies) == 0 {
+		log.Debug("No inclusion latencies in the epoch, keeping the gas price", "price", p.curPrice)
+		return p.curPrice, nil
+	}
+	for _, latency := range latencies {
+		if latency < 0 {
+			return 0, fmt.Errorf("inclusion latency cannot be negative, got %f", latency)
+		}
+	}
+	observed := latencyPercentile(latencies, p.percentile)
+	// The proportion of the target that the inclusion took
+	proportionOfTarget := observed / p.targetSeconds
+
+	proportionToChangeBy := 0.0
+	if proportionOfTarget >= 1 { // If the inclusion is SLOWER than the target
+		proportionToChangeBy = math.Min(proportionOfTarget, 1+p.maxChangePerEpoch)
+	} else {
+		proportionToChangeBy = math.Max(proportionOfTarget, 1-p.maxChangePerEpoch)
+	}
+	updated := float64(max(1, p.curPrice)) * proportionToChangeBy
+	result := clampCeiling(max(p.floorPrice, uint64(math.Ceil(updated))), p.ceilingPrice)
+
+	log.Debug("Calculated next epoch gas price", "observed-latency", observed,
+		"target-latency", p.targetSeconds, "proportionToChangeBy", proportionToChangeBy, "result", result)
+
+	return result, nil
+}
+
+// UpdateGasPrice completes the epoch that ends at the latest block and
+// writes the gas price of the next one
+func (p *InclusionPricer) UpdateGasPrice() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	latestBlockNumber, err := p.getLatestBlockNumberFn()
+	if err != nil {
+		return err
+	}
+	if latestBlockNumber < p.epochStartBlockNumber {
+		return errors.New("Latest block number less than the last epoch's block number")
+	}
+	if latestBlockNumber == p.epochStartBlockNumber {
+		log.Debug("latest block number is equal to epoch start block number", "number", latestBlockNumber)
+		return nil
+	}
+
+	latencies, err := p.getInclusionLatenciesFn(p.epochStartBlockNumber, latestBlockNumber)
+	if err != nil {
+		return err
+	}
+	price, err := p.CalcNextEpochGasPrice(latencies)
+	if err != nil {
+		return err
+	}
+	p.curPrice = price
+	p.epochStartBlockNumber = latestBlockNumber
+	return p.updateL2GasPriceFn(p.curPrice)
+}
+
+func (p *InclusionPricer) GetGasPrice() uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.curPrice
+}
+
+// latencyPercentile returns the latency below which the percentile of the
+// latencies falls, using the nearest rank
+func latencyPercentile(latencies []float64, percentile float64) float64 {
+	sorted := append([]float64(nil), latencies...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(percentile*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,137 @@
+package gasprices
+
+import (
+	"testing"
+)
+
+func TestCalcInclusionGasPrice(t *testing.T) {
+	p, err := NewInclusionPricer(100, 10, 0, 4, 0.5, 0.5, 0, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcs := []struct {
+		name      string
+		latencies []float64
+		expected  uint64
+	}{
+		{"No change when the median is at the target", []float64{1, 4, 8}, 100},
+		{"No change without latencies", nil, 100},
+		{"Raise by a quarter when the median is a quarter slower", []float64{5, 5, 0}, 125},
+		{"Max % change bounds the increase in price", []float64{40, 40, 40}, 150},
+		{"Lower by half when the median is twice as fast", []float64{2, 2, 30}, 50},
+		{"Max % change bounds the reduction in price", []float64{0, 0, 0}, 50},
+	}
+	for _, tc := range tcs {
+		price, err := p.CalcNextEpochGasPrice(tc.latencies)
+		if err != nil || price != tc.expected {
+			t.Fatalf("failed on test: %s: got %d, %v", tc.name, price, err)
+		}
+	}
+
+	// The price does not go below the floor
+	p.curPrice = 12
+	if price, _ := p.CalcNextEpochGasPrice([]float64{0}); price != 10 {
+		t.Fatalf("expected the floor price, got %d", price)
+	}
+	if _, err := p.CalcNextEpochGasPrice([]float64{-1}); err == nil {
+		t.Fatal("expected an error on a negative latency")
+	}
+}
+
+func TestCalcInclusionGasPriceCeiling(t *testing.T) {
+	p, err := NewInclusionPricer(100, 80, 120, 4, 0.5, 0.5, 0, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcs := []struct {
+		name      string
+		latencies []float64
+		expected  uint64
+	}{
+		{"The floor applies below the floor", []float64{0, 0, 0}, 80},
+		{"No clamp within the range", []float64{4.5, 4.5, 4.5}, 113},
+		{"The ceiling applies after the max % change", []float64{40, 40, 40}, 120},
+	}
+	for _, tc := range tcs {
+		price, err := p.CalcNextEpochGasPrice(tc.latencies)
+		if err != nil || price != tc.expected {
+			t.Fatalf("failed on test: %s: got %d, %v", tc.name, price, err)
+		}
+	}
+
+	if _, err := NewInclusionPricer(100, 80, 79, 4, 0.5, 0.5, 0, nil, nil, nil); err == nil {
+		t.Fatal("expected an error on a ceiling below the floor")
+	}
+}
+
+func TestInclusionPricerMeetsObjective(t *testing.T) {
+	// Transactions wait 10 seconds at a price of 100, and the wait shrinks
+	// as the price rises
+	latencyAt := func(price uint64) float64 {
+		return 1000 / float64(price)
+	}
+
+	var written []uint64
+	block := uint64(0)
+	var p *InclusionPricer
+	p, err := NewInclusionPricer(100, 1, 0, 5, 0.9, 0.25, 0,
+		func() (uint64, error) {
+			block++
+			return block, nil
+		},
+		func(from, to uint64) ([]float64, error) {
+			if from+1 != to {
+				t.Fatalf("expected the blocks of a single epoch, got (%d, %d]", from, to)
+			}
+			latency := latencyAt(p.curPrice)
+			return []float64{latency / 2, latency, latency}, nil
+		},
+		func(price uint64) error {
+			written = append(written, price)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := p.UpdateGasPrice(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(written) != 20 {
+		t.Fatalf("expected a write per epoch, got %d", len(written))
+	}
+	// The price rises, by at most the max change per epoch, until the
+	// transactions are included within the target, and settles there
+	for i := 1; i < len(written); i++ {
+		if written[i] < written[i-1] || float64(written[i]) > float64(written[i-1])*1.25+1 {
+			t.Fatalf("price did not rise steadily: %v", written)
+		}
+	}
+	last := written[len(written)-1]
+	if last < 195 || last > 205 {
+		t.Fatalf("price %d does not meet the objective: %v", last, written)
+	}
+	if p.GetGasPrice() != last {
+		t.Fatalf("expected the last written price, got %d", p.GetGasPrice())
+	}
+}
+
+func TestNewInclusionPricerChecksConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name                     string
+		floor                    uint64
+		target, percentile, diff float64
+	}{
+		{"floor", 0, 1, 0.5, 0.1},
+		{"target", 1, 0, 0.5, 0.1},
+		{"percentile", 1, 1, 1.5, 0.1},
+		{"max change", 1, 1, 0.5, 0},
+	} {
+		if _, err := NewInclusionPricer(1, tc.floor, 0, tc.target, tc.percentile, tc.diff, 0, nil, nil, nil); err == nil {
+			t.Fatalf("expected an invalid %s to fail", tc.name)
+		}
+	}
+}
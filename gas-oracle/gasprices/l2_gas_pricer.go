@@ -6,16 +6,25 @@ import (
 	"math"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
 	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
 )
 
+// gasPriceClampedCounter counts the gas prices that were capped to the
+// ceiling
+func gasPriceClampedCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("gas_price_clamped_total", ometrics.DefaultRegistry)
+}
+
 type GetTargetGasPerSecond func() float64
 
 type GasPricer struct {
 	curPrice                 uint64
 	avgGasPerSecondLastEpoch float64
 	floorPrice               uint64
-	tokenPricer              *tokenprice.Client
+	ceilingPrice             uint64
+	tokenPricer              tokenprice.RatioReader
 	getTargetGasPerSecond    GetTargetGasPerSecond
 	maxChangePerEpoch        float64
 }
@@ -27,11 +36,15 @@ func GetLinearInterpolationFn(getX func() float64, x1 float64, x2 float64, y1 fl
 	}
 }
 
-// NewGasPricer creates a GasPricer and checks its config beforehand
-func NewGasPricer(curPrice, floorPrice uint64, tokenPricer *tokenprice.Client, getTargetGasPerSecond GetTargetGasPerSecond, maxPercentChangePerEpoch float64) (*GasPricer, error) {
+// NewGasPricer creates a GasPricer and checks its config beforehand. A
+// ceilingPrice of zero does not cap the gas price.
+func NewGasPricer(curPrice, floorPrice, ceilingPrice uint64, tokenPricer tokenprice.RatioReader, getTargetGasPerSecond GetTargetGasPerSecond, maxPercentChangePerEpoch float64) (*GasPricer, error) {
 	if floorPrice < 1 {
 		return nil, errors.New("floorPrice must be greater than or equal to 1")
 	}
+	if ceilingPrice != 0 && ceilingPrice < floorPrice {
+		return nil, errors.New("ceilingPrice must be greater than or equal to floorPrice")
+	}
 	if maxPercentChangePerEpoch <= 0 {
 		return nil, errors.New("maxPercentChangePerEpoch must be between (0,100]")
 	}
@@ -39,6 +52,7 @@ func NewGasPricer(curPrice, floorPrice uint64, tokenPricer *tokenprice.Client, g
 		tokenPricer:           tokenPricer,
 		curPrice:              max(curPrice, floorPrice),
 		floorPrice:            floorPrice,
+		ceilingPrice:          ceilingPrice,
 		getTargetGasPerSecond: getTargetGasPerSecond,
 		maxChangePerEpoch:     maxPercentChangePerEpoch,
 	}, nil
@@ -72,7 +86,7 @@ func (p *GasPricer) CalcNextEpochGasPrice(avgGasPerSecondLastEpoch float64) (uin
 		return 0.0, err
 	}
 	updated := float64(max(1, p.curPrice)) * proportionToChangeBy * ratio
-	result := max(p.floorPrice, uint64(math.Ceil(updated)))
+	result := clampCeiling(max(p.floorPrice, uint64(math.Ceil(updated))), p.ceilingPrice)
 
 	log.Debug("Calculated next epoch gas price", "proportionToChangeBy", proportionToChangeBy,
 		"proportionOfTarget", proportionOfTarget, "result", result)
@@ -91,6 +105,17 @@ func (p *GasPricer) CompleteEpoch(avgGasPerSecondLastEpoch float64) (uint64, err
 	return gp, nil
 }
 
+// clampCeiling caps the price to the ceiling, a ceiling of zero does not
+// cap it
+func clampCeiling(price, ceiling uint64) uint64 {
+	if ceiling == 0 || price <= ceiling {
+		return price
+	}
+	log.Warn("Clamping the gas price to the ceiling", "computed", price, "ceiling", ceiling)
+	gasPriceClampedCounter().Inc(1)
+	return ceiling
+}
+
 func max(a, b uint64) uint64 {
 	if a >= b {
 		return a
@@ -1,8 +1,11 @@
 package gasprices
 
 import (
+	"context"
 	"math"
 	"testing"
+
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
 )
 
 type CalcGasPriceTestCase struct {
@@ -170,3 +173,51 @@ func TestGasPricerDynamicTarget(t *testing.T) {
 		}
 	}
 }
+
+// parityBackend quotes the same price for every token, a ratio of 1
+type parityBackend struct{}
+
+func (parityBackend) GetPrice(ctx context.Context, pair string) (float64, error) {
+	return 1, nil
+}
+
+func TestCalcGasPriceCeiling(t *testing.T) {
+	gp, err := NewGasPricer(100, 80, 120, tokenprice.NewClientWithBackend(parityBackend{}, 0), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcs := []CalcGasPriceTestCase{
+		{
+			name:                     "The floor applies below the floor",
+			avgGasPerSecondLastEpoch: 5,
+			expectedNextGasPrice:     80,
+		},
+		{
+			name:                     "No clamp within the range",
+			avgGasPerSecondLastEpoch: 11.25,
+			expectedNextGasPrice:     113,
+		},
+		{
+			name:                     "The ceiling applies after the max % change",
+			avgGasPerSecondLastEpoch: 100,
+			expectedNextGasPrice:     120,
+		},
+	}
+	runCalcGasPriceTests(*gp, tcs, t)
+
+	// A price stuck above the ceiling is brought down to it
+	gp.curPrice = 1000
+	if price, _ := gp.CalcNextEpochGasPrice(10); price != 120 {
+		t.Fatalf("expected the ceiling price, got %d", price)
+	}
+
+	// Without a ceiling the price is not capped
+	gp.ceilingPrice = 0
+	if price, _ := gp.CalcNextEpochGasPrice(10); price != 1000 {
+		t.Fatalf("expected the uncapped price, got %d", price)
+	}
+
+	if _, err := NewGasPricer(100, 80, 79, nil, returnConstFn(10), 0.5); err == nil {
+		t.Fatal("expected an error on a ceiling below the floor")
+	}
+}
@@ -0,0 +1,89 @@
+// Package logging formats the logs of the oracle as human readable text or
+// as structured JSON records.
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// FormatText logs every event as a human readable line
+	FormatText = "text"
+	// FormatJSON logs every event as a JSON record on its own line
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat represents the error when the log format is neither
+// text nor json
+var ErrUnknownFormat = errors.New("unknown log format")
+
+// keys are the canonical names of the fields that the oracle logs under
+// other names, so that a log pipeline can index them the same whatever
+// the event
+var keys = map[string]string{
+	"hash":    "tx_hash",
+	"message": "error",
+	"baseFee": "l1_base_fee",
+	"daFee":   "da_fee",
+}
+
+// NewHandler creates the handler that writes the events of at most the
+// level to w in the format
+func NewHandler(format string, level log.Lvl, w io.Writer) (log.Handler, error) {
+	var handler log.Handler
+	switch format {
+	case "", FormatText:
+		handler = log.StreamHandler(w, log.TerminalFormat(true))
+	case FormatJSON:
+		next := log.StreamHandler(w, log.JSONFormat())
+		handler = log.FuncHandler(func(r *log.Record) error {
+			return next.Log(canonical(r))
+		})
+	default:
+		return nil, fmt.Errorf("%w: %q, expected %s or %s", ErrUnknownFormat, format, FormatText, FormatJSON)
+	}
+	return log.LvlFilterHandler(level, handler), nil
+}
+
+// canonical returns a copy of the record whose fields are named by their
+// canonical snake case names
+func canonical(r *log.Record) *log.Record {
+	record := *r
+	record.Ctx = make([]interface{}, len(r.Ctx))
+	copy(record.Ctx, r.Ctx)
+	for i := 0; i < len(record.Ctx); i += 2 {
+		if key, ok := record.Ctx[i].(string); ok {
+			record.Ctx[i] = canonicalKey(key)
+		}
+	}
+	return &record
+}
+
+// canonicalKey returns the canonical name of the key, or the key in snake
+// case, e.g. l2_block for l2-block and tx_gas_limit for tx.gasLimit
+func canonicalKey(key string) string {
+	if name, ok := keys[key]; ok {
+		return name
+	}
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '-' || r == '.':
+			b.WriteByte('_')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFormat(t *testing.T) {
+	var out bytes.Buffer
+	handler, err := NewHandler(FormatJSON, log.LvlInfo, &out)
+	require.NoError(t, err)
+	logger := log.New()
+	logger.SetHandler(handler)
+
+	logger.Info("L2 gas price transaction sent", "epoch", uint64(7), "hash", "0xabc", "l2_gas_price", uint64(1_000_000),
+		"l2-block", uint64(42))
+	logger.Info("L1 base fee transaction sent", "epoch", uint64(3), "hash", "0xdef", "baseFee", uint64(30_000_000_000))
+	logger.Error("cannot update", "channel", "l2_gas_price", "message", errors.New("connection refused"))
+	// The gas price of a transaction is not the L2 gas price of the oracle
+	logger.Info("Dry run would submit", "channel", "l2_gas_price", "gas_price", uint64(2_000_000),
+		"tx_gas_price", uint64(1_500_000_000), "tipCap", uint64(1_000_000_000))
+	logger.Warn("Update not mined, replacing it with a higher gas price", "channel", "l1_base_fee",
+		"hash", "0x123", "tx_gas_price", uint64(1_650_000_000), "bump", 1)
+	// Events below the level are filtered
+	logger.Debug("not written")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	records := make([]map[string]interface{}, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &records[i]), line)
+		require.Contains(t, records[i], "t")
+		require.Contains(t, records[i], "lvl")
+	}
+
+	require.Equal(t, "L2 gas price transaction sent", records[0]["msg"])
+	require.Equal(t, 7.0, records[0]["epoch"])
+	require.Equal(t, "0xabc", records[0]["tx_hash"])
+	require.Equal(t, 1_000_000.0, records[0]["l2_gas_price"])
+	require.Equal(t, 42.0, records[0]["l2_block"])
+
+	require.Equal(t, "0xdef", records[1]["tx_hash"])
+	require.Equal(t, 30_000_000_000.0, records[1]["l1_base_fee"])
+
+	require.Equal(t, "eror", records[2]["lvl"])
+	require.Equal(t, "connection refused", records[2]["error"])
+	require.Equal(t, "l2_gas_price", records[2]["channel"])
+
+	require.Equal(t, 1_500_000_000.0, records[3]["tx_gas_price"])
+	require.Equal(t, 2_000_000.0, records[3]["gas_price"])
+	require.Equal(t, 1_000_000_000.0, records[3]["tip_cap"])
+	require.NotContains(t, records[3], "l2_gas_price")
+
+	require.Equal(t, 1_650_000_000.0, records[4]["tx_gas_price"])
+	require.Equal(t, "0x123", records[4]["tx_hash"])
+	require.NotContains(t, records[4], "l2_gas_price")
+}
+
+func TestTextFormat(t *testing.T) {
+	var out bytes.Buffer
+	handler, err := NewHandler(FormatText, log.LvlInfo, &out)
+	require.NoError(t, err)
+	logger := log.New()
+	logger.SetHandler(handler)
+
+	// The text format keeps the names of the fields
+	logger.Info("L2 gas price transaction sent", "hash", "0xabc")
+	require.Contains(t, out.String(), "hash")
+	require.NotContains(t, out.String(), "tx_hash")
+	require.Error(t, json.Unmarshal(out.Bytes(), &map[string]interface{}{}))
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewHandler("xml", log.LvlInfo, &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestCanonicalKey(t *testing.T) {
+	for key, expected := range map[string]string{
+		"hash":          "tx_hash",
+		"message":       "error",
+		"daFee":         "da_fee",
+		"gas-price":     "gas_price",
+		"tx_gas_price":  "tx_gas_price",
+		"l1-block":      "l1_block",
+		"tx.gasLimit":   "tx_gas_limit",
+		"current-price": "current_price",
+		"epoch":         "epoch",
+	} {
+		require.Equal(t, expected, canonicalKey(key), key)
+	}
+}
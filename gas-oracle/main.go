@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/metrics/influxdb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/mantlenetworkio/mantle/gas-oracle/flags"
+	"github.com/mantlenetworkio/mantle/gas-oracle/logging"
 	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
 	"github.com/mantlenetworkio/mantle/gas-oracle/oracle"
 	"github.com/urfave/cli"
@@ -30,42 +33,160 @@ func main() {
 	app.Description = "Configure with a private key and an Mantle HTTP endpoint " +
 		"to send transactions that update the L2 gas price."
 
-	// Configure the logging
+	// Load the config file and configure the logging
 	app.Before = func(ctx *cli.Context) error {
+		// Explicit flags take precedence over the config file
+		if err := flags.ApplyConfig(ctx); err != nil {
+			return err
+		}
 		loglevel := ctx.GlobalUint64(flags.LogLevelFlag.Name)
-		log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(loglevel), log.StreamHandler(os.Stdout, log.TerminalFormat(true))))
+		// Keep stdout to the audit log when it is written there
+		output := os.Stdout
+		if ctx.GlobalBool(flags.AuditStdoutFlag.Name) {
+			output = os.Stderr
+		}
+		// and to the replay when it is written there
+		if ctx.GlobalString(flags.ReplayFileFlag.Name) != "" && ctx.GlobalString(flags.ReplayOutFlag.Name) == "" {
+			output = os.Stderr
+		}
+		handler, err := logging.NewHandler(ctx.GlobalString(flags.LogFormatFlag.Name), log.Lvl(loglevel), output)
+		if err != nil {
+			return err
+		}
+		log.Root().SetHandler(handler)
 		return nil
 	}
 
+	app.Commands = []cli.Command{
+		{
+			Name:  "gen-config",
+			Usage: "Print an example config file with every option set to its default",
+			Action: func(ctx *cli.Context) error {
+				return flags.WriteExampleConfig(os.Stdout)
+			},
+		},
+		{
+			Name:  "watch",
+			Usage: "Show a refreshing summary of the channels of a running instance",
+			Flags: flags.WatchFlags,
+			Action: func(ctx *cli.Context) error {
+				watchCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer cancel()
+				interval := time.Duration(ctx.Uint64(flags.WatchIntervalSecondsFlag.Name)) * time.Second
+				if interval <= 0 {
+					interval = time.Second
+				}
+				password, err := flags.Secret(ctx, flags.MetricsAuthPasswordFlag, flags.MetricsAuthPasswordFileFlag)
+				if err != nil {
+					return err
+				}
+				token, err := flags.Secret(ctx, flags.MetricsAuthTokenFlag, flags.MetricsAuthTokenFileFlag)
+				if err != nil {
+					return err
+				}
+				return oracle.Watch(watchCtx, oracle.WatchOptions{
+					URL:      ctx.String(flags.WatchURLFlag.Name),
+					Interval: interval,
+					Username: ctx.GlobalString(flags.MetricsAuthUsernameFlag.Name),
+					Password: password,
+					Token:    token,
+				}, os.Stdout)
+			},
+		},
+	}
+
 	// Define the functionality of the application
 	app.Action = func(ctx *cli.Context) error {
 		if args := ctx.Args(); len(args) > 0 {
 			return fmt.Errorf("invalid command: %q", args[0])
 		}
 
+		// Explicit flags and the config file take precedence over the
+		// defaults of the profile
+		if err := flags.ApplyProfile(ctx); err != nil {
+			return err
+		}
+		// The metrics are enabled before any of them is created
+		if flags.MetricsEnabled(ctx) {
+			ometrics.Enable()
+		}
 		config := oracle.NewConfig(ctx)
+
+		// A replay reads its samples from a file and sends nothing
+		if config.ReplayFile != "" {
+			if err := oracle.ValidateConfig(config); err != nil {
+				return err
+			}
+			return oracle.Replay(config, os.Stdout)
+		}
+		// The oracle validates its config once it is created
 		gpo, err := oracle.NewGasPriceOracle(config)
 		if err != nil {
 			return err
 		}
 
+		// A single cycle exits with its outcome, the failure exiting with
+		// a non-zero status
+		if config.Once {
+			return gpo.RunOnce()
+		}
+
 		if err := gpo.Start(); err != nil {
 			return err
 		}
 
-		if config.MetricsEnabled {
+		if config.MetricsEnabled || config.MetricsPrometheus {
 			address := fmt.Sprintf("%s:%d", config.MetricsHTTP, config.MetricsPort)
 			log.Info("Enabling stand-alone metrics HTTP endpoint", "address", address)
-			ometrics.Setup(address)
+			mux := ometrics.NewServeMux()
+			if config.MetricsPrometheus {
+				ometrics.Prometheus(mux)
+			}
+			gpo.RegisterHandlers(mux)
+			// Health probes are served without credentials
+			handler := ometrics.Authenticate(mux, config.MetricsAuthUsername, config.MetricsAuthPassword,
+				config.MetricsAuthToken, "/healthz", "/readyz")
+			if config.MetricsGzip {
+				handler = ometrics.Gzip(handler)
+			}
+			ometrics.Serve(address, handler)
 		}
 
+		// SIGUSR1 drains the oracle, the same as POST /drain
+		drainCh := make(chan os.Signal, 1)
+		signal.Notify(drainCh, syscall.SIGUSR1)
+		go func() {
+			for range drainCh {
+				gpo.Drain()
+			}
+		}()
+
+		// SIGINT and SIGTERM shut the oracle down gracefully
+		shutdownCh := make(chan os.Signal, 1)
+		signal.Notify(shutdownCh, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-shutdownCh
+			gpo.Shutdown()
+		}()
+
 		if config.MetricsEnableInfluxDB {
 			endpoint := config.MetricsInfluxDBEndpoint
 			database := config.MetricsInfluxDBDatabase
 			username := config.MetricsInfluxDBUsername
 			password := config.MetricsInfluxDBPassword
 			log.Info("Enabling metrics export to InfluxDB", "endpoint", endpoint, "username", username, "database", database)
-			go influxdb.InfluxDBWithTags(ometrics.DefaultRegistry, 10*time.Second, endpoint, database, username, password, "geth.", make(map[string]string))
+			err := ometrics.DefaultRegistry.AddExporter(context.Background(), "influxdb", ometrics.InfluxDB{
+				Endpoint:  endpoint,
+				Database:  database,
+				Username:  username,
+				Password:  password,
+				Namespace: "geth.",
+				Tags:      make(map[string]string),
+				Interval:  10 * time.Second,
+			})
+			if err != nil {
+				return err
+			}
 		}
 
 		gpo.Wait()
@@ -13,8 +13,6 @@ import (
 	"github.com/ethereum/go-ethereum/metrics/prometheus"
 )
 
-var DefaultRegistry = metrics.NewRegistry()
-
 type exp struct {
 	expvarLock sync.Mutex // expvar panics if you try to register the same var twice, so we must probe it safely
 	registry   metrics.Registry
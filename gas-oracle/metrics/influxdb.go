@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/influxdb"
+)
+
+// InfluxDB is the exporter that pushes the metrics to an InfluxDB database
+// every interval
+type InfluxDB struct {
+	Endpoint  string
+	Database  string
+	Username  string
+	Password  string
+	Namespace string
+	Tags      map[string]string
+	Interval  time.Duration
+}
+
+func (i InfluxDB) Export(ctx context.Context, registry metrics.Registry) {
+	ticker := time.NewTicker(i.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := influxdb.InfluxDBWithTagsOnce(registry, i.Endpoint, i.Database, i.Username, i.Password, i.Namespace, i.Tags)
+			if err != nil {
+				log.Warn("Unable to send to InfluxDB", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
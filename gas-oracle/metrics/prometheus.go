@@ -0,0 +1,14 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+// Prometheus registers the handler that Prometheus scrapes the metrics of
+// the DefaultRegistry from on mux under /metrics, in the text exposition
+// format
+func Prometheus(mux *http.ServeMux) {
+	mux.Handle("/metrics", prometheus.Handler(DefaultRegistry))
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// DefaultRegistry is the registry that every metric of the service is
+// defined on
+var DefaultRegistry = NewRegistry()
+
+// Enable turns the collection of the metrics on. The metrics that are
+// created before it are no-ops for good, so it is called as soon as the
+// command line, the config file and the profile are applied, and the
+// metrics are created when they are first updated.
+func Enable() {
+	if metrics.Enabled {
+		return
+	}
+	log.Info("Enabling metrics collection")
+	metrics.Enabled = true
+}
+
+// ErrDuplicateExporter is returned when an exporter is added under a name
+// that is already taken
+var ErrDuplicateExporter = errors.New("duplicate metrics exporter")
+
+// Exporter publishes the metrics of a registry to a monitoring system until
+// the context is done, e.g. by pushing them every interval
+type Exporter interface {
+	Export(ctx context.Context, registry metrics.Registry)
+}
+
+// Registry defines every metric once and fans it out to all of the
+// exporters. The metrics are registered on the wrapped registry, which is
+// safe for concurrent use, and every exporter reads the same metrics from
+// it, so that no exporter defines metrics of its own. The HTTP handlers of
+// NewServeMux serve the same metrics.
+type Registry struct {
+	metrics.Registry
+
+	mu        sync.Mutex
+	exporters map[string]Exporter
+}
+
+// NewRegistry creates a registry without exporters
+func NewRegistry() *Registry {
+	return &Registry{
+		Registry:  metrics.NewRegistry(),
+		exporters: make(map[string]Exporter),
+	}
+}
+
+// AddExporter starts the exporter under the name in the background, where
+// it runs until the context is done
+func (r *Registry) AddExporter(ctx context.Context, name string, exporter Exporter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.exporters[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateExporter, name)
+	}
+	r.exporters[name] = exporter
+	go exporter.Export(ctx, r.Registry)
+	return nil
+}
+
+// Exporters returns the names of the exporters in order
+func (r *Registry) Exporters() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.exporters))
+	for name := range r.exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
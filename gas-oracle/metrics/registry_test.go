@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotExporter reads the counter from the registry on every signal, as
+// an exporter would every interval
+type snapshotExporter struct {
+	name  string
+	ticks chan struct{}
+	seen  chan int64
+}
+
+func (s *snapshotExporter) Export(ctx context.Context, registry metrics.Registry) {
+	for {
+		select {
+		case <-s.ticks:
+			counter, ok := registry.Get(s.name).(metrics.Counter)
+			if !ok {
+				s.seen <- -1
+				continue
+			}
+			s.seen <- counter.Count()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func newSnapshotExporter(name string) *snapshotExporter {
+	return &snapshotExporter{
+		name:  name,
+		ticks: make(chan struct{}),
+		seen:  make(chan int64),
+	}
+}
+
+func TestRegistryFansOut(t *testing.T) {
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registry := NewRegistry()
+	first := newSnapshotExporter("test/updates")
+	second := newSnapshotExporter("test/updates")
+	require.NoError(t, registry.AddExporter(ctx, "first", first))
+	require.NoError(t, registry.AddExporter(ctx, "second", second))
+	require.Equal(t, []string{"first", "second"}, registry.Exporters())
+
+	// The metric is defined once, concurrently with the exporters reading it
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				metrics.GetOrRegisterCounter("test/updates", registry).Inc(1)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		first.ticks <- struct{}{}
+		<-first.seen
+		second.ticks <- struct{}{}
+		<-second.seen
+	}
+	wg.Wait()
+
+	// and both exporters see all of its updates
+	first.ticks <- struct{}{}
+	require.Equal(t, int64(800), <-first.seen)
+	second.ticks <- struct{}{}
+	require.Equal(t, int64(800), <-second.seen)
+}
+
+func TestRegistryDuplicateExporter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registry := NewRegistry()
+	require.NoError(t, registry.AddExporter(ctx, "influxdb", newSnapshotExporter("test")))
+	err := registry.AddExporter(ctx, "influxdb", newSnapshotExporter("test"))
+	require.ErrorIs(t, err, ErrDuplicateExporter)
+	require.Equal(t, []string{"influxdb"}, registry.Exporters())
+}
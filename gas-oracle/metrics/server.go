@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"compress/gzip"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+// NewServeMux returns a mux serving the metrics of the DefaultRegistry.
+// Additional handlers can be registered on the returned mux before it is
+// passed to Serve.
+func NewServeMux() *http.ServeMux {
+	m := http.NewServeMux()
+	m.Handle("/debug/metrics", ExpHandler(DefaultRegistry))
+	m.Handle("/debug/metrics/prometheus", prometheus.Handler(DefaultRegistry))
+	return m
+}
+
+// Pprof registers the profiling handlers of net/http/pprof on mux under
+// /debug/pprof/. They expose the internals of the process, so they are only
+// registered on request.
+func Pprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// Serve starts a dedicated HTTP server at the given address in the
+// background.
+func Serve(address string, handler http.Handler) {
+	log.Info("Starting metrics server", "addr", fmt.Sprintf("http://%s/debug/metrics", address))
+	go func() {
+		if err := http.ListenAndServe(address, handler); err != nil {
+			log.Error("Failure in running metrics server", "err", err)
+		}
+	}()
+}
+
+// Authenticate wraps handler so that every request must either carry the
+// basic auth credentials or the bearer token. Empty credentials are not
+// checked, so with all of them empty handler is returned as is. Requests
+// to the exempt paths are always served, which allows unauthenticated
+// health probes.
+func Authenticate(handler http.Handler, username, password, token string, exempt ...string) http.Handler {
+	if username == "" && password == "" && token == "" {
+		return handler
+	}
+	exempted := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exempted[path] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exempted[r.URL.Path] {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if token != "" {
+			auth := r.Header.Get("Authorization")
+			if strings.HasPrefix(auth, "Bearer ") && secureCompare(strings.TrimPrefix(auth, "Bearer "), token) {
+				handler.ServeHTTP(w, r)
+				return
+			}
+		}
+		if username != "" || password != "" {
+			user, pass, ok := r.BasicAuth()
+			if ok && secureCompare(user, username) && secureCompare(pass, password) {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="gas-oracle"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func secureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// Gzip wraps handler so that responses are gzip compressed when the
+// client accepts it
+func Gzip(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		handler.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, writer: gz}, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		encoding = strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0])
+		if encoding == "gzip" || encoding == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer io.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	// The length of the compressed body is not known up front
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", http.DetectContentType(b))
+	}
+	w.Header().Del("Content-Length")
+	return w.writer.Write(b)
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"gas_price":"1"}`))
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+func TestAuthenticateDisabled(t *testing.T) {
+	handler := Authenticate(newTestHandler(), "", "", "")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected no auth by default, got %d", rec.Code)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	handler := Authenticate(newTestHandler(), "user", "pass", "secret", "/healthz")
+
+	tests := []struct {
+		name   string
+		path   string
+		header func(r *http.Request)
+		expect int
+	}{
+		{name: "no credentials", path: "/state", expect: http.StatusUnauthorized},
+		{name: "wrong password", path: "/state", header: func(r *http.Request) {
+			r.SetBasicAuth("user", "wrong")
+		}, expect: http.StatusUnauthorized},
+		{name: "basic auth", path: "/state", header: func(r *http.Request) {
+			r.SetBasicAuth("user", "pass")
+		}, expect: http.StatusOK},
+		{name: "wrong token", path: "/state", header: func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer wrong")
+		}, expect: http.StatusUnauthorized},
+		{name: "token", path: "/state", header: func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer secret")
+		}, expect: http.StatusOK},
+		{name: "exempt path", path: "/healthz", expect: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.header != nil {
+				tc.header(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.expect {
+				t.Fatalf("expected %d, got %d", tc.expect, rec.Code)
+			}
+		})
+	}
+}
+
+func TestGzip(t *testing.T) {
+	handler := Gzip(newTestHandler())
+
+	// Clients that do not accept gzip get the plain body
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatal("compressed without Accept-Encoding")
+	}
+	if rec.Body.String() != `{"gas_price":"1"}` {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/state", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("not compressed")
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"gas_price":"1"`) {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
@@ -0,0 +1,149 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+const (
+	// alertFailing is the status of a channel whose epochs keep failing
+	alertFailing = "failing"
+	// alertRecovered is the status of a failing channel that updated again
+	alertRecovered = "recovered"
+)
+
+// Alert is the JSON body posted to the alert webhook
+type Alert struct {
+	Status  string    `json:"status"`
+	Channel string    `json:"channel"`
+	Time    time.Time `json:"time"`
+	// Failures is the number of consecutive failed epochs, which is the
+	// threshold for a failing alert and all of them for a recovered one
+	Failures uint64 `json:"failures"`
+	// Error is the last error of the channel
+	Error string `json:"error,omitempty"`
+}
+
+// alerter posts an alert to a webhook once a channel failed the threshold
+// of consecutive epochs, and a single recovered alert once it succeeds
+// again. Nothing more is posted while the channel keeps failing. A nil
+// alerter posts nothing.
+type alerter struct {
+	url       string
+	threshold uint64
+	client    *http.Client
+	now       func() time.Time
+
+	mu       sync.Mutex
+	channels map[string]*channelFailures
+}
+
+type channelFailures struct {
+	count   uint64
+	alerted bool
+}
+
+// newAlerter creates the alerter of the webhook url, or returns nil when
+// the url is empty
+func newAlerter(url string, threshold uint64) *alerter {
+	if url == "" {
+		return nil
+	}
+	return &alerter{
+		url:       url,
+		threshold: threshold,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		now:       time.Now,
+		channels:  make(map[string]*channelFailures),
+	}
+}
+
+// wrap runs update as an epoch of the channel and records its outcome
+func (a *alerter) wrap(channel string, update func() error) func() error {
+	if a == nil {
+		return update
+	}
+	return func() error {
+		err := update()
+		a.observe(channel, err)
+		return err
+	}
+}
+
+// observe records the outcome of an epoch of the channel. The updates that
+// were held back on purpose are neither failures nor successes.
+func (a *alerter) observe(channel string, err error) {
+	if errors.Is(err, errThrottled) || errors.Is(err, errBudgetExceeded) || errors.Is(err, errDropLimited) {
+		return
+	}
+	a.mu.Lock()
+	failures, ok := a.channels[channel]
+	if !ok {
+		failures = new(channelFailures)
+		a.channels[channel] = failures
+	}
+	var alert *Alert
+	if err != nil {
+		failures.count++
+		if !failures.alerted && failures.count >= a.threshold {
+			failures.alerted = true
+			alert = &Alert{Status: alertFailing, Failures: failures.count, Error: err.Error()}
+		}
+	} else {
+		if failures.alerted {
+			alert = &Alert{Status: alertRecovered, Failures: failures.count}
+		}
+		failures.count = 0
+		failures.alerted = false
+	}
+	a.mu.Unlock()
+
+	if alert == nil {
+		return
+	}
+	alert.Channel = channel
+	alert.Time = a.now()
+	if err := a.post(alert); err != nil {
+		log.Error("cannot post alert", "channel", channel, "status", alert.Status, "message", err)
+		alertsCounter(channel, "undelivered").Inc(1)
+		return
+	}
+	log.Info("alert posted", "channel", channel, "status", alert.Status, "failures", alert.Failures)
+	alertsCounter(channel, alert.Status).Inc(1)
+}
+
+func (a *alerter) post(alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned %s", res.Status)
+	}
+	return nil
+}
+
+// alertsCounter counts the alerts of the channel by status
+func alertsCounter(channel, status string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("alerts/"+metricName(channel)+"/"+status, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// alertRecorder is a webhook that records the alerts posted to it
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *alertRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var alert Alert
+	if err := json.NewDecoder(req.Body).Decode(&alert); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+}
+
+func (r *alertRecorder) posted() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
+
+func TestAlerter(t *testing.T) {
+	webhook := new(alertRecorder)
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	alerts := newAlerter(server.URL, 3)
+	var err error
+	update := alerts.wrap(daFeeChannel, func() error { return err })
+
+	// Nothing is posted below the threshold
+	err = errors.New("connection refused")
+	require.Error(t, update())
+	require.Error(t, update())
+	require.Empty(t, webhook.posted())
+
+	// The alert is posted once at the threshold, and not again while the
+	// channel keeps failing
+	for i := 0; i < 5; i++ {
+		err = fmt.Errorf("epoch %d: insufficient funds", i)
+		require.Error(t, update())
+	}
+	posted := webhook.posted()
+	require.Len(t, posted, 1)
+	require.Equal(t, alertFailing, posted[0].Status)
+	require.Equal(t, daFeeChannel, posted[0].Channel)
+	require.Equal(t, uint64(3), posted[0].Failures)
+	require.Equal(t, "epoch 0: insufficient funds", posted[0].Error)
+
+	// Held back updates do not recover the channel
+	err = errThrottled
+	require.Error(t, update())
+	require.Len(t, webhook.posted(), 1)
+
+	// A single recovered alert is posted once the channel updates again
+	err = nil
+	require.NoError(t, update())
+	require.NoError(t, update())
+	posted = webhook.posted()
+	require.Len(t, posted, 2)
+	require.Equal(t, alertRecovered, posted[1].Status)
+	require.Equal(t, uint64(7), posted[1].Failures)
+	require.Empty(t, posted[1].Error)
+}
+
+func TestAlerterChannels(t *testing.T) {
+	webhook := new(alertRecorder)
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	// The failures are counted per channel
+	alerts := newAlerter(server.URL, 2)
+	failed := errors.New("connection refused")
+	alerts.observe(l1BaseFeeChannel, failed)
+	alerts.observe(daFeeChannel, failed)
+	require.Empty(t, webhook.posted())
+	alerts.observe(daFeeChannel, failed)
+	posted := webhook.posted()
+	require.Len(t, posted, 1)
+	require.Equal(t, daFeeChannel, posted[0].Channel)
+
+	// A success resets the count
+	alerts.observe(l1BaseFeeChannel, nil)
+	alerts.observe(l1BaseFeeChannel, failed)
+	require.Len(t, webhook.posted(), 1)
+
+	// Without a webhook nothing is posted
+	require.Nil(t, newAlerter("", 2))
+	require.NoError(t, (*alerter)(nil).wrap(daFeeChannel, func() error { return nil })())
+}
@@ -6,19 +6,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
 )
 
-func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContractBackend, cfg *Config) (func() error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
-
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
+func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContractBackend, cfg *Config, guards channelGuards) (func() error, error) {
+	opts, err := cfg.transactor()
 	if err != nil {
 		return nil, err
 	}
@@ -37,51 +31,144 @@ func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContra
 	if err != nil {
 		return nil, err
 	}
+	shadows, err := newShadowOracles(l1BaseFeeChannel, cfg, l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	// In the epoch mode the base fee is sampled from the fee history of
+	// complete L1 epochs
+	var sampler *l1EpochSampler
+	var feeHistory FeeHistoryReader
+	if cfg.l1BaseFeeMode == l1BaseFeeModeEpoch {
+		reader, ok := l1Backend.(FeeHistoryReader)
+		if !ok {
+			return nil, errNoFeeHistory
+		}
+		feeHistory = reader
+		sampler = &l1EpochSampler{epochBlocks: cfg.l1EpochBlocks}
+	}
+	// The samples are filtered by the window across the epochs of the run
+	window := newBaseFeeWindow(cfg.l1BaseFeeWindow)
+
 	return func() error {
-		baseFee, err := contract.L1BaseFee(&bind.CallOpts{
-			Context: context.Background(),
-		})
+		l2Block, err := headNumber(guards.deadline.context(), l2Backend)
 		if err != nil {
 			return err
 		}
-		tip, err := l1Backend.HeaderByNumber(context.Background(), nil)
+		guards.trace.l2Block(l2Block)
+		callOpts := &bind.CallOpts{
+			Context:     guards.deadline.context(),
+			BlockNumber: l2Block,
+		}
+		// The decimals may have changed since startup, never write a base
+		// fee that the contract scales differently
+		if err := checkDecimals(&contract.BVMGasPriceOracleCaller, callOpts, cfg.tokenPriceDecimals); err != nil {
+			return err
+		}
+		baseFee, err := contract.L1BaseFee(callOpts)
+		if err != nil {
+			return err
+		}
+		tip, err := l1Backend.HeaderByNumber(guards.deadline.context(), nil)
 		if err != nil {
 			return err
 		}
+		guards.trace.l1Block(tip.Number)
 		if tip.BaseFee == nil {
 			return errNoBaseFee
 		}
-		if !isDifferenceSignificant(baseFee.Uint64(), tip.BaseFee.Uint64(), cfg.l1BaseFeeSignificanceFactor) {
-			log.Debug("non significant base fee update", "tip", tip.BaseFee, "current", baseFee)
-			return nil
-		}
-
-		// Use the configured gas price if it is set,
-		// otherwise use gas estimation
-		if cfg.gasPrice != nil {
-			opts.GasPrice = cfg.gasPrice
-		} else {
-			gasPrice, err := l2Backend.SuggestGasPrice(opts.Context)
+		if sampler != nil {
+			epochFee, err := sampler.sample(guards.deadline.context(), feeHistory, tip.Number.Uint64())
 			if err != nil {
 				return err
 			}
-			opts.GasPrice = gasPrice
+			guards.trace.input("l1_epoch_boundary", sampler.boundary)
+			tip.BaseFee = epochFee
+		}
+		guards.stuck.observed(baseFee)
+		reportCurrent(l1BaseFeeChannel, baseFee)
+		guards.drops.observed(baseFee)
+		guards.trace.input("current_l1_base_fee", baseFee)
+		guards.trace.input("l1_base_fee", tip.BaseFee)
+		if window != nil {
+			tip.BaseFee = window.add(tip.BaseFee)
+			guards.trace.input("l1_base_fee_median", tip.BaseFee)
+		}
+		tip.BaseFee = roundTo(tip.BaseFee, cfg.l1BaseFeeRoundTo)
+		reference := guards.sent.reference(l1BaseFeeChannel, baseFee)
+		if reference != baseFee {
+			guards.trace.input("last_sent_l1_base_fee", reference)
+		}
+		factor := guards.emergency.significanceFactor(cfg.l1BaseFeeSignificanceFactor)
+		factor = guards.reversals.significanceFactor(factor, baseFee, tip.BaseFee)
+		if !isDifferenceSignificant(reference.Uint64(), tip.BaseFee.Uint64(), factor) {
+			log.Debug("non significant base fee update", "tip", tip.BaseFee, "current", reference)
+			guards.trace.act(actionSkip, reasonBelowSignificance, "not significant")
+			return nil
+		}
+		// Without a significance factor the values that rounded to the
+		// same one are not sent either
+		if reference.Cmp(tip.BaseFee) == 0 {
+			log.Debug("base fee did not change", "tip", tip.BaseFee)
+			guards.trace.act(actionSkip, reasonUnchanged, "not changed")
+			return nil
+		}
+
+		// A channel that was just enabled may ease from a stale value
+		target := tip.BaseFee
+		tip.BaseFee = guards.grace.next(baseFee, tip.BaseFee)
+		if cfg.observes(l1BaseFeeChannel) {
+			observe(l1BaseFeeChannel, guards.trace, "l1_base_fee", tip.BaseFee)
+			return nil
+		}
+		if err := guards.drops.check(baseFee, tip.BaseFee); err != nil {
+			return err
+		}
+		if guards.deadline.exceeded() {
+			return errEpochAborted
+		}
+
+		// The update is built and sent under the context of the loop
+		opts.Context = guards.deadline.epoch()
+		if err := txFees(opts.Context, l2Backend, cfg, opts); err != nil {
+			return err
+		}
+		if cfg.dryRun {
+			return dryRun(l1BaseFeeChannel, guards.trace, cfg.gasPriceOracleAddress, opts, "setL1BaseFee", "l1_base_fee", tip.BaseFee)
 		}
 
 		tx, err := contract.SetL1BaseFee(opts, tip.BaseFee)
+		if err == nil {
+			log.Debug("updating L1 base fee", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
+				"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+			if err = l2Backend.SendTransaction(opts.Context, tx); err != nil {
+				reportSendFailure(l1BaseFeeChannel, err)
+				err = fmt.Errorf("cannot update base fee: %w", err)
+			}
+		}
+		reportOracleSend(l1BaseFeeChannel, cfg.gasPriceOracleAddress, err)
+		// The shadow contracts are updated whether or not the primary was
+		shadows.send(opts.Context, func(shadow *bindings.BVMGasPriceOracle) (*types.Transaction, error) {
+			return shadow.SetL1BaseFee(opts, tip.BaseFee)
+		})
 		if err != nil {
 			return err
 		}
-		log.Debug("updating L1 base fee", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
-			"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
-		if err := l2Backend.SendTransaction(context.Background(), tx); err != nil {
-			return fmt.Errorf("cannot update base fee: %w", err)
-		}
-		log.Info("L1 base fee transaction sent", "hash", tx.Hash().Hex(), "baseFee", tip.BaseFee)
+		log.Info("L1 base fee transaction sent", "epoch", guards.trace.epoch(), "hash", tx.Hash().Hex(), "baseFee", tip.BaseFee,
+			"l1-block", tip.Number, "l2-block", l2Block)
+		reportBlocks(l1BaseFeeChannel, tip.Number, l2Block)
+		reportWritten(l1BaseFeeChannel, tip.BaseFee)
+		guards.sent.sent(l1BaseFeeChannel, tip.BaseFee)
+		guards.trace.output("l1_base_fee", tip.BaseFee)
+		guards.trace.output("tx_hash", tx.Hash().Hex())
+		guards.trace.act(actionUpdate, updateReason(target, tip.BaseFee), "")
+		guards.emergency.updated()
+		guards.stuck.wrote(baseFee, tip.BaseFee)
+		guards.reversals.wrote(baseFee, tip.BaseFee)
 
 		if cfg.waitForReceipt {
 			// Wait for the receipt
-			receipt, err := waitForReceipt(l2Backend, tx)
+			receipt, err := waitForReceipt(opts.Context, l2Backend, tx, cfg.receiptBackoff)
 			if err != nil {
 				return err
 			}
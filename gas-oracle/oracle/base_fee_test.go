@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
 	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/mantlenetworkio/mantle/gas-oracle/units"
 )
 
 func TestBaseFeeUpdate(t *testing.T) {
@@ -30,7 +31,7 @@ func TestBaseFeeUpdate(t *testing.T) {
 		gasPrice:              big.NewInt(784637584),
 	}
 
-	update, err := wrapUpdateBaseFee(sim, sim, cfg)
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -53,7 +54,11 @@ func TestBaseFeeUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	tip.BaseFee = new(big.Int).Mul(tip.BaseFee, big.NewInt(int64(ratio)))
+	scaled, err := units.FromWei(tip.BaseFee).Scale(ratio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tip.BaseFee = scaled.Wei()
 	// Ensure that there is no false negative by
 	// checking that the values don't start out the same
 	if l1BaseFee.Cmp(tip.BaseFee) == 0 {
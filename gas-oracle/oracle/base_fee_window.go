@@ -0,0 +1,52 @@
+package oracle
+
+import (
+	"math/big"
+	"sort"
+)
+
+// baseFeeWindow holds the last base fees that the L1 base fee channel
+// sampled, so that the median of the window is written in place of the
+// latest sample and a single anomalous block cannot drag the value. A nil
+// baseFeeWindow passes the samples through.
+type baseFeeWindow struct {
+	samples []*big.Int
+	next    int
+	full    bool
+}
+
+// newBaseFeeWindow creates the window of the last size samples, or returns
+// nil when size is below 2 and there is nothing to filter
+func newBaseFeeWindow(size uint64) *baseFeeWindow {
+	if size < 2 {
+		return nil
+	}
+	return &baseFeeWindow{samples: make([]*big.Int, size)}
+}
+
+// add adds the sample to the window, evicting the oldest one once the window
+// is full, and returns the median of the window. The median of an even
+// number of samples is the average of the two in the middle, rounded down.
+func (w *baseFeeWindow) add(sample *big.Int) *big.Int {
+	if w == nil {
+		return sample
+	}
+	w.samples[w.next] = new(big.Int).Set(sample)
+	w.next = (w.next + 1) % len(w.samples)
+	if w.next == 0 {
+		w.full = true
+	}
+
+	count := w.next
+	if w.full {
+		count = len(w.samples)
+	}
+	sorted := make([]*big.Int, count)
+	copy(sorted, w.samples[:count])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	if count%2 == 1 {
+		return new(big.Int).Set(sorted[count/2])
+	}
+	median := new(big.Int).Add(sorted[count/2-1], sorted[count/2])
+	return median.Rsh(median, 1)
+}
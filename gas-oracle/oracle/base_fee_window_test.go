@@ -0,0 +1,85 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// spikyBaseFees is a steady L1 base fee with single block spikes
+var spikyBaseFees = []int64{100, 102, 900, 101, 99, 5, 103, 100, 104, 1_000}
+
+func TestBaseFeeWindow(t *testing.T) {
+	window := newBaseFeeWindow(3)
+	var windowed []int64
+	for _, baseFee := range spikyBaseFees {
+		windowed = append(windowed, window.add(big.NewInt(baseFee)).Int64())
+	}
+	// The spikes that the instantaneous values follow are filtered out once
+	// the window holds three samples. The window starts with the average of
+	// the first two.
+	require.Equal(t, []int64{100, 101, 102, 102, 101, 99, 99, 100, 103, 104}, windowed)
+
+	// An even window averages the two samples in the middle
+	window = newBaseFeeWindow(4)
+	for _, baseFee := range []int64{10, 40, 20} {
+		window.add(big.NewInt(baseFee))
+	}
+	require.Equal(t, big.NewInt(25), window.add(big.NewInt(31)))
+	// and evicts the oldest sample once it is full
+	require.Equal(t, big.NewInt(30), window.add(big.NewInt(30)))
+
+	// The samples are copied into the window
+	window = newBaseFeeWindow(2)
+	sample := big.NewInt(10)
+	window.add(sample)
+	sample.SetInt64(1_000)
+	require.Equal(t, big.NewInt(15), window.add(big.NewInt(20)))
+
+	// Windows below two samples do not filter
+	require.Nil(t, newBaseFeeWindow(0))
+	require.Nil(t, newBaseFeeWindow(1))
+	require.Equal(t, big.NewInt(900), (*baseFeeWindow)(nil).add(big.NewInt(900)))
+}
+
+func TestBaseFeeWindowUpdate(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// Without a significance factor every change would be sent
+	written := func(window uint64) []int64 {
+		cfg := &Config{
+			privateKey:            key,
+			l2ChainID:             big.NewInt(1337),
+			gasPriceOracleAddress: addr,
+			gasPrice:              big.NewInt(1_000_000_000),
+			l1BaseFeeWindow:       window,
+		}
+		l1 := &syntheticL1{baseFees: spikyBaseFees}
+		trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+		update, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{trace: trace})
+		require.NoError(t, err)
+		var values []int64
+		for l1.tip = 0; l1.tip < uint64(len(spikyBaseFees)); l1.tip++ {
+			require.NoError(t, trace.wrap(update)())
+			sim.Commit()
+			baseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+			require.NoError(t, err)
+			values = append(values, baseFee.Int64())
+		}
+		return values
+	}
+
+	// The instantaneous base fee is dragged by every spike
+	require.Equal(t, spikyBaseFees, written(0))
+	// while the median of the window is not
+	require.Equal(t, []int64{100, 101, 102, 102, 101, 99, 99, 100, 103, 104}, written(3))
+}
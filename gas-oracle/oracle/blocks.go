@@ -0,0 +1,36 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// headNumber returns the number of the latest block of the backend. The
+// inputs of an epoch are read at that block, so that the decision can be
+// traced back to the exact state of the chain.
+func headNumber(ctx context.Context, backend bind.ContractTransactor) (*big.Int, error) {
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return head.Number, nil
+}
+
+// reportBlocks exports the numbers of the blocks that the last update of
+// the channel was computed at. A nil number is not reported.
+func reportBlocks(channel string, l1Block, l2Block *big.Int) {
+	if l1Block != nil {
+		blocksGauge(channel, "l1").Update(l1Block.Int64())
+	}
+	if l2Block != nil {
+		blocksGauge(channel, "l2").Update(l2Block.Int64())
+	}
+}
+
+func blocksGauge(channel, layer string) metrics.Gauge {
+	return metrics.GetOrRegisterGauge("blocks/"+metricName(channel)+"/"+layer, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatesRecordBlocks(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		sim.Commit()
+	}
+
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	history := newDecisionHistory(10)
+	baseFeeTrace := newDecisionTrace(l1BaseFeeChannel, nil, history)
+	gasPriceTrace := newDecisionTrace(l2GasPriceChannel, nil, history)
+
+	// The L1 base fee is read at the L1 tip, and the value on chain at the
+	// L2 head
+	l1 := &syntheticL1{tip: 2, baseFees: []int64{1e9, 2e9, 3e9}}
+	updateBaseFee, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{trace: baseFeeTrace})
+	require.NoError(t, err)
+	baseFeeHead := sim.Blockchain().CurrentHeader().Number.Uint64()
+	require.NoError(t, baseFeeTrace.wrap(updateBaseFee)())
+	sim.Commit()
+
+	decision, ok := baseFeeTrace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionUpdate, decision.Action)
+	require.Equal(t, uint64(2), decision.L1Block)
+	require.Equal(t, baseFeeHead, decision.L2Block)
+	require.Equal(t, int64(2), blocksGauge(l1BaseFeeChannel, "l1").Value())
+	require.Equal(t, int64(baseFeeHead), blocksGauge(l1BaseFeeChannel, "l2").Value())
+
+	// The L2 gas price reads nothing from L1
+	updateGasPrice, err := wrapUpdateL2GasPriceFn(sim, cfg, channelGuards{trace: gasPriceTrace})
+	require.NoError(t, err)
+	gasPriceHead := sim.Blockchain().CurrentHeader().Number.Uint64()
+	require.NoError(t, gasPriceTrace.wrap(func() error { return updateGasPrice(5) })())
+	sim.Commit()
+
+	decision, ok = gasPriceTrace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, uint64(0), decision.L1Block)
+	require.Equal(t, gasPriceHead, decision.L2Block)
+	require.Equal(t, int64(gasPriceHead), blocksGauge(l2GasPriceChannel, "l2").Value())
+
+	// The blocks are kept in the history
+	series := history.series(l1BaseFeeChannel, time.Time{}, time.Time{})
+	require.Len(t, series, 1)
+	require.Equal(t, uint64(2), series[0].L1Block)
+
+	// and served on /state
+	g := &GasPriceOracle{
+		config:  &Config{},
+		drainer: new(drainer),
+		state:   new(stateStore),
+		traces:  map[string]*decisionTrace{l1BaseFeeChannel: baseFeeTrace, l2GasPriceChannel: gasPriceTrace},
+	}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var state State
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&state))
+	require.Equal(t, map[string]Blocks{
+		l1BaseFeeChannel:  {L1: 2, L2: baseFeeHead},
+		l2GasPriceChannel: {L2: gasPriceHead},
+	}, state.Blocks)
+}
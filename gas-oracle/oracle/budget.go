@@ -0,0 +1,180 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/units"
+)
+
+// errBudgetExceeded represents the error when an update is held back
+// because the updates spent more than the budget within the window
+var errBudgetExceeded = errors.New("update paused, spend budget exceeded")
+
+// errNoSubmissionTracking represents the error when the spend budget is set
+// without recording the outcomes of the updates, which read the receipts
+var errNoSubmissionTracking = errors.New("spend budget requires the submission outcomes to be recorded")
+
+// spend is the gas cost of an update that landed
+type spend struct {
+	at   time.Time
+	cost *big.Int
+}
+
+// spendBudget caps the gas cost of the updates within a rolling window.
+// The costs are read from the receipts of the updates. Once they exceed the
+// budget every update is held back, until enough of the costs roll out of
+// the window or an operator resumes the updates. A nil spendBudget never
+// holds back.
+type spendBudget struct {
+	budget *big.Int
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	spends []spend
+	paused bool
+}
+
+// newSpendBudget creates the budget of the window, or returns nil when the
+// budget is zero
+func newSpendBudget(budget *big.Int, window time.Duration) *spendBudget {
+	if budget == nil || budget.Sign() <= 0 {
+		return nil
+	}
+	return &spendBudget{budget: budget, window: window, now: time.Now}
+}
+
+// record adds the cost of an update that landed with the receipt, sent at
+// gasPrice, and pauses the updates when the budget is exceeded. The gas
+// price of a dynamic fee transaction is its fee cap, which bounds the price
+// that it paid, so that the budget errs on the safe side.
+func (b *spendBudget) record(channel string, receipt *types.Receipt, gasPrice *big.Int) {
+	if b == nil || gasPrice == nil {
+		return
+	}
+	cost := units.FromWei(gasPrice).Mul(receipt.GasUsed).Wei()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spends = append(b.spends, spend{at: b.now(), cost: cost})
+	spent := b.spent()
+	if spent.Cmp(b.budget) > 0 && !b.paused {
+		b.paused = true
+		log.Error("ALERT: spend budget exceeded, pausing updates", "channel", channel,
+			"spent", spent, "budget", b.budget, "window", b.window)
+		budgetExceededCounter().Inc(1)
+		budgetPausedGauge().Update(1)
+	}
+}
+
+// check returns errBudgetExceeded while the updates are paused. The pause
+// ends once the spend within the window is back under the budget.
+func (b *spendBudget) check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	spent := b.spent()
+	if !b.paused {
+		return nil
+	}
+	if spent.Cmp(b.budget) <= 0 {
+		log.Info("Spend budget window rolled, resuming updates", "spent", spent, "budget", b.budget)
+		b.paused = false
+		budgetPausedGauge().Update(0)
+		return nil
+	}
+	return fmt.Errorf("%w: spent %s of %s within %s", errBudgetExceeded, spent, b.budget, b.window)
+}
+
+// resume lifts the pause right away and starts a new window, so that the
+// costs spent so far no longer count
+func (b *spendBudget) resume() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.paused {
+		log.Info("Spend budget resumed by the operator")
+	}
+	b.paused = false
+	b.spends = nil
+	budgetPausedGauge().Update(0)
+	budgetSpentGauge().Update(0)
+}
+
+// status returns the spend within the window and whether the updates are
+// paused
+func (b *spendBudget) status() (*big.Int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent(), b.paused
+}
+
+// spent forgets the costs that rolled out of the window and returns the
+// total of the others, the lock must be held
+func (b *spendBudget) spent() *big.Int {
+	start := b.now().Add(-b.window)
+	kept := b.spends[:0]
+	total := new(big.Int)
+	for _, s := range b.spends {
+		if s.at.After(start) {
+			kept = append(kept, s)
+			total.Add(total, s.cost)
+		}
+	}
+	b.spends = kept
+	budgetSpentGauge().Update(units.FromWei(total).Floor(units.Gwei).Int64())
+	return total
+}
+
+// backend returns a backend that holds back the transactions while the
+// updates are paused
+func (b *spendBudget) backend(backend DeployContractBackend) DeployContractBackend {
+	if b == nil {
+		return backend
+	}
+	return &budgetBackend{backendDecorator: decorate(backend), budget: b}
+}
+
+// budgetBackend checks the spend budget before sending
+type budgetBackend struct {
+	backendDecorator
+	budget *spendBudget
+}
+
+func (b *budgetBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.budget.check(); err != nil {
+		return err
+	}
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+// validateSpendBudget makes sure that the receipts that the spend budget
+// reads are polled
+func (c *Config) validateSpendBudget() error {
+	if c.spendBudgetWei != nil && c.spendBudgetWei.Sign() > 0 && c.submissionTimeoutSeconds == 0 {
+		return errNoSubmissionTracking
+	}
+	return nil
+}
+
+func budgetExceededCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("budget/exceeded", ometrics.DefaultRegistry)
+}
+
+func budgetPausedGauge() metrics.Gauge {
+	return metrics.GetOrRegisterGauge("budget/paused", ometrics.DefaultRegistry)
+}
+
+func budgetSpentGauge() metrics.Gauge {
+	return metrics.GetOrRegisterGauge("budget/spent_gwei", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,173 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendBudgetRollingWindow(t *testing.T) {
+	gwei := big.NewInt(1e9)
+	budget := newSpendBudget(new(big.Int).Mul(big.NewInt(50_000), gwei), time.Hour)
+	now := time.Unix(1_000, 0)
+	budget.now = func() time.Time { return now }
+	backend := budget.backend(&scriptedBackend{})
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	ctx := context.Background()
+	receipt := &types.Receipt{GasUsed: 21_000}
+
+	// Two updates of 21000 gwei fit in the budget of 50000 gwei
+	budget.record(l1BaseFeeChannel, receipt, gwei)
+	now = now.Add(10 * time.Minute)
+	budget.record(l1BaseFeeChannel, receipt, gwei)
+	require.NoError(t, backend.SendTransaction(ctx, tx))
+	require.Equal(t, int64(42_000), budgetSpentGauge().Value())
+
+	// a third one exceeds it and pauses the updates
+	exceeded := budgetExceededCounter().Count()
+	now = now.Add(10 * time.Minute)
+	budget.record(l1BaseFeeChannel, receipt, gwei)
+	require.ErrorIs(t, backend.SendTransaction(ctx, tx), errBudgetExceeded)
+	require.Equal(t, exceeded+1, budgetExceededCounter().Count())
+	require.Equal(t, int64(1), budgetPausedGauge().Value())
+
+	// until the first one rolls out of the window
+	now = now.Add(39 * time.Minute)
+	require.ErrorIs(t, backend.SendTransaction(ctx, tx), errBudgetExceeded)
+	now = now.Add(time.Minute)
+	require.NoError(t, backend.SendTransaction(ctx, tx))
+	spent, paused := budget.status()
+	require.False(t, paused)
+	require.Equal(t, new(big.Int).Mul(big.NewInt(42_000), gwei), spent)
+	require.Equal(t, int64(0), budgetPausedGauge().Value())
+
+	require.Nil(t, newSpendBudget(new(big.Int), time.Hour))
+	require.ErrorIs(t, (&Config{spendBudgetWei: big.NewInt(1)}).validateSpendBudget(), errNoSubmissionTracking)
+	require.NoError(t, (&Config{spendBudgetWei: big.NewInt(1), submissionTimeoutSeconds: 60}).validateSpendBudget())
+}
+
+func TestSpendBudgetPausesUpdates(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// Any update exceeds a budget of a single wei
+	budget := newSpendBudget(big.NewInt(1), time.Hour)
+	tracker := newSubmissionTracker(time.Minute)
+	tracker.budget = budget
+	l1 := &syntheticL1{baseFees: []int64{1e9, 2e9, 3e9}}
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	backend := tracker.backend(l1BaseFeeChannel, budget.backend(sim))
+	update, err := wrapUpdateBaseFee(l1, backend, cfg, channelGuards{trace: trace})
+	require.NoError(t, err)
+	update = trace.wrap(update)
+	requireBaseFee := func(want int64) {
+		t.Helper()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(want), l1BaseFee)
+	}
+
+	require.NoError(t, update())
+	sim.Commit()
+	require.NoError(t, tracker.poll(context.Background()))
+	requireBaseFee(1e9)
+	_, paused := budget.status()
+	require.True(t, paused)
+
+	// The updates are held back while the budget is exceeded
+	l1.tip = 1
+	require.ErrorIs(t, update(), errBudgetExceeded)
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, reasonBudgetExceeded, decision.ReasonCode)
+	sim.Commit()
+	requireBaseFee(1e9)
+
+	// until an operator resumes them
+	g := &GasPriceOracle{drainer: new(drainer), budget: budget}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	do := func(method string) map[string]string {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, "/budget", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		return body
+	}
+	require.Equal(t, "paused", do(http.MethodGet)["status"])
+	body := do(http.MethodDelete)
+	require.Equal(t, "active", body["status"])
+	require.Equal(t, "0", body["spent"])
+
+	l1.tip = 2
+	require.NoError(t, update())
+	sim.Commit()
+	requireBaseFee(3e9)
+}
+
+func TestSpendBudgetRefusalReleasesNonce(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// The nonce manager wraps the budget, as in NewGasPriceOracle
+	budget := newSpendBudget(big.NewInt(1), time.Hour)
+	tracker := newSubmissionTracker(time.Minute)
+	tracker.budget = budget
+	nonces := newNonceManager(opts.From, sim, time.Hour)
+	backend := tracker.backend(l2GasPriceChannel, nonces.backend(budget.backend(sim)))
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	update, err := wrapUpdateL2GasPriceFn(backend, cfg, channelGuards{})
+	require.NoError(t, err)
+	requirePrice := func(price uint64) {
+		t.Helper()
+		gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, price, gasPrice.Uint64())
+	}
+
+	require.NoError(t, update(1))
+	sim.Commit()
+	require.NoError(t, tracker.poll(context.Background()))
+	requirePrice(1)
+
+	// The refused update was built with a nonce that it never used
+	require.ErrorIs(t, update(2), errBudgetExceeded)
+	sim.Commit()
+	requirePrice(1)
+
+	// so the update that follows the resume reuses it and is mined
+	budget.resume()
+	require.NoError(t, update(3))
+	sim.Commit()
+	requirePrice(3)
+}
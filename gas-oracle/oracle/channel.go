@@ -0,0 +1,165 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// Names of the update channels. They key per-channel configuration,
+// metrics and logs.
+const (
+	l1BaseFeeChannel  = "l1-base-fee"
+	l2GasPriceChannel = "l2-gas-price"
+	daFeeChannel      = "da-fee"
+	overheadChannel   = "overhead"
+	scalarChannel     = "scalar"
+)
+
+// Modes are the strategies used by the channels to compute their values
+const (
+	// modeGasPerSecond moves the L2 gas price towards a target gas per second
+	modeGasPerSecond = "gas-per-second"
+	// modeInclusionTime moves the L2 gas price towards a target inclusion
+	// time
+	modeInclusionTime = "inclusion-time"
+	// modeLatestBaseFee uses the base fee of the latest L1 block
+	modeLatestBaseFee = "latest-base-fee"
+	// modeRollupFee uses the rollup fee of the DA fee contract
+	modeRollupFee = "rollup-fee"
+	// modeTargetValue moves a fee parameter to its configured target
+	modeTargetValue = "target-value"
+)
+
+// channelGuards are the collaborators that every update of a channel
+// consults and reports to. Each of them is nil safe, so the zero value runs
+// the update without any of them.
+type channelGuards struct {
+	deadline  *inputDeadline
+	trace     *decisionTrace
+	emergency *emergencyMode
+	stuck     *stuckDetector
+	reversals *reversalDamper
+	grace     *enableGrace
+	drops     *dropLimit
+	sent      *sentState
+}
+
+// channelModes returns the mode of every enabled channel
+func (c *Config) channelModes() map[string]string {
+	modes := make(map[string]string)
+	if c.enableL1BaseFee {
+		modes[l1BaseFeeChannel] = modeLatestBaseFee
+	}
+	if c.enableL2GasPrice {
+		modes[l2GasPriceChannel] = modeGasPerSecond
+		if c.l2GasPriceMode == modeInclusionTime {
+			modes[l2GasPriceChannel] = modeInclusionTime
+		}
+	}
+	if c.enableDaFee {
+		modes[daFeeChannel] = modeRollupFee
+	}
+	if c.enableOverhead {
+		modes[overheadChannel] = modeTargetValue
+	}
+	if c.enableScalar {
+		modes[scalarChannel] = modeTargetValue
+	}
+	return modes
+}
+
+// errMissingChannelInput represents the error when an enabled channel
+// lacks an input that it needs to compute or write its value
+var errMissingChannelInput = errors.New("enabled channel is missing an input")
+
+// validateChannelInputs makes sure that every enabled channel has its
+// endpoints, its target address and the inputs of its mode configured, so
+// that a missing dependency fails at startup rather than in an epoch
+func (c *Config) validateChannelInputs() error {
+	var missing []string
+	need := func(channel string, ok bool, input string) {
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%s needs %s", channel, input))
+		}
+	}
+	target := func(channel string, e endpoints) {
+		need(channel, e.read != "", "an endpoint to read from")
+		need(channel, e.write != "", "an endpoint to write to")
+		need(channel, c.gasPriceOracleAddress != (common.Address{}), "the gas price oracle address (--gas-price-oracle-address)")
+	}
+	if c.enableL1BaseFee {
+		target(l1BaseFeeChannel, c.l1BaseFeeEndpoints)
+		for _, backend := range c.tokenPricerBackends {
+			url, flag := c.tokenPriceSource(backend)
+			need(l1BaseFeeChannel, url != "", fmt.Sprintf("a token price source (--%s)", flag))
+		}
+	}
+	if c.enableL2GasPrice {
+		target(l2GasPriceChannel, c.l2GasPriceEndpoints)
+	}
+	if c.enableDaFee {
+		target(daFeeChannel, c.daFeeEndpoints)
+		need(daFeeChannel, c.daFeeContractAddress != (common.Address{}), "the DA fee contract address (--da-fee-contract-address)")
+	}
+	// The fee parameters are written through the endpoints of the L2 gas
+	// price
+	if c.enableOverhead {
+		target(overheadChannel, c.l2GasPriceEndpoints)
+	}
+	if c.enableScalar {
+		target(scalarChannel, c.l2GasPriceEndpoints)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", errMissingChannelInput, strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// modeReporter exports the mode of each channel as an info metric so that
+// dashboards can tell which strategy produced a value. The gauge
+// `mode/<channel>/<mode>` is 1 for the active mode of a channel and 0 for
+// the modes that were active before.
+type modeReporter struct {
+	mu     sync.Mutex
+	active map[string]string
+}
+
+func newModeReporter() *modeReporter {
+	return &modeReporter{
+		active: make(map[string]string),
+	}
+}
+
+// report sets the active mode of every channel in modes. Channels that
+// were reported before and are missing from modes no longer have an active
+// mode.
+func (r *modeReporter) report(modes map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for channel, mode := range r.active {
+		if modes[channel] != mode {
+			modeGauge(channel, mode).Update(0)
+			delete(r.active, channel)
+		}
+	}
+	for channel, mode := range modes {
+		modeGauge(channel, mode).Update(1)
+		r.active[channel] = mode
+	}
+}
+
+func modeGauge(channel, mode string) metrics.Gauge {
+	name := "mode/" + metricName(channel) + "/" + metricName(mode)
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
+
+// metricName turns a channel or mode name into a valid metric name
+func metricName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
@@ -0,0 +1,66 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+func modeGaugeValue(t *testing.T, channel, mode string) int64 {
+	t.Helper()
+	name := "mode/" + metricName(channel) + "/" + metricName(mode)
+	gauge, ok := ometrics.DefaultRegistry.Get(name).(metrics.Gauge)
+	if !ok {
+		return 0
+	}
+	return gauge.Value()
+}
+
+func TestChannelModes(t *testing.T) {
+	cfg := &Config{
+		enableL1BaseFee:  true,
+		enableL2GasPrice: true,
+	}
+	modes := cfg.channelModes()
+	if modes[l1BaseFeeChannel] != modeLatestBaseFee {
+		t.Fatalf("unexpected l1 base fee mode %q", modes[l1BaseFeeChannel])
+	}
+	if modes[l2GasPriceChannel] != modeGasPerSecond {
+		t.Fatalf("unexpected l2 gas price mode %q", modes[l2GasPriceChannel])
+	}
+	if _, ok := modes[daFeeChannel]; ok {
+		t.Fatal("disabled channel has a mode")
+	}
+}
+
+func TestModeReporter(t *testing.T) {
+	r := newModeReporter()
+	g := &GasPriceOracle{
+		config: &Config{enableL2GasPrice: true, enableDaFee: true},
+		modes:  r,
+	}
+
+	g.ReportModes()
+	if v := modeGaugeValue(t, l2GasPriceChannel, modeGasPerSecond); v != 1 {
+		t.Fatalf("l2 gas price mode not reported: %d", v)
+	}
+	if v := modeGaugeValue(t, daFeeChannel, modeRollupFee); v != 1 {
+		t.Fatalf("da fee mode not reported: %d", v)
+	}
+
+	// A reload with a different mode moves the label
+	r.report(map[string]string{
+		l2GasPriceChannel: "test-mode",
+	})
+	if v := modeGaugeValue(t, l2GasPriceChannel, modeGasPerSecond); v != 0 {
+		t.Fatalf("previous l2 gas price mode still active: %d", v)
+	}
+	if v := modeGaugeValue(t, l2GasPriceChannel, "test-mode"); v != 1 {
+		t.Fatalf("new l2 gas price mode not reported: %d", v)
+	}
+	// The da fee channel is no longer enabled
+	if v := modeGaugeValue(t, daFeeChannel, modeRollupFee); v != 0 {
+		t.Fatalf("disabled channel still has a mode: %d", v)
+	}
+}
@@ -2,47 +2,181 @@ package oracle
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
 	"github.com/mantlenetworkio/mantle/gas-oracle/flags"
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/mantlenetworkio/mantle/gas-oracle/units"
 	"github.com/urfave/cli"
 )
 
+// endpoints are the RPC urls that a channel reads its inputs from and
+// sends its updates to
+type endpoints struct {
+	read  string
+	write string
+}
+
 // Config represents the configuration options for the gas oracle
 type Config struct {
 	l1ChainID                        *big.Int
 	l2ChainID                        *big.Int
 	ethereumHttpUrl                  string
 	layerTwoHttpUrl                  string
+	l1BaseFeeEndpoints               endpoints
+	l2GasPriceEndpoints              endpoints
+	daFeeEndpoints                   endpoints
 	gasPriceOracleAddress            common.Address
+	shadowGasPriceOracleAddresses    []common.Address
 	daFeeContractAddress             common.Address
+	submissionPath                   string
+	depositPortalAddress             common.Address
+	depositGasLimit                  uint64
 	privateKey                       *ecdsa.PrivateKey
+	signerEndpoint                   string
+	signerAddress                    string
+	kmsKeyID                         string
+	kmsRegion                        string
+	addressFlags                     map[string]string
+	signer                           txSigner
 	gasPrice                         *big.Int
+	gasPriceSource                   string
+	gasPriceHistoryBlocks            uint64
+	gasPriceHistoryPercentile        float64
+	txType                           string
+	txMaxFeePerGas                   *big.Int
+	txMaxPriorityFeePerGas           *big.Int
 	waitForReceipt                   bool
+	receiptBackoff                   backoff.Policy
+	connectBackoff                   backoff.Policy
+	heartbeatIntervalSeconds         uint64
+	watchdogTimeoutSeconds           uint64
+	healthStalenessEpochs            uint64
+	stateFile                        string
+	shutdownReferenceURL             string
+	shutdownDivergenceTolerance      float64
+	shutdownTimeoutSeconds           uint64
+	heartbeatMaxCost                 uint64
 	floorPrice                       uint64
+	ceilingPrice                     uint64
 	targetGasPerSecond               uint64
+	l2GasPriceMode                   string
+	targetInclusionSeconds           float64
+	inclusionLatencyPercentile       float64
+	inclusionPollSeconds             uint64
 	maxPercentChangePerEpoch         float64
 	averageBlockGasLimitPerEpoch     uint64
 	epochLengthSeconds               uint64
 	l1BaseFeeEpochLengthSeconds      uint64
 	daFeeEpochLengthSeconds          uint64
+	overheadEpochLengthSeconds       uint64
+	scalarEpochLengthSeconds         uint64
+	epochInputBudgetMs               uint64
 	l2GasPriceSignificanceFactor     float64
+	l2GasPriceSignificanceFactorUp   *float64
+	l2GasPriceSignificanceFactorDown *float64
+	l2GasPriceEMAAlpha               float64
+	l2GasPriceSpreadBlocks           uint64
 	bybitBackendURL                  string
+	binanceBackendURL                string
+	tokenPricerBackends              []string
+	tokenPricerQuorum                int
+	tokenPricerAggregation           string
+	tokenPricerMaxRetries            int
+	tokenPricerRetryBackoff          backoff.Policy
+	tokenPricerDeadlineSeconds       uint64
+	tokenPricerRateLimit             float64
+	tokenPriceAPIKey                 string
+	tokenPriceAPISecret              string
 	tokenPricerUpdateFrequencySecond uint64
+	tokenPriceMaxStaleSeconds        uint64
+	tokenPriceMaxAgeSeconds          uint64
+	tokenPriceSymbols                []string
+	tokenPriceStaleMarginPerMinute   float64
+	tokenPriceStrict                 bool
+	tokenPriceDecimals               *big.Int
+	tokenPriceAdaptiveMinSeconds     uint64
+	tokenPriceAdaptiveMaxSeconds     uint64
+	tokenPriceVolatilityThreshold    float64
+	tokenPriceMaxDeviation           float64
+	tokenPriceBreakerReset           int
 	l1BaseFeeSignificanceFactor      float64
+	l1BaseFeeMode                    string
+	l1BaseFeeWindow                  uint64
+	dualComputeL1HttpUrl             string
+	dualComputeTolerance             float64
+	l1EpochBlocks                    uint64
+	l1BaseFeeMaxFreezeSeconds        uint64
 	daFeeSignificanceFactor          float64
+	daFeeMin                         uint64
+	daFeeMax                         uint64
+	overhead                         uint64
+	overheadSignificanceFactor       float64
+	scalar                           uint64
+	scalarSignificanceFactor         float64
+	gasPriceRoundTo                  uint64
+	l1BaseFeeRoundTo                 uint64
+	daFeeRoundTo                     uint64
+	daFeeModel                       string
+	daFeeExpression                  string
+	emergencyUpdateThreshold         uint64
+	emergencyWindowSeconds           uint64
+	emergencySignificanceFactor      float64
+	reversalWindowWrites             uint64
+	reversalThreshold                uint64
+	reversalSignificanceFactor       float64
+	maxDropPerPeriod                 float64
+	maxDropPeriodSeconds             uint64
+	stuckWriteThreshold              uint64
+	nonceReconcileIntervalSeconds    uint64
+	txResubmitTimeoutSeconds         uint64
+	txMaxBumps                       uint64
+	simulateBeforeSend               bool
+	maxInflightUpdates               uint64
+	maxPendingTransactions           uint64
+	submissionTimeoutSeconds         uint64
+	submissionPollSeconds            uint64
+	spendBudgetWei                   *big.Int
+	spendBudgetWindowSeconds         uint64
+	channelPriorities                map[string]int
+	observeOnly                      map[string]bool
+	optionErrs                       []error
+	dryRun                           bool
+	enableGrace                      map[string]string
+	alertWebhookURL                  string
+	alertFailureThreshold            uint64
 	enableL1BaseFee                  bool
 	enableL2GasPrice                 bool
 	enableDaFee                      bool
+	enableOverhead                   bool
+	enableScalar                     bool
+	historySize                      uint64
+	// AuditStdout writes the decision of every epoch to stdout
+	AuditStdout bool
+	// Once runs a single cycle of every enabled channel and exits
+	Once bool
+	// ReplayFile replays the samples of the file in place of the RPC
+	// endpoints, writing the decisions to ReplayOut or stdout
+	ReplayFile string
+	ReplayOut  string
 	// Metrics config
 	MetricsEnabled          bool
+	MetricsPrometheus       bool
 	MetricsHTTP             string
 	MetricsPort             int
+	MetricsAuthUsername     string
+	MetricsAuthPassword     string
+	MetricsAuthToken        string
+	MetricsGzip             bool
+	Pprof                   bool
 	MetricsEnableInfluxDB   bool
 	MetricsInfluxDBEndpoint string
 	MetricsInfluxDBDatabase string
@@ -50,39 +184,233 @@ type Config struct {
 	MetricsInfluxDBPassword string
 }
 
+var (
+	// defaultReceiptBackoff polls for receipts at a steady pace
+	defaultReceiptBackoff = backoff.Constant(300 * time.Millisecond)
+	// defaultConnectBackoff retries connecting once per second
+	defaultConnectBackoff = backoff.Constant(time.Second)
+	// defaultTokenPricerRetryBackoff spreads the retries of the exchange
+	// requests over a few seconds
+	defaultTokenPricerRetryBackoff = backoff.Policy{
+		Initial:    250 * time.Millisecond,
+		Max:        4 * time.Second,
+		Multiplier: 2,
+		Jitter:     0.2,
+	}
+)
+
+// parseBackoff reads the backoff policy of a use site from its flag. Keys
+// that the flag leaves out keep their default. An invalid policy keeps the
+// default and is reported by ValidateConfig.
+func (c *Config) parseBackoff(ctx *cli.Context, flag cli.StringFlag, defaults backoff.Policy) backoff.Policy {
+	policy, err := backoff.Parse(ctx.GlobalString(flag.Name), defaults)
+	if err != nil {
+		c.optionError(flag, err)
+		return defaults
+	}
+	return policy
+}
+
+// optionError keeps the error of an option that cannot be parsed, so that
+// ValidateConfig reports it along with the other problems of the config
+func (c *Config) optionError(flag cli.StringFlag, err error) {
+	c.optionErrs = append(c.optionErrs, fmt.Errorf("--%s: %w", flag.Name, err))
+}
+
 // NewConfig creates a new Config
 func NewConfig(ctx *cli.Context) *Config {
 	cfg := Config{}
 	cfg.ethereumHttpUrl = ctx.GlobalString(flags.EthereumHttpUrlFlag.Name)
 	cfg.layerTwoHttpUrl = ctx.GlobalString(flags.LayerTwoHttpUrlFlag.Name)
-	addr := ctx.GlobalString(flags.GasPriceOracleAddressFlag.Name)
-	cfg.gasPriceOracleAddress = common.HexToAddress(addr)
-	daFeeContractAddress := ctx.GlobalString(flags.DaFeeContractAddressFlag.Name)
-	cfg.daFeeContractAddress = common.HexToAddress(daFeeContractAddress)
+	cfg.l1BaseFeeEndpoints = channelEndpoints(ctx, flags.L1BaseFeeReadHttpUrlFlag, flags.L1BaseFeeWriteHttpUrlFlag, cfg.ethereumHttpUrl, cfg.layerTwoHttpUrl)
+	cfg.l2GasPriceEndpoints = channelEndpoints(ctx, flags.L2GasPriceReadHttpUrlFlag, flags.L2GasPriceWriteHttpUrlFlag, cfg.layerTwoHttpUrl, cfg.layerTwoHttpUrl)
+	cfg.daFeeEndpoints = channelEndpoints(ctx, flags.DaFeeReadHttpUrlFlag, flags.DaFeeWriteHttpUrlFlag, cfg.ethereumHttpUrl, cfg.layerTwoHttpUrl)
+	cfg.submissionPath = ctx.GlobalString(flags.SubmissionPathFlag.Name)
+	cfg.depositPortalAddress = common.HexToAddress(ctx.GlobalString(flags.DepositPortalAddressFlag.Name))
+	cfg.depositGasLimit = ctx.GlobalUint64(flags.DepositGasLimitFlag.Name)
 	cfg.targetGasPerSecond = ctx.GlobalUint64(flags.TargetGasPerSecondFlag.Name)
+	cfg.l2GasPriceMode = ctx.GlobalString(flags.L2GasPriceModeFlag.Name)
+	cfg.targetInclusionSeconds = ctx.GlobalFloat64(flags.TargetInclusionSecondsFlag.Name)
+	cfg.inclusionLatencyPercentile = ctx.GlobalFloat64(flags.InclusionLatencyPercentileFlag.Name)
+	cfg.inclusionPollSeconds = ctx.GlobalUint64(flags.InclusionPollSecondsFlag.Name)
 	cfg.maxPercentChangePerEpoch = ctx.GlobalFloat64(flags.MaxPercentChangePerEpochFlag.Name)
 	cfg.averageBlockGasLimitPerEpoch = ctx.GlobalUint64(flags.AverageBlockGasLimitPerEpochFlag.Name)
 	cfg.epochLengthSeconds = ctx.GlobalUint64(flags.EpochLengthSecondsFlag.Name)
 	cfg.l1BaseFeeEpochLengthSeconds = ctx.GlobalUint64(flags.L1BaseFeeEpochLengthSecondsFlag.Name)
 	cfg.daFeeEpochLengthSeconds = ctx.GlobalUint64(flags.DaFeeEpochLengthSecondsFlag.Name)
+	cfg.overheadEpochLengthSeconds = ctx.GlobalUint64(flags.OverheadEpochLengthSecondsFlag.Name)
+	cfg.scalarEpochLengthSeconds = ctx.GlobalUint64(flags.ScalarEpochLengthSecondsFlag.Name)
+	cfg.epochInputBudgetMs = ctx.GlobalUint64(flags.EpochInputBudgetMsFlag.Name)
 	cfg.l2GasPriceSignificanceFactor = ctx.GlobalFloat64(flags.L2GasPriceSignificanceFactorFlag.Name)
+	if ctx.GlobalIsSet(flags.L2GasPriceSignificanceFactorUpFlag.Name) {
+		factor := ctx.GlobalFloat64(flags.L2GasPriceSignificanceFactorUpFlag.Name)
+		cfg.l2GasPriceSignificanceFactorUp = &factor
+	}
+	if ctx.GlobalIsSet(flags.L2GasPriceSignificanceFactorDownFlag.Name) {
+		factor := ctx.GlobalFloat64(flags.L2GasPriceSignificanceFactorDownFlag.Name)
+		cfg.l2GasPriceSignificanceFactorDown = &factor
+	}
+	cfg.l2GasPriceEMAAlpha = ctx.GlobalFloat64(flags.L2GasPriceEMAAlphaFlag.Name)
+	cfg.l2GasPriceSpreadBlocks = ctx.GlobalUint64(flags.L2GasPriceSpreadBlocksFlag.Name)
 	cfg.bybitBackendURL = ctx.GlobalString(flags.BybitBackendURL.Name)
+	cfg.binanceBackendURL = ctx.GlobalString(flags.BinanceBackendURLFlag.Name)
+	for _, backend := range strings.Split(ctx.GlobalString(flags.TokenPricerBackendFlag.Name), ",") {
+		if backend = strings.TrimSpace(backend); backend != "" {
+			cfg.tokenPricerBackends = append(cfg.tokenPricerBackends, backend)
+		}
+	}
+	cfg.tokenPricerQuorum = ctx.GlobalInt(flags.TokenPricerQuorumFlag.Name)
+	cfg.tokenPricerAggregation = ctx.GlobalString(flags.TokenPricerAggregationFlag.Name)
+	cfg.tokenPricerMaxRetries = ctx.GlobalInt(flags.TokenPricerMaxRetriesFlag.Name)
+	cfg.tokenPricerRetryBackoff = cfg.parseBackoff(ctx, flags.TokenPricerRetryBackoffFlag, defaultTokenPricerRetryBackoff)
+	cfg.tokenPricerDeadlineSeconds = ctx.GlobalUint64(flags.TokenPricerDeadlineSecondsFlag.Name)
+	cfg.tokenPricerRateLimit = ctx.GlobalFloat64(flags.TokenPricerRateLimitFlag.Name)
 	cfg.tokenPricerUpdateFrequencySecond = ctx.GlobalUint64(flags.TokenPricerUpdateFrequencySecond.Name)
+	cfg.tokenPriceMaxStaleSeconds = ctx.GlobalUint64(flags.TokenPriceMaxStaleSecondsFlag.Name)
+	cfg.tokenPriceMaxAgeSeconds = ctx.GlobalUint64(flags.TokenPriceMaxAgeFlag.Name)
+	for _, symbol := range strings.Split(ctx.GlobalString(flags.TokenPriceSymbolsFlag.Name), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			cfg.tokenPriceSymbols = append(cfg.tokenPriceSymbols, symbol)
+		}
+	}
+	cfg.tokenPriceStaleMarginPerMinute = ctx.GlobalFloat64(flags.TokenPriceStaleMarginPerMinuteFlag.Name)
+	cfg.tokenPriceStrict = ctx.GlobalBool(flags.TokenPriceStrictFlag.Name)
+	cfg.tokenPriceDecimals = new(big.Int).SetUint64(ctx.GlobalUint64(flags.TokenPriceDecimalsFlag.Name))
+	cfg.tokenPriceAdaptiveMinSeconds = ctx.GlobalUint64(flags.TokenPriceAdaptiveMinSecondsFlag.Name)
+	cfg.tokenPriceAdaptiveMaxSeconds = ctx.GlobalUint64(flags.TokenPriceAdaptiveMaxSecondsFlag.Name)
+	cfg.tokenPriceVolatilityThreshold = ctx.GlobalFloat64(flags.TokenPriceVolatilityThresholdFlag.Name)
+	cfg.tokenPriceMaxDeviation = ctx.GlobalFloat64(flags.TokenPriceMaxDeviationFlag.Name)
+	cfg.tokenPriceBreakerReset = ctx.GlobalInt(flags.TokenPriceBreakerResetFlag.Name)
 	cfg.floorPrice = ctx.GlobalUint64(flags.FloorPriceFlag.Name)
+	cfg.ceilingPrice = ctx.GlobalUint64(flags.CeilingPriceFlag.Name)
+	cfg.heartbeatIntervalSeconds = ctx.GlobalUint64(flags.HeartbeatIntervalSecondsFlag.Name)
+	cfg.watchdogTimeoutSeconds = ctx.GlobalUint64(flags.WatchdogTimeoutSecondsFlag.Name)
+	cfg.healthStalenessEpochs = ctx.GlobalUint64(flags.HealthStalenessEpochsFlag.Name)
+	cfg.stateFile = ctx.GlobalString(flags.StateFileFlag.Name)
+	cfg.shutdownReferenceURL = ctx.GlobalString(flags.ShutdownReferenceURLFlag.Name)
+	cfg.shutdownDivergenceTolerance = ctx.GlobalFloat64(flags.ShutdownDivergenceToleranceFlag.Name)
+	cfg.shutdownTimeoutSeconds = ctx.GlobalUint64(flags.ShutdownTimeoutSecondsFlag.Name)
+	cfg.alertWebhookURL = ctx.GlobalString(flags.AlertWebhookURLFlag.Name)
+	cfg.alertFailureThreshold = ctx.GlobalUint64(flags.AlertFailureThresholdFlag.Name)
+	cfg.heartbeatMaxCost = ctx.GlobalUint64(flags.HeartbeatMaxCostFlag.Name)
 	cfg.l1BaseFeeSignificanceFactor = ctx.GlobalFloat64(flags.L1BaseFeeSignificanceFactorFlag.Name)
+	cfg.l1BaseFeeMode = ctx.GlobalString(flags.L1BaseFeeModeFlag.Name)
+	cfg.l1BaseFeeWindow = ctx.GlobalUint64(flags.L1BaseFeeWindowFlag.Name)
+	cfg.dualComputeL1HttpUrl = ctx.GlobalString(flags.DualComputeL1HttpUrlFlag.Name)
+	cfg.dualComputeTolerance = ctx.GlobalFloat64(flags.DualComputeToleranceFlag.Name)
+	cfg.l1EpochBlocks = ctx.GlobalUint64(flags.L1EpochBlocksFlag.Name)
+	cfg.l1BaseFeeMaxFreezeSeconds = ctx.GlobalUint64(flags.L1BaseFeeMaxFreezeSecondsFlag.Name)
 	cfg.daFeeSignificanceFactor = ctx.GlobalFloat64(flags.DaFeeSignificanceFactorFlag.Name)
+	cfg.daFeeMin = ctx.GlobalUint64(flags.DaFeeMinFlag.Name)
+	cfg.daFeeMax = ctx.GlobalUint64(flags.DaFeeMaxFlag.Name)
+	cfg.overhead = ctx.GlobalUint64(flags.OverheadFlag.Name)
+	cfg.overheadSignificanceFactor = ctx.GlobalFloat64(flags.OverheadSignificanceFactorFlag.Name)
+	cfg.scalar = ctx.GlobalUint64(flags.ScalarFlag.Name)
+	cfg.scalarSignificanceFactor = ctx.GlobalFloat64(flags.ScalarSignificanceFactorFlag.Name)
+	cfg.gasPriceRoundTo = ctx.GlobalUint64(flags.GasPriceRoundToFlag.Name)
+	cfg.l1BaseFeeRoundTo = ctx.GlobalUint64(flags.L1BaseFeeRoundToFlag.Name)
+	cfg.daFeeRoundTo = ctx.GlobalUint64(flags.DaFeeRoundToFlag.Name)
+	cfg.daFeeModel = ctx.GlobalString(flags.DaFeeModelFlag.Name)
+	cfg.daFeeExpression = ctx.GlobalString(flags.DaFeeExpressionFlag.Name)
+	cfg.emergencyUpdateThreshold = ctx.GlobalUint64(flags.EmergencyUpdateThresholdFlag.Name)
+	cfg.emergencyWindowSeconds = ctx.GlobalUint64(flags.EmergencyWindowSecondsFlag.Name)
+	cfg.emergencySignificanceFactor = ctx.GlobalFloat64(flags.EmergencySignificanceFactorFlag.Name)
+	cfg.reversalWindowWrites = ctx.GlobalUint64(flags.ReversalWindowWritesFlag.Name)
+	cfg.reversalThreshold = ctx.GlobalUint64(flags.ReversalThresholdFlag.Name)
+	cfg.reversalSignificanceFactor = ctx.GlobalFloat64(flags.ReversalSignificanceFactorFlag.Name)
+	cfg.maxDropPerPeriod = ctx.GlobalFloat64(flags.MaxDropPerPeriodFlag.Name)
+	cfg.maxDropPeriodSeconds = ctx.GlobalUint64(flags.MaxDropPeriodSecondsFlag.Name)
+	cfg.stuckWriteThreshold = ctx.GlobalUint64(flags.StuckWriteThresholdFlag.Name)
+	cfg.nonceReconcileIntervalSeconds = ctx.GlobalUint64(flags.NonceReconcileIntervalSecondsFlag.Name)
+	cfg.txResubmitTimeoutSeconds = ctx.GlobalUint64(flags.TxResubmitTimeoutFlag.Name)
+	cfg.txMaxBumps = ctx.GlobalUint64(flags.TxMaxBumpsFlag.Name)
+	cfg.simulateBeforeSend = ctx.GlobalBoolT(flags.SimulateBeforeSendFlag.Name)
+	cfg.maxInflightUpdates = ctx.GlobalUint64(flags.MaxInflightUpdatesFlag.Name)
+	cfg.maxPendingTransactions = ctx.GlobalUint64(flags.MaxPendingTransactionsFlag.Name)
+	cfg.submissionTimeoutSeconds = ctx.GlobalUint64(flags.SubmissionTimeoutSecondsFlag.Name)
+	cfg.submissionPollSeconds = ctx.GlobalUint64(flags.SubmissionPollSecondsFlag.Name)
+	cfg.spendBudgetWei = units.FromUint64(ctx.GlobalUint64(flags.SpendBudgetGweiFlag.Name), units.Gwei).Wei()
+	cfg.spendBudgetWindowSeconds = ctx.GlobalUint64(flags.SpendBudgetWindowSecondsFlag.Name)
+	priorities, err := parseChannelPriorities(ctx.GlobalString(flags.ChannelPriorityFlag.Name))
+	if err != nil {
+		cfg.optionError(flags.ChannelPriorityFlag, err)
+	}
+	cfg.channelPriorities = priorities
+	// A channel that cannot be told apart must not fall back to writing,
+	// so the error is kept for ValidateConfig to refuse the config
+	observeOnly, err := parseObserveOnly(ctx.GlobalString(flags.ObserveOnlyFlag.Name))
+	if err != nil {
+		cfg.optionError(flags.ObserveOnlyFlag, err)
+	}
+	cfg.observeOnly = observeOnly
+	grace, err := parseEnableGrace(ctx.GlobalString(flags.EnableGraceFlag.Name))
+	if err != nil {
+		cfg.optionError(flags.EnableGraceFlag, err)
+	}
+	cfg.enableGrace = grace
 	cfg.enableL1BaseFee = ctx.GlobalBool(flags.EnableL1BaseFeeFlag.Name)
 	cfg.enableL2GasPrice = ctx.GlobalBool(flags.EnableL2GasPriceFlag.Name)
 	cfg.enableDaFee = ctx.GlobalBool(flags.EnableDaFeeFlag.Name)
+	cfg.enableOverhead = ctx.GlobalBool(flags.EnableOverheadFlag.Name)
+	cfg.enableScalar = ctx.GlobalBool(flags.EnableScalarFlag.Name)
+
+	// The contract addresses that are not set by their flags are read from
+	// the deployments, the DA fee contract only when its channel is enabled
+	var deployments flags.Deployments
+	if path := ctx.GlobalString(flags.DeploymentsFlag.Name); path != "" {
+		deployments, err = flags.LoadDeployments(path)
+		if err != nil {
+			log.Crit(err.Error())
+		}
+	}
+	// The addresses are also kept as they were set, so that ValidateConfig
+	// can report the ones that are not addresses
+	cfg.addressFlags = make(map[string]string)
+	for _, flag := range []cli.StringFlag{flags.GasPriceOracleAddressFlag, flags.DaFeeContractAddressFlag, flags.DepositPortalAddressFlag, flags.SignerAddressFlag} {
+		if ctx.GlobalIsSet(flag.Name) {
+			cfg.addressFlags[flag.Name] = ctx.GlobalString(flag.Name)
+		}
+	}
+	// The first gas price oracle is the primary, and the updates are also
+	// sent to the ones that follow
+	gasPriceOracleAddresses, err := flags.ContractAddresses(ctx, flags.GasPriceOracleAddressFlag, deployments, flags.GasPriceOracleContract)
+	if err != nil {
+		log.Crit(err.Error())
+	}
+	cfg.gasPriceOracleAddress = gasPriceOracleAddresses[0]
+	cfg.shadowGasPriceOracleAddresses = gasPriceOracleAddresses[1:]
+	if !cfg.enableDaFee {
+		deployments = nil
+	}
+	cfg.daFeeContractAddress = configAddress(ctx, flags.DaFeeContractAddressFlag, deployments, flags.DaFeeContract)
+
+	cfg.dryRun = ctx.GlobalBool(flags.DryRunFlag.Name)
+	cfg.ReplayFile = ctx.GlobalString(flags.ReplayFileFlag.Name)
+	cfg.ReplayOut = ctx.GlobalString(flags.ReplayOutFlag.Name)
+	cfg.signerEndpoint = ctx.GlobalString(flags.SignerEndpointFlag.Name)
+	cfg.signerAddress = ctx.GlobalString(flags.SignerAddressFlag.Name)
+	cfg.kmsKeyID = ctx.GlobalString(flags.KMSKeyIDFlag.Name)
+	cfg.kmsRegion = ctx.GlobalString(flags.KMSRegionFlag.Name)
 
-	if ctx.GlobalIsSet(flags.PrivateKeyFlag.Name) {
-		hex := ctx.GlobalString(flags.PrivateKeyFlag.Name)
+	// Secrets are read from their files at startup, a secret that is set
+	// both inline and from a file is ambiguous
+	hex, err := flags.Secret(ctx, flags.PrivateKeyFlag, flags.PrivateKeyFileFlag)
+	if err != nil {
+		log.Crit(err.Error())
+	}
+	if hex != "" {
 		hex = strings.TrimPrefix(hex, "0x")
 		key, err := crypto.HexToECDSA(hex)
 		if err != nil {
-			log.Error(fmt.Sprintf("Option %q: %v", flags.PrivateKeyFlag.Name, err))
+			cfg.optionError(flags.PrivateKeyFlag, err)
 		}
 		cfg.privateKey = key
+	} else if cfg.signerEndpoint != "" || cfg.kmsKeyID != "" {
+		// The transactions are signed by the external signer or the KMS key
+	} else if cfg.dryRun {
+		cfg.privateKey = dryRunKey()
+	} else if cfg.ReplayFile != "" {
+		// A replay signs nothing
 	} else {
 		log.Crit("No private key configured")
 	}
@@ -101,18 +429,144 @@ func NewConfig(ctx *cli.Context) *Config {
 		cfg.gasPrice = new(big.Int).SetUint64(gasPrice)
 	}
 
+	cfg.gasPriceSource = ctx.GlobalString(flags.GasPriceSourceFlag.Name)
+	if cfg.gasPriceSource == "" {
+		if cfg.gasPrice != nil {
+			cfg.gasPriceSource = gasPriceSourceFixed
+		} else {
+			cfg.gasPriceSource = gasPriceSourceNode
+		}
+	}
+	cfg.gasPriceHistoryBlocks = ctx.GlobalUint64(flags.GasPriceHistoryBlocksFlag.Name)
+	cfg.gasPriceHistoryPercentile = ctx.GlobalFloat64(flags.GasPriceHistoryPercentileFlag.Name)
+	cfg.txType = ctx.GlobalString(flags.TxTypeFlag.Name)
+	if ctx.GlobalIsSet(flags.TxMaxFeePerGasFlag.Name) {
+		cfg.txMaxFeePerGas = new(big.Int).SetUint64(ctx.GlobalUint64(flags.TxMaxFeePerGasFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.TxMaxPriorityFeePerGasFlag.Name) {
+		cfg.txMaxPriorityFeePerGas = new(big.Int).SetUint64(ctx.GlobalUint64(flags.TxMaxPriorityFeePerGasFlag.Name))
+	}
+
 	if ctx.GlobalIsSet(flags.WaitForReceiptFlag.Name) {
 		cfg.waitForReceipt = true
 	}
+	cfg.receiptBackoff = cfg.parseBackoff(ctx, flags.ReceiptBackoffFlag, defaultReceiptBackoff)
+	cfg.connectBackoff = cfg.parseBackoff(ctx, flags.ConnectBackoffFlag, defaultConnectBackoff)
 
+	cfg.historySize = ctx.GlobalUint64(flags.HistorySizeFlag.Name)
+	cfg.AuditStdout = ctx.GlobalBool(flags.AuditStdoutFlag.Name)
+	cfg.Once = ctx.GlobalBool(flags.OnceFlag.Name)
 	cfg.MetricsEnabled = ctx.GlobalBool(flags.MetricsEnabledFlag.Name)
+	cfg.MetricsPrometheus = ctx.GlobalBool(flags.MetricsPrometheusFlag.Name)
 	cfg.MetricsHTTP = ctx.GlobalString(flags.MetricsHTTPFlag.Name)
 	cfg.MetricsPort = ctx.GlobalInt(flags.MetricsPortFlag.Name)
+	cfg.MetricsAuthUsername = ctx.GlobalString(flags.MetricsAuthUsernameFlag.Name)
+	cfg.tokenPriceAPIKey = configSecretFile(ctx, flags.TokenPriceAPIKeyFileFlag)
+	cfg.tokenPriceAPISecret = configSecretFile(ctx, flags.TokenPriceAPISecretFileFlag)
+	cfg.MetricsAuthPassword = configSecret(ctx, flags.MetricsAuthPasswordFlag, flags.MetricsAuthPasswordFileFlag)
+	cfg.MetricsAuthToken = configSecret(ctx, flags.MetricsAuthTokenFlag, flags.MetricsAuthTokenFileFlag)
+	cfg.MetricsGzip = ctx.GlobalBool(flags.MetricsGzipFlag.Name)
+	cfg.Pprof = ctx.GlobalBool(flags.PprofFlag.Name)
 	cfg.MetricsEnableInfluxDB = ctx.GlobalBool(flags.MetricsEnableInfluxDBFlag.Name)
 	cfg.MetricsInfluxDBEndpoint = ctx.GlobalString(flags.MetricsInfluxDBEndpointFlag.Name)
 	cfg.MetricsInfluxDBDatabase = ctx.GlobalString(flags.MetricsInfluxDBDatabaseFlag.Name)
 	cfg.MetricsInfluxDBUsername = ctx.GlobalString(flags.MetricsInfluxDBUsernameFlag.Name)
-	cfg.MetricsInfluxDBPassword = ctx.GlobalString(flags.MetricsInfluxDBPasswordFlag.Name)
+	cfg.MetricsInfluxDBPassword = configSecret(ctx, flags.MetricsInfluxDBPasswordFlag, flags.MetricsInfluxDBPasswordFileFlag)
 
 	return &cfg
 }
+
+// channelEndpoints returns the endpoints of a channel, falling back to the
+// given defaults when the per-channel overrides are not set
+func channelEndpoints(ctx *cli.Context, readFlag, writeFlag cli.StringFlag, read, write string) endpoints {
+	if url := ctx.GlobalString(readFlag.Name); url != "" {
+		read = url
+	}
+	if url := ctx.GlobalString(writeFlag.Name); url != "" {
+		write = url
+	}
+	return endpoints{read: read, write: write}
+}
+
+// configSecret reads a secret of the config, exiting when it cannot be read
+func configSecret(ctx *cli.Context, inline, file cli.StringFlag) string {
+	value, err := flags.Secret(ctx, inline, file)
+	if err != nil {
+		log.Crit(err.Error())
+	}
+	return value
+}
+
+// configAddress reads a contract address of the config, exiting when the
+// deployments do not hold it
+func configAddress(ctx *cli.Context, flag cli.StringFlag, deployments flags.Deployments, contract string) common.Address {
+	address, err := flags.ContractAddress(ctx, flag, deployments, contract)
+	if err != nil {
+		log.Crit(err.Error())
+	}
+	return address
+}
+
+// configSecretFile reads a secret of the config that is only read from a
+// file, exiting when it cannot be read
+func configSecretFile(ctx *cli.Context, file cli.StringFlag) string {
+	value, err := flags.SecretFile(ctx, file)
+	if err != nil {
+		log.Crit(err.Error())
+	}
+	return value
+}
+
+// errIncompleteCredentials represents the error when only one of the API
+// key and secret of the price source is configured
+var errIncompleteCredentials = errors.New("incomplete token price API credentials")
+
+// errUnsignedBackend represents the error when API credentials are
+// configured for a price source that does not sign its requests
+var errUnsignedBackend = errors.New("token price backend does not sign its requests")
+
+// validateTokenPriceCredentials makes sure that the requests of the price
+// source are either signed with both an API key and secret, or unsigned
+func (c *Config) validateTokenPriceCredentials() error {
+	if (c.tokenPriceAPIKey == "") != (c.tokenPriceAPISecret == "") {
+		return fmt.Errorf("%w: both --%s and --%s must be set", errIncompleteCredentials,
+			flags.TokenPriceAPIKeyFileFlag.Name, flags.TokenPriceAPISecretFileFlag.Name)
+	}
+	if c.tokenPriceAPIKey == "" {
+		return nil
+	}
+	for _, backend := range c.tokenPricerBackends {
+		if backend == tokenprice.BackendBybit {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errUnsignedBackend, strings.Join(c.tokenPricerBackends, ","))
+}
+
+// tokenPriceBackend creates the backend that the token prices are read
+// from. Several backends are combined by a MedianPricer, which needs the
+// quorum of them to return a price.
+func (c *Config) tokenPriceBackend() (tokenprice.Backend, error) {
+	backends := make([]tokenprice.Backend, 0, len(c.tokenPricerBackends))
+	for _, name := range c.tokenPricerBackends {
+		url, _ := c.tokenPriceSource(name)
+		backend, err := tokenprice.NewBackend(name, url)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	if len(backends) == 1 && c.tokenPricerQuorum <= 1 {
+		return backends[0], nil
+	}
+	return tokenprice.NewMedianPricer(backends, c.tokenPricerQuorum, c.tokenPricerAggregation)
+}
+
+// tokenPriceSource returns the URL of the token price backend along with
+// the name of the flag that sets it
+func (c *Config) tokenPriceSource(backend string) (string, string) {
+	if backend == tokenprice.BackendBinance {
+		return c.binanceBackendURL, flags.BinanceBackendURLFlag.Name
+	}
+	return c.bybitBackendURL, flags.BybitBackendURL.Name
+}
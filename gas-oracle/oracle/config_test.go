@@ -0,0 +1,318 @@
+package oracle
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
+	"github.com/mantlenetworkio/mantle/gas-oracle/flags"
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/urfave/cli"
+)
+
+// newTestContext returns a cli.Context with all of the flags registered
+// and parsed from args. A private key is always configured.
+func newTestContext(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	args = append([]string{"--private-key", hexutil.Encode(crypto.FromECDSA(key))}, args...)
+	return parseTestContext(t, args...)
+}
+
+// parseTestContext returns a cli.Context with all of the flags registered
+// and parsed from args alone
+func parseTestContext(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet("gas-oracle", flag.ContinueOnError)
+	for _, f := range flags.Flags {
+		f.Apply(set)
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+func TestPrivateKeyFile(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte(hexutil.Encode(crypto.FromECDSA(key))+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewConfig(parseTestContext(t, "--private-key-file", path))
+	if cfg.privateKey == nil || !cfg.privateKey.Equal(key) {
+		t.Fatal("expected the private key of the file")
+	}
+}
+
+func TestChannelEndpoints(t *testing.T) {
+	l1 := "http://l1:8545"
+	l2 := "http://l2:9545"
+
+	cfg := NewConfig(newTestContext(t,
+		"--ethereum-http-url", l1,
+		"--layer-two-http-url", l2,
+	))
+	expect := map[string]endpoints{
+		l1BaseFeeChannel:  {read: l1, write: l2},
+		l2GasPriceChannel: {read: l2, write: l2},
+		daFeeChannel:      {read: l1, write: l2},
+	}
+	got := map[string]endpoints{
+		l1BaseFeeChannel:  cfg.l1BaseFeeEndpoints,
+		l2GasPriceChannel: cfg.l2GasPriceEndpoints,
+		daFeeChannel:      cfg.daFeeEndpoints,
+	}
+	for name, e := range expect {
+		if got[name] != e {
+			t.Fatalf("%s: expected default endpoints %v, got %v", name, e, got[name])
+		}
+	}
+
+	cfg = NewConfig(newTestContext(t,
+		"--ethereum-http-url", l1,
+		"--layer-two-http-url", l2,
+		"--l1-base-fee-read-http-url", "http://l1-replica:8545",
+		"--l1-base-fee-write-http-url", "http://l2-submit:9545",
+		"--l2-gas-price-read-http-url", "http://l2-replica:9545",
+		"--da-fee-write-http-url", "http://l2-submit:9545",
+	))
+	expect = map[string]endpoints{
+		l1BaseFeeChannel:  {read: "http://l1-replica:8545", write: "http://l2-submit:9545"},
+		l2GasPriceChannel: {read: "http://l2-replica:9545", write: l2},
+		daFeeChannel:      {read: l1, write: "http://l2-submit:9545"},
+	}
+	got = map[string]endpoints{
+		l1BaseFeeChannel:  cfg.l1BaseFeeEndpoints,
+		l2GasPriceChannel: cfg.l2GasPriceEndpoints,
+		daFeeChannel:      cfg.daFeeEndpoints,
+	}
+	for name, e := range expect {
+		if got[name] != e {
+			t.Fatalf("%s: expected endpoints %v, got %v", name, e, got[name])
+		}
+	}
+}
+
+func TestBackoffPolicies(t *testing.T) {
+	cfg := NewConfig(newTestContext(t))
+	if cfg.receiptBackoff != defaultReceiptBackoff || cfg.connectBackoff != defaultConnectBackoff {
+		t.Fatalf("unexpected default policies %v and %v", cfg.receiptBackoff, cfg.connectBackoff)
+	}
+
+	cfg = NewConfig(newTestContext(t,
+		"--receipt-backoff", "max=5s,multiplier=2,jitter=0.2",
+		"--connect-backoff", "multiplier=0",
+	))
+	expect := backoff.Policy{Initial: 300 * time.Millisecond, Max: 5 * time.Second, Multiplier: 2, Jitter: 0.2}
+	if cfg.receiptBackoff != expect {
+		t.Fatalf("expected %v, got %v", expect, cfg.receiptBackoff)
+	}
+	// An invalid policy falls back to the default
+	if cfg.connectBackoff != defaultConnectBackoff {
+		t.Fatalf("expected the default policy, got %v", cfg.connectBackoff)
+	}
+}
+
+func TestDirectionalSignificanceFactors(t *testing.T) {
+	// The directional factors are unset by default
+	cfg := NewConfig(newTestContext(t))
+	if cfg.l2GasPriceSignificanceFactorUp != nil || cfg.l2GasPriceSignificanceFactorDown != nil {
+		t.Fatal("expected no directional significance factors")
+	}
+
+	// and a factor of zero is set rather than ignored
+	cfg = NewConfig(newTestContext(t, "--significant-factor-up", "0", "--significant-factor-down", "0.2"))
+	if cfg.l2GasPriceSignificanceFactorUp == nil || *cfg.l2GasPriceSignificanceFactorUp != 0 {
+		t.Fatalf("expected an up factor of 0, got %v", cfg.l2GasPriceSignificanceFactorUp)
+	}
+	if cfg.l2GasPriceSignificanceFactorDown == nil || *cfg.l2GasPriceSignificanceFactorDown != 0.2 {
+		t.Fatalf("expected a down factor of 0.2, got %v", cfg.l2GasPriceSignificanceFactorDown)
+	}
+}
+
+func TestEnabledChannelInputs(t *testing.T) {
+	cfg := NewConfig(newTestContext(t, "--enable-l1-base-fee", "--enable-l2-gas-price", "--enable-da-gas-price"))
+	if err := cfg.validateChannelInputs(); err != nil {
+		t.Fatalf("unexpected error with the default inputs: %v", err)
+	}
+
+	// A disabled channel does not need its inputs
+	cfg = NewConfig(newTestContext(t, "--da-fee-contract-address", ""))
+	if err := cfg.validateChannelInputs(); err != nil {
+		t.Fatalf("unexpected error for a disabled channel: %v", err)
+	}
+
+	for _, test := range []struct {
+		args    []string
+		message string
+	}{
+		{
+			[]string{"--enable-da-gas-price", "--da-fee-contract-address", ""},
+			"da-fee needs the DA fee contract address (--da-fee-contract-address)",
+		},
+		{
+			[]string{"--enable-l1-base-fee", "--bybitBackendURL", ""},
+			"l1-base-fee needs a token price source (--bybitBackendURL)",
+		},
+		{
+			[]string{"--enable-l1-base-fee", "--token-pricer-backend", "binance", "--binance-backend-url", ""},
+			"l1-base-fee needs a token price source (--binance-backend-url)",
+		},
+		{
+			[]string{"--enable-l2-gas-price", "--gas-price-oracle-address", ""},
+			"l2-gas-price needs the gas price oracle address (--gas-price-oracle-address)",
+		},
+		{
+			[]string{"--enable-da-gas-price", "--ethereum-http-url", ""},
+			"da-fee needs an endpoint to read from",
+		},
+	} {
+		_, err := NewGasPriceOracle(NewConfig(newTestContext(t, test.args...)))
+		if !errors.Is(err, errMissingChannelInput) {
+			t.Fatalf("%v: expected a missing input, got %v", test.args, err)
+		}
+		if !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%v: expected %q in %q", test.args, test.message, err)
+		}
+	}
+}
+
+func TestTokenPriceCredentials(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	secretFile := filepath.Join(dir, "secret")
+	for path, value := range map[string]string{keyFile: "key\n", secretFile: "secret\n"} {
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := NewConfig(newTestContext(t, "--token-price-api-key-file", keyFile, "--token-price-api-secret-file", secretFile))
+	if cfg.tokenPriceAPIKey != "key" || cfg.tokenPriceAPISecret != "secret" {
+		t.Fatalf("expected the credentials of the files, got %q and %q", cfg.tokenPriceAPIKey, cfg.tokenPriceAPISecret)
+	}
+	if err := cfg.validateTokenPriceCredentials(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewConfig(newTestContext(t)).validateTokenPriceCredentials(); err != nil {
+		t.Fatal(err)
+	}
+	cfg = NewConfig(newTestContext(t, "--token-price-api-key-file", keyFile))
+	if err := cfg.validateTokenPriceCredentials(); !errors.Is(err, errIncompleteCredentials) {
+		t.Fatalf("expected incomplete credentials, got %v", err)
+	}
+	// Only the Bybit backend signs its requests
+	cfg = NewConfig(newTestContext(t, "--token-pricer-backend", "binance",
+		"--token-price-api-key-file", keyFile, "--token-price-api-secret-file", secretFile))
+	if err := cfg.validateTokenPriceCredentials(); !errors.Is(err, errUnsignedBackend) {
+		t.Fatalf("expected an unsigned backend, got %v", err)
+	}
+	// unless Bybit is one of the backends
+	cfg = NewConfig(newTestContext(t, "--token-pricer-backend", "binance,bybit",
+		"--token-price-api-key-file", keyFile, "--token-price-api-secret-file", secretFile))
+	if err := cfg.validateTokenPriceCredentials(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenPricerBackend(t *testing.T) {
+	_, err := NewGasPriceOracle(NewConfig(newTestContext(t, "--token-pricer-backend", "kraken")))
+	if !errors.Is(err, tokenprice.ErrUnknownBackend) {
+		t.Fatalf("expected an unknown backend, got %v", err)
+	}
+	cfg := NewConfig(newTestContext(t, "--token-pricer-backend", "binance"))
+	if url, flag := cfg.tokenPriceSource(tokenprice.BackendBinance); url != "https://api.binance.com" || flag != "binance-backend-url" {
+		t.Fatalf("expected the binance source, got %s of --%s", url, flag)
+	}
+	backend, err := cfg.tokenPriceBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backend.(*tokenprice.BinanceBackend); !ok {
+		t.Fatalf("expected a single binance backend, got %T", backend)
+	}
+}
+
+func TestTokenPricerQuorum(t *testing.T) {
+	cfg := NewConfig(newTestContext(t, "--token-pricer-backend", "bybit, binance", "--token-pricer-quorum", "2"))
+	if strings.Join(cfg.tokenPricerBackends, ",") != "bybit,binance" {
+		t.Fatalf("expected both backends, got %v", cfg.tokenPricerBackends)
+	}
+	backend, err := cfg.tokenPriceBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backend.(*tokenprice.MedianPricer); !ok {
+		t.Fatalf("expected a median of the backends, got %T", backend)
+	}
+
+	_, err = NewGasPriceOracle(NewConfig(newTestContext(t, "--token-pricer-backend", "bybit,binance", "--token-pricer-quorum", "3")))
+	if !errors.Is(err, tokenprice.ErrNoQuorum) {
+		t.Fatalf("expected an unreachable quorum, got %v", err)
+	}
+	_, err = NewGasPriceOracle(NewConfig(newTestContext(t, "--token-pricer-backend", "bybit,binance", "--token-pricer-aggregation", "mode")))
+	if !errors.Is(err, tokenprice.ErrUnknownAggregation) {
+		t.Fatalf("expected an unknown aggregation, got %v", err)
+	}
+}
+
+func TestDeploymentAddresses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	deployments := `{
+		"BVM_GasPriceOracle": "0x4200000000000000000000000000000000000abc",
+		"BVM_EigenDataLayrFee": "0x1Edd37fc504513bAa00D782E52478d0a3f675553"
+	}`
+	if err := os.WriteFile(path, []byte(deployments), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewConfig(newTestContext(t, "--deployments", path, "--enable-da-gas-price"))
+	if cfg.gasPriceOracleAddress != common.HexToAddress("0x4200000000000000000000000000000000000abc") {
+		t.Fatalf("expected the gas price oracle of the deployments, got %s", cfg.gasPriceOracleAddress)
+	}
+	if cfg.daFeeContractAddress != common.HexToAddress("0x1Edd37fc504513bAa00D782E52478d0a3f675553") {
+		t.Fatalf("expected the DA fee contract of the deployments, got %s", cfg.daFeeContractAddress)
+	}
+
+	// An address flag overrides the deployments
+	cfg = NewConfig(newTestContext(t, "--deployments", path, "--enable-da-gas-price",
+		"--gas-price-oracle-address", "0x420000000000000000000000000000000000000F"))
+	if cfg.gasPriceOracleAddress != common.HexToAddress("0x420000000000000000000000000000000000000F") {
+		t.Fatalf("expected the gas price oracle of the flag, got %s", cfg.gasPriceOracleAddress)
+	}
+	if cfg.daFeeContractAddress != common.HexToAddress("0x1Edd37fc504513bAa00D782E52478d0a3f675553") {
+		t.Fatalf("expected the DA fee contract of the deployments, got %s", cfg.daFeeContractAddress)
+	}
+
+	// The gas price oracles that follow the first one are its shadows
+	cfg = NewConfig(newTestContext(t, "--deployments", path,
+		"--gas-price-oracle-address", "0x420000000000000000000000000000000000000F, 0x4200000000000000000000000000000000000abc"))
+	if cfg.gasPriceOracleAddress != common.HexToAddress("0x420000000000000000000000000000000000000F") {
+		t.Fatalf("expected the first gas price oracle of the flag, got %s", cfg.gasPriceOracleAddress)
+	}
+	if len(cfg.shadowGasPriceOracleAddresses) != 1 || cfg.shadowGasPriceOracleAddresses[0] != common.HexToAddress("0x4200000000000000000000000000000000000abc") {
+		t.Fatalf("expected the second gas price oracle of the flag as a shadow, got %v", cfg.shadowGasPriceOracleAddresses)
+	}
+
+	// The DA fee contract is only read for an enabled DA fee channel
+	cfg = NewConfig(newTestContext(t, "--deployments", path))
+	if cfg.daFeeContractAddress != common.HexToAddress(flags.DaFeeContractAddressFlag.Value) {
+		t.Fatalf("expected the default DA fee contract, got %s", cfg.daFeeContractAddress)
+	}
+}
@@ -3,22 +3,19 @@ package oracle
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
 )
 
-func wrapUpdateDaFee(daBackend *bindings.BVMEigenDataLayrFee, l2Backend DeployContractBackend, cfg *Config) (func() error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
-
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
+func wrapUpdateDaFee(l1Backend bind.ContractBackend, l2Backend DeployContractBackend, cfg *Config, models *daFeeModelSwitch, quotes tokenQuoter, guards channelGuards) (func() error, error) {
+	opts, err := cfg.transactor()
 	if err != nil {
 		return nil, err
 	}
@@ -37,51 +34,122 @@ func wrapUpdateDaFee(daBackend *bindings.BVMEigenDataLayrFee, l2Backend DeployCo
 	if err != nil {
 		return nil, err
 	}
+	shadows, err := newShadowOracles(daFeeChannel, cfg, l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	daContract, err := bindings.NewBVMEigenDataLayrFee(cfg.daFeeContractAddress, l1Backend)
+	if err != nil {
+		return nil, err
+	}
 	return func() error {
-
+		l2Block, err := headNumber(guards.deadline.context(), l2Backend)
+		if err != nil {
+			return err
+		}
+		guards.trace.l2Block(l2Block)
 		currentDaFee, err := contract.DaGasPrice(&bind.CallOpts{
-			Context: context.Background(),
+			Context:     guards.deadline.context(),
+			BlockNumber: l2Block,
 		})
 		if err != nil {
 			return err
 		}
-		daFee, err := daBackend.GetRollupFee(&bind.CallOpts{
-			Context: context.Background(),
-		})
+		l1Header, err := l1Backend.HeaderByNumber(guards.deadline.context(), nil)
+		if err != nil {
+			return err
+		}
+		l1Block := l1Header.Number
+		guards.trace.l1Block(l1Block)
+		// The model in use reads the inputs that it needs at the L1 block
+		model := models.current()
+		guards.trace.input("da_fee_model", model.Name())
+		inputs := &daFeeReader{header: l1Header, contract: daContract, backend: l1Backend, quotes: quotes, trace: guards.trace}
+		daFee, err := model.Fee(guards.deadline.context(), inputs)
 		if err != nil {
 			return err
 		}
-		if !isDifferenceSignificant(currentDaFee.Uint64(), daFee.Uint64(), cfg.daFeeSignificanceFactor) {
-			log.Debug("non significant da fee update", "da", daFee, "current", currentDaFee)
+		guards.stuck.observed(currentDaFee)
+		reportCurrent(daFeeChannel, currentDaFee)
+		guards.drops.observed(currentDaFee)
+		guards.trace.input("current_da_fee", currentDaFee)
+		guards.trace.input("da_fee", daFee)
+		daFee = roundTo(daFee, cfg.daFeeRoundTo)
+		target := daFee
+		daFee = clampDaFee(daFee, cfg.daFeeMin, cfg.daFeeMax)
+		reference := guards.sent.reference(daFeeChannel, currentDaFee)
+		if reference != currentDaFee {
+			guards.trace.input("last_sent_da_fee", reference)
+		}
+		factor := guards.emergency.significanceFactor(cfg.daFeeSignificanceFactor)
+		factor = guards.reversals.significanceFactor(factor, currentDaFee, daFee)
+		if !isDifferenceSignificant(reference.Uint64(), daFee.Uint64(), factor) {
+			log.Debug("non significant da fee update", "da", daFee, "current", reference)
+			guards.trace.act(actionSkip, reasonBelowSignificance, "not significant")
+			return nil
+		}
+		// Without a significance factor the values that rounded to the
+		// same one are not sent either
+		if reference.Cmp(daFee) == 0 {
+			log.Debug("da fee did not change", "da", daFee)
+			guards.trace.act(actionSkip, reasonUnchanged, "not changed")
 			return nil
 		}
 
-		// Use the configured gas price if it is set,
-		// otherwise use gas estimation
-		if cfg.gasPrice != nil {
-			opts.GasPrice = cfg.gasPrice
-		} else {
-			gasPrice, err := l2Backend.SuggestGasPrice(opts.Context)
-			if err != nil {
-				return err
-			}
-			opts.GasPrice = gasPrice
+		// A channel that was just enabled may ease from a stale value
+		daFee = guards.grace.next(currentDaFee, daFee)
+		if cfg.observes(daFeeChannel) {
+			observe(daFeeChannel, guards.trace, "da_fee", daFee)
+			return nil
+		}
+		if err := guards.drops.check(currentDaFee, daFee); err != nil {
+			return err
+		}
+		if guards.deadline.exceeded() {
+			return errEpochAborted
+		}
+
+		// The update is built and sent under the context of the loop
+		opts.Context = guards.deadline.epoch()
+		if err := txFees(opts.Context, l2Backend, cfg, opts); err != nil {
+			return err
+		}
+		if cfg.dryRun {
+			return dryRun(daFeeChannel, guards.trace, cfg.gasPriceOracleAddress, opts, "setDAGasPrice", "da_fee", daFee)
 		}
 
 		tx, err := contract.SetDAGasPrice(opts, daFee)
+		if err == nil {
+			log.Debug("updating da fee", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
+				"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+			if err = l2Backend.SendTransaction(opts.Context, tx); err != nil {
+				reportSendFailure(daFeeChannel, err)
+				err = fmt.Errorf("cannot update base fee: %w", err)
+			}
+		}
+		reportOracleSend(daFeeChannel, cfg.gasPriceOracleAddress, err)
+		// The shadow contracts are updated whether or not the primary was
+		shadows.send(opts.Context, func(shadow *bindings.BVMGasPriceOracle) (*types.Transaction, error) {
+			return shadow.SetDAGasPrice(opts, daFee)
+		})
 		if err != nil {
 			return err
 		}
-		log.Debug("updating da fee", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
-			"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
-		if err := l2Backend.SendTransaction(context.Background(), tx); err != nil {
-			return fmt.Errorf("cannot update base fee: %w", err)
-		}
-		log.Info("L1 base fee transaction sent", "hash", tx.Hash().Hex(), "baseFee", daFee)
+		log.Info("DA fee transaction sent", "epoch", guards.trace.epoch(), "hash", tx.Hash().Hex(), "daFee", daFee,
+			"l1-block", l1Block, "l2-block", l2Block)
+		reportBlocks(daFeeChannel, l1Block, l2Block)
+		reportWritten(daFeeChannel, daFee)
+		guards.sent.sent(daFeeChannel, daFee)
+		guards.trace.output("da_fee", daFee)
+		guards.trace.output("tx_hash", tx.Hash().Hex())
+		guards.trace.act(actionUpdate, updateReason(target, daFee), "")
+		guards.emergency.updated()
+		guards.stuck.wrote(currentDaFee, daFee)
+		guards.reversals.wrote(currentDaFee, daFee)
 
 		if cfg.waitForReceipt {
 			// Wait for the receipt
-			receipt, err := waitForReceipt(l2Backend, tx)
+			receipt, err := waitForReceipt(opts.Context, l2Backend, tx, cfg.receiptBackoff)
 			if err != nil {
 				return err
 			}
@@ -92,3 +160,32 @@ func wrapUpdateDaFee(daBackend *bindings.BVMEigenDataLayrFee, l2Backend DeployCo
 		return nil
 	}, nil
 }
+
+// clampDaFee bounds the DA fee to [min, max] to protect users from a
+// pathological rollup fee. A zero bound is not applied.
+func clampDaFee(daFee *big.Int, min, max uint64) *big.Int {
+	var bound *big.Int
+	switch {
+	case max > 0 && daFee.Cmp(new(big.Int).SetUint64(max)) > 0:
+		bound = new(big.Int).SetUint64(max)
+	case min > 0 && daFee.Cmp(new(big.Int).SetUint64(min)) < 0:
+		bound = new(big.Int).SetUint64(min)
+	default:
+		return daFee
+	}
+	log.Warn("DA fee out of bounds, clamping", "da", daFee, "clamped", bound, "min", min, "max", max)
+	daFeeClampedCounter().Inc(1)
+	return bound
+}
+
+// validateDaFeeBounds makes sure that the DA fee bounds are consistent
+func (c *Config) validateDaFeeBounds() error {
+	if c.daFeeMin > 0 && c.daFeeMax > 0 && c.daFeeMin > c.daFeeMax {
+		return fmt.Errorf("DA fee min %d is above the max %d", c.daFeeMin, c.daFeeMax)
+	}
+	return nil
+}
+
+func daFeeClampedCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("da_fee/clamped", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,216 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// errInvalidDAFeeExpression represents the error when a DA fee expression
+// cannot be parsed
+var errInvalidDAFeeExpression = errors.New("invalid DA fee expression")
+
+// daFeeVariable reads an input of a DA fee expression
+type daFeeVariable func(DAFeeInputs, context.Context) (*big.Int, error)
+
+// daFeeVariables are the inputs that a DA fee expression can refer to,
+// besides the prices of the tracked tokens
+var daFeeVariables = map[string]daFeeVariable{
+	"rollup_fee":    DAFeeInputs.RollupFee,
+	"l1_base_fee":   DAFeeInputs.L1BaseFee,
+	"blob_base_fee": DAFeeInputs.BlobBaseFee,
+}
+
+// tokenPriceVariable returns the name of the variable of the price of a
+// tracked token, e.g. price_mntusdt for MNTUSDT
+func tokenPriceVariable(symbol string) string {
+	return "price_" + strings.ToLower(symbol)
+}
+
+// expressionVariables returns the variables of a DA fee expression, with
+// the price of every tracked symbol registered next to the fixed inputs
+func expressionVariables(symbols []string) map[string]daFeeVariable {
+	variables := make(map[string]daFeeVariable, len(daFeeVariables)+len(symbols))
+	for name, read := range daFeeVariables {
+		variables[name] = read
+	}
+	for _, symbol := range symbols {
+		symbol := symbol
+		variables[tokenPriceVariable(symbol)] = func(inputs DAFeeInputs, ctx context.Context) (*big.Int, error) {
+			return inputs.TokenPrice(ctx, symbol)
+		}
+	}
+	return variables
+}
+
+// daFeeExpression is a compiled DA fee expression. Only the inputs that it
+// refers to are read.
+type daFeeExpression func(ctx context.Context, inputs DAFeeInputs) (*big.Int, error)
+
+// parseDAFeeExpression compiles an integer expression over the DA fee
+// variables and the prices of the tracked symbols with +, -, *, / and
+// parentheses, e.g. "l1_base_fee * 16 + rollup_fee / 2" or
+// "l1_base_fee * price_ethusdt / price_mntusdt". Division rounds down.
+func parseDAFeeExpression(source string, symbols []string) (daFeeExpression, error) {
+	p := &exprParser{source: source, variables: expressionVariables(symbols)}
+	p.next()
+	expr, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		return nil, p.errorf("unexpected %q", p.token)
+	}
+	return expr, nil
+}
+
+// exprParser is a recursive descent parser over the tokens of an
+// expression
+type exprParser struct {
+	source    string
+	variables map[string]daFeeVariable
+	pos       int
+	token     string
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s in %q", errInvalidDAFeeExpression, fmt.Sprintf(format, args...), p.source)
+}
+
+// next moves to the next token, which is empty at the end of the source
+func (p *exprParser) next() {
+	for p.pos < len(p.source) && p.source[p.pos] == ' ' {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.source) {
+		p.token = ""
+		return
+	}
+	word := func(r byte) bool {
+		return r == '_' || unicode.IsLetter(rune(r)) || unicode.IsDigit(rune(r))
+	}
+	if word(p.source[p.pos]) {
+		for p.pos < len(p.source) && word(p.source[p.pos]) {
+			p.pos++
+		}
+	} else {
+		p.pos++
+	}
+	p.token = p.source[start:p.pos]
+}
+
+// sum parses terms joined by + and -
+func (p *exprParser) sum() (daFeeExpression, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "+" || p.token == "-" {
+		op := p.token
+		p.next()
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			left = binaryExpr(left, right, func(z, x, y *big.Int) (*big.Int, error) { return z.Add(x, y), nil })
+		} else {
+			left = binaryExpr(left, right, func(z, x, y *big.Int) (*big.Int, error) { return z.Sub(x, y), nil })
+		}
+	}
+	return left, nil
+}
+
+// product parses factors joined by * and /
+func (p *exprParser) product() (daFeeExpression, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "*" || p.token == "/" {
+		op := p.token
+		p.next()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		if op == "*" {
+			left = binaryExpr(left, right, func(z, x, y *big.Int) (*big.Int, error) { return z.Mul(x, y), nil })
+		} else {
+			left = binaryExpr(left, right, func(z, x, y *big.Int) (*big.Int, error) {
+				if y.Sign() == 0 {
+					return nil, errors.New("DA fee expression divides by zero")
+				}
+				return z.Quo(x, y), nil
+			})
+		}
+	}
+	return left, nil
+}
+
+// factor parses a number, a variable or a parenthesized expression
+func (p *exprParser) factor() (daFeeExpression, error) {
+	token := p.token
+	switch {
+	case token == "":
+		return nil, p.errorf("unexpected end")
+	case token == "(":
+		p.next()
+		expr, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if p.token != ")" {
+			return nil, p.errorf("missing )")
+		}
+		p.next()
+		return expr, nil
+	case unicode.IsDigit(rune(token[0])):
+		value, ok := new(big.Int).SetString(token, 10)
+		if !ok {
+			return nil, p.errorf("invalid number %q", token)
+		}
+		p.next()
+		return func(context.Context, DAFeeInputs) (*big.Int, error) {
+			return new(big.Int).Set(value), nil
+		}, nil
+	}
+
+	read, ok := p.variables[token]
+	if !ok {
+		known := make([]string, 0, len(p.variables))
+		for name := range p.variables {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return nil, p.errorf("unknown %q, expected a number, ( or one of %s", token, strings.Join(known, ", "))
+	}
+	p.next()
+	return func(ctx context.Context, inputs DAFeeInputs) (*big.Int, error) {
+		value, err := read(inputs, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", token, err)
+		}
+		return new(big.Int).Set(value), nil
+	}, nil
+}
+
+// binaryExpr evaluates both operands and combines them with op
+func binaryExpr(left, right daFeeExpression, op func(z, x, y *big.Int) (*big.Int, error)) daFeeExpression {
+	return func(ctx context.Context, inputs DAFeeInputs) (*big.Int, error) {
+		x, err := left(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+		y, err := right(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+		return op(new(big.Int), x, y)
+	}
+}
@@ -0,0 +1,308 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+)
+
+const (
+	// daFeeModelRollup uses the rollup fee of the DA fee contract
+	daFeeModelRollup = "rollup"
+	// daFeeModelCalldata prices the data as L1 calldata
+	daFeeModelCalldata = "calldata"
+	// daFeeModelBlob prices the data as L1 blobs when the chain has them
+	// and they are cheaper than calldata
+	daFeeModelBlob = "blob"
+	// daFeeModelExpression evaluates the configured expression
+	daFeeModelExpression = "expression"
+)
+
+var (
+	// errUnknownDAFeeModel represents the error when the DA fee model is
+	// not one of the known models
+	errUnknownDAFeeModel = errors.New("unknown DA fee model")
+	// errNoBlobBaseFee represents the error when the L1 chain does not
+	// report a blob base fee
+	errNoBlobBaseFee = errors.New("no blob base fee")
+	// errNoTokenPrice represents the error when the price of a symbol is
+	// not tracked
+	errNoTokenPrice = errors.New("no token price")
+)
+
+// tokenPriceUnit scales the token prices that the DA fee models read to
+// 18 decimals, as the expressions are evaluated over integers
+var tokenPriceUnit = big.NewFloat(1e18)
+
+// DAFeeInputs are the inputs that a DA fee model can read. They are read
+// at the same L1 block, and only when a model asks for them.
+type DAFeeInputs interface {
+	// RollupFee is the rollup fee of the DA fee contract
+	RollupFee(ctx context.Context) (*big.Int, error)
+	// L1BaseFee is the base fee of the L1 block
+	L1BaseFee(ctx context.Context) (*big.Int, error)
+	// BlobBaseFee is the blob base fee of L1, errNoBlobBaseFee before
+	// EIP-4844
+	BlobBaseFee(ctx context.Context) (*big.Int, error)
+	// TokenPrice is the price of a tracked symbol with 18 decimals
+	TokenPrice(ctx context.Context, symbol string) (*big.Int, error)
+}
+
+// DAFeeModel computes the DA fee from its inputs
+type DAFeeModel interface {
+	// Name is the name that selects the model
+	Name() string
+	// Fee returns the DA fee for the inputs
+	Fee(ctx context.Context, inputs DAFeeInputs) (*big.Int, error)
+}
+
+// rollupModel uses the rollup fee of the DA fee contract as is
+type rollupModel struct{}
+
+func (rollupModel) Name() string { return daFeeModelRollup }
+
+func (rollupModel) Fee(ctx context.Context, inputs DAFeeInputs) (*big.Int, error) {
+	return inputs.RollupFee(ctx)
+}
+
+// calldataModel prices every byte of data at the L1 gas of a non-zero
+// calldata byte
+type calldataModel struct{}
+
+func (calldataModel) Name() string { return daFeeModelCalldata }
+
+func (calldataModel) Fee(ctx context.Context, inputs DAFeeInputs) (*big.Int, error) {
+	baseFee, err := inputs.L1BaseFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(baseFee, new(big.Int).SetUint64(params.TxDataNonZeroGasEIP2028)), nil
+}
+
+// blobModel prices every byte of data at one blob gas when L1 has blobs
+// and that is cheaper than calldata, and as calldata otherwise
+type blobModel struct{}
+
+func (blobModel) Name() string { return daFeeModelBlob }
+
+func (blobModel) Fee(ctx context.Context, inputs DAFeeInputs) (*big.Int, error) {
+	calldata, err := calldataModel{}.Fee(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	blobBaseFee, err := inputs.BlobBaseFee(ctx)
+	if errors.Is(err, errNoBlobBaseFee) {
+		log.Debug("no blob base fee, pricing DA as calldata")
+		return calldata, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if blobBaseFee.Cmp(calldata) < 0 {
+		return blobBaseFee, nil
+	}
+	return calldata, nil
+}
+
+// expressionModel evaluates an expression over the inputs
+type expressionModel struct {
+	source string
+	expr   daFeeExpression
+}
+
+func (m *expressionModel) Name() string { return daFeeModelExpression }
+
+func (m *expressionModel) Fee(ctx context.Context, inputs DAFeeInputs) (*big.Int, error) {
+	fee, err := m.expr(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if fee.Sign() < 0 {
+		return nil, fmt.Errorf("DA fee expression %q is negative: %d", m.source, fee)
+	}
+	return fee, nil
+}
+
+// newDAFeeModel returns the model of the name. The expression, which can
+// refer to the prices of the symbols, is only used by the expression model.
+func newDAFeeModel(name, expression string, symbols []string) (DAFeeModel, error) {
+	switch name {
+	case "", daFeeModelRollup:
+		return rollupModel{}, nil
+	case daFeeModelCalldata:
+		return calldataModel{}, nil
+	case daFeeModelBlob:
+		return blobModel{}, nil
+	case daFeeModelExpression:
+		if expression == "" {
+			return nil, errors.New("the expression DA fee model requires a DA fee expression")
+		}
+		expr, err := parseDAFeeExpression(expression, symbols)
+		if err != nil {
+			return nil, err
+		}
+		return &expressionModel{source: expression, expr: expr}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownDAFeeModel, name)
+	}
+}
+
+// daFeeModelSwitch holds the DA fee model in use, which can be switched
+// while the oracle runs. A nil daFeeModelSwitch uses the rollup model.
+type daFeeModelSwitch struct {
+	mu    sync.RWMutex
+	model DAFeeModel
+}
+
+// newDAFeeModelSwitch creates a switch that starts with the model
+func newDAFeeModelSwitch(model DAFeeModel) *daFeeModelSwitch {
+	return &daFeeModelSwitch{model: model}
+}
+
+// current returns the model in use
+func (s *daFeeModelSwitch) current() DAFeeModel {
+	if s == nil {
+		return rollupModel{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.model
+}
+
+// set switches to the model, which applies from the next epoch on
+func (s *daFeeModelSwitch) set(model DAFeeModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.model.Name() != model.Name() {
+		log.Info("Switching the DA fee model", "from", s.model.Name(), "to", model.Name())
+	}
+	s.model = model
+}
+
+// daFeeReader reads the inputs of the DA fee models at an L1 block. Every
+// input is read at most once and recorded in the decision trace.
+type daFeeReader struct {
+	header   *types.Header
+	contract *bindings.BVMEigenDataLayrFee
+	backend  bind.ContractBackend
+	quotes   tokenQuoter
+	trace    *decisionTrace
+
+	rollupFee   *big.Int
+	blobBaseFee *big.Int
+	tokenPrices map[string]*big.Int
+}
+
+// tokenQuoter reads the cached price of a tracked symbol
+type tokenQuoter interface {
+	Quote(symbol string) (tokenprice.Quote, error)
+}
+
+func (r *daFeeReader) RollupFee(ctx context.Context) (*big.Int, error) {
+	if r.rollupFee != nil {
+		return r.rollupFee, nil
+	}
+	fee, err := r.contract.GetRollupFee(&bind.CallOpts{
+		Context:     ctx,
+		BlockNumber: r.header.Number,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.trace.input("rollup_fee", fee)
+	r.rollupFee = fee
+	return fee, nil
+}
+
+func (r *daFeeReader) L1BaseFee(ctx context.Context) (*big.Int, error) {
+	if r.header.BaseFee == nil {
+		return nil, errNoBaseFee
+	}
+	r.trace.input("l1_base_fee", r.header.BaseFee)
+	return r.header.BaseFee, nil
+}
+
+func (r *daFeeReader) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	if r.blobBaseFee != nil {
+		return r.blobBaseFee, nil
+	}
+	reader, ok := r.backend.(BlobBaseFeeReader)
+	if !ok {
+		return nil, errNoBlobBaseFee
+	}
+	fee, err := reader.BlobBaseFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.trace.input("blob_base_fee", fee)
+	r.blobBaseFee = fee
+	return fee, nil
+}
+
+// TokenPrice reads the price of the symbol from the token price basket. A
+// price that cannot be refreshed fails the epoch rather than pricing the DA
+// fee with an outdated one.
+func (r *daFeeReader) TokenPrice(ctx context.Context, symbol string) (*big.Int, error) {
+	if price, ok := r.tokenPrices[symbol]; ok {
+		return price, nil
+	}
+	if r.quotes == nil {
+		return nil, fmt.Errorf("%w: %s", errNoTokenPrice, symbol)
+	}
+	quote, err := r.quotes.Quote(symbol)
+	if err != nil {
+		return nil, err
+	}
+	price, _ := new(big.Float).Mul(big.NewFloat(quote.Price), tokenPriceUnit).Int(nil)
+	r.trace.input(tokenPriceVariable(symbol), price)
+	if r.tokenPrices == nil {
+		r.tokenPrices = make(map[string]*big.Int)
+	}
+	r.tokenPrices[symbol] = price
+	return price, nil
+}
+
+// BlobBaseFeeReader represents a backend that can read the blob base fee
+type BlobBaseFeeReader interface {
+	BlobBaseFee(ctx context.Context) (*big.Int, error)
+}
+
+// blobBaseFeeClient reads the blob base fee of L1 with eth_blobBaseFee
+type blobBaseFeeClient struct {
+	bind.ContractBackend
+	rpc *rpc.Client
+}
+
+// newBlobBaseFeeClient wraps the backend of the url to read the blob base
+// fee
+func newBlobBaseFeeClient(backend bind.ContractBackend, url string) (*blobBaseFeeClient, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &blobBaseFeeClient{ContractBackend: backend, rpc: client}, nil
+}
+
+// BlobBaseFee returns errNoBlobBaseFee when the node does not know the
+// method, as before EIP-4844
+func (c *blobBaseFeeClient) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	var fee hexutil.Big
+	err := c.rpc.CallContext(ctx, &fee, "eth_blobBaseFee")
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return nil, errNoBlobBaseFee
+	} else if err != nil {
+		return nil, err
+	}
+	return fee.ToInt(), nil
+}
@@ -0,0 +1,241 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDAFeeInputs serves fixed inputs, a nil blob base fee as before
+// EIP-4844
+type staticDAFeeInputs struct {
+	rollupFee, l1BaseFee, blobBaseFee *big.Int
+	tokenPrices                       map[string]*big.Int
+}
+
+func (s *staticDAFeeInputs) RollupFee(context.Context) (*big.Int, error) { return s.rollupFee, nil }
+func (s *staticDAFeeInputs) L1BaseFee(context.Context) (*big.Int, error) { return s.l1BaseFee, nil }
+func (s *staticDAFeeInputs) BlobBaseFee(context.Context) (*big.Int, error) {
+	if s.blobBaseFee == nil {
+		return nil, errNoBlobBaseFee
+	}
+	return s.blobBaseFee, nil
+}
+
+func (s *staticDAFeeInputs) TokenPrice(_ context.Context, symbol string) (*big.Int, error) {
+	price, ok := s.tokenPrices[symbol]
+	if !ok {
+		return nil, errNoTokenPrice
+	}
+	return price, nil
+}
+
+// staticQuotes serves fixed token prices, counting the reads
+type staticQuotes struct {
+	prices map[string]float64
+	reads  int
+}
+
+func (q *staticQuotes) Quote(symbol string) (tokenprice.Quote, error) {
+	q.reads++
+	price, ok := q.prices[symbol]
+	if !ok {
+		return tokenprice.Quote{}, errors.New("price source down")
+	}
+	return tokenprice.Quote{Symbol: symbol, Price: price}, nil
+}
+
+func TestDAFeeModels(t *testing.T) {
+	ctx := context.Background()
+	inputs := &staticDAFeeInputs{rollupFee: big.NewInt(1000), l1BaseFee: big.NewInt(10), blobBaseFee: big.NewInt(5)}
+	legacy := &staticDAFeeInputs{rollupFee: big.NewInt(1000), l1BaseFee: big.NewInt(10)}
+
+	for _, test := range []struct {
+		model, expression string
+		inputs            DAFeeInputs
+		want              int64
+	}{
+		{daFeeModelRollup, "", inputs, 1000},
+		{daFeeModelCalldata, "", inputs, 160},
+		{daFeeModelBlob, "", inputs, 5},
+		// Blobs are only used when they are cheaper
+		{daFeeModelBlob, "", &staticDAFeeInputs{l1BaseFee: big.NewInt(10), blobBaseFee: big.NewInt(200)}, 160},
+		// Without blobs the data is priced as calldata
+		{daFeeModelBlob, "", legacy, 160},
+		{daFeeModelExpression, "rollup_fee / 2 + l1_base_fee * (3 - blob_base_fee / 5)", inputs, 520},
+	} {
+		model, err := newDAFeeModel(test.model, test.expression, nil)
+		require.NoError(t, err)
+		require.Equal(t, test.model, model.Name())
+		fee, err := model.Fee(ctx, test.inputs)
+		require.NoError(t, err, test.model)
+		require.Equal(t, big.NewInt(test.want), fee, test.model)
+	}
+
+	// An expression over an input that is not available fails the epoch
+	model, err := newDAFeeModel(daFeeModelExpression, "blob_base_fee * 2", nil)
+	require.NoError(t, err)
+	_, err = model.Fee(ctx, legacy)
+	require.ErrorIs(t, err, errNoBlobBaseFee)
+	model, err = newDAFeeModel(daFeeModelExpression, "l1_base_fee - rollup_fee", nil)
+	require.NoError(t, err)
+	_, err = model.Fee(ctx, inputs)
+	require.Error(t, err)
+}
+
+func TestParseDAFeeExpression(t *testing.T) {
+	for _, source := range []string{"", "rollup_fee +", "(rollup_fee", "gas_price * 2", "rollup_fee 2", "1 % 2"} {
+		_, err := parseDAFeeExpression(source, nil)
+		require.ErrorIs(t, err, errInvalidDAFeeExpression, source)
+	}
+	_, err := newDAFeeModel(daFeeModelExpression, "", nil)
+	require.Error(t, err)
+	_, err = newDAFeeModel("eigen", "", nil)
+	require.ErrorIs(t, err, errUnknownDAFeeModel)
+
+	expr, err := parseDAFeeExpression("rollup_fee / 0", nil)
+	require.NoError(t, err)
+	_, err = expr(context.Background(), &staticDAFeeInputs{rollupFee: big.NewInt(1)})
+	require.Error(t, err)
+}
+
+func TestDAFeeExpressionReadsTokenPrices(t *testing.T) {
+	symbols := []string{"ETHUSDT", "MNTUSDT"}
+	model, err := newDAFeeModel(daFeeModelExpression, "l1_base_fee * price_ethusdt / price_mntusdt", symbols)
+	require.NoError(t, err)
+
+	// Every tracked symbol is read from the basket, once per epoch
+	quotes := &staticQuotes{prices: map[string]float64{"ETHUSDT": 2000, "MNTUSDT": 0.5}}
+	trace := newDecisionTrace(daFeeChannel, nil, nil)
+	var fee *big.Int
+	require.NoError(t, trace.wrap(func() error {
+		inputs := &daFeeReader{header: &types.Header{BaseFee: big.NewInt(1e9)}, quotes: quotes, trace: trace}
+		fee, err = model.Fee(context.Background(), inputs)
+		if err != nil {
+			return err
+		}
+		_, err = inputs.TokenPrice(context.Background(), "ETHUSDT")
+		return err
+	})())
+	require.Equal(t, big.NewInt(4_000e9), fee)
+	require.Equal(t, 2, quotes.reads)
+	decision, _ := trace.lastDecision()
+	require.Equal(t, new(big.Int).Mul(big.NewInt(2000), big.NewInt(1e18)), decision.Inputs["price_ethusdt"])
+	require.Equal(t, big.NewInt(5e17), decision.Inputs["price_mntusdt"])
+
+	// A price that cannot be read fails the epoch
+	delete(quotes.prices, "MNTUSDT")
+	inputs := &daFeeReader{header: &types.Header{BaseFee: big.NewInt(1e9)}, quotes: quotes}
+	_, err = model.Fee(context.Background(), inputs)
+	require.Error(t, err)
+	_, err = model.Fee(context.Background(), &daFeeReader{header: &types.Header{BaseFee: big.NewInt(1e9)}})
+	require.ErrorIs(t, err, errNoTokenPrice)
+
+	// Only the tracked symbols are variables
+	_, err = newDAFeeModel(daFeeModelExpression, "l1_base_fee * price_btcusdt", symbols)
+	require.ErrorIs(t, err, errInvalidDAFeeExpression)
+	_, err = newDAFeeModel(daFeeModelExpression, "l1_base_fee * price_ethusdt", nil)
+	require.ErrorIs(t, err, errInvalidDAFeeExpression)
+}
+
+func TestSwitchingDAFeeModelChangesFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	_, _, da, err := bindings.DeployBVMEigenDataLayrFee(opts, sim)
+	require.NoError(t, err)
+	sim.Commit()
+	_, err = da.Initialize(opts, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	_, err = da.SetRollupFee(opts, big.NewInt(1), big.NewInt(1000))
+	require.NoError(t, err)
+	sim.Commit()
+
+	// The inputs are read from the DA fee contract at the same L1 block
+	ctx := context.Background()
+	head, err := sim.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	trace := newDecisionTrace(daFeeChannel, nil, nil)
+	models := newDAFeeModelSwitch(rollupModel{})
+	daFee := func() *big.Int {
+		t.Helper()
+		var fee *big.Int
+		require.NoError(t, trace.wrap(func() error {
+			inputs := &daFeeReader{header: head, contract: da, backend: sim, trace: trace}
+			fee, err = models.current().Fee(ctx, inputs)
+			return err
+		})())
+		return fee
+	}
+	require.Equal(t, big.NewInt(1000), daFee())
+	decision, _ := trace.lastDecision()
+	require.Equal(t, big.NewInt(1000), decision.Inputs["rollup_fee"])
+
+	// The same inputs are priced by the model switched to on the metrics
+	// server
+	g := &GasPriceOracle{config: &Config{daFeeExpression: "rollup_fee*2"}, drainer: new(drainer), daFeeModel: models}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	post := func(query string) (int, map[string]string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/da-fee-model?"+query, nil))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		return rec.Code, body
+	}
+	code, body := post("model=expression&expression=rollup_fee*3")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]string{"model": daFeeModelExpression, "expression": "rollup_fee*3"}, body)
+	require.Equal(t, big.NewInt(3000), daFee())
+
+	// Without an expression the configured one is used
+	code, _ = post("model=expression")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, big.NewInt(2000), daFee())
+
+	code, _ = post("model=calldata")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, new(big.Int).Mul(head.BaseFee, big.NewInt(16)), daFee())
+	// The simulated chain has no blobs
+	code, _ = post("model=blob")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, new(big.Int).Mul(head.BaseFee, big.NewInt(16)), daFee())
+
+	// An invalid model keeps the one in use
+	code, _ = post("model=expression&expression=rollup_fee*")
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = post("model=eigen")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, daFeeModelBlob, models.current().Name())
+}
+
+func TestBlobBaseFeeClient(t *testing.T) {
+	ctx := context.Background()
+	cancun := newFakeRPC(map[string]interface{}{"eth_blobBaseFee": "0x5"})
+	defer cancun.Close()
+	client, err := newBlobBaseFeeClient(nil, cancun.URL)
+	require.NoError(t, err)
+	fee, err := client.BlobBaseFee(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5), fee)
+
+	// A node before EIP-4844 does not know the method
+	london := newFakeRPC(map[string]interface{}{})
+	defer london.Close()
+	client, err = newBlobBaseFeeClient(nil, london.URL)
+	require.NoError(t, err)
+	_, err = client.BlobBaseFee(ctx)
+	require.ErrorIs(t, err, errNoBlobBaseFee)
+}
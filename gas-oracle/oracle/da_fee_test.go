@@ -1 +1,34 @@
 package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClampDaFee(t *testing.T) {
+	before := daFeeClampedCounter().Count()
+
+	// Clamp high
+	require.Equal(t, big.NewInt(500), clampDaFee(big.NewInt(10_000), 100, 500))
+	// Clamp low
+	require.Equal(t, big.NewInt(100), clampDaFee(big.NewInt(7), 100, 500))
+	require.Equal(t, before+2, daFeeClampedCounter().Count())
+
+	// In range and at the bounds
+	for _, fee := range []int64{100, 300, 500} {
+		require.Equal(t, big.NewInt(fee), clampDaFee(big.NewInt(fee), 100, 500))
+	}
+	// Unset bounds are not applied
+	require.Equal(t, big.NewInt(7), clampDaFee(big.NewInt(7), 0, 500))
+	require.Equal(t, big.NewInt(10_000), clampDaFee(big.NewInt(10_000), 100, 0))
+	require.Equal(t, before+2, daFeeClampedCounter().Count())
+}
+
+func TestValidateDaFeeBounds(t *testing.T) {
+	require.NoError(t, (&Config{}).validateDaFeeBounds())
+	require.NoError(t, (&Config{daFeeMin: 100, daFeeMax: 100}).validateDaFeeBounds())
+	require.NoError(t, (&Config{daFeeMin: 100}).validateDaFeeBounds())
+	require.Error(t, (&Config{daFeeMin: 500, daFeeMax: 100}).validateDaFeeBounds())
+}
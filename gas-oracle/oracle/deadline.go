@@ -0,0 +1,107 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errEpochAborted represents the error when collecting the inputs of an
+// epoch took longer than the input budget
+var errEpochAborted = errors.New("epoch aborted, inputs exceeded the latency budget")
+
+// inputDeadline bounds the time that a channel spends collecting the
+// inputs of an epoch. Input reads use its context, which expires once the
+// budget is spent, so that a slow RPC aborts the epoch instead of running
+// a long overdue update. Sending the update is not bound by the budget.
+// A nil inputDeadline or a zero budget does not bound anything.
+//
+// The epochs run under the context of the loop that runs them, so that the
+// watchdog cancelling a stalled loop also aborts the reads, the sends and
+// the receipt waits of its epoch.
+type inputDeadline struct {
+	budget time.Duration
+	mu     sync.Mutex
+	loop   context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newInputDeadline(budget time.Duration) *inputDeadline {
+	return &inputDeadline{budget: budget}
+}
+
+// bind runs the epochs that follow under ctx, the context of the loop
+func (d *inputDeadline) bind(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.loop = ctx
+}
+
+// epoch returns the context for sending the update of the epoch in
+// progress and waiting for its receipt. It is done with the loop, but is
+// not bound by the budget.
+func (d *inputDeadline) epoch() context.Context {
+	if d == nil {
+		return context.Background()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.parent()
+}
+
+// parent returns the context of the loop. It must be called with the lock
+// held.
+func (d *inputDeadline) parent() context.Context {
+	if d.loop == nil {
+		return context.Background()
+	}
+	return d.loop
+}
+
+// context returns the context for the input reads of the epoch in progress
+func (d *inputDeadline) context() context.Context {
+	if d == nil {
+		return context.Background()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ctx == nil {
+		return d.parent()
+	}
+	return d.ctx
+}
+
+// exceeded returns true when the budget of the epoch in progress is spent
+func (d *inputDeadline) exceeded() bool {
+	return errors.Is(d.context().Err(), context.DeadlineExceeded)
+}
+
+// wrap runs update as an epoch with its own input budget. An update that
+// fails after the budget is spent is reported as aborted.
+func (d *inputDeadline) wrap(update func() error) func() error {
+	if d == nil || d.budget <= 0 {
+		return update
+	}
+	return func() error {
+		d.mu.Lock()
+		d.ctx, d.cancel = context.WithTimeout(d.parent(), d.budget)
+		d.mu.Unlock()
+		defer func() {
+			d.mu.Lock()
+			d.cancel()
+			d.ctx, d.cancel = nil, nil
+			d.mu.Unlock()
+		}()
+
+		err := update()
+		if err != nil && d.exceeded() {
+			return errEpochAborted
+		}
+		return err
+	}
+}
@@ -0,0 +1,117 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputDeadlineAbortsSlowEpoch(t *testing.T) {
+	d := newInputDeadline(20 * time.Millisecond)
+
+	sent := 0
+	update := d.wrap(func() error {
+		// An input read that does not return before the budget is spent
+		select {
+		case <-d.context().Done():
+			return d.context().Err()
+		case <-time.After(time.Second):
+		}
+		sent++
+		return nil
+	})
+	require.ErrorIs(t, update(), errEpochAborted)
+	require.Equal(t, 0, sent)
+
+	// The next epoch gets a fresh budget
+	update = d.wrap(func() error {
+		require.NoError(t, d.context().Err())
+		sent++
+		return nil
+	})
+	require.NoError(t, update())
+	require.Equal(t, 1, sent)
+}
+
+func TestInputDeadlineKeepsOtherErrors(t *testing.T) {
+	d := newInputDeadline(time.Second)
+	failure := errors.New("failure")
+	require.ErrorIs(t, d.wrap(func() error { return failure })(), failure)
+
+	// Without a budget the update is not bound
+	var none *inputDeadline
+	require.NoError(t, none.context().Err())
+	require.False(t, none.exceeded())
+}
+
+func TestInputDeadlineFollowsLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := newInputDeadline(time.Minute)
+	d.bind(ctx)
+
+	// Cancelling the loop ends the reads and the sends of its epoch
+	update := d.wrap(func() error {
+		require.NoError(t, d.context().Err())
+		require.NoError(t, d.epoch().Err())
+		cancel()
+		require.ErrorIs(t, d.context().Err(), context.Canceled)
+		require.ErrorIs(t, d.epoch().Err(), context.Canceled)
+		return d.epoch().Err()
+	})
+	require.ErrorIs(t, update(), context.Canceled)
+
+	// as well as the wait for the receipt
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	_, err := waitForReceipt(d.epoch(), &scriptedBackend{}, tx, defaultReceiptBackoff)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBaseFeeEpochSkippedOnSlowInputs(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	l2 := newFakeRPC(map[string]interface{}{
+		// L1BaseFee returns 1
+		"eth_call":                common.BigToHash(big.NewInt(1)).Hex(),
+		"eth_gasPrice":            "0x1",
+		"eth_getTransactionCount": "0x0",
+		"eth_estimateGas":         "0x5208",
+		"eth_sendRawTransaction":  common.Hash{}.Hex(),
+		"eth_getBlockByNumber":    &types.Header{Difficulty: common.Big0, Number: big.NewInt(1)},
+	})
+	defer l2.Close()
+	l1 := newFakeRPC(map[string]interface{}{
+		// The L1 header is read slower than the budget allows
+		"eth_getBlockByNumber": func(params []json.RawMessage) (interface{}, error) {
+			time.Sleep(200 * time.Millisecond)
+			return nil, errors.New("too late")
+		},
+	})
+	defer l1.Close()
+
+	l1Client, err := ethclient.Dial(l1.URL)
+	require.NoError(t, err)
+	l2Client, err := ethclient.Dial(l2.URL)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		l1BaseFeeSignificanceFactor: 0.1,
+	}
+	deadline := newInputDeadline(50 * time.Millisecond)
+	update, err := wrapUpdateBaseFee(l1Client, l2Client, cfg, channelGuards{deadline: deadline})
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.ErrorIs(t, deadline.wrap(update)(), errEpochAborted)
+	require.Less(t, time.Since(start), 200*time.Millisecond, "epoch not aborted at the deadline")
+	require.Equal(t, 0, l2.called("eth_sendRawTransaction"))
+}
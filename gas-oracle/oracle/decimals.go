@@ -0,0 +1,39 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errDecimalsMismatch represents the error when the fee contract scales
+// the L1 base fee with other decimals than the token price ratio is
+// scaled for
+var errDecimalsMismatch = errors.New("token price decimals do not match the fee contract")
+
+// checkDecimals makes sure that the fee contract scales the L1 base fee by
+// the expected decimals. The L1 fee is charged on the written L1 base fee,
+// which includes the token price ratio, times scalar / 10^decimals, so a
+// mismatch makes the fee off by orders of magnitude. A nil expectation
+// skips the check.
+func checkDecimals(contract *bindings.BVMGasPriceOracleCaller, opts *bind.CallOpts, expected *big.Int) error {
+	if expected == nil {
+		return nil
+	}
+	decimals, err := contract.Decimals(opts)
+	if err != nil {
+		return fmt.Errorf("cannot read the fee contract decimals: %w", err)
+	}
+	if decimals.Cmp(expected) != 0 {
+		log.Error("Token price decimals do not match the fee contract", "expected", expected, "contract", decimals)
+		metrics.GetOrRegisterCounter("l1_base_fee/decimals_mismatch", ometrics.DefaultRegistry).Inc(1)
+		return fmt.Errorf("%w: expected %s, contract has %s", errDecimalsMismatch, expected, decimals)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDecimals(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	_, err = gpo.SetDecimals(opts, big.NewInt(6))
+	require.NoError(t, err)
+	sim.Commit()
+
+	contract, err := bindings.NewBVMGasPriceOracleCaller(addr, sim)
+	require.NoError(t, err)
+	callOpts := &bind.CallOpts{}
+	require.NoError(t, checkDecimals(contract, callOpts, big.NewInt(6)))
+	require.ErrorIs(t, checkDecimals(contract, callOpts, big.NewInt(18)), errDecimalsMismatch)
+	// Without an expectation there is nothing to check
+	require.NoError(t, checkDecimals(contract, callOpts, nil))
+}
+
+func TestBaseFeeUpdateChecksDecimals(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	_, err = gpo.SetDecimals(opts, big.NewInt(6))
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		tokenPriceDecimals:    big.NewInt(6),
+	}
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update())
+	sim.Commit()
+	written, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.NotZero(t, written.Sign())
+
+	// The decimals change under the running oracle, so that it stops
+	// writing rather than writing a base fee off by orders of magnitude
+	_, err = gpo.SetDecimals(opts, big.NewInt(9))
+	require.NoError(t, err)
+	_, err = gpo.SetL1BaseFee(opts, big.NewInt(1))
+	require.NoError(t, err)
+	sim.Commit()
+	require.ErrorIs(t, update(), errDecimalsMismatch)
+	sim.Commit()
+	written, err = gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), written.Int64())
+}
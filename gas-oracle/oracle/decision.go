@@ -0,0 +1,266 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+const (
+	// actionUpdate means that an update transaction was sent
+	actionUpdate = "update"
+	// actionSkip means that no update was needed
+	actionSkip = "skip"
+	// actionObserve means that an update was needed on an observe-only
+	// channel or in dry-run mode, and was only reported
+	actionObserve = "observe"
+	// actionNone means that the epoch ended without a decision
+	actionNone = "none"
+	// actionAborted means that the inputs exceeded the latency budget
+	actionAborted = "aborted"
+	// actionThrottled means that the update was held back while too many
+	// transactions were pending
+	actionThrottled = "throttled"
+	// actionError means that the epoch failed
+	actionError = "error"
+)
+
+// The reason codes classify why a decision was taken, so that the
+// decisions can be counted by reason
+const (
+	// reasonThresholdMet means that the value changed significantly and
+	// was written as computed
+	reasonThresholdMet = "threshold_met"
+	// reasonClamped means that the value changed significantly and was
+	// written after being clamped by the bounds or the enable grace
+	reasonClamped = "clamped"
+	// reasonBelowSignificance means that the value did not change enough
+	reasonBelowSignificance = "below_significance"
+	// reasonUnchanged means that the value did not change at all
+	reasonUnchanged = "unchanged"
+	// reasonFrozen means that the channel was frozen
+	reasonFrozen = "frozen"
+	// reasonObserveOnly means that the channel only reports its updates
+	reasonObserveOnly = "observe_only"
+	// reasonDryRun means that the oracle only reports its updates
+	reasonDryRun = "dry_run"
+	// reasonRateLimited means that too many transactions were pending
+	reasonRateLimited = "rate_limited"
+	// reasonBudgetExceeded means that the updates spent more than the
+	// spend budget
+	reasonBudgetExceeded = "budget_exceeded"
+	// reasonDropLimited means that the value would fall further within the
+	// period than the drop limit allows
+	reasonDropLimited = "drop_limited"
+	// reasonStalledInput means that the inputs exceeded the latency budget
+	reasonStalledInput = "stalled_input"
+	// reasonStaleTokenPrice means that the cached token price was too old
+	// to compute the value with
+	reasonStaleTokenPrice = "stale_token_price"
+	// reasonFailed means that the epoch failed
+	reasonFailed = "failed"
+	// reasonUndecided means that the epoch ended without a decision
+	reasonUndecided = "undecided"
+)
+
+// updateReason returns the reason code of an update that writes value in
+// place of the computed target
+func updateReason(target, value *big.Int) string {
+	if target.Cmp(value) != 0 {
+		return reasonClamped
+	}
+	return reasonThresholdMet
+}
+
+// Decision describes what a channel decided in an epoch, along with the
+// inputs that the decision was based on and the values that it produced
+type Decision struct {
+	Time    time.Time `json:"time"`
+	Channel string    `json:"channel"`
+	// L1Block and L2Block are the numbers of the blocks that the inputs
+	// were read at, zero when the channel reads nothing from that layer
+	L1Block uint64                 `json:"l1_block,omitempty"`
+	L2Block uint64                 `json:"l2_block,omitempty"`
+	Inputs  map[string]interface{} `json:"inputs"`
+	Outputs map[string]interface{} `json:"outputs"`
+	Action  string                 `json:"action"`
+	// ReasonCode is one of the reason codes, Reason details it
+	ReasonCode string `json:"reason_code"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// decisionTrace collects the Decision of the epoch in progress on a
+// channel. A nil decisionTrace records nothing.
+type decisionTrace struct {
+	channel string
+	audit   *auditLog
+	history *decisionHistory
+	now     func() time.Time
+
+	mu      sync.Mutex
+	current *Decision
+	last    *Decision
+	// values are what the channel last computed and sent, along with the
+	// number of epochs that it ran
+	values ChannelState
+}
+
+func newDecisionTrace(channel string, audit *auditLog, history *decisionHistory) *decisionTrace {
+	return &decisionTrace{channel: channel, audit: audit, history: history, now: time.Now}
+}
+
+// input records a value that the decision is based on
+func (t *decisionTrace) input(key string, value interface{}) {
+	t.set(func(d *Decision) { d.Inputs[key] = value })
+}
+
+// l1Block records the number of the L1 block that the inputs are read at
+func (t *decisionTrace) l1Block(number *big.Int) {
+	t.set(func(d *Decision) { d.L1Block = number.Uint64() })
+}
+
+// l2Block records the number of the L2 block that the inputs are read at
+func (t *decisionTrace) l2Block(number *big.Int) {
+	t.set(func(d *Decision) { d.L2Block = number.Uint64() })
+}
+
+// output records a value that the decision produced
+func (t *decisionTrace) output(key string, value interface{}) {
+	t.set(func(d *Decision) { d.Outputs[key] = value })
+}
+
+// act records the decision along with its reason code
+func (t *decisionTrace) act(action, code, reason string) {
+	t.set(func(d *Decision) {
+		d.Action = action
+		d.ReasonCode = code
+		d.Reason = reason
+	})
+}
+
+func (t *decisionTrace) set(fn func(*Decision)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current != nil {
+		fn(t.current)
+	}
+}
+
+// lastDecision returns the decision of the last epoch that completed
+func (t *decisionTrace) lastDecision() (Decision, bool) {
+	if t == nil {
+		return Decision{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.last == nil {
+		return Decision{}, false
+	}
+	return *t.last, true
+}
+
+// channelState returns what the channel last computed and sent
+func (t *decisionTrace) channelState() (ChannelState, bool) {
+	if t == nil {
+		return ChannelState{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.values, t.values.Epoch > 0
+}
+
+// epoch returns the number of the epoch in progress, counted from 1
+func (t *decisionTrace) epoch() uint64 {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.values.Epoch + 1
+}
+
+// wrap runs update as an epoch and records its decision once it returns.
+// A failed epoch is recorded as such, whatever was decided before.
+func (t *decisionTrace) wrap(update func() error) func() error {
+	if t == nil {
+		return update
+	}
+	return func() error {
+		t.mu.Lock()
+		t.current = &Decision{
+			Time:       t.now(),
+			Channel:    t.channel,
+			Inputs:     make(map[string]interface{}),
+			Outputs:    make(map[string]interface{}),
+			Action:     actionNone,
+			ReasonCode: reasonUndecided,
+		}
+		t.mu.Unlock()
+
+		err := update()
+
+		t.mu.Lock()
+		decision := t.current
+		t.current = nil
+		switch {
+		case errors.Is(err, errEpochAborted):
+			decision.Action, decision.ReasonCode, decision.Reason = actionAborted, reasonStalledInput, err.Error()
+		case errors.Is(err, errThrottled):
+			decision.Action, decision.ReasonCode, decision.Reason = actionThrottled, reasonRateLimited, err.Error()
+		case errors.Is(err, errBudgetExceeded):
+			decision.Action, decision.ReasonCode, decision.Reason = actionThrottled, reasonBudgetExceeded, err.Error()
+		case errors.Is(err, errDropLimited):
+			decision.Action, decision.ReasonCode, decision.Reason = actionThrottled, reasonDropLimited, err.Error()
+		case errors.Is(err, errStaleTokenPrice):
+			decision.Action, decision.ReasonCode, decision.Reason = actionSkip, reasonStaleTokenPrice, err.Error()
+		case err != nil:
+			decision.Action, decision.ReasonCode, decision.Reason = actionError, reasonFailed, err.Error()
+		}
+		t.last = decision
+		t.values.observe(decision)
+		t.mu.Unlock()
+
+		decisionsCounter(decision.Channel, decision.ReasonCode).Inc(1)
+		t.audit.write(decision)
+		t.history.add(*decision)
+		return err
+	}
+}
+
+// decisionsCounter counts the decisions of the channel by reason code
+func decisionsCounter(channel, code string) metrics.Counter {
+	name := "decisions/" + metricName(channel) + "/" + code
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
+
+// auditLog writes every decision as a line of JSON. A nil auditLog
+// writes nothing.
+type auditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	return &auditLog{enc: json.NewEncoder(w)}
+}
+
+func (a *auditLog) write(decision *Decision) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.enc.Encode(decision); err != nil {
+		log.Error("cannot write audit log", "message", err)
+	}
+}
@@ -0,0 +1,167 @@
+package oracle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func readAudit(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var decisions []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.UseNumber()
+		var decision map[string]interface{}
+		require.NoError(t, dec.Decode(&decision), "audit line is not JSON: %s", scanner.Text())
+		decisions = append(decisions, decision)
+	}
+	require.NoError(t, scanner.Err())
+	return decisions
+}
+
+func TestAuditLogBaseFeeEpochs(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		// The simulated base fee moves by less than this between blocks
+		l1BaseFeeSignificanceFactor: 0.5,
+	}
+	var buf bytes.Buffer
+	trace := newDecisionTrace(l1BaseFeeChannel, newAuditLog(&buf), nil)
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{trace: trace})
+	require.NoError(t, err)
+	update = trace.wrap(update)
+
+	// The first epoch sets the base fee, the second finds it close enough
+	tip := sim.Blockchain().CurrentHeader()
+	require.NoError(t, update())
+	sim.Commit()
+	require.NoError(t, update())
+
+	decisions := readAudit(t, &buf)
+	require.Len(t, decisions, 2)
+
+	first := decisions[0]
+	require.Equal(t, l1BaseFeeChannel, first["channel"])
+	require.Equal(t, actionUpdate, first["action"])
+	inputs := first["inputs"].(map[string]interface{})
+	require.Equal(t, json.Number("0"), inputs["current_l1_base_fee"])
+	require.Equal(t, json.Number(tip.BaseFee.String()), inputs["l1_base_fee"])
+	outputs := first["outputs"].(map[string]interface{})
+	require.Equal(t, json.Number(tip.BaseFee.String()), outputs["l1_base_fee"])
+	require.NotEmpty(t, outputs["tx_hash"])
+
+	second := decisions[1]
+	require.Equal(t, actionSkip, second["action"])
+	require.Equal(t, "not significant", second["reason"])
+	require.Empty(t, second["outputs"])
+
+	last, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionSkip, last.Action)
+}
+
+func TestAuditLogFailedEpochs(t *testing.T) {
+	var buf bytes.Buffer
+	trace := newDecisionTrace(daFeeChannel, newAuditLog(&buf), nil)
+
+	failure := errors.New("failure")
+	require.ErrorIs(t, trace.wrap(func() error { return failure })(), failure)
+	require.ErrorIs(t, trace.wrap(func() error { return errEpochAborted })(), errEpochAborted)
+	require.NoError(t, trace.wrap(func() error { return nil })())
+
+	decisions := readAudit(t, &buf)
+	require.Len(t, decisions, 3)
+	require.Equal(t, actionError, decisions[0]["action"])
+	require.Equal(t, failure.Error(), decisions[0]["reason"])
+	require.Equal(t, actionAborted, decisions[1]["action"])
+	require.Equal(t, actionNone, decisions[2]["action"])
+
+	// Without a trace nothing is recorded
+	var none *decisionTrace
+	none.input("key", 1)
+	require.NoError(t, none.wrap(func() error { return nil })())
+	_, ok := none.lastDecision()
+	require.False(t, ok)
+}
+
+func TestDecisionReasonCounters(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, legacy, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	tip := sim.Blockchain().CurrentHeader()
+	// The value on chain is far below the L1 base fee
+	_, err = legacy.SetL1BaseFee(opts, new(big.Int).Div(tip.BaseFee, big.NewInt(10)))
+	require.NoError(t, err)
+	sim.Commit()
+
+	counts := func() map[string]int64 {
+		counts := make(map[string]int64)
+		for _, code := range []string{reasonThresholdMet, reasonClamped, reasonBelowSignificance,
+			reasonFrozen, reasonRateLimited, reasonStalledInput, reasonFailed, reasonUndecided} {
+			counts[code] = decisionsCounter(l1BaseFeeChannel, code).Count()
+		}
+		return counts
+	}
+	before := counts()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		// The simulated base fee moves by less than this between blocks
+		l1BaseFeeSignificanceFactor: 0.3,
+	}
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	grace := newEnableGrace(l1BaseFeeChannel, graceEase, 3)
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{trace: trace, grace: grace})
+	require.NoError(t, err)
+	update = trace.wrap(update)
+
+	// The grace clamps the first write, the second catches up and the
+	// third is not significant
+	for _, code := range []string{reasonClamped, reasonThresholdMet, reasonBelowSignificance} {
+		require.NoError(t, update())
+		sim.Commit()
+		decision, _ := trace.lastDecision()
+		require.Equal(t, code, decision.ReasonCode)
+	}
+
+	// A frozen channel skips its updates
+	freezer := newFreezer(l1BaseFeeChannel, time.Hour)
+	freezer.freeze(time.Minute)
+	require.NoError(t, trace.wrap(freezer.wrap(update, trace))())
+
+	require.ErrorIs(t, trace.wrap(func() error { return errThrottled })(), errThrottled)
+	require.ErrorIs(t, trace.wrap(func() error { return errEpochAborted })(), errEpochAborted)
+	require.Error(t, trace.wrap(func() error { return errors.New("failure") })())
+	require.NoError(t, trace.wrap(func() error { return nil })())
+
+	after := counts()
+	for code := range before {
+		require.Equal(t, before[code]+1, after[code], code)
+	}
+}
@@ -0,0 +1,166 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// submissionPathOwner sends updates as a call of the owner on L2
+	submissionPathOwner = "owner"
+	// submissionPathDeposit sends updates as a deposit through the portal
+	// on L1, which executes them on L2 as a system transaction
+	submissionPathDeposit = "deposit"
+)
+
+var (
+	// errUnknownSubmissionPath represents the error when the configured
+	// submission path is not known
+	errUnknownSubmissionPath = errors.New("unknown submission path")
+	// errNoPortalAddress represents the error when the deposit path is
+	// selected without the address of the portal
+	errNoPortalAddress = errors.New("no deposit portal address provided")
+)
+
+// portalABI is the part of the L1 portal ABI used to deposit transactions
+const portalABI = `[{"inputs":[{"internalType":"address","name":"_to","type":"address"},{"internalType":"uint256","name":"_value","type":"uint256"},{"internalType":"uint64","name":"_gasLimit","type":"uint64"},{"internalType":"bool","name":"_isCreation","type":"bool"},{"internalType":"bytes","name":"_data","type":"bytes"}],"name":"depositTransaction","outputs":[],"stateMutability":"payable","type":"function"}]`
+
+var parsedPortalABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(portalABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// validateSubmissionPath makes sure that the submission path is known and
+// has what it needs
+func (c *Config) validateSubmissionPath() error {
+	switch c.submissionPath {
+	case "", submissionPathOwner:
+	case submissionPathDeposit:
+		if c.depositPortalAddress == (common.Address{}) {
+			return errNoPortalAddress
+		}
+	default:
+		return fmt.Errorf("%w: %s", errUnknownSubmissionPath, c.submissionPath)
+	}
+	return nil
+}
+
+// depositBackend sends the update transactions of a channel as deposits
+// through the portal on L1 instead of sending them on L2. Transactions are
+// still built and estimated against L2, then their target and calldata are
+// wrapped in a call of `depositTransaction` signed for L1. The deposit is
+// sent from the same account, so that it executes on L2 as the owner.
+type depositBackend struct {
+	backendDecorator
+	l1  DeployContractBackend
+	cfg *Config
+
+	mu       sync.Mutex
+	deposits map[common.Hash]common.Hash
+}
+
+// maxDeposits bounds the deposits remembered for their receipts
+const maxDeposits = 256
+
+func newDepositBackend(l2, l1 DeployContractBackend, cfg *Config) *depositBackend {
+	return &depositBackend{
+		backendDecorator: decorate(l2),
+		l1:               l1,
+		cfg:              cfg,
+		deposits:         make(map[common.Hash]common.Hash),
+	}
+}
+
+// depositTx builds and signs the L1 deposit of the L2 transaction
+func (b *depositBackend) depositTx(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	if tx.To() == nil {
+		return nil, errors.New("cannot deposit a contract creation")
+	}
+	if b.cfg.l1ChainID == nil {
+		return nil, fmt.Errorf("layer-one: %w", errNoChainID)
+	}
+	gasLimit := b.cfg.depositGasLimit
+	if gasLimit == 0 {
+		gasLimit = tx.Gas()
+	}
+	data, err := parsedPortalABI.Pack("depositTransaction", *tx.To(), tx.Value(), gasLimit, false, tx.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	from := b.cfg.from()
+	portal := b.cfg.depositPortalAddress
+	nonce, err := b.l1.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := b.l1.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := b.l1.EstimateGas(ctx, ethereum.CallMsg{
+		From: from,
+		To:   &portal,
+		Data: data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot estimate deposit: %w", err)
+	}
+	deposit := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       &portal,
+		Value:    new(big.Int),
+		Data:     data,
+	})
+	return b.cfg.signTx(deposit, b.cfg.l1ChainID)
+}
+
+// SendTransaction deposits tx through the portal on L1
+func (b *depositBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	deposit, err := b.depositTx(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err := b.l1.SendTransaction(ctx, deposit); err != nil {
+		return fmt.Errorf("cannot deposit: %w", err)
+	}
+	log.Info("Update deposited through the portal", "hash", deposit.Hash().Hex(), "to", tx.To().Hex())
+
+	b.mu.Lock()
+	if len(b.deposits) >= maxDeposits {
+		for hash := range b.deposits {
+			delete(b.deposits, hash)
+			break
+		}
+	}
+	b.deposits[tx.Hash()] = deposit.Hash()
+	b.mu.Unlock()
+	return nil
+}
+
+// TransactionReceipt returns the receipt of the L1 deposit of a transaction
+// sent through the backend
+func (b *depositBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	deposit, ok := b.deposits[hash]
+	b.mu.Unlock()
+	if !ok {
+		return b.DeployContractBackend.TransactionReceipt(ctx, hash)
+	}
+	return b.l1.TransactionReceipt(ctx, deposit)
+}
@@ -0,0 +1,138 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositPathTransactionShape(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	var mu sync.Mutex
+	var sent []*types.Transaction
+	l1 := newFakeRPC(map[string]interface{}{
+		"eth_getTransactionCount": "0x7",
+		"eth_gasPrice":            "0x3b9aca00",
+		"eth_estimateGas":         "0x186a0",
+		"eth_sendRawTransaction": func(params []json.RawMessage) (interface{}, error) {
+			var raw hexutil.Bytes
+			if err := json.Unmarshal(params[0], &raw); err != nil {
+				return nil, err
+			}
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(raw); err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			sent = append(sent, tx)
+			mu.Unlock()
+			return tx.Hash().Hex(), nil
+		},
+	})
+	defer l1.Close()
+	l1Client, err := ethclient.Dial(l1.URL)
+	require.NoError(t, err)
+
+	portal := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	cfg := &Config{
+		privateKey:            key,
+		l1ChainID:             big.NewInt(900),
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		submissionPath:        submissionPathDeposit,
+		depositPortalAddress:  portal,
+		depositGasLimit:       150_000,
+	}
+	require.NoError(t, cfg.validateSubmissionPath())
+	update, err := wrapUpdateBaseFee(sim, newDepositBackend(sim, l1Client, cfg), cfg, channelGuards{})
+	require.NoError(t, err)
+
+	tip := sim.Blockchain().CurrentHeader()
+	require.NoError(t, update())
+	sim.Commit()
+
+	// Nothing is sent on L2
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 0, l1BaseFee.Sign())
+
+	// The update is deposited through the portal on L1 by the owner
+	require.Len(t, sent, 1)
+	deposit := sent[0]
+	require.Equal(t, portal, *deposit.To())
+	require.Equal(t, uint64(7), deposit.Nonce())
+	require.Equal(t, uint64(100_000), deposit.Gas())
+	require.Equal(t, 0, deposit.Value().Sign())
+	require.Equal(t, big.NewInt(900), deposit.ChainId())
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(900)), deposit)
+	require.NoError(t, err)
+	require.Equal(t, from, sender)
+
+	method, err := parsedPortalABI.MethodById(deposit.Data()[:4])
+	require.NoError(t, err)
+	require.Equal(t, "depositTransaction", method.Name)
+	args, err := method.Inputs.Unpack(deposit.Data()[4:])
+	require.NoError(t, err)
+	require.Equal(t, addr, args[0])
+	require.Equal(t, 0, args[1].(*big.Int).Sign())
+	require.Equal(t, uint64(150_000), args[2])
+	require.Equal(t, false, args[3])
+
+	parsed, err := bindings.BVMGasPriceOracleMetaData.GetAbi()
+	require.NoError(t, err)
+	calldata, err := parsed.Pack("setL1BaseFee", tip.BaseFee)
+	require.NoError(t, err)
+	require.Equal(t, calldata, args[4])
+}
+
+func TestDepositGasLimitDefaultsToEstimate(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	tx := types.NewTx(&types.LegacyTx{To: &to, Gas: 42_000, Value: new(big.Int), Data: []byte{1}})
+
+	l1 := newFakeRPC(map[string]interface{}{
+		"eth_getTransactionCount": "0x0",
+		"eth_gasPrice":            "0x1",
+		"eth_estimateGas":         "0x5208",
+	})
+	defer l1.Close()
+	l1Client, err := ethclient.Dial(l1.URL)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		privateKey:           key,
+		l1ChainID:            big.NewInt(900),
+		depositPortalAddress: common.HexToAddress("0x1000000000000000000000000000000000000001"),
+	}
+	deposit, err := newDepositBackend(nil, l1Client, cfg).depositTx(context.Background(), tx)
+	require.NoError(t, err)
+	args, err := parsedPortalABI.Methods["depositTransaction"].Inputs.Unpack(deposit.Data()[4:])
+	require.NoError(t, err)
+	require.Equal(t, uint64(42_000), args[2])
+}
+
+func TestValidateSubmissionPath(t *testing.T) {
+	require.NoError(t, (&Config{}).validateSubmissionPath())
+	require.NoError(t, (&Config{submissionPath: submissionPathOwner}).validateSubmissionPath())
+	require.ErrorIs(t, (&Config{submissionPath: submissionPathDeposit}).validateSubmissionPath(), errNoPortalAddress)
+	require.ErrorIs(t, (&Config{submissionPath: "system"}).validateSubmissionPath(), errUnknownSubmissionPath)
+}
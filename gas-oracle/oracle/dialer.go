@@ -0,0 +1,56 @@
+package oracle
+
+import (
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
+)
+
+// dialer dials RPC endpoints and keeps a single client per url so that
+// channels configured with the same endpoint share the connection
+type dialer struct {
+	clients map[string]*ethclient.Client
+	backoff backoff.Policy
+}
+
+// newDialer creates a dialer that retries connecting with the policy
+func newDialer(policy backoff.Policy) *dialer {
+	return &dialer{
+		clients: make(map[string]*ethclient.Client),
+		backoff: policy,
+	}
+}
+
+// dial returns the client for the url, connecting to it the first time
+// that the url is seen
+func (d *dialer) dial(url string) (*ethclient.Client, error) {
+	if client, ok := d.clients[url]; ok {
+		return client, nil
+	}
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureConnection(client, d.backoff); err != nil {
+		client.Close()
+		return nil, err
+	}
+	d.clients[url] = client
+	return client, nil
+}
+
+// dialChannel returns the clients for the read and write endpoints of a
+// channel
+func (d *dialer) dialChannel(name string, e endpoints) (*ethclient.Client, *ethclient.Client, error) {
+	read, err := d.dial(e.read)
+	if err != nil {
+		log.Error("Unable to connect to read endpoint", "channel", name)
+		return nil, nil, err
+	}
+	write, err := d.dial(e.write)
+	if err != nil {
+		log.Error("Unable to connect to write endpoint", "channel", name)
+		return nil, nil, err
+	}
+	return read, write, nil
+}
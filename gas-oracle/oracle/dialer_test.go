@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
+)
+
+func TestDialerSharesClients(t *testing.T) {
+	srv := newFakeRPC(map[string]interface{}{"eth_chainId": "0x1"})
+	defer srv.Close()
+	other := newFakeRPC(map[string]interface{}{"eth_chainId": "0x1"})
+	defer other.Close()
+
+	d := newDialer(backoff.Constant(time.Millisecond))
+	read, write, err := d.dialChannel("test", endpoints{read: srv.URL, write: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != write {
+		t.Fatal("same endpoint dialed twice")
+	}
+	read, write, err = d.dialChannel("test", endpoints{read: srv.URL, write: other.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read == write {
+		t.Fatal("different endpoints share a client")
+	}
+}
+
+func TestBaseFeeChannelEndpoints(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	// The read endpoint serves the L1 header
+	read := newFakeRPC(map[string]interface{}{
+		"eth_chainId": "0x1",
+		"eth_getBlockByNumber": &types.Header{
+			Difficulty: common.Big0,
+			Number:     big.NewInt(100),
+			BaseFee:    big.NewInt(1_000_000_000),
+		},
+	})
+	defer read.Close()
+	// The write endpoint serves the L2 contract and accepts transactions
+	write := newFakeRPC(map[string]interface{}{
+		"eth_chainId": "0x539",
+		"eth_getBlockByNumber": &types.Header{
+			Difficulty: common.Big0,
+			Number:     big.NewInt(7),
+		},
+		"eth_call":                "0x" + strings.Repeat("0", 64),
+		"eth_getCode":             "0x6080",
+		"eth_estimateGas":         "0x5208",
+		"eth_getTransactionCount": "0x0",
+		"eth_sendRawTransaction":  common.Hash{}.Hex(),
+	})
+	defer write.Close()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: common.HexToAddress("0x420000000000000000000000000000000000000F"),
+		gasPrice:              big.NewInt(1),
+		l1BaseFeeEndpoints:    endpoints{read: read.URL, write: write.URL},
+	}
+
+	readClient, writeClient, err := newDialer(backoff.Constant(time.Millisecond)).dialChannel(l1BaseFeeChannel, cfg.l1BaseFeeEndpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	update, err := wrapUpdateBaseFee(readClient, writeClient, cfg, channelGuards{trace: trace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trace.wrap(update)(); err != nil {
+		t.Fatal(err)
+	}
+
+	if read.called("eth_getBlockByNumber") == 0 {
+		t.Fatal("L1 header not read from the read endpoint")
+	}
+	if read.called("eth_call") != 0 || read.called("eth_sendRawTransaction") != 0 {
+		t.Fatal("read endpoint used for the L2 contract")
+	}
+	decision, _ := trace.lastDecision()
+	if decision.L1Block != 100 {
+		t.Fatalf("L1 header read from the write endpoint, at block %d", decision.L1Block)
+	}
+	if decision.L2Block != 7 {
+		t.Fatalf("L2 contract read at block %d, not at the head of the write endpoint", decision.L2Block)
+	}
+	if write.called("eth_call") == 0 {
+		t.Fatal("current base fee not read from the write endpoint")
+	}
+	if write.called("eth_sendRawTransaction") != 1 {
+		t.Fatal("update not sent to the write endpoint")
+	}
+}
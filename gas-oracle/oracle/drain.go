@@ -0,0 +1,52 @@
+package oracle
+
+import "sync"
+
+// drainer tracks whether the oracle still accepts new updates and how
+// many updates are currently in flight. Once draining, no new update is
+// started but the in flight ones are allowed to finish.
+type drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inflight int
+}
+
+// begin reserves a slot for a new update. It returns false when the
+// oracle is draining, in which case the update must not be started.
+func (d *drainer) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inflight++
+	return true
+}
+
+// end releases the slot reserved by begin
+func (d *drainer) end() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inflight--
+}
+
+// drain stops new updates from being started
+func (d *drainer) drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.draining = true
+}
+
+// isDraining returns true once drain has been called
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// isDrained returns true when draining and no update is in flight
+func (d *drainer) isDrained() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining && d.inflight == 0
+}
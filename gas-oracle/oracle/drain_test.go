@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrainStopsNewUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := &GasPriceOracle{
+		ctx:     ctx,
+		stop:    make(chan struct{}),
+		drainer: new(drainer),
+	}
+
+	var calls int32
+	go g.loop("test", 5*time.Millisecond, func() error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	// Wait for the loop to run a few updates
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&calls) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("loop did not run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	g.Drain()
+	// Give an in flight update the chance to finish
+	time.Sleep(20 * time.Millisecond)
+	if !g.drainer.isDrained() {
+		t.Fatal("not drained")
+	}
+
+	drained := atomic.LoadInt32(&calls)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != drained {
+		t.Fatalf("updates scheduled after drain: %d before, %d after", drained, got)
+	}
+}
+
+func TestDrainWaitsForInflightUpdate(t *testing.T) {
+	d := new(drainer)
+	if !d.begin() {
+		t.Fatal("cannot begin before draining")
+	}
+	d.drain()
+	if d.begin() {
+		t.Fatal("can begin while draining")
+	}
+	if d.isDrained() {
+		t.Fatal("drained with an update in flight")
+	}
+	d.end()
+	if !d.isDrained() {
+		t.Fatal("not drained after the update finished")
+	}
+}
+
+func TestDrainHandlers(t *testing.T) {
+	g := &GasPriceOracle{
+		ctx:     context.Background(),
+		stop:    make(chan struct{}),
+		drainer: new(drainer),
+	}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get("/readyz"); code != http.StatusOK {
+		t.Fatalf("readyz before drain: %d", code)
+	}
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Fatalf("healthz before drain: %d", code)
+	}
+	if code := get("/drain"); code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET /drain: %d", code)
+	}
+
+	// Simulate an update that is in flight while draining
+	g.drainer.begin()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/drain", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /drain: %d", rec.Code)
+	}
+
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("readyz while draining: %d", code)
+	}
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Fatalf("healthz while draining: %d", code)
+	}
+
+	g.drainer.end()
+
+	if code := get("/healthz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("healthz once drained: %d", code)
+	}
+}
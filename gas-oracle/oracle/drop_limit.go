@@ -0,0 +1,149 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errDropLimited represents the error when a decrease is held back because
+// the value of the channel fell too far within the period
+var errDropLimited = errors.New("update paused, value dropped too fast")
+
+// sample is a value read from the contract
+type sample struct {
+	at    time.Time
+	value *big.Int
+}
+
+// dropLimit is a backstop against a bug or a manipulated input slashing a
+// fee. The values read from the contract are kept for a rolling period, and
+// a write may not fall more than maxDrop below the highest of them. This
+// bounds the cumulative decrease over the period, however small the steps
+// that the per-epoch limit allows. A write that would breach the limit
+// pauses the decreases of the channel for a whole period, or until an
+// operator resumes them, while increases are still written. A nil
+// dropLimit never holds back.
+type dropLimit struct {
+	channel string
+	maxDrop float64
+	period  time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	samples  []sample
+	paused   bool
+	pausedAt time.Time
+}
+
+// newDropLimit creates the limit of the channel, or returns nil when the
+// maximum drop is zero
+func newDropLimit(channel string, maxDrop float64, period time.Duration) *dropLimit {
+	if maxDrop <= 0 {
+		return nil
+	}
+	return &dropLimit{channel: channel, maxDrop: maxDrop, period: period, now: time.Now}
+}
+
+// observed records the value read from the contract
+func (d *dropLimit) observed(current *big.Int) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.samples = append(d.samples, sample{at: d.now(), value: new(big.Int).Set(current)})
+}
+
+// check returns errDropLimited when writing next over current would breach
+// the limit, or while the decreases are paused
+func (d *dropLimit) check(current, next *big.Int) error {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	peak := d.peak(now)
+	if d.paused && now.Sub(d.pausedAt) >= d.period {
+		log.Info("Drop limit period passed, resuming decreases", "channel", d.channel, "peak", peak)
+		d.paused = false
+		dropLimitPausedGauge(d.channel).Update(0)
+	}
+	if next.Cmp(current) >= 0 {
+		return nil
+	}
+	if d.paused {
+		return fmt.Errorf("%w: %s since %s", errDropLimited, d.channel, d.pausedAt.UTC().Format(time.RFC3339))
+	}
+
+	floor, _ := new(big.Float).Mul(new(big.Float).SetInt(peak), big.NewFloat(1-d.maxDrop)).Int(nil)
+	if next.Cmp(floor) >= 0 {
+		return nil
+	}
+	d.paused = true
+	d.pausedAt = now
+	log.Error("ALERT: value dropping too fast, pausing decreases", "channel", d.channel,
+		"next", next, "peak", peak, "floor", floor, "period", d.period)
+	dropLimitBreachCounter(d.channel).Inc(1)
+	dropLimitPausedGauge(d.channel).Update(1)
+	return fmt.Errorf("%w: %s would fall to %s, below %s within %s", errDropLimited, d.channel, next, floor, d.period)
+}
+
+// resume lifts a pause right away and forgets the values read so far
+func (d *dropLimit) resume() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.paused {
+		log.Info("Drop limit resumed by the operator", "channel", d.channel)
+	}
+	d.paused = false
+	d.samples = nil
+	dropLimitPausedGauge(d.channel).Update(0)
+}
+
+// status returns the highest value within the period and whether the
+// decreases are paused
+func (d *dropLimit) status() (*big.Int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.peak(d.now()), d.paused
+}
+
+// peak forgets the values that rolled out of the period and returns the
+// highest of the others, the lock must be held
+func (d *dropLimit) peak(now time.Time) *big.Int {
+	start := now.Add(-d.period)
+	kept := d.samples[:0]
+	peak := new(big.Int)
+	for _, s := range d.samples {
+		if s.at.After(start) {
+			kept = append(kept, s)
+			if s.value.Cmp(peak) > 0 {
+				peak = s.value
+			}
+		}
+	}
+	d.samples = kept
+	return new(big.Int).Set(peak)
+}
+
+func dropLimitBreachCounter(channel string) metrics.Counter {
+	name := "drop_limit/" + metricName(channel) + "/breaches"
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
+
+func dropLimitPausedGauge(channel string) metrics.Gauge {
+	name := "drop_limit/" + metricName(channel) + "/paused"
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
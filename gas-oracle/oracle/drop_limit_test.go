@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropLimitBoundsCumulativeDecrease(t *testing.T) {
+	drops := newDropLimit(daFeeChannel, 0.25, time.Hour)
+	now := time.Unix(1_000, 0)
+	drops.now = func() time.Time { return now }
+	breaches := dropLimitBreachCounter(daFeeChannel).Count()
+
+	// Every step drops by 10%, well within a per-epoch cap, but the
+	// third one takes the value more than 25% below the peak
+	step := func(current, next int64) error {
+		now = now.Add(5 * time.Minute)
+		drops.observed(big.NewInt(current))
+		return drops.check(big.NewInt(current), big.NewInt(next))
+	}
+	require.NoError(t, step(100, 90))
+	require.NoError(t, step(90, 81))
+	require.ErrorIs(t, step(81, 73), errDropLimited)
+	require.Equal(t, breaches+1, dropLimitBreachCounter(daFeeChannel).Count())
+	require.Equal(t, int64(1), dropLimitPausedGauge(daFeeChannel).Value())
+
+	// While paused even a small decrease is held back, an increase is not
+	require.ErrorIs(t, step(81, 80), errDropLimited)
+	require.NoError(t, step(81, 85))
+	require.Equal(t, breaches+1, dropLimitBreachCounter(daFeeChannel).Count())
+	peak, paused := drops.status()
+	require.True(t, paused)
+	require.Equal(t, big.NewInt(100), peak)
+
+	// The pause ends after a whole period, once the peak rolled out
+	now = now.Add(time.Hour)
+	require.NoError(t, step(85, 70))
+	require.Equal(t, int64(0), dropLimitPausedGauge(daFeeChannel).Value())
+
+	// or when an operator resumes the decreases
+	require.ErrorIs(t, step(85, 10), errDropLimited)
+	drops.resume()
+	require.NoError(t, step(85, 70))
+	require.Equal(t, breaches+2, dropLimitBreachCounter(daFeeChannel).Count())
+
+	// Disabled without a maximum drop
+	require.Nil(t, newDropLimit(daFeeChannel, 0, time.Hour))
+	var none *dropLimit
+	none.observed(big.NewInt(100))
+	require.NoError(t, none.check(big.NewInt(100), big.NewInt(1)))
+}
+
+func TestDropLimitHoldsBaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	l1 := &syntheticL1{baseFees: []int64{10e9, 9e9, 8e9, 7e9, 12e9}}
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	drops := newDropLimit(l1BaseFeeChannel, 0.25, time.Hour)
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	update, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{trace: trace, drops: drops})
+	require.NoError(t, err)
+	update = trace.wrap(update)
+
+	for _, step := range []struct {
+		tip  uint64
+		want int64
+		err  error
+	}{
+		{0, 10e9, nil}, {1, 9e9, nil}, {2, 8e9, nil},
+		// 7 gwei is more than 25% below the peak of 10 gwei
+		{3, 8e9, errDropLimited},
+		// Increases are still written
+		{4, 12e9, nil},
+	} {
+		l1.tip = step.tip
+		if step.err != nil {
+			require.ErrorIs(t, update(), step.err)
+			decision, ok := trace.lastDecision()
+			require.True(t, ok)
+			require.Equal(t, actionThrottled, decision.Action)
+			require.Equal(t, reasonDropLimited, decision.ReasonCode)
+		} else {
+			require.NoError(t, update())
+		}
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(step.want), l1BaseFee, "tip %d", step.tip)
+	}
+
+	g := &GasPriceOracle{drainer: new(drainer), drops: map[string]*dropLimit{l1BaseFeeChannel: drops}}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	do := func(method string) map[string]map[string]string {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, "/drop-limit", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]map[string]string
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		return body
+	}
+	body := do(http.MethodGet)
+	require.Equal(t, "paused", body[l1BaseFeeChannel]["status"])
+	require.Equal(t, "10000000000", body[l1BaseFeeChannel]["peak"])
+	require.Equal(t, "active", do(http.MethodDelete)[l1BaseFeeChannel]["status"])
+}
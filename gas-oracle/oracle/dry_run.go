@@ -0,0 +1,65 @@
+package oracle
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errDryRun represents the error when a transaction is sent in dry-run
+// mode
+var errDryRun = errors.New("dry run")
+
+// dryRunKey returns the key that a dry run without a private key computes
+// its updates with. It is never funded and never signs a transaction.
+func dryRunKey() *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		log.Crit("Cannot generate the dry run key", "message", err)
+	}
+	log.Info("Dry run without a private key, using an ephemeral key",
+		"address", crypto.PubkeyToAddress(key.PublicKey).Hex())
+	return key
+}
+
+// dryRun reports the transaction that an update would submit, calling
+// method of the gas price oracle at to with value, in place of signing and
+// sending it
+func dryRun(channel string, trace *decisionTrace, to common.Address, opts *bind.TransactOpts, method, key string, value *big.Int) error {
+	parsed, err := bindings.BVMGasPriceOracleMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	data, err := parsed.Pack(method, value)
+	if err != nil {
+		return err
+	}
+	log.Info("Dry run would submit", "channel", channel, "to", to.Hex(), "data", hexutil.Encode(data),
+		key, value, "tx_gas_price", opts.GasPrice, "tipCap", opts.GasTipCap, "feeCap", opts.GasFeeCap)
+	trace.output(key, value)
+	trace.output("to", to.Hex())
+	trace.output("calldata", hexutil.Encode(data))
+	trace.act(actionObserve, reasonDryRun, "dry run")
+	dryRunGauge(channel).Update(value.Int64())
+	return nil
+}
+
+// dryRunOnly returns a backend that refuses to send the transactions of a
+// channel in dry-run mode, so that nothing is written even if a code path
+// misses the check
+func dryRunOnly(channel string, backend DeployContractBackend) DeployContractBackend {
+	return &observedBackend{backendDecorator: decorate(backend), channel: channel, err: errDryRun}
+}
+
+func dryRunGauge(channel string) metrics.Gauge {
+	return metrics.GetOrRegisterGauge("dry_run/"+metricName(channel), ometrics.DefaultRegistry)
+}
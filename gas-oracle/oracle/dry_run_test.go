@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs records the context of the log records with the message
+// until the returned function is called
+func captureLogs(msg string) (*[]map[string]interface{}, func()) {
+	var records []map[string]interface{}
+	handler := log.Root().GetHandler()
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Msg == msg {
+			ctx := make(map[string]interface{})
+			for i := 0; i+1 < len(r.Ctx); i += 2 {
+				ctx[r.Ctx[i].(string)] = r.Ctx[i+1]
+			}
+			records = append(records, ctx)
+		}
+		return nil
+	}))
+	return &records, func() { log.Root().SetHandler(handler) }
+}
+
+func TestDryRunNeverSigns(t *testing.T) {
+	owner, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(owner)
+	opts, _ := bind.NewKeyedTransactorWithChainID(owner, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// The key of a dry run need not be the owner, nor be funded
+	key, _ := crypto.GenerateKey()
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		gasPriceSource:        gasPriceSourceFixed,
+		dryRun:                true,
+	}
+	recorder := &buildRecorder{DeployContractBackend: sim}
+	records, restore := captureLogs("Dry run would submit")
+	defer restore()
+
+	baseFeeTrace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	updateBaseFee, err := wrapUpdateBaseFee(sim, recorder, cfg, channelGuards{trace: baseFeeTrace})
+	require.NoError(t, err)
+	tip := sim.Blockchain().CurrentHeader()
+	require.NoError(t, baseFeeTrace.wrap(updateBaseFee)())
+
+	gasPriceTrace := newDecisionTrace(l2GasPriceChannel, nil, nil)
+	updateGasPrice, err := wrapUpdateL2GasPriceFn(recorder, cfg, channelGuards{trace: gasPriceTrace})
+	require.NoError(t, err)
+	require.NoError(t, gasPriceTrace.wrap(func() error { return updateGasPrice(5e9) })())
+	sim.Commit()
+
+	// Nothing is built, sent or written
+	require.Zero(t, recorder.nonces, "transaction built in a dry run")
+	require.Zero(t, recorder.sent, "transaction sent in a dry run")
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 0, l1BaseFee.Cmp(common.Big0))
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 0, gasPrice.Cmp(common.Big0))
+
+	// while the computed values are logged with the calldata and target
+	// that they would be submitted with
+	parsed, err := bindings.BVMGasPriceOracleMetaData.GetAbi()
+	require.NoError(t, err)
+	baseFeeData, err := parsed.Pack("setL1BaseFee", tip.BaseFee)
+	require.NoError(t, err)
+	gasPriceData, err := parsed.Pack("setGasPrice", big.NewInt(5e9))
+	require.NoError(t, err)
+	require.Len(t, *records, 2)
+	require.Equal(t, l1BaseFeeChannel, (*records)[0]["channel"])
+	require.Equal(t, addr.Hex(), (*records)[0]["to"])
+	require.Equal(t, hexutil.Encode(baseFeeData), (*records)[0]["data"])
+	require.Equal(t, tip.BaseFee, (*records)[0]["l1_base_fee"])
+	require.Equal(t, l2GasPriceChannel, (*records)[1]["channel"])
+	require.Equal(t, hexutil.Encode(gasPriceData), (*records)[1]["data"])
+
+	decision, ok := baseFeeTrace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionObserve, decision.Action)
+	require.Equal(t, reasonDryRun, decision.ReasonCode)
+	require.Equal(t, hexutil.Encode(baseFeeData), decision.Outputs["calldata"])
+	require.Equal(t, int64(5e9), dryRunGauge(l2GasPriceChannel).Value())
+}
+
+func TestDryRunBackendRefusesToSend(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	backend := dryRunOnly(l1BaseFeeChannel, sim)
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)), &types.LegacyTx{
+		To:       &common.Address{},
+		Gas:      21000,
+		GasPrice: big.NewInt(1e9),
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, backend.SendTransaction(context.Background(), tx), errDryRun)
+}
+
+func TestDryRunWithoutPrivateKey(t *testing.T) {
+	cfg := NewConfig(parseTestContext(t, "--dry-run"))
+	require.True(t, cfg.dryRun)
+	require.NotNil(t, cfg.privateKey)
+
+	cfg = NewConfig(newTestContext(t))
+	require.False(t, cfg.dryRun)
+}
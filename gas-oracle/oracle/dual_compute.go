@@ -0,0 +1,123 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errPipelinesDisagree represents the error when the two pipelines of the
+// dual-compute mode produce values that are not within tolerance
+var errPipelinesDisagree = errors.New("dual-compute pipelines disagree")
+
+// dualComputeBackend reads the inputs of a channel through two independent
+// pipelines and only hands them over when both agree within tolerance. A
+// bug or a compromised source in one pipeline then stops the update
+// instead of being written. Everything else is served by the primary.
+type dualComputeBackend struct {
+	bind.ContractTransactor
+	secondary bind.ContractTransactor
+	channel   string
+	tolerance float64
+}
+
+func newDualComputeBackend(channel string, primary, secondary bind.ContractTransactor, tolerance float64) *dualComputeBackend {
+	return &dualComputeBackend{
+		ContractTransactor: primary,
+		secondary:          secondary,
+		channel:            channel,
+		tolerance:          tolerance,
+	}
+}
+
+// HeaderByNumber returns the header of the primary once the base fees of
+// both pipelines agree. The secondary is read at the block of the primary,
+// as the heads of two sources are seldom the same block.
+func (b *dualComputeBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	primary, err := b.ContractTransactor.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := b.secondary.HeaderByNumber(ctx, primary.Number)
+	if err != nil {
+		return nil, fmt.Errorf("secondary pipeline: %w", err)
+	}
+	if primary.BaseFee == nil || secondary.BaseFee == nil {
+		return nil, errNoBaseFee
+	}
+	if err := b.check(primary.BaseFee, secondary.BaseFee); err != nil {
+		return nil, err
+	}
+	return primary, nil
+}
+
+// FeeHistory returns the fee history of the primary once the average base
+// fees of both pipelines agree
+func (b *dualComputeBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	primaryReader, ok := b.ContractTransactor.(FeeHistoryReader)
+	if !ok {
+		return nil, errNoFeeHistory
+	}
+	secondaryReader, ok := b.secondary.(FeeHistoryReader)
+	if !ok {
+		return nil, errNoFeeHistory
+	}
+	primary, err := primaryReader.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := secondaryReader.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, fmt.Errorf("secondary pipeline: %w", err)
+	}
+	primaryFee, err := averageBaseFee(primary)
+	if err != nil {
+		return nil, err
+	}
+	secondaryFee, err := averageBaseFee(secondary)
+	if err != nil {
+		return nil, fmt.Errorf("secondary pipeline: %w", err)
+	}
+	if err := b.check(primaryFee, secondaryFee); err != nil {
+		return nil, err
+	}
+	return primary, nil
+}
+
+func (b *dualComputeBackend) check(primary, secondary *big.Int) error {
+	if withinTolerance(primary, secondary, b.tolerance) {
+		return nil
+	}
+	log.Error("Dual-compute pipelines disagree, not updating", "channel", b.channel,
+		"primary", primary, "secondary", secondary, "tolerance", b.tolerance)
+	disagreementCounter(b.channel).Inc(1)
+	return fmt.Errorf("%w: primary %d, secondary %d", errPipelinesDisagree, primary, secondary)
+}
+
+// withinTolerance returns true when a and b differ by at most tolerance
+// relative to the larger of the two
+func withinTolerance(a, b *big.Int, tolerance float64) bool {
+	larger := a
+	if b.Cmp(a) > 0 {
+		larger = b
+	}
+	if larger.Sign() == 0 {
+		return true
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Abs(new(big.Int).Sub(a, b)))
+	relative, _ := diff.Quo(diff, new(big.Float).SetInt(larger)).Float64()
+	return relative <= tolerance
+}
+
+func disagreementCounter(channel string) metrics.Counter {
+	name := "dual_compute/" + metricName(channel) + "/disagreements"
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDualComputeWritesOnAgreement(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	primary := &syntheticL1{baseFees: []int64{2_000_000_000}}
+	secondary := &syntheticL1{baseFees: []int64{2_010_000_000}}
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	l1 := newDualComputeBackend(l1BaseFeeChannel, primary, secondary, 0.01)
+	update, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{})
+	require.NoError(t, err)
+
+	// The pipelines agree within tolerance, the primary is written
+	require.NoError(t, update())
+	sim.Commit()
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2_000_000_000), l1BaseFee)
+
+	// A pipeline that goes astray stops the update
+	primary.baseFees[0] = 4_000_000_000
+	before := disagreementCounter(l1BaseFeeChannel).Count()
+	require.ErrorIs(t, update(), errPipelinesDisagree)
+	sim.Commit()
+	l1BaseFee, err = gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2_000_000_000), l1BaseFee)
+	require.Equal(t, before+1, disagreementCounter(l1BaseFeeChannel).Count())
+}
+
+func TestDualComputeReadsSecondaryAtPrimaryHead(t *testing.T) {
+	// The secondary is one block ahead, with a base fee far from the one
+	// of the head of the primary
+	primary := &syntheticL1{tip: 1, baseFees: []int64{100, 200}}
+	secondary := &syntheticL1{tip: 2, baseFees: []int64{100, 200, 900}}
+	l1 := newDualComputeBackend(l1BaseFeeChannel, primary, secondary, 0)
+
+	header, err := l1.HeaderByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), header.Number)
+	require.Equal(t, big.NewInt(200), header.BaseFee)
+
+	// A secondary that has not reached the head of the primary yet fails
+	// the read rather than comparing older blocks
+	primary.tip, primary.baseFees = 3, []int64{100, 200, 900, 900}
+	_, err = l1.HeaderByNumber(context.Background(), nil)
+	require.ErrorIs(t, err, ethereum.NotFound)
+}
+
+func TestDualComputeFeeHistory(t *testing.T) {
+	primary := &syntheticL1{baseFees: []int64{100, 200, 300, 400, 500}}
+	secondary := &syntheticL1{baseFees: []int64{100, 200, 300, 400, 500}}
+	l1 := newDualComputeBackend(l1BaseFeeChannel, primary, secondary, 0)
+
+	fee, err := epochBaseFee(context.Background(), l1, 4, 4)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(250), fee)
+
+	secondary.baseFees[1] = 1000
+	_, err = epochBaseFee(context.Background(), l1, 4, 4)
+	require.ErrorIs(t, err, errPipelinesDisagree)
+}
+
+func TestWithinTolerance(t *testing.T) {
+	require.True(t, withinTolerance(big.NewInt(100), big.NewInt(100), 0))
+	require.True(t, withinTolerance(big.NewInt(100), big.NewInt(101), 0.01))
+	require.True(t, withinTolerance(big.NewInt(101), big.NewInt(100), 0.01))
+	require.False(t, withinTolerance(big.NewInt(100), big.NewInt(110), 0.05))
+	require.True(t, withinTolerance(big.NewInt(0), big.NewInt(0), 0))
+	require.False(t, withinTolerance(big.NewInt(0), big.NewInt(1), 0.5))
+}
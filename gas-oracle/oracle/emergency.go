@@ -0,0 +1,118 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// emergencyMode widens the significance factor of a channel while it is
+// unstable, to avoid update storms during extreme volatility. The channel
+// enters the emergency mode when it sends threshold updates within the
+// window, and leaves it once fewer than half of that many remain within the
+// window. A nil emergencyMode never engages.
+type emergencyMode struct {
+	channel   string
+	threshold int
+	window    time.Duration
+	factor    float64
+	now       func() time.Time
+
+	mu      sync.Mutex
+	updates []time.Time
+	engaged bool
+}
+
+// newEmergencyMode creates the emergency mode of the channel, or returns nil
+// when the threshold is zero
+func newEmergencyMode(channel string, threshold uint64, window time.Duration, factor float64) *emergencyMode {
+	if threshold == 0 {
+		return nil
+	}
+	return &emergencyMode{
+		channel:   channel,
+		threshold: int(threshold),
+		window:    window,
+		factor:    factor,
+		now:       time.Now,
+	}
+}
+
+// significanceFactor returns the factor that the channel uses in place of
+// normal. The emergency factor is only used when it is wider.
+func (e *emergencyMode) significanceFactor(normal float64) float64 {
+	if e == nil {
+		return normal
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.transition()
+	if e.engaged && e.factor > normal {
+		return e.factor
+	}
+	return normal
+}
+
+// updated records that the channel sent an update
+func (e *emergencyMode) updated() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.updates = append(e.updates, e.now())
+	e.transition()
+}
+
+// active returns true while the channel is in the emergency mode
+func (e *emergencyMode) active() bool {
+	if e == nil {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.transition()
+	return e.engaged
+}
+
+// transition drops the updates that left the window and engages or
+// reverts the emergency mode. It must be called with mu held.
+func (e *emergencyMode) transition() {
+	cutoff := e.now().Add(-e.window)
+	i := 0
+	for i < len(e.updates) && !e.updates[i].After(cutoff) {
+		i++
+	}
+	e.updates = e.updates[i:]
+
+	switch {
+	case !e.engaged && len(e.updates) >= e.threshold:
+		e.engaged = true
+		log.Warn("Channel unstable, engaging emergency significance factor", "channel", e.channel,
+			"updates", len(e.updates), "window", e.window, "factor", e.factor)
+		emergencyGauge(e.channel).Update(1)
+		emergencyTransitionCounter(e.channel).Inc(1)
+	case e.engaged && len(e.updates) < (e.threshold+1)/2:
+		e.engaged = false
+		log.Info("Channel stable, reverting to normal significance factor", "channel", e.channel,
+			"updates", len(e.updates), "window", e.window)
+		emergencyGauge(e.channel).Update(0)
+		emergencyTransitionCounter(e.channel).Inc(1)
+	}
+}
+
+func emergencyGauge(channel string) metrics.Gauge {
+	name := "emergency/" + metricName(channel)
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
+
+func emergencyTransitionCounter(channel string) metrics.Counter {
+	name := "emergency/" + metricName(channel) + "/transitions"
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,90 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencyModeEngagesAndReverts(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	e := newEmergencyMode(l1BaseFeeChannel, 4, 10*time.Minute, 0.5)
+	e.now = func() time.Time { return now }
+
+	// Rapid updates engage the emergency factor
+	for i := 0; i < 3; i++ {
+		e.updated()
+		now = now.Add(time.Minute)
+	}
+	require.False(t, e.active())
+	require.Equal(t, 0.1, e.significanceFactor(0.1))
+	e.updated()
+	require.True(t, e.active())
+	require.Equal(t, 0.5, e.significanceFactor(0.1))
+	require.Equal(t, int64(1), emergencyGauge(l1BaseFeeChannel).Value())
+
+	// A normal factor wider than the emergency one is kept
+	require.Equal(t, 0.8, e.significanceFactor(0.8))
+
+	// The emergency holds while updates remain within the window
+	now = now.Add(8 * time.Minute)
+	require.True(t, e.active())
+
+	// It reverts once the channel calms down
+	now = now.Add(2 * time.Minute)
+	require.False(t, e.active())
+	require.Equal(t, 0.1, e.significanceFactor(0.1))
+	require.Equal(t, int64(0), emergencyGauge(l1BaseFeeChannel).Value())
+
+	// Disabled without a threshold
+	require.Nil(t, newEmergencyMode(l1BaseFeeChannel, 0, time.Minute, 0.5))
+	var none *emergencyMode
+	none.updated()
+	require.False(t, none.active())
+	require.Equal(t, 0.1, none.significanceFactor(0.1))
+}
+
+func TestEmergencyModeDampensBaseFeeUpdates(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	emergency := newEmergencyMode(l1BaseFeeChannel, 2, time.Hour, 0.5)
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{emergency: emergency})
+	require.NoError(t, err)
+
+	// The simulated base fee moves by more than the normal factor between
+	// blocks, so every epoch updates until the emergency engages
+	for i := 0; i < 2; i++ {
+		tip := sim.Blockchain().CurrentHeader()
+		require.NoError(t, update())
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, 0, l1BaseFee.Cmp(tip.BaseFee))
+	}
+	require.True(t, emergency.active())
+
+	before, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.NoError(t, update())
+	sim.Commit()
+	after, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 0, before.Cmp(after), "base fee updated in the emergency mode")
+}
@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// feeGaugeNames are the names of the gauges of the value of every channel
+// in the contract
+var feeGaugeNames = map[string]string{
+	l1BaseFeeChannel:  "l1_base_fee",
+	l2GasPriceChannel: "gas_price",
+	daFeeChannel:      "da_fee",
+	overheadChannel:   "overhead",
+	scalarChannel:     "scalar",
+}
+
+// reportCurrent exports the value of the channel read from the contract
+func reportCurrent(channel string, value *big.Int) {
+	feeGauge(channel).Update(value.Int64())
+}
+
+// reportWritten exports the value that an update of the channel wrote
+// along with when it was sent, the unix time of `last_update/<channel>`
+func reportWritten(channel string, value *big.Int) {
+	feeGauge(channel).Update(value.Int64())
+	lastUpdateGauge(channel).Update(time.Now().Unix())
+}
+
+// reportSendFailure counts the update of the channel that could not be
+// sent in `tx/send_failures/<channel>`. The updates that were held back on
+// purpose are not failures.
+func reportSendFailure(channel string, err error) {
+	if errors.Is(err, errThrottled) || errors.Is(err, errBudgetExceeded) {
+		return
+	}
+	sendFailuresCounter(channel).Inc(1)
+}
+
+func feeGauge(channel string) metrics.Gauge {
+	return metrics.GetOrRegisterGauge(feeGaugeNames[channel], ometrics.DefaultRegistry)
+}
+
+func lastUpdateGauge(channel string) metrics.Gauge {
+	return metrics.GetOrRegisterGauge("last_update/"+metricName(channel), ometrics.DefaultRegistry)
+}
+
+func sendFailuresCounter(channel string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/send_failures/"+metricName(channel), ometrics.DefaultRegistry)
+}
@@ -0,0 +1,85 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSender fails every transaction that it is asked to send
+type failingSender struct {
+	DeployContractBackend
+}
+
+func (b *failingSender) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return errors.New("nonce too low")
+}
+
+// scrape returns the metrics served to Prometheus
+func scrape(t *testing.T) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	ometrics.Prometheus(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestFeeMetrics(t *testing.T) {
+	for _, channel := range []string{l1BaseFeeChannel, l2GasPriceChannel, daFeeChannel} {
+		reportCurrent(channel, big.NewInt(1e9))
+		reportWritten(channel, big.NewInt(2e9))
+	}
+	failures := sendFailuresCounter(l1BaseFeeChannel).Count()
+	reportSendFailure(l1BaseFeeChannel, errThrottled)
+	reportSendFailure(l1BaseFeeChannel, errBudgetExceeded)
+	require.Equal(t, failures, sendFailuresCounter(l1BaseFeeChannel).Count(), "held back updates are not failures")
+	require.Equal(t, int64(2e9), feeGauge(daFeeChannel).Value())
+	require.NotZero(t, lastUpdateGauge(daFeeChannel).Value())
+
+	// A transaction that cannot be sent is a failure
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		gasPriceSource:        gasPriceSourceFixed,
+	}
+	update, err := wrapUpdateBaseFee(sim, &failingSender{DeployContractBackend: sim}, cfg, channelGuards{})
+	require.NoError(t, err)
+	require.Error(t, update())
+	require.Equal(t, failures+1, sendFailuresCounter(l1BaseFeeChannel).Count())
+
+	body := scrape(t)
+	for _, name := range []string{
+		"gas_price",
+		"l1_base_fee",
+		"da_fee",
+		"last_update_l1_base_fee",
+		"last_update_l2_gas_price",
+		"last_update_da_fee",
+		"tx_send_failures_l1_base_fee",
+	} {
+		require.Contains(t, body, "# TYPE "+name+" ", name)
+	}
+}
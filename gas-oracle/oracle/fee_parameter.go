@@ -0,0 +1,187 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+)
+
+// feeParameter is a parameter of the L1 fee that the `BVM_GasPriceOracle`
+// exposes next to the gas prices, which is moved to a configured target
+type feeParameter struct {
+	channel string
+	// key names the parameter in the decisions and the logs
+	key    string
+	method string
+	target uint64
+	factor float64
+	read   func(*bindings.BVMGasPriceOracle, *bind.CallOpts) (*big.Int, error)
+	write  func(*bindings.BVMGasPriceOracle, *bind.TransactOpts, *big.Int) (*types.Transaction, error)
+}
+
+// overheadParameter returns the overhead parameter as configured
+func (c *Config) overheadParameter() *feeParameter {
+	return &feeParameter{
+		channel: overheadChannel,
+		key:     "overhead",
+		method:  "setOverhead",
+		target:  c.overhead,
+		factor:  c.overheadSignificanceFactor,
+		read: func(contract *bindings.BVMGasPriceOracle, opts *bind.CallOpts) (*big.Int, error) {
+			return contract.Overhead(opts)
+		},
+		write: func(contract *bindings.BVMGasPriceOracle, opts *bind.TransactOpts, value *big.Int) (*types.Transaction, error) {
+			return contract.SetOverhead(opts, value)
+		},
+	}
+}
+
+// scalarParameter returns the scalar parameter as configured
+func (c *Config) scalarParameter() *feeParameter {
+	return &feeParameter{
+		channel: scalarChannel,
+		key:     "scalar",
+		method:  "setScalar",
+		target:  c.scalar,
+		factor:  c.scalarSignificanceFactor,
+		read: func(contract *bindings.BVMGasPriceOracle, opts *bind.CallOpts) (*big.Int, error) {
+			return contract.Scalar(opts)
+		},
+		write: func(contract *bindings.BVMGasPriceOracle, opts *bind.TransactOpts, value *big.Int) (*types.Transaction, error) {
+			return contract.SetScalar(opts, value)
+		},
+	}
+}
+
+// wrapUpdateFeeParameter returns a function that writes the target of the
+// parameter to the contract once the value on chain differs from it by
+// more than the significance factor of the parameter
+func wrapUpdateFeeParameter(param *feeParameter, l2Backend DeployContractBackend, cfg *Config, guards channelGuards) (func() error, error) {
+	opts, err := cfg.transactor()
+	if err != nil {
+		return nil, err
+	}
+	// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
+	// then we can remove setting the context here
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	// Don't send the transaction using the `contract` so that we can inspect
+	// it beforehand
+	opts.NoSend = true
+
+	contract, err := bindings.NewBVMGasPriceOracle(cfg.gasPriceOracleAddress, l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	shadows, err := newShadowOracles(param.channel, cfg, l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	target := new(big.Int).SetUint64(param.target)
+
+	return func() error {
+		l2Block, err := headNumber(guards.deadline.context(), l2Backend)
+		if err != nil {
+			return err
+		}
+		guards.trace.l2Block(l2Block)
+		current, err := param.read(contract, &bind.CallOpts{
+			Context:     guards.deadline.context(),
+			BlockNumber: l2Block,
+		})
+		if err != nil {
+			return err
+		}
+		guards.stuck.observed(current)
+		reportCurrent(param.channel, current)
+		guards.drops.observed(current)
+		guards.trace.input("current_"+param.key, current)
+		guards.trace.input(param.key, target)
+		reference := guards.sent.reference(param.channel, current)
+		if reference != current {
+			guards.trace.input("last_sent_"+param.key, reference)
+		}
+		factor := guards.emergency.significanceFactor(param.factor)
+		factor = guards.reversals.significanceFactor(factor, current, target)
+		if !isDifferenceSignificant(reference.Uint64(), target.Uint64(), factor) {
+			log.Debug("non significant "+param.key+" update", "target", target, "current", reference)
+			guards.trace.act(actionSkip, reasonBelowSignificance, "not significant")
+			return nil
+		}
+		if reference.Cmp(target) == 0 {
+			log.Debug(param.key+" did not change", "target", target)
+			guards.trace.act(actionSkip, reasonUnchanged, "not changed")
+			return nil
+		}
+
+		// A channel that was just enabled may ease from a stale value
+		value := guards.grace.next(current, target)
+		if cfg.observes(param.channel) {
+			observe(param.channel, guards.trace, param.key, value)
+			return nil
+		}
+		if err := guards.drops.check(current, value); err != nil {
+			return err
+		}
+		if guards.deadline.exceeded() {
+			return errEpochAborted
+		}
+
+		// The update is built and sent under the context of the loop
+		opts.Context = guards.deadline.epoch()
+		if err := txFees(opts.Context, l2Backend, cfg, opts); err != nil {
+			return err
+		}
+		if cfg.dryRun {
+			return dryRun(param.channel, guards.trace, cfg.gasPriceOracleAddress, opts, param.method, param.key, value)
+		}
+
+		tx, err := param.write(contract, opts, value)
+		if err == nil {
+			log.Debug("updating "+param.key, "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
+				"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+			if err = l2Backend.SendTransaction(opts.Context, tx); err != nil {
+				reportSendFailure(param.channel, err)
+				err = fmt.Errorf("cannot update %s: %w", param.key, err)
+			}
+		}
+		reportOracleSend(param.channel, cfg.gasPriceOracleAddress, err)
+		// The shadow contracts are updated whether or not the primary was
+		shadows.send(opts.Context, func(shadow *bindings.BVMGasPriceOracle) (*types.Transaction, error) {
+			return param.write(shadow, opts, value)
+		})
+		if err != nil {
+			return err
+		}
+		log.Info(param.key+" transaction sent", "epoch", guards.trace.epoch(), "hash", tx.Hash().Hex(),
+			param.key, value, "l2-block", l2Block)
+		reportBlocks(param.channel, nil, l2Block)
+		reportWritten(param.channel, value)
+		guards.sent.sent(param.channel, value)
+		guards.trace.output(param.key, value)
+		guards.trace.output("tx_hash", tx.Hash().Hex())
+		guards.trace.act(actionUpdate, updateReason(target, value), "")
+		guards.emergency.updated()
+		guards.stuck.wrote(current, value)
+		guards.reversals.wrote(current, value)
+
+		if cfg.waitForReceipt {
+			// Wait for the receipt
+			receipt, err := waitForReceipt(opts.Context, l2Backend, tx, cfg.receiptBackoff)
+			if err != nil {
+				return err
+			}
+
+			log.Info(param.key+" transaction confirmed", "hash", tx.Hash().Hex(),
+				"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
+		}
+		return nil
+	}, nil
+}
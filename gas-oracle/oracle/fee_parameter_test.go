@@ -0,0 +1,142 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateScalar(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	_, err = gpo.SetScalar(opts, big.NewInt(1_000_000))
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:               key,
+		l2ChainID:                big.NewInt(1337),
+		gasPriceOracleAddress:    addr,
+		gasPrice:                 big.NewInt(1_000_000_000),
+		scalarSignificanceFactor: 0.05,
+	}
+	scalar := func() uint64 {
+		value, err := gpo.Scalar(&bind.CallOpts{})
+		require.NoError(t, err)
+		return value.Uint64()
+	}
+
+	// A target within the significance factor is not written
+	cfg.scalar = 1_020_000
+	backend := &buildRecorder{DeployContractBackend: sim}
+	update, err := wrapUpdateFeeParameter(cfg.scalarParameter(), backend, cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update())
+	sim.Commit()
+	require.Equal(t, 0, backend.sent)
+	require.Equal(t, uint64(1_000_000), scalar())
+
+	// A target beyond it calls the setter
+	cfg.scalar = 1_100_000
+	update, err = wrapUpdateFeeParameter(cfg.scalarParameter(), backend, cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update())
+	sim.Commit()
+	require.Equal(t, 1, backend.sent)
+	require.Equal(t, uint64(1_100_000), scalar())
+
+	// and once it is reached nothing more is sent
+	require.NoError(t, update())
+	sim.Commit()
+	require.Equal(t, 1, backend.sent)
+}
+
+func TestUpdateOverhead(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:                 key,
+		l2ChainID:                  big.NewInt(1337),
+		gasPriceOracleAddress:      addr,
+		gasPrice:                   big.NewInt(1_000_000_000),
+		overhead:                   2100,
+		overheadSignificanceFactor: 0.05,
+	}
+	trace := newDecisionTrace(overheadChannel, nil, nil)
+	update, err := wrapUpdateFeeParameter(cfg.overheadParameter(), sim, cfg, channelGuards{trace: trace})
+	require.NoError(t, err)
+	require.NoError(t, trace.wrap(update)())
+	sim.Commit()
+
+	overhead, err := gpo.Overhead(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2100), overhead.Uint64())
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionUpdate, decision.Action)
+	require.Equal(t, big.NewInt(2100), decision.Outputs["overhead"])
+}
+
+func TestUpdateFeeParameterGuards(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	_, err = gpo.SetScalar(opts, big.NewInt(1_000_000))
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:               key,
+		l2ChainID:                big.NewInt(1337),
+		gasPriceOracleAddress:    addr,
+		gasPrice:                 big.NewInt(1_000_000_000),
+		scalar:                   2_000_000,
+		scalarSignificanceFactor: 0.05,
+	}
+	scalar := func() uint64 {
+		value, err := gpo.Scalar(&bind.CallOpts{})
+		require.NoError(t, err)
+		return value.Uint64()
+	}
+
+	// An easing channel moves towards its target by the max change
+	trace := newDecisionTrace(scalarChannel, nil, nil)
+	update, err := wrapUpdateFeeParameter(cfg.scalarParameter(), sim, cfg, channelGuards{
+		trace: trace,
+		grace: newEnableGrace(scalarChannel, graceEase, 0.1),
+	})
+	require.NoError(t, err)
+	require.NoError(t, trace.wrap(update)())
+	sim.Commit()
+	require.Equal(t, uint64(1_100_000), scalar())
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, reasonClamped, decision.ReasonCode)
+
+	// and a drop beyond the limit is held back
+	cfg.scalar = 500_000
+	update, err = wrapUpdateFeeParameter(cfg.scalarParameter(), sim, cfg, channelGuards{
+		drops: newDropLimit(scalarChannel, 0.2, time.Hour),
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, update(), errDropLimited)
+	sim.Commit()
+	require.Equal(t, uint64(1_100_000), scalar())
+}
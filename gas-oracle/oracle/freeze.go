@@ -0,0 +1,94 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// freezer holds the value of a channel flat until a deadline, for example
+// to avoid propagating an L1 fee spike that is known to be transient. The
+// freeze expires on its own.
+type freezer struct {
+	mu      sync.Mutex
+	channel string
+	until   time.Time
+	max     time.Duration
+	now     func() time.Time
+}
+
+// newFreezer creates a freezer for the channel. A freeze is capped to max,
+// a zero max does not cap it.
+func newFreezer(channel string, max time.Duration) *freezer {
+	return &freezer{
+		channel: channel,
+		max:     max,
+		now:     time.Now,
+	}
+}
+
+// freeze holds the channel for the given duration, replacing any freeze in
+// place, and returns when the freeze expires
+func (f *freezer) freeze(duration time.Duration) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.max > 0 && duration > f.max {
+		duration = f.max
+	}
+	f.until = f.now().Add(duration)
+	log.Info("Channel frozen", "channel", f.channel, "until", f.until)
+	frozenGauge(f.channel).Update(1)
+	return f.until
+}
+
+// thaw lifts the freeze right away
+func (f *freezer) thaw() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.until.IsZero() {
+		log.Info("Channel thawed", "channel", f.channel)
+	}
+	f.until = time.Time{}
+	frozenGauge(f.channel).Update(0)
+}
+
+// frozenUntil returns when the freeze in place expires, or false when the
+// channel is not frozen
+func (f *freezer) frozenUntil() (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.until.IsZero() {
+		return time.Time{}, false
+	}
+	if !f.now().Before(f.until) {
+		log.Info("Channel freeze expired", "channel", f.channel)
+		f.until = time.Time{}
+		frozenGauge(f.channel).Update(0)
+		return time.Time{}, false
+	}
+	return f.until, true
+}
+
+// wrap skips update while the channel is frozen, which keeps the value
+// that was last set in place
+func (f *freezer) wrap(update func() error, trace *decisionTrace) func() error {
+	return func() error {
+		if until, frozen := f.frozenUntil(); frozen {
+			log.Debug("channel frozen, skipping update", "channel", f.channel, "until", until)
+			trace.act(actionSkip, reasonFrozen, "frozen until "+until.UTC().Format(time.RFC3339))
+			return nil
+		}
+		return update()
+	}
+}
+
+func frozenGauge(channel string) metrics.Gauge {
+	name := "freeze/" + metricName(channel)
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
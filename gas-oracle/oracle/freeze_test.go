@@ -0,0 +1,110 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeHoldsBaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{})
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	f := newFreezer(l1BaseFeeChannel, time.Hour)
+	f.now = func() time.Time { return now }
+	update = f.wrap(update, nil)
+
+	until := f.freeze(10 * time.Minute)
+	require.Equal(t, now.Add(10*time.Minute), until)
+
+	// The base fee holds at its value from before the freeze
+	for i := 0; i < 3; i++ {
+		now = now.Add(3 * time.Minute)
+		require.NoError(t, update())
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, 0, l1BaseFee.Cmp(common.Big0), "base fee updated while frozen")
+	}
+
+	// Updates resume once the freeze expires
+	now = now.Add(time.Minute)
+	tip := sim.Blockchain().CurrentHeader()
+	require.NoError(t, update())
+	sim.Commit()
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 0, l1BaseFee.Cmp(tip.BaseFee), "base fee not updated after the freeze")
+	_, frozen := f.frozenUntil()
+	require.False(t, frozen)
+}
+
+func TestFreezeIsCapped(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	f := newFreezer(l1BaseFeeChannel, time.Hour)
+	f.now = func() time.Time { return now }
+
+	require.Equal(t, now.Add(time.Hour), f.freeze(24*time.Hour))
+	f.thaw()
+	_, frozen := f.frozenUntil()
+	require.False(t, frozen)
+}
+
+func TestFreezeHandler(t *testing.T) {
+	g := &GasPriceOracle{
+		drainer:        new(drainer),
+		baseFeeFreezer: newFreezer(l1BaseFeeChannel, time.Hour),
+	}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+
+	do := func(method, target string) (int, map[string]string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		return rec.Code, body
+	}
+
+	code, body := do(http.MethodGet, "/freeze")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "thawed", body["status"])
+
+	code, _ = do(http.MethodPost, "/freeze?duration=soon")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, body = do(http.MethodPost, "/freeze?duration=30m")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "frozen", body["status"])
+	require.NotEmpty(t, body["until"])
+
+	code, body = do(http.MethodDelete, "/freeze")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "thawed", body["status"])
+
+	code, _ = do(http.MethodPut, "/freeze")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+}
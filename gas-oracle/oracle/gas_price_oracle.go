@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"runtime/pprof"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
 	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
 	"github.com/mantlenetworkio/mantle/gas-oracle/gasprices"
 	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
@@ -39,28 +42,107 @@ type GasPriceOracle struct {
 	l1ChainID       *big.Int
 	l2ChainID       *big.Int
 	ctx             context.Context
+	cancel          context.CancelFunc
 	stop            chan struct{}
 	contract        *bindings.BVMGasPriceOracle
 	l2Backend       DeployContractBackend
 	l1Backend       bind.ContractTransactor
-	daBackend       *bindings.BVMEigenDataLayrFee
-	gasPriceUpdater *gasprices.GasPriceUpdater
+	l1RawBackend    bind.ContractTransactor
+	baseFeeBackend  DeployContractBackend
+	daBackend       bind.ContractBackend
+	daFeeBackend    DeployContractBackend
+	overheadBackend DeployContractBackend
+	scalarBackend   DeployContractBackend
+	gasPriceUpdater l2GasPricer
+	inclusion       *inclusionTracker
+	submissions     *submissionTracker
+	resubmit        *resubmitter
+	budget          *spendBudget
+	tokenPricer     *tokenprice.Client
+	priceCache      *priceCache
+	l2FeeHistory    FeeHistoryReader
 	config          *Config
+	drainer         *drainer
+	modes           *modeReporter
+	baseFeeFreezer  *freezer
+	heartbeat       *heartbeat
+	pending         *pendingReceipts
+	sent            *sentState
+	state           *stateStore
+	deadlines       map[string]*inputDeadline
+	traces          map[string]*decisionTrace
+	history         *decisionHistory
+	emergencies     map[string]*emergencyMode
+	stuck           map[string]*stuckDetector
+	reversals       map[string]*reversalDamper
+	graces          map[string]*enableGrace
+	drops           map[string]*dropLimit
+	watchdog        *watchdog
+	l1Heads         HeadSubscriber
+	readiness       *readiness
+	daFeeModel      *daFeeModelSwitch
+	reference       *referenceFeed
+	alerts          *alerter
 }
 
 // Start runs the GasPriceOracle
 func (g *GasPriceOracle) Start() error {
+	if err := g.prepare(); err != nil {
+		return err
+	}
+
+	if g.config.enableL1BaseFee {
+		go g.BaseFeeLoop()
+	}
+	if g.config.enableDaFee {
+		go g.DaFeeLoop()
+	}
+	if g.config.enableL2GasPrice {
+		go g.Loop()
+	}
+	if g.config.enableOverhead {
+		go g.FeeParameterLoop(g.config.overheadParameter(), g.config.overheadEpochLengthSeconds)
+	}
+	if g.config.enableScalar {
+		go g.FeeParameterLoop(g.config.scalarParameter(), g.config.scalarEpochLengthSeconds)
+	}
+	if g.inclusion != nil {
+		go g.InclusionLoop()
+	}
+	if g.submissions != nil {
+		go g.SubmissionLoop()
+	}
+	if g.resubmit != nil {
+		go g.ResubmitLoop()
+	}
+	if g.config.heartbeatIntervalSeconds > 0 && !g.config.dryRun {
+		go g.HeartbeatLoop()
+	}
+	if len(g.config.tokenPriceSymbols) > 0 {
+		go g.TokenPriceLoop()
+	}
+	if g.priceCache != nil {
+		go g.TokenRatioLoop()
+	}
+	go g.watchdog.run(g.ctx)
+
+	return nil
+}
+
+// prepare checks the configuration and reports the state that the oracle
+// starts from
+func (g *GasPriceOracle) prepare() error {
 	if g.config.l1ChainID == nil {
 		return fmt.Errorf("layer-one: %w", errNoChainID)
 	}
 	if g.config.l2ChainID == nil {
 		return fmt.Errorf("layer-two: %w", errNoChainID)
 	}
-	if g.config.privateKey == nil {
+	if !g.config.hasSigner() {
 		return errNoPrivateKey
 	}
 
-	address := crypto.PubkeyToAddress(g.config.privateKey.PublicKey)
+	address := g.config.from()
 	log.Info("Starting Gas Price Oracle", "l1-chain-id", g.l1ChainID,
 		"l2-chain-id", g.l2ChainID, "address", address.Hex())
 
@@ -70,21 +152,31 @@ func (g *GasPriceOracle) Start() error {
 	if err != nil {
 		return err
 	}
-	gasPriceGauge.Update(int64(price.Uint64()))
+	reportCurrent(l2GasPriceChannel, price)
 
 	log.Info("Starting Gas Price Oracle enableL1BaseFee", "enableL1BaseFee",
-		g.config.enableL1BaseFee, "enableL2GasPrice", g.config.enableL2GasPrice, "enableDaFee", g.config.enableDaFee)
+		g.config.enableL1BaseFee, "enableL2GasPrice", g.config.enableL2GasPrice, "enableDaFee", g.config.enableDaFee,
+		"enableOverhead", g.config.enableOverhead, "enableScalar", g.config.enableScalar)
+	g.ReportModes()
 
+	// Estimate the update gas of every enabled channel for cost budgeting
+	backends := make(map[string]bind.ContractTransactor)
 	if g.config.enableL1BaseFee {
-		go g.BaseFeeLoop()
+		backends[l1BaseFeeChannel] = g.baseFeeBackend
 	}
 	if g.config.enableDaFee {
-		go g.DaFeeLoop()
+		backends[daFeeChannel] = g.daFeeBackend
 	}
 	if g.config.enableL2GasPrice {
-		go g.Loop()
+		backends[l2GasPriceChannel] = g.l2Backend
 	}
-
+	if g.config.enableOverhead {
+		backends[overheadChannel] = g.overheadBackend
+	}
+	if g.config.enableScalar {
+		backends[scalarChannel] = g.scalarBackend
+	}
+	reportUpdateGas(g.ctx, backends, address, g.config.gasPriceOracleAddress)
 	return nil
 }
 
@@ -106,8 +198,13 @@ func (g *GasPriceOracle) ensure() error {
 	if err != nil {
 		return err
 	}
-	address := crypto.PubkeyToAddress(g.config.privateKey.PublicKey)
+	address := g.config.from()
 	if address != owner {
+		// A dry run never signs, so it needs no key of the owner
+		if g.config.dryRun {
+			log.Warn("Signing key does not match contract owner", "signer", address.Hex(), "owner", owner.Hex())
+			return nil
+		}
 		log.Error("Signing key does not match contract owner", "signer", address.Hex(), "owner", owner.Hex())
 		return errInvalidSigningKey
 	}
@@ -116,71 +213,301 @@ func (g *GasPriceOracle) ensure() error {
 
 // Loop is the main logic of the gas-oracle
 func (g *GasPriceOracle) Loop() {
-	timer := time.NewTicker(time.Duration(g.config.epochLengthSeconds) * time.Second)
-	defer timer.Stop()
-
-	for {
-		select {
-		case <-timer.C:
-			log.Trace("polling", "time", time.Now())
-			if err := g.Update(); err != nil {
-				log.Error("cannot update gas price", "message", err)
-			}
+	interval := time.Duration(g.config.epochLengthSeconds) * time.Second
+	g.readiness.track(l2GasPriceChannel, interval)
+	g.loop(l2GasPriceChannel, interval, g.alerts.wrap(l2GasPriceChannel, g.gasPriceUpdate()))
+}
 
-		case <-g.ctx.Done():
-			g.Stop()
-		}
+func (g *GasPriceOracle) BaseFeeLoop() {
+	update, err := g.baseFeeUpdate()
+	if err != nil {
+		panic(err)
 	}
+	interval := time.Duration(g.config.l1BaseFeeEpochLengthSeconds) * time.Second
+	g.readiness.track(l1BaseFeeChannel, interval)
+	// Over a WebSocket the base fee is read on every new head of L1
+	g.loopOnHeads(l1BaseFeeChannel, interval, g.l1Heads, g.alerts.wrap(l1BaseFeeChannel, update))
 }
 
-func (g *GasPriceOracle) BaseFeeLoop() {
-	timer := time.NewTicker(time.Duration(g.config.l1BaseFeeEpochLengthSeconds) * time.Second)
-	defer timer.Stop()
+func (g *GasPriceOracle) DaFeeLoop() {
+	update, err := g.daFeeUpdate()
+	if err != nil {
+		panic(err)
+	}
+	interval := time.Duration(g.config.daFeeEpochLengthSeconds) * time.Second
+	g.readiness.track(daFeeChannel, interval)
+	g.loop(daFeeChannel, interval, g.alerts.wrap(daFeeChannel, update))
+}
 
-	updateBaseFee, err := wrapUpdateBaseFee(g.l1Backend, g.l2Backend, g.config)
+// FeeParameterLoop moves the fee parameter to its target every epoch
+func (g *GasPriceOracle) FeeParameterLoop(param *feeParameter, epochLengthSeconds uint64) {
+	update, err := g.feeParameterUpdate(param)
 	if err != nil {
 		panic(err)
 	}
+	interval := time.Duration(epochLengthSeconds) * time.Second
+	g.readiness.track(param.channel, interval)
+	g.loop(param.channel, interval, g.alerts.wrap(param.channel, update))
+}
 
-	for {
-		select {
-		case <-timer.C:
-			if err := updateBaseFee(); err != nil {
-				log.Error("cannot update l1 base fee", "messgae", err)
-			}
+// gasPriceUpdate returns an epoch of the L2 gas price
+func (g *GasPriceOracle) gasPriceUpdate() func() error {
+	return g.traces[l2GasPriceChannel].wrap(g.deadlines[l2GasPriceChannel].wrap(func() error {
+		log.Trace("polling", "time", time.Now())
+		return g.Update()
+	}))
+}
 
-		case <-g.ctx.Done():
-			g.Stop()
-		}
+// baseFeeUpdate returns an epoch of the L1 base fee
+func (g *GasPriceOracle) baseFeeUpdate() (func() error, error) {
+	updateBaseFee, err := wrapUpdateBaseFee(g.l1Backend, g.baseFeeBackend, g.config, g.guards(l1BaseFeeChannel))
+	if err != nil {
+		return nil, err
+	}
+
+	reportEffectiveScalar, err := wrapReportEffectiveScalar(g.l1RawBackend, g.baseFeeBackend, g.config, g.state)
+	if err != nil {
+		return nil, err
 	}
+	updateBaseFee = g.baseFeeFreezer.wrap(updateBaseFee, g.traces[l1BaseFeeChannel])
+
+	return g.traces[l1BaseFeeChannel].wrap(g.deadlines[l1BaseFeeChannel].wrap(func() error {
+		if err := updateBaseFee(); err != nil {
+			return err
+		}
+		if err := reportEffectiveScalar(); err != nil {
+			log.Warn("cannot report effective scalar", "message", err)
+		}
+		return nil
+	})), nil
 }
 
-func (g *GasPriceOracle) DaFeeLoop() {
-	timer := time.NewTicker(time.Duration(g.config.daFeeEpochLengthSeconds) * time.Second)
-	defer timer.Stop()
+// daFeeUpdate returns an epoch of the DA fee
+func (g *GasPriceOracle) daFeeUpdate() (func() error, error) {
+	var quotes tokenQuoter
+	if g.tokenPricer != nil {
+		quotes = g.tokenPricer
+	}
+	updateDaFee, err := wrapUpdateDaFee(g.daBackend, g.daFeeBackend, g.config, g.daFeeModel, quotes, g.guards(daFeeChannel))
+	if err != nil {
+		return nil, err
+	}
+	return g.traces[daFeeChannel].wrap(g.deadlines[daFeeChannel].wrap(updateDaFee)), nil
+}
 
-	updateDaFee, err := wrapUpdateDaFee(g.daBackend, g.l2Backend, g.config)
+// feeParameterUpdate returns an epoch of the fee parameter. Each parameter
+// is written through a submitter of its own, labelled with its channel.
+func (g *GasPriceOracle) feeParameterUpdate(param *feeParameter) (func() error, error) {
+	backend := g.overheadBackend
+	if param.channel == scalarChannel {
+		backend = g.scalarBackend
+	}
+	update, err := wrapUpdateFeeParameter(param, backend, g.config, g.guards(param.channel))
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	return g.traces[param.channel].wrap(g.deadlines[param.channel].wrap(update)), nil
+}
+
+// guards returns the collaborators of the updates of the channel
+func (g *GasPriceOracle) guards(channel string) channelGuards {
+	return channelGuards{
+		deadline:  g.deadlines[channel],
+		trace:     g.traces[channel],
+		emergency: g.emergencies[channel],
+		stuck:     g.stuck[channel],
+		reversals: g.reversals[channel],
+		grace:     g.graces[channel],
+		drops:     g.drops[channel],
+		sent:      g.sent,
+	}
+}
+
+// HeartbeatLoop sends a heartbeat whenever no update was sent for the
+// heartbeat interval
+func (g *GasPriceOracle) HeartbeatLoop() {
+	interval := time.Duration(g.config.heartbeatIntervalSeconds) * time.Second
+	// Check often enough for a heartbeat to follow the idle interval closely
+	check := interval / 4
+	if check < time.Second {
+		check = time.Second
 	}
+	g.loop("heartbeat", check, g.heartbeat.beat)
+}
+
+// InclusionLoop polls the L2 transaction pool to measure how long the
+// transactions wait to be included
+func (g *GasPriceOracle) InclusionLoop() {
+	interval := time.Duration(g.config.inclusionPollSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	g.loop("inclusion-latency", interval, func() error {
+		return g.inclusion.observe(g.ctx)
+	})
+}
+
+// SubmissionLoop polls the receipts of the updates that were sent to
+// record their outcomes
+func (g *GasPriceOracle) SubmissionLoop() {
+	interval := time.Duration(g.config.submissionPollSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	g.loop("submissions", interval, func() error {
+		return g.submissions.poll(g.ctx)
+	})
+}
+
+// ResubmitLoop replaces the updates that were not mined in time
+func (g *GasPriceOracle) ResubmitLoop() {
+	// Check often enough for a replacement to follow the timeout closely
+	check := g.resubmit.timeout / 4
+	if check < time.Second {
+		check = time.Second
+	}
+	g.loop("resubmit", check, func() error {
+		return g.resubmit.poll(g.ctx)
+	})
+}
+
+// TokenPriceLoop keeps the prices of the tracked symbols fresh
+func (g *GasPriceOracle) TokenPriceLoop() {
+	interval := time.Duration(g.config.tokenPricerUpdateFrequencySecond) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	g.loop("token-price", interval, g.tokenPricer.Refresh)
+}
+
+// TokenRatioLoop polls the exchanges for the token price ratio that the
+// updates read from the cache
+func (g *GasPriceOracle) TokenRatioLoop() {
+	interval := time.Duration(g.config.tokenPricerUpdateFrequencySecond) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	g.loop("token-ratio", interval, func() error {
+		return g.priceCache.poll(g.tokenPricer)
+	})
+}
+
+// loop calls update once per interval until the context is done. No new
+// update is started once the oracle is draining. The watchdog restarts the
+// loop when an update stalls.
+func (g *GasPriceOracle) loop(name string, interval time.Duration, update func() error) {
+	g.loopOnHeads(name, interval, nil, update)
+}
+
+// loopOnHeads is loop, calling update on every new head of heads instead,
+// and once per interval while there is no subscription to them. A nil heads
+// calls update once per interval.
+func (g *GasPriceOracle) loopOnHeads(name string, interval time.Duration, heads HeadSubscriber, update func() error) {
+	g.watchdog.supervise(g.ctx, name, interval, func(ctx context.Context, progress func()) {
+		// The epochs of the channel run under the context of this loop
+		g.deadlines[name].bind(ctx)
+		if heads != nil {
+			g.run(ctx, name, headTicks(ctx, name, heads, interval), update, progress)
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		g.run(ctx, name, ticker.C, update, progress)
+	})
+}
+
+// run calls update on every tick until the context is done, reporting the
+// progress after every iteration
+func (g *GasPriceOracle) run(ctx context.Context, name string, ticks <-chan time.Time, update func() error, progress func()) {
+	// Label the goroutine so that profiles tell the loops apart
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels("loop", name)))
 
 	for {
 		select {
-		case <-timer.C:
-			if err := updateDaFee(); err != nil {
-				log.Error("cannot update da fee", "messgae", err)
+		case <-ticks:
+			// A cancelled loop must not start another update, even
+			// when a tick is ready too
+			if ctx.Err() != nil {
+				return
+			}
+			if !g.drainer.begin() {
+				log.Trace("draining, skipping update", "channel", name)
+				progress()
+				continue
+			}
+			err := update()
+			if errors.Is(err, errEpochAborted) {
+				log.Warn("epoch aborted, waiting for the next one", "channel", name, "message", err)
+			} else if errors.Is(err, errThrottled) {
+				log.Warn("update throttled, waiting for the next epoch", "channel", name, "message", err)
+			} else if errors.Is(err, errBudgetExceeded) {
+				log.Warn("updates paused by the spend budget", "channel", name, "message", err)
+			} else if errors.Is(err, errDropLimited) {
+				log.Warn("decreases paused by the drop limit", "channel", name, "message", err)
+			} else if errors.Is(err, errStaleTokenPrice) {
+				log.Warn("token price too old, skipping the update", "channel", name, "message", err)
+			} else if err != nil {
+				log.Error("cannot update", "channel", name, "message", err)
+			} else {
+				g.readiness.succeeded(name)
 			}
+			g.drainer.end()
+			progress()
 
-		case <-g.ctx.Done():
-			g.Stop()
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
+// ReportModes exports the mode of every enabled channel. It must be
+// called again whenever the configuration of the channels changes.
+func (g *GasPriceOracle) ReportModes() {
+	modes := g.config.channelModes()
+	for channel, mode := range modes {
+		log.Info("Channel mode", "channel", channel, "mode", mode)
+	}
+	g.modes.report(modes)
+}
+
+// Drain stops the oracle from starting new updates. Updates that are
+// already in flight are allowed to finish.
+func (g *GasPriceOracle) Drain() {
+	if g.drainer.isDraining() {
+		return
+	}
+	log.Info("Draining Gas Price Oracle")
+	g.drainer.drain()
+}
+
+// FreezeBaseFee holds the L1 base fee at its current value for the given
+// duration while the other channels keep updating. It returns when the
+// freeze expires.
+func (g *GasPriceOracle) FreezeBaseFee(duration time.Duration) time.Time {
+	return g.baseFeeFreezer.freeze(duration)
+}
+
+// ThawBaseFee lifts a freeze of the L1 base fee before it expires
+func (g *GasPriceOracle) ThawBaseFee() {
+	g.baseFeeFreezer.thaw()
+}
+
+// ResumeSpending lifts a pause of the spend budget and starts a new window
+func (g *GasPriceOracle) ResumeSpending() {
+	g.budget.resume()
+}
+
+// ResumeDecreases lifts a pause of the drop limit on every channel
+func (g *GasPriceOracle) ResumeDecreases() {
+	for _, drops := range g.drops {
+		drops.resume()
+	}
+}
+
 // Update will update the gas price
 func (g *GasPriceOracle) Update() error {
 	l2GasPrice, err := g.contract.GasPrice(&bind.CallOpts{
-		Context: g.ctx,
+		Context: g.deadlines[l2GasPriceChannel].context(),
 	})
 	if err != nil {
 		return fmt.Errorf("cannot get gas price: %w", err)
@@ -199,40 +526,276 @@ func (g *GasPriceOracle) Update() error {
 
 	local := g.gasPriceUpdater.GetGasPrice()
 	log.Info("Update", "original", l2GasPrice, "current", newGasPrice, "local", local)
+
+	if g.config.l2GasPriceSpreadBlocks > 0 {
+		if err := reportSpread(g.ctx, g.l2FeeHistory, g.config.l2GasPriceSpreadBlocks, newGasPrice); err != nil {
+			log.Warn("cannot report gas price spread", "message", err)
+		}
+	}
 	return nil
 }
 
 // NewGasPriceOracle creates a new GasPriceOracle based on a Config
 func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
-	tokenPricer := tokenprice.NewClient(cfg.bybitBackendURL, cfg.tokenPricerUpdateFrequencySecond)
-	if tokenPricer == nil {
-		return nil, fmt.Errorf("invalid token price client")
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.signerEndpoint != "" {
+		signer, err := dialExternalSigner(cfg.signerEndpoint, common.HexToAddress(cfg.signerAddress))
+		if err != nil {
+			return nil, err
+		}
+		cfg.signer = signer
 	}
-	// Create the L2 client
-	l2Client, err := ethclient.Dial(cfg.layerTwoHttpUrl)
+	if cfg.kmsKeyID != "" {
+		signer, err := dialKMSSigner(cfg.kmsKeyID, cfg.kmsRegion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.signer = signer
+	}
+	daFeeModel, err := newDAFeeModel(cfg.daFeeModel, cfg.daFeeExpression, cfg.tokenPriceSymbols)
 	if err != nil {
 		return nil, err
 	}
-
-	l1Client, err := NewL1Client(cfg.ethereumHttpUrl, tokenPricer)
+	tokenPriceBackend, err := cfg.tokenPriceBackend()
 	if err != nil {
 		return nil, err
 	}
-	daFeeClient, err := bindings.NewBVMEigenDataLayrFee(cfg.daFeeContractAddress, l1Client.Client)
-	// Ensure that we can actually connect to both backends
+	tokenPricer := tokenprice.NewClientWithBackend(tokenPriceBackend, cfg.tokenPricerUpdateFrequencySecond)
+	tokenPricer.SetDecay(tokenprice.Decay{
+		MaxStale:        time.Duration(cfg.tokenPriceMaxStaleSeconds) * time.Second,
+		MarginPerMinute: cfg.tokenPriceStaleMarginPerMinute,
+	})
+	tokenPricer.SetStrict(cfg.tokenPriceStrict)
+	tokenPricer.SetRetry(tokenprice.Retry{
+		Policy:     cfg.tokenPricerRetryBackoff,
+		MaxRetries: cfg.tokenPricerMaxRetries,
+		Deadline:   time.Duration(cfg.tokenPricerDeadlineSeconds) * time.Second,
+	})
+	// The exchanges share a single limit
+	tokenPricer.SetLimiter(tokenprice.NewLimiter(cfg.tokenPricerRateLimit))
+	tokenPricer.SetCredentials(tokenprice.Credentials{
+		Key:    cfg.tokenPriceAPIKey,
+		Secret: cfg.tokenPriceAPISecret,
+	})
+	tokenPricer.SetAdaptive(tokenprice.Adaptive{
+		Min:       time.Duration(cfg.tokenPriceAdaptiveMinSeconds) * time.Second,
+		Max:       time.Duration(cfg.tokenPriceAdaptiveMaxSeconds) * time.Second,
+		Threshold: cfg.tokenPriceVolatilityThreshold,
+	})
+	tokenPricer.SetBreaker(tokenprice.Breaker{
+		MaxDeviation: cfg.tokenPriceMaxDeviation,
+		ResetAfter:   cfg.tokenPriceBreakerReset,
+	})
+	tokenPricer.SetSymbols(cfg.tokenPriceSymbols...)
+	// With a max age the updates read the token price that a loop polls
+	// into the cache, instead of polling the exchanges on demand
+	var tokenRatios tokenprice.RatioReader = tokenPricer
+	priceCache := newPriceCache(time.Duration(cfg.tokenPriceMaxAgeSeconds) * time.Second)
+	if priceCache != nil {
+		tokenRatios = priceCache
+	}
+	// Channels configured with the same endpoint share a client
+	clients := newDialer(cfg.connectBackoff)
 	log.Info("Connecting to layer two")
-	if err := ensureConnection(l2Client); err != nil {
+	l2Client, err := clients.dial(cfg.layerTwoHttpUrl)
+	if err != nil {
 		log.Error("Unable to connect to layer two")
 		return nil, err
 	}
 	log.Info("Connecting to layer one")
-	if err := ensureConnection(l1Client.Client); err != nil {
+	l1Client, err := clients.dial(cfg.ethereumHttpUrl)
+	if err != nil {
 		log.Error("Unable to connect to layer one")
 		return nil, err
 	}
 
+	baseFeeReadClient, baseFeeWriteClient, err := clients.dialChannel(l1BaseFeeChannel, cfg.l1BaseFeeEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	gasPriceReadClient, gasPriceWriteClient, err := clients.dialChannel(l2GasPriceChannel, cfg.l2GasPriceEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	daFeeReadClient, daFeeWriteClient, err := clients.dialChannel(daFeeChannel, cfg.daFeeEndpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// Updates are sent on L2 by the owner, or deposited through the portal
+	// on L1 when the deposit path is selected. The fee parameters are written
+	// to the same endpoint as the L2 gas price, each as its own channel.
+	var baseFeeSubmitter, gasPriceSubmitter, daFeeSubmitter DeployContractBackend = baseFeeWriteClient, gasPriceWriteClient, daFeeWriteClient
+	var overheadSubmitter, scalarSubmitter DeployContractBackend = gasPriceWriteClient, gasPriceWriteClient
+	var heartbeatBackend DeployContractBackend = gasPriceWriteClient
+	var resubmit *resubmitter
+	var nonces *nonceManager
+	if cfg.submissionPath != submissionPathDeposit && cfg.hasSigner() {
+		// Updates that revert once simulated are not sent
+		if cfg.simulateBeforeSend {
+			baseFeeSubmitter = simulateFirst(l1BaseFeeChannel, baseFeeSubmitter)
+			gasPriceSubmitter = simulateFirst(l2GasPriceChannel, gasPriceSubmitter)
+			daFeeSubmitter = simulateFirst(daFeeChannel, daFeeSubmitter)
+			overheadSubmitter = simulateFirst(overheadChannel, overheadSubmitter)
+			scalarSubmitter = simulateFirst(scalarChannel, scalarSubmitter)
+		}
+		// Updates that are not mined in time are replaced at the same nonce
+		// with a higher gas price
+		resubmit = newResubmitter(cfg, time.Duration(cfg.txResubmitTimeoutSeconds)*time.Second, cfg.txMaxBumps)
+		baseFeeSubmitter = resubmit.backend(l1BaseFeeChannel, baseFeeSubmitter)
+		gasPriceSubmitter = resubmit.backend(l2GasPriceChannel, gasPriceSubmitter)
+		daFeeSubmitter = resubmit.backend(daFeeChannel, daFeeSubmitter)
+		overheadSubmitter = resubmit.backend(overheadChannel, overheadSubmitter)
+		scalarSubmitter = resubmit.backend(scalarChannel, scalarSubmitter)
+		// Updates are held back while too many transactions of the signer
+		// are pending
+		throttle := newPendingThrottle(cfg.from(), l2Client, cfg.maxPendingTransactions)
+		baseFeeSubmitter = throttle.backend(l1BaseFeeChannel, baseFeeSubmitter)
+		gasPriceSubmitter = throttle.backend(l2GasPriceChannel, gasPriceSubmitter)
+		daFeeSubmitter = throttle.backend(daFeeChannel, daFeeSubmitter)
+		overheadSubmitter = throttle.backend(overheadChannel, overheadSubmitter)
+		scalarSubmitter = throttle.backend(scalarChannel, scalarSubmitter)
+		// The channels share the nonces of the signer, which are reconciled
+		// with L2 in case the signer is used elsewhere. The replacements
+		// reuse the nonces, which are then always kept locally.
+		reconcile := time.Duration(cfg.nonceReconcileIntervalSeconds) * time.Second
+		if reconcile == 0 && resubmit != nil {
+			reconcile = resubmit.timeout
+		}
+		nonces = newNonceManager(cfg.from(), l2Client, reconcile)
+		heartbeatBackend = nonces.backend(heartbeatBackend)
+	}
+	if cfg.submissionPath == submissionPathDeposit {
+		log.Info("Depositing updates through the portal", "portal", cfg.depositPortalAddress.Hex())
+		baseFeeSubmitter = newDepositBackend(baseFeeWriteClient, l1Client, cfg)
+		gasPriceSubmitter = newDepositBackend(gasPriceWriteClient, l1Client, cfg)
+		daFeeSubmitter = newDepositBackend(daFeeWriteClient, l1Client, cfg)
+		overheadSubmitter = newDepositBackend(gasPriceWriteClient, l1Client, cfg)
+		scalarSubmitter = newDepositBackend(gasPriceWriteClient, l1Client, cfg)
+		// The updates are simulated on L2 before they are deposited
+		if cfg.simulateBeforeSend {
+			baseFeeSubmitter = simulateFirst(l1BaseFeeChannel, baseFeeSubmitter)
+			gasPriceSubmitter = simulateFirst(l2GasPriceChannel, gasPriceSubmitter)
+			daFeeSubmitter = simulateFirst(daFeeChannel, daFeeSubmitter)
+			overheadSubmitter = simulateFirst(overheadChannel, overheadSubmitter)
+			scalarSubmitter = simulateFirst(scalarChannel, scalarSubmitter)
+		}
+	}
+
+	// Observe-only channels never send, whatever the update path
+	if cfg.observes(l1BaseFeeChannel) {
+		baseFeeSubmitter = observeOnly(l1BaseFeeChannel, baseFeeSubmitter)
+	}
+	if cfg.observes(l2GasPriceChannel) {
+		gasPriceSubmitter = observeOnly(l2GasPriceChannel, gasPriceSubmitter)
+	}
+	if cfg.observes(daFeeChannel) {
+		daFeeSubmitter = observeOnly(daFeeChannel, daFeeSubmitter)
+	}
+	if cfg.observes(overheadChannel) {
+		overheadSubmitter = observeOnly(overheadChannel, overheadSubmitter)
+	}
+	if cfg.observes(scalarChannel) {
+		scalarSubmitter = observeOnly(scalarChannel, scalarSubmitter)
+	}
+	// and neither does a dry run
+	if cfg.dryRun {
+		baseFeeSubmitter = dryRunOnly(l1BaseFeeChannel, baseFeeSubmitter)
+		gasPriceSubmitter = dryRunOnly(l2GasPriceChannel, gasPriceSubmitter)
+		daFeeSubmitter = dryRunOnly(daFeeChannel, daFeeSubmitter)
+		overheadSubmitter = dryRunOnly(overheadChannel, overheadSubmitter)
+		scalarSubmitter = dryRunOnly(scalarChannel, scalarSubmitter)
+	}
+
+	// The updates are paused while they spend more than the budget
+	spend := newSpendBudget(cfg.spendBudgetWei, time.Duration(cfg.spendBudgetWindowSeconds)*time.Second)
+	baseFeeSubmitter = spend.backend(baseFeeSubmitter)
+	gasPriceSubmitter = spend.backend(gasPriceSubmitter)
+	daFeeSubmitter = spend.backend(daFeeSubmitter)
+	overheadSubmitter = spend.backend(overheadSubmitter)
+	scalarSubmitter = spend.backend(scalarSubmitter)
+
+	// The channels share a queue to send their updates, ordered by the
+	// priority of the channel
+	queue := newSubmitter(cfg.maxInflightUpdates, cfg.channelPriorities)
+	baseFeeSubmitter = queue.backend(l1BaseFeeChannel, baseFeeSubmitter)
+	gasPriceSubmitter = queue.backend(l2GasPriceChannel, gasPriceSubmitter)
+	daFeeSubmitter = queue.backend(daFeeChannel, daFeeSubmitter)
+	overheadSubmitter = queue.backend(overheadChannel, overheadSubmitter)
+	scalarSubmitter = queue.backend(scalarChannel, scalarSubmitter)
+
+	// The nonce of an update is reserved when it is built, before any of the
+	// backends above can refuse to send it. The nonce manager wraps them all,
+	// so that it hands out the same nonce again after a refused send rather
+	// than leaving a gap that every later update queues behind.
+	baseFeeSubmitter = nonces.backend(baseFeeSubmitter)
+	gasPriceSubmitter = nonces.backend(gasPriceSubmitter)
+	daFeeSubmitter = nonces.backend(daFeeSubmitter)
+	overheadSubmitter = nonces.backend(overheadSubmitter)
+	scalarSubmitter = nonces.backend(scalarSubmitter)
+
+	// The outcome of every update that a channel means to send is recorded,
+	// including the ones that the queue or the throttle hold back
+	submissions := newSubmissionTracker(time.Duration(cfg.submissionTimeoutSeconds) * time.Second)
+	if submissions != nil {
+		submissions.budget = spend
+	}
+	baseFeeSubmitter = submissions.backend(l1BaseFeeChannel, baseFeeSubmitter)
+	gasPriceSubmitter = submissions.backend(l2GasPriceChannel, gasPriceSubmitter)
+	daFeeSubmitter = submissions.backend(daFeeChannel, daFeeSubmitter)
+	overheadSubmitter = submissions.backend(overheadChannel, overheadSubmitter)
+	scalarSubmitter = submissions.backend(scalarChannel, scalarSubmitter)
+
+	// Every update that is sent is recorded, so that a heartbeat is only
+	// sent while the signer is idle
+	beat := newHeartbeat(heartbeatBackend, cfg,
+		time.Duration(cfg.heartbeatIntervalSeconds)*time.Second, new(big.Int).SetUint64(cfg.heartbeatMaxCost))
+	baseFeeWriteBackend := beat.track(baseFeeSubmitter)
+	gasPriceWriteBackend := beat.track(gasPriceSubmitter)
+	daFeeWriteBackend := beat.track(daFeeSubmitter)
+	overheadWriteBackend := beat.track(overheadSubmitter)
+	scalarWriteBackend := beat.track(scalarSubmitter)
+
+	// The updates whose receipt is awaited are recorded, so that the ones
+	// still pending at shutdown can be reported
+	pending := newPendingReceipts(cfg.waitForReceipt)
+	baseFeeWriteBackend = pending.backend(l1BaseFeeChannel, baseFeeWriteBackend)
+	gasPriceWriteBackend = pending.backend(l2GasPriceChannel, gasPriceWriteBackend)
+	daFeeWriteBackend = pending.backend(daFeeChannel, daFeeWriteBackend)
+	overheadWriteBackend = pending.backend(overheadChannel, overheadWriteBackend)
+	scalarWriteBackend = pending.backend(scalarChannel, scalarWriteBackend)
+
+	// The blob-aware DA fee model reads the blob base fee from the DA fee
+	// read endpoint
+	daFeeClient, err := newBlobBaseFeeClient(daFeeReadClient, cfg.daFeeEndpoints.read)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseFeeClient bind.ContractTransactor = NewL1Client(baseFeeReadClient, tokenRatios)
+	// Over a WebSocket the L1 base fee follows the new heads of L1 rather
+	// than being polled
+	var l1Heads HeadSubscriber
+	if isWebSocket(cfg.l1BaseFeeEndpoints.read) {
+		log.Info("Following the new heads of layer one", "channel", l1BaseFeeChannel)
+		l1Heads = baseFeeReadClient
+	}
+	// In the dual-compute mode the L1 base fee is also read through a
+	// second L1 endpoint, and only written when both agree
+	if cfg.dualComputeL1HttpUrl != "" {
+		log.Info("Connecting to the dual-compute layer one")
+		secondaryClient, err := clients.dial(cfg.dualComputeL1HttpUrl)
+		if err != nil {
+			log.Error("Unable to connect to the dual-compute layer one")
+			return nil, err
+		}
+		baseFeeClient = newDualComputeBackend(l1BaseFeeChannel, baseFeeClient,
+			NewL1Client(secondaryClient, tokenRatios), cfg.dualComputeTolerance)
+	}
 	address := cfg.gasPriceOracleAddress
-	contract, err := bindings.NewBVMGasPriceOracle(address, l2Client)
+	contract, err := bindings.NewBVMGasPriceOracle(address, gasPriceWriteBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -247,13 +810,14 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 
 	// Create a gas pricer for the gas price updater
 	log.Info("Creating GasPricer", "currentPrice", currentPrice,
-		"floorPrice", cfg.floorPrice, "targetGasPerSecond", cfg.targetGasPerSecond,
+		"floorPrice", cfg.floorPrice, "ceilingPrice", cfg.ceilingPrice, "targetGasPerSecond", cfg.targetGasPerSecond,
 		"maxPercentChangePerEpoch", cfg.maxPercentChangePerEpoch)
 
 	gasPricer, err := gasprices.NewGasPricer(
 		currentPrice.Uint64(),
 		cfg.floorPrice,
-		tokenPricer,
+		cfg.ceilingPrice,
+		tokenRatios,
 		func() float64 {
 			return float64(cfg.targetGasPerSecond)
 		},
@@ -290,83 +854,242 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		cfg.l1ChainID = l1ChainID
 	}
 
-	if cfg.privateKey == nil {
+	if !cfg.hasSigner() {
 		return nil, errNoPrivateKey
 	}
 
-	tip, err := l2Client.HeaderByNumber(context.Background(), nil)
+	tip, err := gasPriceReadClient.HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// Every channel bounds the time spent collecting the inputs of an epoch
+	budget := time.Duration(cfg.epochInputBudgetMs) * time.Millisecond
+	deadlines := map[string]*inputDeadline{
+		l1BaseFeeChannel:  newInputDeadline(budget),
+		l2GasPriceChannel: newInputDeadline(budget),
+		daFeeChannel:      newInputDeadline(budget),
+		overheadChannel:   newInputDeadline(budget),
+		scalarChannel:     newInputDeadline(budget),
+	}
+	// Every channel records the decision of each epoch
+	var audit *auditLog
+	if cfg.AuditStdout {
+		audit = newAuditLog(os.Stdout)
+	}
+	history := newDecisionHistory(cfg.historySize)
+	traces := map[string]*decisionTrace{
+		l1BaseFeeChannel:  newDecisionTrace(l1BaseFeeChannel, audit, history),
+		l2GasPriceChannel: newDecisionTrace(l2GasPriceChannel, audit, history),
+		daFeeChannel:      newDecisionTrace(daFeeChannel, audit, history),
+		overheadChannel:   newDecisionTrace(overheadChannel, audit, history),
+		scalarChannel:     newDecisionTrace(scalarChannel, audit, history),
+	}
+	// Every channel widens its significance factor while it is unstable
+	window := time.Duration(cfg.emergencyWindowSeconds) * time.Second
+	emergencies := map[string]*emergencyMode{
+		l1BaseFeeChannel:  newEmergencyMode(l1BaseFeeChannel, cfg.emergencyUpdateThreshold, window, cfg.emergencySignificanceFactor),
+		l2GasPriceChannel: newEmergencyMode(l2GasPriceChannel, cfg.emergencyUpdateThreshold, window, cfg.emergencySignificanceFactor),
+		daFeeChannel:      newEmergencyMode(daFeeChannel, cfg.emergencyUpdateThreshold, window, cfg.emergencySignificanceFactor),
+		overheadChannel:   newEmergencyMode(overheadChannel, cfg.emergencyUpdateThreshold, window, cfg.emergencySignificanceFactor),
+		scalarChannel:     newEmergencyMode(scalarChannel, cfg.emergencyUpdateThreshold, window, cfg.emergencySignificanceFactor),
+	}
+	// Every channel checks that its writes change the contract state
+	stuck := map[string]*stuckDetector{
+		l1BaseFeeChannel:  newStuckDetector(l1BaseFeeChannel, cfg.stuckWriteThreshold),
+		l2GasPriceChannel: newStuckDetector(l2GasPriceChannel, cfg.stuckWriteThreshold),
+		daFeeChannel:      newStuckDetector(daFeeChannel, cfg.stuckWriteThreshold),
+		overheadChannel:   newStuckDetector(overheadChannel, cfg.stuckWriteThreshold),
+		scalarChannel:     newStuckDetector(scalarChannel, cfg.stuckWriteThreshold),
+	}
+	// Every channel reports the reversals of its direction, and dampens
+	// them when they are too frequent
+	reversals := map[string]*reversalDamper{
+		l1BaseFeeChannel:  newReversalDamper(l1BaseFeeChannel, cfg.reversalWindowWrites, cfg.reversalThreshold, cfg.reversalSignificanceFactor),
+		l2GasPriceChannel: newReversalDamper(l2GasPriceChannel, cfg.reversalWindowWrites, cfg.reversalThreshold, cfg.reversalSignificanceFactor),
+		daFeeChannel:      newReversalDamper(daFeeChannel, cfg.reversalWindowWrites, cfg.reversalThreshold, cfg.reversalSignificanceFactor),
+		overheadChannel:   newReversalDamper(overheadChannel, cfg.reversalWindowWrites, cfg.reversalThreshold, cfg.reversalSignificanceFactor),
+		scalarChannel:     newReversalDamper(scalarChannel, cfg.reversalWindowWrites, cfg.reversalThreshold, cfg.reversalSignificanceFactor),
+	}
+	// Every channel catches up with the value on chain as chosen for it
+	graces := map[string]*enableGrace{
+		l1BaseFeeChannel:  newEnableGrace(l1BaseFeeChannel, cfg.enableGrace[l1BaseFeeChannel], cfg.maxPercentChangePerEpoch),
+		l2GasPriceChannel: newEnableGrace(l2GasPriceChannel, cfg.enableGrace[l2GasPriceChannel], cfg.maxPercentChangePerEpoch),
+		daFeeChannel:      newEnableGrace(daFeeChannel, cfg.enableGrace[daFeeChannel], cfg.maxPercentChangePerEpoch),
+		overheadChannel:   newEnableGrace(overheadChannel, cfg.enableGrace[overheadChannel], cfg.maxPercentChangePerEpoch),
+		scalarChannel:     newEnableGrace(scalarChannel, cfg.enableGrace[scalarChannel], cfg.maxPercentChangePerEpoch),
+	}
+	// Every channel bounds how far its value may fall within a period
+	period := time.Duration(cfg.maxDropPeriodSeconds) * time.Second
+	drops := map[string]*dropLimit{
+		l1BaseFeeChannel:  newDropLimit(l1BaseFeeChannel, cfg.maxDropPerPeriod, period),
+		l2GasPriceChannel: newDropLimit(l2GasPriceChannel, cfg.maxDropPerPeriod, period),
+		daFeeChannel:      newDropLimit(daFeeChannel, cfg.maxDropPerPeriod, period),
+		overheadChannel:   newDropLimit(overheadChannel, cfg.maxDropPerPeriod, period),
+		scalarChannel:     newDropLimit(scalarChannel, cfg.maxDropPerPeriod, period),
+	}
+
+	// Every channel makes its first decision against the value that it last
+	// sent before a restart
+	sent := loadSentState(cfg.stateFile)
+
 	// Start at the tip
 	epochStartBlockNumber := tip.Number.Uint64()
 	// getLatestBlockNumberFn is used by the GasPriceUpdater
 	// to get the latest block number
-	getLatestBlockNumberFn := wrapGetLatestBlockNumberFn(l2Client)
+	getLatestBlockNumberFn := wrapGetLatestBlockNumberFn(gasPriceReadClient, deadlines[l2GasPriceChannel])
 	// updateL2GasPriceFn is used by the GasPriceUpdater to
 	// update the gas price
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(l2Client, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(gasPriceWriteBackend, cfg, channelGuards{
+		deadline:  deadlines[l2GasPriceChannel],
+		trace:     traces[l2GasPriceChannel],
+		emergency: emergencies[l2GasPriceChannel],
+		stuck:     stuck[l2GasPriceChannel],
+		reversals: reversals[l2GasPriceChannel],
+		grace:     graces[l2GasPriceChannel],
+		drops:     drops[l2GasPriceChannel],
+		sent:      sent,
+	})
 	if err != nil {
 		return nil, err
 	}
+	// The computed gas price is smoothed before it is checked for
+	// significance
+	updateL2GasPriceFn = newEMASmoother(l2GasPriceChannel, cfg.l2GasPriceEMAAlpha, sent).wrap(updateL2GasPriceFn, traces[l2GasPriceChannel])
 	// getGasUsedByBlockFn is used by the GasPriceUpdater
 	// to fetch the amount of gas that a block has used
-	getGasUsedByBlockFn := wrapGetGasUsedByBlock(l2Client)
-
-	log.Info("Creating GasPriceUpdater", "epochStartBlockNumber", epochStartBlockNumber,
-		"averageBlockGasLimitPerEpoch", cfg.averageBlockGasLimitPerEpoch,
-		"epochLengthSeconds", cfg.epochLengthSeconds)
-
-	gasPriceUpdater, err := gasprices.NewGasPriceUpdater(
-		gasPricer,
-		epochStartBlockNumber,
-		cfg.averageBlockGasLimitPerEpoch,
-		cfg.epochLengthSeconds,
-		getLatestBlockNumberFn,
-		getGasUsedByBlockFn,
-		updateL2GasPriceFn,
-	)
+	getGasUsedByBlockFn := wrapGetGasUsedByBlock(gasPriceReadClient, deadlines[l2GasPriceChannel])
 
+	var gasPriceUpdater l2GasPricer
+	var inclusion *inclusionTracker
+	if cfg.l2GasPriceMode == modeInclusionTime {
+		// Pending transactions are tracked for ten epochs at most
+		horizon := 10 * time.Duration(cfg.epochLengthSeconds) * time.Second
+		inclusion, err = newInclusionTracker(cfg.l2GasPriceEndpoints.read, horizon)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Creating InclusionPricer", "epochStartBlockNumber", epochStartBlockNumber,
+			"targetInclusionSeconds", cfg.targetInclusionSeconds,
+			"inclusionLatencyPercentile", cfg.inclusionLatencyPercentile)
+
+		gasPriceUpdater, err = gasprices.NewInclusionPricer(
+			currentPrice.Uint64(),
+			cfg.floorPrice,
+			cfg.ceilingPrice,
+			cfg.targetInclusionSeconds,
+			cfg.inclusionLatencyPercentile,
+			cfg.maxPercentChangePerEpoch,
+			epochStartBlockNumber,
+			getLatestBlockNumberFn,
+			func(from, to uint64) ([]float64, error) {
+				return inclusion.latencies(deadlines[l2GasPriceChannel].context(), from, to)
+			},
+			updateL2GasPriceFn,
+		)
+	} else {
+		log.Info("Creating GasPriceUpdater", "epochStartBlockNumber", epochStartBlockNumber,
+			"averageBlockGasLimitPerEpoch", cfg.averageBlockGasLimitPerEpoch,
+			"epochLengthSeconds", cfg.epochLengthSeconds)
+
+		gasPriceUpdater, err = gasprices.NewGasPriceUpdater(
+			gasPricer,
+			epochStartBlockNumber,
+			cfg.averageBlockGasLimitPerEpoch,
+			cfg.epochLengthSeconds,
+			getLatestBlockNumberFn,
+			getGasUsedByBlockFn,
+			updateL2GasPriceFn,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	// The oracle is ready while every enabled channel keeps finishing its
+	// epochs and both layers answer
+	ready := newReadiness(cfg.healthStalenessEpochs, map[string]headerReader{
+		"l1-rpc": l1Client,
+		"l2-rpc": l2Client,
+	})
+
+	// Cancelling the root context stops every loop
+	ctx, cancel := context.WithCancel(context.Background())
+
 	gpo := GasPriceOracle{
 		l2ChainID:       l2ChainID,
 		l1ChainID:       l1ChainID,
-		ctx:             context.Background(),
+		ctx:             ctx,
+		cancel:          cancel,
 		stop:            make(chan struct{}),
 		contract:        contract,
 		gasPriceUpdater: gasPriceUpdater,
+		inclusion:       inclusion,
+		submissions:     submissions,
+		resubmit:        resubmit,
+		budget:          spend,
+		tokenPricer:     tokenPricer,
+		priceCache:      priceCache,
+		l2FeeHistory:    gasPriceReadClient,
 		config:          cfg,
-		l2Backend:       l2Client,
-		l1Backend:       l1Client,
+		l2Backend:       gasPriceWriteBackend,
+		l1Backend:       baseFeeClient,
+		l1RawBackend:    baseFeeReadClient,
+		baseFeeBackend:  baseFeeWriteBackend,
 		daBackend:       daFeeClient,
+		daFeeBackend:    daFeeWriteBackend,
+		overheadBackend: overheadWriteBackend,
+		scalarBackend:   scalarWriteBackend,
+		heartbeat:       beat,
+		pending:         pending,
+		sent:            sent,
+		state:           new(stateStore),
+		deadlines:       deadlines,
+		traces:          traces,
+		history:         history,
+		emergencies:     emergencies,
+		stuck:           stuck,
+		reversals:       reversals,
+		graces:          graces,
+		drops:           drops,
+		daFeeModel:      newDAFeeModelSwitch(daFeeModel),
+		watchdog:        newWatchdog(time.Duration(cfg.watchdogTimeoutSeconds) * time.Second),
+		l1Heads:         l1Heads,
+		readiness:       ready,
+		reference:       newReferenceFeed(cfg.shutdownReferenceURL),
+		alerts:          newAlerter(cfg.alertWebhookURL, cfg.alertFailureThreshold),
+		drainer:         new(drainer),
+		modes:           newModeReporter(),
+		baseFeeFreezer:  newFreezer(l1BaseFeeChannel, time.Duration(cfg.l1BaseFeeMaxFreezeSeconds)*time.Second),
 	}
 
 	if err := gpo.ensure(); err != nil {
 		return nil, err
 	}
+	if cfg.enableL1BaseFee {
+		if err := checkDecimals(&gpo.contract.BVMGasPriceOracleCaller, &bind.CallOpts{Context: gpo.ctx}, cfg.tokenPriceDecimals); err != nil {
+			return nil, err
+		}
+	}
 
 	return &gpo, nil
 }
 
+// connectRetries is the number of times that connecting to an endpoint is
+// retried before giving up
+const connectRetries = 90
+
 // Ensure that we can actually connect
-func ensureConnection(client *ethclient.Client) error {
-	t := time.NewTicker(1 * time.Second)
-	retries := 0
-	defer t.Stop()
-	for ; true; <-t.C {
+func ensureConnection(client *ethclient.Client, policy backoff.Policy) error {
+	b := policy.New()
+	for {
 		_, err := client.ChainID(context.Background())
 		if err == nil {
-			break
-		} else {
-			retries += 1
-			if retries > 90 {
-				return err
-			}
+			return nil
+		}
+		if b.Attempt() >= connectRetries {
+			return err
 		}
+		time.Sleep(b.Next())
 	}
-	return nil
 }
@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Grace modes choose how a channel catches up with the value that is on
+// chain when it is enabled, which may be stale by a large amount
+const (
+	// graceImmediate writes the computed value at once
+	graceImmediate = "immediate"
+	// graceEase moves towards the computed value in steps capped to the
+	// max percent change per epoch
+	graceEase = "ease"
+)
+
+// parseEnableGrace parses the grace modes of the channels of the form
+// `l1-base-fee=ease,da-fee=immediate`
+func parseEnableGrace(spec string) (map[string]string, error) {
+	modes := make(map[string]string)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid channel grace %q", field)
+		}
+		channel := strings.TrimSpace(parts[0])
+		switch channel {
+		case l1BaseFeeChannel, l2GasPriceChannel, daFeeChannel, overheadChannel, scalarChannel:
+		default:
+			return nil, fmt.Errorf("unknown channel %q", channel)
+		}
+		mode := strings.TrimSpace(parts[1])
+		switch mode {
+		case graceImmediate, graceEase:
+		default:
+			return nil, fmt.Errorf("unknown grace mode of %s: %q", channel, mode)
+		}
+		modes[channel] = mode
+	}
+	return modes, nil
+}
+
+// enableGrace eases a channel from the value on chain to its computed
+// value after it is enabled. Until the computed value is within the max
+// change of the value on chain, every write moves by at most the max
+// change. Once a write reaches the computed value the channel has caught up
+// and writes as usual. A value of zero on chain cannot be eased from and is
+// corrected at once. A nil enableGrace corrects at once.
+type enableGrace struct {
+	channel   string
+	maxChange float64
+
+	mu     sync.Mutex
+	easing bool
+}
+
+// newEnableGrace creates the grace of the channel, or returns nil when the
+// channel corrects at once
+func newEnableGrace(channel, mode string, maxChange float64) *enableGrace {
+	if mode != graceEase || maxChange <= 0 {
+		return nil
+	}
+	return &enableGrace{channel: channel, maxChange: maxChange, easing: true}
+}
+
+// next returns the value to write over current in place of target
+func (g *enableGrace) next(current, target *big.Int) *big.Int {
+	if g == nil {
+		return target
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.easing {
+		return target
+	}
+	if current.Sign() == 0 {
+		g.easing = false
+		log.Info("Nothing on chain to ease from, correcting at once", "channel", g.channel, "target", target)
+		return target
+	}
+
+	factor := 1 + g.maxChange
+	if target.Cmp(current) < 0 {
+		factor = 1 - g.maxChange
+	}
+	bound, _ := new(big.Float).Mul(new(big.Float).SetInt(current), big.NewFloat(factor)).Int(nil)
+	if (factor > 1 && target.Cmp(bound) <= 0) || (factor < 1 && target.Cmp(bound) >= 0) {
+		g.easing = false
+		log.Info("Channel caught up after being enabled", "channel", g.channel, "value", target)
+		return target
+	}
+	log.Info("Easing towards the computed value after being enabled", "channel", g.channel,
+		"current", current, "target", target, "next", bound)
+	return bound
+}
+
+// active returns true while the channel has not caught up
+func (g *enableGrace) active() bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.easing
+}
@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// enableBaseFeeChannel deploys the oracle with a stale L1 base fee on chain
+// and returns the L1 base fee written by every epoch once the channel is
+// enabled with the grace mode
+func enableBaseFeeChannel(t *testing.T, mode string, stale, target int64, epochs int) []int64 {
+	t.Helper()
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	_, err = gpo.SetL1BaseFee(opts, big.NewInt(stale))
+	require.NoError(t, err)
+	sim.Commit()
+
+	l1 := &syntheticL1{baseFees: []int64{target}}
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		l1BaseFeeSignificanceFactor: 0.01,
+		maxPercentChangePerEpoch:    0.1,
+	}
+	grace := newEnableGrace(l1BaseFeeChannel, mode, cfg.maxPercentChangePerEpoch)
+	update, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{grace: grace})
+	require.NoError(t, err)
+
+	var written []int64
+	for i := 0; i < epochs; i++ {
+		require.NoError(t, update())
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		written = append(written, l1BaseFee.Int64())
+	}
+	return written
+}
+
+func TestEnableGraceEasesFromStaleValue(t *testing.T) {
+	written := enableBaseFeeChannel(t, graceEase, 1e9, 1.5e9, 6)
+	require.Equal(t, []int64{1.1e9, 1.21e9, 1.331e9, 1.4641e9, 1.5e9, 1.5e9}, written)
+
+	// Easing down is capped the same way
+	written = enableBaseFeeChannel(t, graceEase, 2e9, 1.5e9, 4)
+	require.Equal(t, []int64{1.8e9, 1.62e9, 1.5e9, 1.5e9}, written)
+}
+
+func TestEnableGraceCorrectsImmediately(t *testing.T) {
+	written := enableBaseFeeChannel(t, graceImmediate, 1e9, 1.5e9, 2)
+	require.Equal(t, []int64{1.5e9, 1.5e9}, written)
+
+	// Without a value on chain there is nothing to ease from
+	written = enableBaseFeeChannel(t, graceEase, 0, 1.5e9, 1)
+	require.Equal(t, []int64{1.5e9}, written)
+}
+
+func TestEnableGraceCaughtUp(t *testing.T) {
+	g := newEnableGrace(daFeeChannel, graceEase, 0.1)
+	require.True(t, g.active())
+	require.Equal(t, big.NewInt(110), g.next(big.NewInt(100), big.NewInt(200)))
+	require.Equal(t, big.NewInt(115), g.next(big.NewInt(110), big.NewInt(115)))
+	require.False(t, g.active())
+	// Once caught up, later jumps are written at once
+	require.Equal(t, big.NewInt(400), g.next(big.NewInt(115), big.NewInt(400)))
+
+	require.Nil(t, newEnableGrace(daFeeChannel, graceImmediate, 0.1))
+	require.Nil(t, newEnableGrace(daFeeChannel, "", 0.1))
+	var none *enableGrace
+	require.Equal(t, big.NewInt(400), none.next(big.NewInt(100), big.NewInt(400)))
+}
+
+func TestParseEnableGrace(t *testing.T) {
+	modes, err := parseEnableGrace(" l1-base-fee = ease ,da-fee=immediate")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{l1BaseFeeChannel: graceEase, daFeeChannel: graceImmediate}, modes)
+
+	_, err = parseEnableGrace("da-fee=slow")
+	require.Error(t, err)
+	// The fee parameters catch up like the other channels
+	modes, err = parseEnableGrace("overhead=ease,scalar=immediate")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{overheadChannel: graceEase, scalarChannel: graceImmediate}, modes)
+	_, err = parseEnableGrace("heartbeat=ease")
+	require.Error(t, err)
+	_, err = parseEnableGrace("da-fee")
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errSubscriptionEnded represents the error when the node ends a
+// subscription without an error
+var errSubscriptionEnded = errors.New("subscription ended")
+
+// HeadSubscriber subscribes to the new heads of a chain
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// isWebSocket returns true when the endpoint is reached over a WebSocket,
+// which can push the new heads
+func isWebSocket(url string) bool {
+	return strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
+
+// headTicks returns a channel that ticks on every new head of the chain,
+// so that a loop updates as soon as a block lands rather than once per
+// interval. While there is no subscription, because it cannot be made or
+// because it dropped, the channel ticks once per interval instead and the
+// subscription is made again after every tick. The heads that arrive while
+// the loop is busy are coalesced into a single tick. The channel stops
+// ticking once the context is done.
+func headTicks(ctx context.Context, name string, heads HeadSubscriber, interval time.Duration) <-chan time.Time {
+	ticks := make(chan time.Time, 1)
+	tick := func() {
+		select {
+		case ticks <- time.Now():
+		default:
+		}
+	}
+	go func() {
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := followHeads(ctx, name, heads, tick); err != nil {
+				log.Warn("New heads unavailable, polling", "channel", name, "interval", interval, "message", err)
+				headFallbackCounter(name).Inc(1)
+			}
+			select {
+			case <-time.After(interval):
+				tick()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ticks
+}
+
+// followHeads ticks on every new head until the subscription drops or the
+// context is done
+func followHeads(ctx context.Context, name string, heads HeadSubscriber, tick func()) error {
+	ch := make(chan *types.Header, 16)
+	sub, err := heads.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	log.Info("Subscribed to new heads", "channel", name)
+	for {
+		select {
+		case head := <-ch:
+			log.Trace("new head", "channel", name, "number", head.Number, "base-fee", head.BaseFee)
+			tick()
+		case err := <-sub.Err():
+			if err == nil {
+				err = errSubscriptionEnded
+			}
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// headFallbackCounter counts the times that a loop fell back to polling
+func headFallbackCounter(name string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("heads/"+metricName(name)+"/fallbacks", ometrics.DefaultRegistry)
+}
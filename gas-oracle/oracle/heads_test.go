@@ -0,0 +1,167 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeads is an L1 endpoint that pushes the heads fed to it over a
+// subscription, and serves the last one as the latest header
+type fakeHeads struct {
+	bind.ContractTransactor
+
+	mu            sync.Mutex
+	head          *types.Header
+	ch            chan<- *types.Header
+	drop          chan error
+	subscriptions int
+}
+
+func newFakeHeads(backend bind.ContractTransactor) *fakeHeads {
+	return &fakeHeads{ContractTransactor: backend, drop: make(chan error)}
+}
+
+func (f *fakeHeads) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ch = ch
+	f.subscriptions++
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		select {
+		case err := <-f.drop:
+			return err
+		case <-quit:
+			return nil
+		}
+	}), nil
+}
+
+func (f *fakeHeads) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.head, nil
+}
+
+func (f *fakeHeads) subscribed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subscriptions
+}
+
+// push makes a header with the base fee the latest and sends it over the
+// subscription
+func (f *fakeHeads) push(number, baseFee int64) {
+	f.mu.Lock()
+	f.head = &types.Header{Number: big.NewInt(number), BaseFee: big.NewInt(baseFee)}
+	ch := f.ch
+	f.mu.Unlock()
+	ch <- f.head
+}
+
+func TestBaseFeeFollowsHeads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		// The default of --l1-base-fee-significant-factor
+		l1BaseFeeSignificanceFactor: 0.10,
+	}
+	heads := newFakeHeads(sim)
+	recorder := &buildRecorder{DeployContractBackend: sim}
+	update, err := wrapUpdateBaseFee(heads, recorder, cfg, channelGuards{})
+	require.NoError(t, err)
+	var updates int32
+	g := &GasPriceOracle{ctx: ctx, drainer: new(drainer)}
+	// The interval is too long for polling to ever update
+	go g.loopOnHeads(l1BaseFeeChannel, time.Hour, heads, func() error {
+		defer atomic.AddInt32(&updates, 1)
+		return update()
+	})
+	require.Eventually(t, func() bool { return heads.subscribed() == 1 }, time.Second, time.Millisecond)
+
+	// Every head is an epoch of the L1 base fee
+	epoch := func(number, baseFee int64) {
+		before := atomic.LoadInt32(&updates)
+		heads.push(number, baseFee)
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&updates) == before+1 }, time.Second, time.Millisecond)
+		sim.Commit()
+	}
+	requireBaseFee := func(baseFee int64) {
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(baseFee), l1BaseFee)
+	}
+	epoch(1, 1_000_000_000)
+	require.Equal(t, 1, recorder.sent)
+	requireBaseFee(1_000_000_000)
+
+	// A change below the significance factor is not sent
+	epoch(2, 1_050_000_000)
+	require.Equal(t, 1, recorder.sent)
+	requireBaseFee(1_000_000_000)
+
+	// while a spike is sent on the head that it lands in
+	epoch(3, 2_000_000_000)
+	require.Equal(t, 2, recorder.sent)
+	requireBaseFee(2_000_000_000)
+}
+
+func TestHeadTicksFallBackToPolling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	heads := newFakeHeads(nil)
+	ticks := headTicks(ctx, l1BaseFeeChannel, heads, 20*time.Millisecond)
+	require.Eventually(t, func() bool { return heads.subscribed() == 1 }, time.Second, time.Millisecond)
+	heads.push(1, 1)
+	<-ticks
+
+	// Once the subscription drops the channel ticks at the interval, and
+	// subscribes again
+	fallbacks := headFallbackCounter(l1BaseFeeChannel).Count()
+	heads.drop <- errors.New("connection reset")
+	select {
+	case <-ticks:
+	case <-time.After(time.Second):
+		t.Fatal("no tick after the subscription dropped")
+	}
+	require.Equal(t, fallbacks+1, headFallbackCounter(l1BaseFeeChannel).Count())
+	require.Eventually(t, func() bool { return heads.subscribed() == 2 }, time.Second, time.Millisecond)
+	heads.push(2, 1)
+	select {
+	case <-ticks:
+	case <-time.After(time.Second):
+		t.Fatal("no tick after subscribing again")
+	}
+}
+
+func TestIsWebSocket(t *testing.T) {
+	require.True(t, isWebSocket("ws://127.0.0.1:8546"))
+	require.True(t, isWebSocket("wss://mainnet.example.org"))
+	require.False(t, isWebSocket("http://127.0.0.1:8545"))
+	require.False(t, isWebSocket("/tmp/geth.ipc"))
+}
@@ -0,0 +1,122 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/units"
+)
+
+// heartbeatGasLimit is the gas used by a plain transfer
+const heartbeatGasLimit = 21_000
+
+var (
+	// errHeartbeatTooExpensive represents the error when a heartbeat would
+	// cost more than its budget
+	errHeartbeatTooExpensive = errors.New("heartbeat exceeds its cost budget")
+)
+
+// heartbeat keeps the signer active by sending a zero value transfer to
+// itself when no update has been sent for a while
+type heartbeat struct {
+	mu       sync.Mutex
+	lastSent time.Time
+	idle     time.Duration
+	maxCost  *big.Int
+	backend  DeployContractBackend
+	cfg      *Config
+	now      func() time.Time
+}
+
+// newHeartbeat creates a heartbeat that sends through backend once no
+// transaction was sent for idle. A heartbeat never costs more than
+// maxCost.
+func newHeartbeat(backend DeployContractBackend, cfg *Config, idle time.Duration, maxCost *big.Int) *heartbeat {
+	return &heartbeat{
+		lastSent: time.Now(),
+		idle:     idle,
+		maxCost:  maxCost,
+		backend:  backend,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// sent records that a transaction was sent by the signer
+func (h *heartbeat) sent() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastSent = h.now()
+}
+
+// track returns backend with every transaction that is sent through it
+// recorded, so that no heartbeat is sent while real updates are
+func (h *heartbeat) track(backend DeployContractBackend) DeployContractBackend {
+	return &trackedBackend{backendDecorator: decorate(backend), heartbeat: h}
+}
+
+// beat sends a heartbeat when the signer was idle for too long
+func (h *heartbeat) beat() error {
+	h.mu.Lock()
+	idle := h.now().Sub(h.lastSent)
+	h.mu.Unlock()
+	if idle < h.idle {
+		return nil
+	}
+
+	ctx := context.Background()
+	gasPrice, err := txGasPrice(ctx, h.backend, h.cfg)
+	if err != nil {
+		return err
+	}
+	cost := units.FromWei(gasPrice).Mul(heartbeatGasLimit).Wei()
+	if cost.Cmp(h.maxCost) > 0 {
+		return fmt.Errorf("%w: cost %d, budget %d", errHeartbeatTooExpensive, cost, h.maxCost)
+	}
+
+	address := h.cfg.from()
+	nonce, err := h.backend.PendingNonceAt(ctx, address)
+	if err != nil {
+		return err
+	}
+	tx, err := h.cfg.signTx(
+		types.NewTransaction(nonce, address, new(big.Int), heartbeatGasLimit, gasPrice, nil),
+		h.cfg.l2ChainID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := h.backend.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("cannot send heartbeat: %w", err)
+	}
+	h.sent()
+	heartbeatCounter().Inc(1)
+	log.Info("Heartbeat transaction sent", "hash", tx.Hash().Hex(), "nonce", nonce, "idle", idle)
+	return nil
+}
+
+// trackedBackend records every transaction sent through it on a heartbeat
+type trackedBackend struct {
+	backendDecorator
+	heartbeat *heartbeat
+}
+
+func (b *trackedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.DeployContractBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.heartbeat.sent()
+	return nil
+}
+
+func heartbeatCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/heartbeat", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,92 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHeartbeat(t *testing.T, maxCost int64) (*heartbeat, *backends.SimulatedBackend, *time.Time) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	t.Cleanup(func() { sim.Close() })
+
+	cfg := &Config{
+		privateKey: key,
+		l2ChainID:  big.NewInt(1337),
+		gasPrice:   big.NewInt(1_000_000_000),
+	}
+	now := time.Unix(1_700_000_000, 0)
+	h := newHeartbeat(sim, cfg, 10*time.Minute, big.NewInt(maxCost))
+	h.now = func() time.Time { return now }
+	h.sent()
+	return h, sim, &now
+}
+
+func pendingNonce(t *testing.T, h *heartbeat) uint64 {
+	address := crypto.PubkeyToAddress(h.cfg.privateKey.PublicKey)
+	nonce, err := h.backend.PendingNonceAt(context.Background(), address)
+	require.NoError(t, err)
+	return nonce
+}
+
+func signTransfer(t *testing.T, h *heartbeat, nonce uint64, to common.Address) *types.Transaction {
+	tx, err := types.SignTx(
+		types.NewTransaction(nonce, to, big.NewInt(1), heartbeatGasLimit, h.cfg.gasPrice, nil),
+		types.NewEIP155Signer(h.cfg.l2ChainID),
+		h.cfg.privateKey,
+	)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestHeartbeatAfterIdleInterval(t *testing.T) {
+	h, sim, now := newTestHeartbeat(t, 1e18)
+
+	// Not idle for long enough
+	*now = now.Add(9 * time.Minute)
+	require.NoError(t, h.beat())
+	require.Equal(t, uint64(0), pendingNonce(t, h))
+
+	*now = now.Add(time.Minute)
+	require.NoError(t, h.beat())
+	require.Equal(t, uint64(1), pendingNonce(t, h))
+	sim.Commit()
+
+	// The heartbeat itself resets the idle interval
+	*now = now.Add(5 * time.Minute)
+	require.NoError(t, h.beat())
+	require.Equal(t, uint64(1), pendingNonce(t, h))
+}
+
+func TestHeartbeatSkippedWhileUpdating(t *testing.T) {
+	h, _, now := newTestHeartbeat(t, 1e18)
+	backend := h.track(h.backend)
+
+	// A real update every few minutes keeps the heartbeat quiet
+	for i := 0; i < 5; i++ {
+		*now = now.Add(6 * time.Minute)
+		require.NoError(t, h.beat())
+		require.Equal(t, uint64(i), pendingNonce(t, h))
+
+		address := crypto.PubkeyToAddress(h.cfg.privateKey.PublicKey)
+		tx := signTransfer(t, h, uint64(i), address)
+		require.NoError(t, backend.SendTransaction(context.Background(), tx))
+	}
+}
+
+func TestHeartbeatCostBudget(t *testing.T) {
+	// 21000 gas at 1 gwei costs more than the budget
+	h, _, now := newTestHeartbeat(t, 21_000*1_000_000_000-1)
+
+	*now = now.Add(time.Hour)
+	require.ErrorIs(t, h.beat(), errHeartbeatTooExpensive)
+	require.Equal(t, uint64(0), pendingNonce(t, h))
+}
@@ -0,0 +1,66 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+)
+
+// decisionHistory keeps the most recent decisions of every channel in
+// memory, so that a window of inputs and outputs can be queried without a
+// metrics stack. A nil decisionHistory keeps nothing.
+type decisionHistory struct {
+	size int
+
+	mu        sync.RWMutex
+	decisions map[string][]Decision
+}
+
+// newDecisionHistory creates a history that keeps size decisions per
+// channel, or returns nil when size is zero
+func newDecisionHistory(size uint64) *decisionHistory {
+	if size == 0 {
+		return nil
+	}
+	return &decisionHistory{
+		size:      int(size),
+		decisions: make(map[string][]Decision),
+	}
+}
+
+// add records a decision, dropping the oldest of its channel once the
+// history is full
+func (h *decisionHistory) add(decision Decision) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	decisions := append(h.decisions[decision.Channel], decision)
+	if len(decisions) > h.size {
+		decisions = append([]Decision(nil), decisions[len(decisions)-h.size:]...)
+	}
+	h.decisions[decision.Channel] = decisions
+}
+
+// series returns the decisions of the channel made within [from, to],
+// oldest first. A zero from or to leaves that end open.
+func (h *decisionHistory) series(channel string, from, to time.Time) []Decision {
+	series := []Decision{}
+	if h == nil {
+		return series
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, decision := range h.decisions[channel] {
+		if !from.IsZero() && decision.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && decision.Time.After(to) {
+			continue
+		}
+		series = append(series, decision)
+	}
+	return series
+}
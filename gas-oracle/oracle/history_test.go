@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesFiltersByChannelAndTime(t *testing.T) {
+	history := newDecisionHistory(100)
+	start := time.Unix(1_700_000_000, 0).UTC()
+	now := start
+	traces := make(map[string]*decisionTrace)
+	for _, channel := range []string{l1BaseFeeChannel, l2GasPriceChannel} {
+		trace := newDecisionTrace(channel, nil, history)
+		trace.now = func() time.Time { return now }
+		traces[channel] = trace
+	}
+
+	// One epoch per channel every minute
+	for i := 0; i < 5; i++ {
+		for channel, trace := range traces {
+			value := int64(i)
+			if channel == l1BaseFeeChannel {
+				value += 100
+			}
+			require.NoError(t, trace.wrap(func() error {
+				trace.input("value", value)
+				trace.output("value", value)
+				trace.act(actionUpdate, reasonThresholdMet, "")
+				return nil
+			})())
+		}
+		now = now.Add(time.Minute)
+	}
+
+	g := &GasPriceOracle{drainer: new(drainer), history: history}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	get := func(target string) (int, []Decision) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		var series []Decision
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&series))
+		return rec.Code, series
+	}
+
+	// RFC3339 bounds are inclusive
+	from := start.Add(time.Minute).Format(time.RFC3339)
+	to := start.Add(3 * time.Minute).Format(time.RFC3339)
+	code, series := get("/series?channel=l2-gas-price&from=" + from + "&to=" + to)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, series, 3)
+	for i, decision := range series {
+		require.Equal(t, l2GasPriceChannel, decision.Channel)
+		require.Equal(t, start.Add(time.Duration(i+1)*time.Minute), decision.Time.UTC())
+		require.EqualValues(t, i+1, decision.Outputs["value"])
+	}
+
+	// Unix seconds and open ends
+	code, series = get("/series?channel=l1-base-fee&from=" + strconv.FormatInt(start.Add(4*time.Minute).Unix(), 10))
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, series, 1)
+	require.EqualValues(t, 104, series[0].Inputs["value"])
+
+	code, series = get("/series?channel=l1-base-fee")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, series, 5)
+
+	code, series = get("/series?channel=da-fee")
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, series)
+
+	code, _ = get("/series?channel=token-price")
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/series?channel=da-fee&from=yesterday")
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestHistoryKeepsMostRecent(t *testing.T) {
+	history := newDecisionHistory(3)
+	start := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 5; i++ {
+		history.add(Decision{Channel: daFeeChannel, Time: start.Add(time.Duration(i) * time.Second)})
+	}
+	series := history.series(daFeeChannel, time.Time{}, time.Time{})
+	require.Len(t, series, 3)
+	require.Equal(t, start.Add(2*time.Second), series[0].Time)
+
+	var none *decisionHistory
+	none.add(Decision{Channel: daFeeChannel})
+	require.Empty(t, none.series(daFeeChannel, time.Time{}, time.Time{}))
+}
@@ -0,0 +1,142 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// inclusionTracker measures how long the L2 transactions wait to be
+// included. It polls the transaction pool of the L2 node to learn when
+// every transaction was first seen pending, and reads the blocks to learn
+// when it was included. A transaction that is included between two polls
+// is never seen pending and has no latency.
+type inclusionTracker struct {
+	rpc *rpc.Client
+	now func() time.Time
+	// horizon is how long a pending transaction is tracked for
+	horizon time.Duration
+
+	mu   sync.Mutex
+	seen map[common.Hash]time.Time
+}
+
+// newInclusionTracker tracks the transactions of the L2 node of the url
+func newInclusionTracker(url string, horizon time.Duration) (*inclusionTracker, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &inclusionTracker{rpc: client, now: time.Now, horizon: horizon, seen: make(map[common.Hash]time.Time)}, nil
+}
+
+// txPoolTxs are the transactions of a pool by sender and nonce
+type txPoolTxs map[string]map[string]struct {
+	Hash common.Hash `json:"hash"`
+}
+
+// txPoolContent is the part of the txpool_content response that is used
+type txPoolContent struct {
+	Pending txPoolTxs `json:"pending"`
+	Queued  txPoolTxs `json:"queued"`
+}
+
+// observe records the transactions that are pending for the first time
+func (t *inclusionTracker) observe(ctx context.Context) error {
+	var content txPoolContent
+	if err := t.rpc.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return fmt.Errorf("cannot read the transaction pool: %w", err)
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, pool := range []txPoolTxs{content.Pending, content.Queued} {
+		for _, txs := range pool {
+			for _, tx := range txs {
+				if _, ok := t.seen[tx.Hash]; !ok {
+					t.seen[tx.Hash] = now
+				}
+			}
+		}
+	}
+	// Forget the transactions that were dropped rather than included
+	for hash, seen := range t.seen {
+		if now.Sub(seen) > t.horizon {
+			delete(t.seen, hash)
+		}
+	}
+	return nil
+}
+
+// inclusionBlock is the part of a block that is used
+type inclusionBlock struct {
+	Time         hexutil.Uint64 `json:"timestamp"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+// latencies returns the seconds that the transactions included in the
+// blocks after from up to to waited since they were first seen
+func (t *inclusionTracker) latencies(ctx context.Context, from, to uint64) ([]float64, error) {
+	var latencies []float64
+	for number := from + 1; number <= to; number++ {
+		var block *inclusionBlock
+		err := t.rpc.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		included := time.Unix(int64(block.Time), 0)
+
+		t.mu.Lock()
+		for _, hash := range block.Transactions {
+			seen, ok := t.seen[hash]
+			if !ok {
+				continue
+			}
+			delete(t.seen, hash)
+			// Block times have a resolution of seconds
+			latency := included.Sub(seen).Seconds()
+			if latency < 0 {
+				latency = 0
+			}
+			latencies = append(latencies, latency)
+		}
+		t.mu.Unlock()
+	}
+	log.Debug("inclusion latencies", "from", from, "to", to, "count", len(latencies))
+	return latencies, nil
+}
+
+// errInvalidInclusionTarget represents the error when the inclusion-time
+// mode lacks a valid target
+var errInvalidInclusionTarget = errors.New("invalid inclusion time target")
+
+// validateInclusionTime makes sure that the inclusion-time mode has a
+// target and a percentile to meet it at
+func (c *Config) validateInclusionTime() error {
+	switch c.l2GasPriceMode {
+	case "", modeGasPerSecond:
+		return nil
+	case modeInclusionTime:
+		if c.targetInclusionSeconds <= 0 {
+			return fmt.Errorf("%w: the target inclusion seconds must be set", errInvalidInclusionTarget)
+		}
+		if c.inclusionLatencyPercentile <= 0 || c.inclusionLatencyPercentile > 1 {
+			return fmt.Errorf("%w: the inclusion latency percentile must be between 0 and 1, got %v",
+				errInvalidInclusionTarget, c.inclusionLatencyPercentile)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown L2 gas price mode %q", c.l2GasPriceMode)
+	}
+}
@@ -0,0 +1,96 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInclusionTracker(t *testing.T) {
+	a, b, c := common.HexToHash("0xa"), common.HexToHash("0xb"), common.HexToHash("0xc")
+	start := time.Unix(1_000, 0)
+	var mu sync.Mutex
+	pool := map[string]interface{}{}
+	setPool := func(p map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		pool = p
+	}
+	blocks := map[uint64]interface{}{
+		1: map[string]interface{}{"timestamp": hexutil.Uint64(1_003), "transactions": []common.Hash{a}},
+		2: map[string]interface{}{"timestamp": hexutil.Uint64(1_010), "transactions": []common.Hash{b, c}},
+	}
+	l2 := newFakeRPC(map[string]interface{}{
+		"txpool_content": func([]json.RawMessage) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return pool, nil
+		},
+		"eth_getBlockByNumber": func(params []json.RawMessage) (interface{}, error) {
+			var number hexutil.Uint64
+			if err := json.Unmarshal(params[0], &number); err != nil {
+				return nil, err
+			}
+			return blocks[uint64(number)], nil
+		},
+	})
+	defer l2.Close()
+
+	tracker, err := newInclusionTracker(l2.URL, time.Minute)
+	require.NoError(t, err)
+	now := start
+	tracker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// a and b are pending at the start, b is still pending 4 seconds later
+	// along with a queued c
+	setPool(map[string]interface{}{
+		"pending": map[string]interface{}{"0x01": map[string]interface{}{"0": map[string]interface{}{"hash": a}, "1": map[string]interface{}{"hash": b}}},
+		"queued":  map[string]interface{}{},
+	})
+	require.NoError(t, tracker.observe(ctx))
+	now = start.Add(4 * time.Second)
+	setPool(map[string]interface{}{
+		"pending": map[string]interface{}{"0x01": map[string]interface{}{"1": map[string]interface{}{"hash": b}}},
+		"queued":  map[string]interface{}{"0x02": map[string]interface{}{"5": map[string]interface{}{"hash": c}}},
+	})
+	require.NoError(t, tracker.observe(ctx))
+
+	// The latencies run from the first sighting to the block time
+	latencies, err := tracker.latencies(ctx, 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, []float64{3, 10, 6}, latencies)
+	// and every transaction is counted once
+	latencies, err = tracker.latencies(ctx, 1, 2)
+	require.NoError(t, err)
+	require.Empty(t, latencies)
+
+	// Transactions that are never included are forgotten
+	now = start.Add(2 * time.Minute)
+	require.NoError(t, tracker.observe(ctx))
+	require.Len(t, tracker.seen, 2)
+	now = start.Add(4 * time.Minute)
+	setPool(map[string]interface{}{})
+	require.NoError(t, tracker.observe(ctx))
+	require.Empty(t, tracker.seen)
+
+	_, err = tracker.latencies(ctx, 2, 3)
+	require.Error(t, err)
+}
+
+func TestValidateInclusionTime(t *testing.T) {
+	require.NoError(t, (&Config{}).validateInclusionTime())
+	require.NoError(t, (&Config{l2GasPriceMode: modeInclusionTime, targetInclusionSeconds: 5, inclusionLatencyPercentile: 0.9}).validateInclusionTime())
+	require.ErrorIs(t, (&Config{l2GasPriceMode: modeInclusionTime, inclusionLatencyPercentile: 0.9}).validateInclusionTime(), errInvalidInclusionTarget)
+	require.ErrorIs(t, (&Config{l2GasPriceMode: modeInclusionTime, targetInclusionSeconds: 5, inclusionLatencyPercentile: 90}).validateInclusionTime(), errInvalidInclusionTarget)
+	require.Error(t, (&Config{l2GasPriceMode: "latency"}).validateInclusionTime())
+
+	cfg := &Config{enableL2GasPrice: true, l2GasPriceMode: modeInclusionTime}
+	require.Equal(t, modeInclusionTime, cfg.channelModes()[l2GasPriceChannel])
+}
@@ -0,0 +1,172 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// kmsTimeout is how long a request to KMS can take
+const kmsTimeout = 10 * time.Second
+
+var (
+	// errNoKMSRegion represents the error when a KMS key is configured
+	// without its region
+	errNoKMSRegion = errors.New("no KMS region configured")
+	// errInvalidKMSKey represents the error when the public key of the KMS
+	// key is not a secp256k1 key
+	errInvalidKMSKey = errors.New("KMS key is not a secp256k1 key")
+	// errInvalidKMSSignature represents the error when a signature of KMS
+	// does not recover to the public key of the KMS key
+	errInvalidKMSSignature = errors.New("invalid KMS signature")
+)
+
+// secp256k1HalfN is half of the order of the curve, above which the S value
+// of a signature is not canonical
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// kmsClient is the part of the KMS API that signs with a key
+type kmsClient interface {
+	// GetPublicKey returns the DER encoded SubjectPublicKeyInfo of the key
+	GetPublicKey(ctx context.Context, keyID string) ([]byte, error)
+	// Sign returns the DER encoded ECDSA signature of the digest by the key
+	Sign(ctx context.Context, keyID string, digest []byte) ([]byte, error)
+}
+
+// kmsSigner signs the transactions of an account whose private key is an
+// AWS KMS key, so that the private key never leaves KMS
+type kmsSigner struct {
+	client  kmsClient
+	keyID   string
+	pubkey  []byte
+	account common.Address
+}
+
+// newKMSSigner derives the address of the account from the public key of
+// the KMS key
+func newKMSSigner(client kmsClient, keyID string) (*kmsSigner, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	der, err := client.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the public key of the KMS key: %w", err)
+	}
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidKMSKey, err)
+	}
+	pubkey, err := crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidKMSKey, err)
+	}
+	account := crypto.PubkeyToAddress(*pubkey)
+	log.Info("Signing with the KMS key", "key-id", keyID, "address", account.Hex())
+	return &kmsSigner{
+		client:  client,
+		keyID:   keyID,
+		pubkey:  crypto.FromECDSAPub(pubkey),
+		account: account,
+	}, nil
+}
+
+func (s *kmsSigner) address() common.Address {
+	return s.account
+}
+
+// signTx signs the hash of the transaction with the KMS key. KMS returns
+// the R and S values only, so S is made canonical and the recovery id is
+// the one that recovers the public key of the KMS key.
+func (s *kmsSigner) signTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	der, err := s.client.Sign(ctx, s.keyID, hash.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign with the KMS key: %w", err)
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidKMSSignature, err)
+	}
+	if rs.S.Cmp(secp256k1HalfN) > 0 {
+		rs.S = new(big.Int).Sub(crypto.S256().Params().N, rs.S)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	rs.R.FillBytes(sig[:32])
+	rs.S.FillBytes(sig[32:64])
+	for _, v := range []byte{0, 1} {
+		sig[64] = v
+		pubkey, err := crypto.Ecrecover(hash.Bytes(), sig)
+		if err == nil && bytes.Equal(pubkey, s.pubkey) {
+			return tx.WithSignature(signer, sig)
+		}
+	}
+	return nil, errInvalidKMSSignature
+}
+
+// kmsAPIClient signs with the KMS client of the AWS SDK
+type kmsAPIClient struct {
+	client *kms.Client
+}
+
+// newKMSAPIClient creates the KMS client of the region. Its credentials
+// are resolved the way of the AWS SDK: from the environment, the shared
+// config and credentials files, a web identity token, the container or the
+// instance role.
+func newKMSAPIClient(ctx context.Context, region string, optFns ...func(*kms.Options)) (*kmsAPIClient, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the AWS config: %w", err)
+	}
+	return &kmsAPIClient{client: kms.NewFromConfig(cfg, optFns...)}, nil
+}
+
+func (c *kmsAPIClient) GetPublicKey(ctx context.Context, keyID string) ([]byte, error) {
+	res, err := c.client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, err
+	}
+	return res.PublicKey, nil
+}
+
+func (c *kmsAPIClient) Sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	res, err := c.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(keyID),
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Signature, nil
+}
+
+// dialKMSSigner creates the signer of the KMS key in the region
+func dialKMSSigner(keyID, region string) (*kmsSigner, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	client, err := newKMSAPIClient(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return newKMSSigner(client, keyID)
+}
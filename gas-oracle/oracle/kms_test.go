@@ -0,0 +1,144 @@
+package oracle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awskms "github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKMS signs with a key the way KMS does, returning the S values of its
+// signatures in the upper half of the curve order when highS is set
+type fakeKMS struct {
+	key   *ecdsa.PrivateKey
+	highS bool
+	signs int
+}
+
+func (f *fakeKMS) GetPublicKey(ctx context.Context, keyID string) ([]byte, error) {
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			// id-ecPublicKey on secp256k1
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}},
+		},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&f.key.PublicKey), BitLength: 8 * 65},
+	})
+}
+
+func (f *fakeKMS) Sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, f.key)
+	if err != nil {
+		return nil, err
+	}
+	f.signs++
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if f.highS {
+		s.Sub(crypto.S256().Params().N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func TestKMSSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	for _, highS := range []bool{false, true} {
+		kms := &fakeKMS{key: key, highS: highS}
+		signer, err := newKMSSigner(kms, "alias/gas-oracle")
+		require.NoError(t, err)
+		require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.address())
+
+		// Whichever the recovery id, the signature recovers the address
+		cfg := &Config{signer: signer, l2ChainID: big.NewInt(1337)}
+		opts, err := cfg.transactor()
+		require.NoError(t, err)
+		for nonce := uint64(0); nonce < 8; nonce++ {
+			tx := types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+			signed, err := opts.Signer(opts.From, tx)
+			require.NoError(t, err)
+			require.True(t, signed.Protected())
+			from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1337)), signed)
+			require.NoError(t, err)
+			require.Equal(t, opts.From, from)
+		}
+		require.Equal(t, 8, kms.signs)
+	}
+
+	// A signature by another key is refused
+	other, _ := crypto.GenerateKey()
+	signer, err := newKMSSigner(&fakeKMS{key: key}, "alias/gas-oracle")
+	require.NoError(t, err)
+	signer.client = &fakeKMS{key: other}
+	_, err = signer.signTx(types.NewTransaction(0, common.Address{1}, nil, 21000, big.NewInt(1), nil), big.NewInt(1337))
+	require.ErrorIs(t, err, errInvalidKMSSignature)
+}
+
+func TestKMSAPIClient(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	kms := &fakeKMS{key: key}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The requests are signed with the credentials of the environment
+		require.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		require.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request")
+		require.Equal(t, "token", r.Header.Get("X-Amz-Security-Token"))
+		var req struct {
+			KeyId            string
+			Message          []byte
+			MessageType      string
+			SigningAlgorithm string
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "alias/gas-oracle", req.KeyId)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			der, _ := kms.GetPublicKey(r.Context(), req.KeyId)
+			json.NewEncoder(w).Encode(map[string][]byte{"PublicKey": der})
+		case "TrentService.Sign":
+			require.Equal(t, "DIGEST", req.MessageType)
+			require.Equal(t, "ECDSA_SHA_256", req.SigningAlgorithm)
+			der, _ := kms.Sign(r.Context(), req.KeyId, req.Message)
+			json.NewEncoder(w).Encode(map[string][]byte{"Signature": der})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	// No shared config or credentials file is read
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	client, err := newKMSAPIClient(context.Background(), "eu-west-1", func(o *awskms.Options) {
+		o.BaseEndpoint = aws.String(server.URL)
+	})
+	require.NoError(t, err)
+
+	signer, err := newKMSSigner(client, "alias/gas-oracle")
+	require.NoError(t, err)
+	signed, err := signer.signTx(types.NewTransaction(0, common.Address{1}, nil, 21000, big.NewInt(1), nil), big.NewInt(1337))
+	require.NoError(t, err)
+	from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1337)), signed)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), from)
+	require.Equal(t, 1, kms.signs)
+}
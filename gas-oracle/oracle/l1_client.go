@@ -4,25 +4,46 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/mantlenetworkio/mantle/gas-oracle/units"
 )
 
 type L1Client struct {
 	*ethclient.Client
-	tokenPricer *tokenprice.Client
+	tokenPricer tokenprice.RatioReader
 }
 
-func NewL1Client(ethereumHttpUrl string, tokenPricer *tokenprice.Client) (*L1Client, error) {
-	l1Client, err := ethclient.Dial(ethereumHttpUrl)
-	if err != nil {
-		return nil, err
-	}
+func NewL1Client(l1Client *ethclient.Client, tokenPricer tokenprice.RatioReader) *L1Client {
 	return &L1Client{
 		Client:      l1Client,
 		tokenPricer: tokenPricer,
-	}, nil
+	}
+}
+
+// FeeHistory returns the fee history with the base fees scaled the same as
+// those of the headers
+func (c *L1Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	ratio, err := c.tokenPricer.PriceRatio()
+	if err != nil {
+		return nil, err
+	}
+	history, err := c.Client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	for i, baseFee := range history.BaseFee {
+		if baseFee != nil {
+			scaled, err := units.FromWei(baseFee).Scale(ratio)
+			if err != nil {
+				return nil, err
+			}
+			history.BaseFee[i] = scaled.Wei()
+		}
+	}
+	return history, nil
 }
 
 func (c *L1Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
@@ -37,6 +58,11 @@ func (c *L1Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.
 	if tip == nil {
 		return tip, nil
 	}
-	tip.BaseFee = new(big.Int).Mul(tip.BaseFee, big.NewInt(int64(ratio)))
+	// The base fee is converted into the L2 token at the price ratio
+	scaled, err := units.FromWei(tip.BaseFee).Scale(ratio)
+	if err != nil {
+		return nil, err
+	}
+	tip.BaseFee = scaled.Wei()
 	return tip, nil
 }
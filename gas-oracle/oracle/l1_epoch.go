@@ -0,0 +1,81 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// l1BaseFeeModeTip follows the base fee of the L1 tip
+	l1BaseFeeModeTip = "tip"
+	// l1BaseFeeModeEpoch follows the average base fee of the last complete
+	// L1 epoch
+	l1BaseFeeModeEpoch = "epoch"
+)
+
+var (
+	// errUnknownL1BaseFeeMode represents the error when the configured L1
+	// base fee mode is not known
+	errUnknownL1BaseFeeMode = errors.New("unknown L1 base fee mode")
+	// errNoCompleteL1Epoch represents the error when L1 has not completed
+	// an epoch yet
+	errNoCompleteL1Epoch = errors.New("no complete L1 epoch")
+)
+
+// validateL1BaseFeeMode makes sure that the L1 base fee mode is known and
+// has what it needs
+func (c *Config) validateL1BaseFeeMode() error {
+	switch c.l1BaseFeeMode {
+	case "", l1BaseFeeModeTip:
+	case l1BaseFeeModeEpoch:
+		if c.l1EpochBlocks == 0 {
+			return errors.New("the epoch L1 base fee mode requires the L1 epoch blocks")
+		}
+	default:
+		return fmt.Errorf("%w: %s", errUnknownL1BaseFeeMode, c.l1BaseFeeMode)
+	}
+	return nil
+}
+
+// l1EpochBoundary returns the first block of the epoch that the block is in
+func l1EpochBoundary(number, epochBlocks uint64) uint64 {
+	return number - number%epochBlocks
+}
+
+// epochBaseFee returns the average base fee of the complete L1 epoch that
+// ends right before the boundary
+func epochBaseFee(ctx context.Context, reader FeeHistoryReader, boundary, epochBlocks uint64) (*big.Int, error) {
+	if boundary < epochBlocks {
+		return nil, errNoCompleteL1Epoch
+	}
+	last := new(big.Int).SetUint64(boundary - 1)
+	history, err := reader.FeeHistory(ctx, epochBlocks, last, nil)
+	if err != nil {
+		return nil, err
+	}
+	return averageBaseFee(history)
+}
+
+// l1EpochSampler computes the L1 base fee once per L1 epoch and holds it
+// until the next boundary
+type l1EpochSampler struct {
+	epochBlocks uint64
+	boundary    uint64
+	baseFee     *big.Int
+}
+
+// sample returns the base fee of the last complete epoch as of the tip
+func (s *l1EpochSampler) sample(ctx context.Context, reader FeeHistoryReader, tip uint64) (*big.Int, error) {
+	boundary := l1EpochBoundary(tip, s.epochBlocks)
+	if s.baseFee != nil && boundary == s.boundary {
+		return s.baseFee, nil
+	}
+	baseFee, err := epochBaseFee(ctx, reader, boundary, s.epochBlocks)
+	if err != nil {
+		return nil, err
+	}
+	s.boundary, s.baseFee = boundary, baseFee
+	return baseFee, nil
+}
@@ -0,0 +1,135 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticL1 serves headers and fee history from a base fee per block
+type syntheticL1 struct {
+	bind.ContractTransactor
+	tip       uint64
+	baseFees  []int64
+	histories int
+}
+
+func (s *syntheticL1) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	n := s.tip
+	if number != nil {
+		n = number.Uint64()
+	}
+	if n > s.tip {
+		return nil, ethereum.NotFound
+	}
+	return &types.Header{
+		Number:  new(big.Int).SetUint64(n),
+		BaseFee: big.NewInt(s.baseFees[n]),
+	}, nil
+}
+
+func (s *syntheticL1) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	s.histories++
+	last := lastBlock.Uint64()
+	first := last + 1 - blockCount
+	history := &ethereum.FeeHistory{OldestBlock: new(big.Int).SetUint64(first)}
+	for n := first; n <= last+1; n++ {
+		history.BaseFee = append(history.BaseFee, big.NewInt(s.baseFees[n]))
+		if n <= last {
+			history.GasUsedRatio = append(history.GasUsedRatio, 0.5)
+		}
+	}
+	return history, nil
+}
+
+func TestEpochBaseFee(t *testing.T) {
+	l1 := &syntheticL1{baseFees: []int64{
+		// epoch 0
+		100, 200, 300, 400,
+		// epoch 1
+		1000, 1000, 3000, 3000,
+		// epoch 2
+		50, 60,
+	}}
+
+	_, err := epochBaseFee(context.Background(), l1, 0, 4)
+	require.ErrorIs(t, err, errNoCompleteL1Epoch)
+
+	fee, err := epochBaseFee(context.Background(), l1, 4, 4)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(250), fee)
+
+	// The next block is left out, only the epoch is averaged
+	fee, err = epochBaseFee(context.Background(), l1, 8, 4)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2000), fee)
+
+	// The sample holds within an epoch and moves at the boundary
+	sampler := &l1EpochSampler{epochBlocks: 4}
+	l1.histories = 0
+	for _, tip := range []uint64{4, 5, 7} {
+		fee, err := sampler.sample(context.Background(), l1, tip)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(250), fee)
+	}
+	require.Equal(t, 1, l1.histories)
+	fee, err = sampler.sample(context.Background(), l1, 9)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2000), fee)
+	require.Equal(t, uint64(8), sampler.boundary)
+	require.Equal(t, 2, l1.histories)
+}
+
+func TestBaseFeeUpdateOnL1EpochBoundaries(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	l1 := &syntheticL1{baseFees: []int64{
+		1e9, 2e9, 3e9, 2e9,
+		// Spikes within an epoch do not move the base fee
+		9e9, 1e9, 9e9, 1e9,
+		1e9,
+	}}
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		l1BaseFeeSignificanceFactor: 0.1,
+		l1BaseFeeMode:               l1BaseFeeModeEpoch,
+		l1EpochBlocks:               4,
+	}
+	require.NoError(t, cfg.validateL1BaseFeeMode())
+	update, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{})
+	require.NoError(t, err)
+
+	for _, step := range []struct {
+		tip  uint64
+		want int64
+	}{{4, 2e9}, {5, 2e9}, {6, 2e9}, {7, 2e9}, {8, 5e9}} {
+		l1.tip = step.tip
+		require.NoError(t, update())
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(step.want), l1BaseFee, "tip %d", step.tip)
+	}
+}
+
+func TestValidateL1BaseFeeMode(t *testing.T) {
+	require.NoError(t, (&Config{}).validateL1BaseFeeMode())
+	require.NoError(t, (&Config{l1BaseFeeMode: l1BaseFeeModeTip}).validateL1BaseFeeMode())
+	require.Error(t, (&Config{l1BaseFeeMode: l1BaseFeeModeEpoch}).validateL1BaseFeeMode())
+	require.ErrorIs(t, (&Config{l1BaseFeeMode: "slot"}).validateL1BaseFeeMode(), errUnknownL1BaseFeeMode)
+}
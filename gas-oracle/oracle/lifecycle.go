@@ -0,0 +1,252 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// The outcomes of an update that is meant to be sent
+const (
+	// outcomeSubmitted is an update that was sent and awaits its receipt
+	outcomeSubmitted = "submitted"
+	// outcomeConfirmed is an update that was included successfully
+	outcomeConfirmed = "confirmed"
+	// outcomeFailed is an update that was rejected or reverted
+	outcomeFailed = "failed"
+	// outcomeCoalesced is an update that was held back, the next epoch
+	// sends the latest value in its place
+	outcomeCoalesced = "coalesced"
+	// outcomeDropped is an update that was sent but never included
+	outcomeDropped = "dropped"
+)
+
+// The categories of the failed updates
+const (
+	failureReverted    = "reverted"
+	failureNonce       = "nonce"
+	failureUnderpriced = "underpriced"
+	failureFunds       = "insufficient_funds"
+	failureTimeout     = "timeout"
+	failureRejected    = "rejected"
+)
+
+// failureCategory returns the category of the error that sending an update
+// failed with
+func failureCategory(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return failureTimeout
+	}
+	if errors.Is(err, errSimulatedRevert) {
+		return failureReverted
+	}
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "nonce too"), strings.Contains(message, "already known"):
+		return failureNonce
+	case strings.Contains(message, "underpriced"), strings.Contains(message, "less than block base fee"):
+		return failureUnderpriced
+	case strings.Contains(message, "insufficient funds"):
+		return failureFunds
+	case strings.Contains(message, "timeout"), strings.Contains(message, "deadline exceeded"):
+		return failureTimeout
+	default:
+		return failureRejected
+	}
+}
+
+// submission is an update that was sent and awaits its receipt
+type submission struct {
+	channel   string
+	hash      common.Hash
+	submitted time.Time
+	// gasPrice is the price that the update was sent at
+	gasPrice *big.Int
+	// reader reads the receipt through the path that the update was sent on
+	reader bind.DeployBackend
+}
+
+// submissionTracker records the lifecycle of every update that a channel
+// means to send, from the attempt to its outcome, so that the share of the
+// updates that land and how long they take can be measured. The pending
+// submissions are resolved by polling their receipts, and the ones without
+// a receipt after timeout are dropped. A nil submissionTracker records
+// nothing.
+type submissionTracker struct {
+	now     func() time.Time
+	timeout time.Duration
+	// budget is charged the costs of the receipts
+	budget *spendBudget
+
+	mu      sync.Mutex
+	pending map[common.Hash]*submission
+}
+
+// newSubmissionTracker creates the tracker, or returns nil when timeout is
+// zero
+func newSubmissionTracker(timeout time.Duration) *submissionTracker {
+	if timeout <= 0 {
+		return nil
+	}
+	return &submissionTracker{
+		now:     time.Now,
+		timeout: timeout,
+		pending: make(map[common.Hash]*submission),
+	}
+}
+
+// sent records the attempt of the channel to send tx, which started at
+// attempted and returned err. The latency of an update includes the time
+// that it waited in the queue.
+func (t *submissionTracker) sent(channel string, tx *types.Transaction, reader bind.DeployBackend, attempted time.Time, err error) {
+	switch {
+	case errors.Is(err, errThrottled), errors.Is(err, errBudgetExceeded):
+		t.record(channel, tx.Hash(), outcomeCoalesced, "", attempted, t.now())
+	case err != nil:
+		t.record(channel, tx.Hash(), outcomeFailed, failureCategory(err), attempted, t.now())
+	default:
+		submissionCounter(channel, outcomeSubmitted).Inc(1)
+		t.mu.Lock()
+		t.pending[tx.Hash()] = &submission{channel: channel, hash: tx.Hash(), submitted: attempted, gasPrice: tx.GasPrice(), reader: reader}
+		submissionPendingGauge(channel).Update(int64(t.countPending(channel)))
+		t.mu.Unlock()
+	}
+}
+
+// poll resolves the pending submissions that have a receipt, or that have
+// waited longer than the timeout
+func (t *submissionTracker) poll(ctx context.Context) error {
+	t.mu.Lock()
+	pending := make([]*submission, 0, len(t.pending))
+	for _, s := range t.pending {
+		pending = append(pending, s)
+	}
+	t.mu.Unlock()
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].submitted.Before(pending[j].submitted)
+	})
+
+	var failed error
+	for _, s := range pending {
+		receipt, err := s.reader.TransactionReceipt(ctx, s.hash)
+		now := t.now()
+		switch {
+		case errors.Is(err, ethereum.NotFound) || (err == nil && receipt == nil):
+			if now.Sub(s.submitted) > t.timeout {
+				t.resolve(s, outcomeDropped, "", now)
+			}
+		case err != nil:
+			if failed == nil {
+				failed = err
+			}
+		case receipt.Status == types.ReceiptStatusSuccessful:
+			t.budget.record(s.channel, receipt, s.gasPrice)
+			t.resolve(s, outcomeConfirmed, "", now)
+		default:
+			// A reverted update still pays for its gas
+			t.budget.record(s.channel, receipt, s.gasPrice)
+			t.resolve(s, outcomeFailed, failureReverted, now)
+		}
+	}
+	return failed
+}
+
+// resolve records the outcome of a pending submission
+func (t *submissionTracker) resolve(s *submission, outcome, category string, now time.Time) {
+	t.mu.Lock()
+	delete(t.pending, s.hash)
+	submissionPendingGauge(s.channel).Update(int64(t.countPending(s.channel)))
+	t.mu.Unlock()
+	t.record(s.channel, s.hash, outcome, category, s.submitted, now)
+}
+
+// record exports the outcome of an update that was attempted at submitted
+// and resolved at resolved
+func (t *submissionTracker) record(channel string, hash common.Hash, outcome, category string, submitted, resolved time.Time) {
+	submissionCounter(channel, outcome).Inc(1)
+	if category != "" {
+		submissionFailureCounter(channel, category).Inc(1)
+	}
+	latency := resolved.Sub(submitted)
+	if outcome == outcomeConfirmed {
+		submissionLatencyTimer(channel).Update(latency)
+	}
+	ctx := []interface{}{"channel", channel, "hash", hash.Hex(), "outcome", outcome,
+		"submitted", submitted.UTC().Format(time.RFC3339), "resolved", resolved.UTC().Format(time.RFC3339), "latency", latency}
+	if category != "" {
+		ctx = append(ctx, "category", category)
+	}
+	if outcome == outcomeConfirmed || outcome == outcomeCoalesced {
+		log.Debug("Update resolved", ctx...)
+	} else {
+		log.Warn("Update did not land", ctx...)
+	}
+}
+
+// countPending returns the pending submissions of the channel, the lock
+// must be held
+func (t *submissionTracker) countPending(channel string) int {
+	count := 0
+	for _, s := range t.pending {
+		if s.channel == channel {
+			count++
+		}
+	}
+	return count
+}
+
+// backend returns a backend that records the updates of the channel
+func (t *submissionTracker) backend(channel string, backend DeployContractBackend) DeployContractBackend {
+	if t == nil {
+		return backend
+	}
+	return &lifecycleBackend{backendDecorator: decorate(backend), channel: channel, tracker: t}
+}
+
+// lifecycleBackend records every transaction that it sends
+type lifecycleBackend struct {
+	backendDecorator
+	channel string
+	tracker *submissionTracker
+}
+
+func (b *lifecycleBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	attempted := b.tracker.now()
+	err := b.DeployContractBackend.SendTransaction(ctx, tx)
+	b.tracker.sent(b.channel, tx, b.DeployContractBackend, attempted, err)
+	return err
+}
+
+func submissionCounter(channel, outcome string) metrics.Counter {
+	name := "submissions/" + metricName(channel) + "/" + outcome
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
+
+func submissionFailureCounter(channel, category string) metrics.Counter {
+	name := "submissions/" + metricName(channel) + "/failed/" + category
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
+
+func submissionPendingGauge(channel string) metrics.Gauge {
+	name := "submissions/" + metricName(channel) + "/pending"
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
+
+// submissionLatencyTimer measures how long the confirmed updates took to
+// land, its percentiles are exported along with it
+func submissionLatencyTimer(channel string) metrics.Timer {
+	name := "submissions/" + metricName(channel) + "/latency"
+	return metrics.GetOrRegisterTimer(name, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,133 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedBackend fails the sends with err, and returns receipt for every
+// transaction
+type scriptedBackend struct {
+	DeployContractBackend
+	err     error
+	receipt *types.Receipt
+}
+
+func (b *scriptedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.err
+}
+
+func (b *scriptedBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return b.receipt, nil
+}
+
+func TestSubmissionConfirmed(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	tracker := newSubmissionTracker(time.Minute)
+	now := time.Unix(1_000, 0)
+	tracker.now = func() time.Time { return now }
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	update, err := wrapUpdateBaseFee(sim, tracker.backend(l1BaseFeeChannel, sim), cfg, channelGuards{})
+	require.NoError(t, err)
+
+	submitted := submissionCounter(l1BaseFeeChannel, outcomeSubmitted).Count()
+	confirmed := submissionCounter(l1BaseFeeChannel, outcomeConfirmed).Count()
+	latencies := submissionLatencyTimer(l1BaseFeeChannel).Count()
+	require.NoError(t, update())
+	require.Equal(t, submitted+1, submissionCounter(l1BaseFeeChannel, outcomeSubmitted).Count())
+	require.Equal(t, int64(1), submissionPendingGauge(l1BaseFeeChannel).Value())
+
+	// Without a receipt the update stays submitted
+	now = now.Add(2 * time.Second)
+	require.NoError(t, tracker.poll(context.Background()))
+	require.Len(t, tracker.pending, 1)
+
+	// and is confirmed once it is included
+	sim.Commit()
+	now = now.Add(3 * time.Second)
+	require.NoError(t, tracker.poll(context.Background()))
+	require.Empty(t, tracker.pending)
+	require.Equal(t, confirmed+1, submissionCounter(l1BaseFeeChannel, outcomeConfirmed).Count())
+	require.Equal(t, latencies+1, submissionLatencyTimer(l1BaseFeeChannel).Count())
+	require.Equal(t, int64(0), submissionPendingGauge(l1BaseFeeChannel).Value())
+}
+
+func TestSubmissionFailed(t *testing.T) {
+	tracker := newSubmissionTracker(time.Minute)
+	now := time.Unix(1_000, 0)
+	tracker.now = func() time.Time { return now }
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	ctx := context.Background()
+	count := func(category string) int64 {
+		return submissionFailureCounter(daFeeChannel, category).Count()
+	}
+
+	// An update that is included but reverts
+	reverted := count(failureReverted)
+	backend := &scriptedBackend{}
+	require.NoError(t, tracker.backend(daFeeChannel, backend).SendTransaction(ctx, tx))
+	backend.receipt = &types.Receipt{Status: types.ReceiptStatusFailed}
+	require.NoError(t, tracker.poll(ctx))
+	require.Equal(t, reverted+1, count(failureReverted))
+	require.Empty(t, tracker.pending)
+
+	// Updates that are rejected when they are sent
+	for _, tc := range []struct {
+		err      error
+		category string
+	}{
+		{errors.New("nonce too low"), failureNonce},
+		{errors.New("replacement transaction underpriced"), failureUnderpriced},
+		{errors.New("insufficient funds for gas * price + value"), failureFunds},
+		{context.DeadlineExceeded, failureTimeout},
+		{errObserveOnly, failureRejected},
+	} {
+		before := count(tc.category)
+		backend := &scriptedBackend{err: tc.err}
+		require.ErrorIs(t, tracker.backend(daFeeChannel, backend).SendTransaction(ctx, tx), tc.err)
+		require.Equal(t, before+1, count(tc.category), tc.category)
+	}
+	require.Empty(t, tracker.pending)
+
+	// An update that is held back is coalesced into the next one
+	coalesced := submissionCounter(daFeeChannel, outcomeCoalesced).Count()
+	backend = &scriptedBackend{err: fmt.Errorf("%w: 3 pending", errThrottled)}
+	require.Error(t, tracker.backend(daFeeChannel, backend).SendTransaction(ctx, tx))
+	require.Equal(t, coalesced+1, submissionCounter(daFeeChannel, outcomeCoalesced).Count())
+
+	// An update that is never included is dropped after the timeout
+	dropped := submissionCounter(daFeeChannel, outcomeDropped).Count()
+	backend = &scriptedBackend{}
+	require.NoError(t, tracker.backend(daFeeChannel, backend).SendTransaction(ctx, tx))
+	now = now.Add(time.Minute)
+	require.NoError(t, tracker.poll(ctx))
+	require.Len(t, tracker.pending, 1)
+	now = now.Add(time.Second)
+	require.NoError(t, tracker.poll(ctx))
+	require.Empty(t, tracker.pending)
+	require.Equal(t, dropped+1, submissionCounter(daFeeChannel, outcomeDropped).Count())
+
+	require.Nil(t, newSubmissionTracker(0))
+}
@@ -0,0 +1,15 @@
+package oracle
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestMain(m *testing.M) {
+	// Metrics are only collected when enabled, which normally happens
+	// when the --metrics flag is passed
+	metrics.Enabled = true
+	os.Exit(m.Run())
+}
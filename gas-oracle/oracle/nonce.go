@@ -0,0 +1,151 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// NonceReader reads the nonces of an account from the chain
+type NonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// nonceManager hands out the nonces of the signer from a local counter, so
+// that the channels can have several updates in flight without reusing a
+// nonce. The counter drifts from the chain when the signer is also used by
+// another tool or by hand, so it is reconciled against the chain every
+// interval and right after a failed send. A nil nonceManager leaves the
+// nonces to the node.
+type nonceManager struct {
+	signer   common.Address
+	reader   NonceReader
+	interval time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	nonce      uint64
+	synced     bool
+	sendFailed bool
+	lastSync   time.Time
+}
+
+// newNonceManager creates the nonce manager of the signer, or returns nil
+// when interval is zero
+func newNonceManager(signer common.Address, reader NonceReader, interval time.Duration) *nonceManager {
+	if interval == 0 {
+		return nil
+	}
+	return &nonceManager{
+		signer:   signer,
+		reader:   reader,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// next reserves the next nonce of the signer
+func (m *nonceManager) next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.synced || m.sendFailed || m.now().Sub(m.lastSync) >= m.interval {
+		if err := m.reconcile(ctx); err != nil {
+			return 0, err
+		}
+	}
+	nonce := m.nonce
+	m.nonce++
+	return nonce, nil
+}
+
+// failed forces a reset to the pending nonce before the next nonce is
+// handed out, as the nonce of a transaction that was not sent is not used
+// on chain
+func (m *nonceManager) failed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sendFailed = true
+}
+
+// reconcile compares the local nonce with the chain. A pending nonce
+// beyond the local one means that another sender used the signer. A local
+// nonce beyond the pending one, while no transaction of the signer is
+// pending, means that transactions of the oracle were dropped. In both
+// cases, and after a failed send, the local nonce is reset to the pending
+// nonce.
+func (m *nonceManager) reconcile(ctx context.Context) error {
+	pending, err := m.reader.PendingNonceAt(ctx, m.signer)
+	if err != nil {
+		return err
+	}
+	latest, err := m.reader.NonceAt(ctx, m.signer, nil)
+	if err != nil {
+		return err
+	}
+	m.lastSync = m.now()
+
+	switch {
+	case !m.synced:
+		log.Info("Signer nonce synced", "signer", m.signer.Hex(), "nonce", pending)
+	case m.sendFailed:
+		log.Debug("Signer nonce reset after a failed send", "signer", m.signer.Hex(),
+			"local", m.nonce, "pending", pending)
+	case pending > m.nonce:
+		log.Warn("Signer nonce drifted after external activity, resyncing", "signer", m.signer.Hex(),
+			"local", m.nonce, "pending", pending, "latest", latest)
+		nonceResyncCounter().Inc(1)
+	case pending < m.nonce && pending == latest:
+		log.Warn("Signer transactions were dropped, resyncing nonce", "signer", m.signer.Hex(),
+			"local", m.nonce, "pending", pending)
+		nonceResyncCounter().Inc(1)
+	default:
+		return nil
+	}
+	m.nonce = pending
+	m.synced = true
+	m.sendFailed = false
+	return nil
+}
+
+// backend returns a backend that signs the transactions of the signer
+// with the nonces of the manager
+func (m *nonceManager) backend(backend DeployContractBackend) DeployContractBackend {
+	if m == nil {
+		return backend
+	}
+	return &noncedBackend{backendDecorator: decorate(backend), nonces: m}
+}
+
+// noncedBackend serves the nonces of the signer from a nonce manager
+type noncedBackend struct {
+	backendDecorator
+	nonces *nonceManager
+}
+
+func (b *noncedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if account != b.nonces.signer {
+		return b.DeployContractBackend.PendingNonceAt(ctx, account)
+	}
+	return b.nonces.next(ctx)
+}
+
+func (b *noncedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.DeployContractBackend.SendTransaction(ctx, tx); err != nil {
+		b.nonces.failed()
+		return err
+	}
+	return nil
+}
+
+func nonceResyncCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("nonce/resyncs", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,131 @@
+package oracle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// sendExternal sends a transfer from the signer behind the back of the
+// oracle, as another tool would
+func sendExternal(t *testing.T, sim *backends.SimulatedBackend, key *ecdsa.PrivateKey) {
+	ctx := context.Background()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := sim.PendingNonceAt(ctx, from)
+	require.NoError(t, err)
+	gasPrice, err := sim.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	tx, err := types.SignTx(types.NewTransaction(nonce, from, new(big.Int), 21_000, gasPrice, nil),
+		types.NewEIP155Signer(big.NewInt(1337)), key)
+	require.NoError(t, err)
+	require.NoError(t, sim.SendTransaction(ctx, tx))
+	sim.Commit()
+}
+
+func TestNonceResyncsAfterExternalTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	now := time.Unix(1_700_000_000, 0)
+	nonces := newNonceManager(opts.From, sim, time.Minute)
+	nonces.now = func() time.Time { return now }
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	update, err := wrapUpdateL2GasPriceFn(nonces.backend(sim), cfg, channelGuards{})
+	require.NoError(t, err)
+
+	requirePrice := func(price uint64) {
+		gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, price, gasPrice.Uint64())
+	}
+
+	require.NoError(t, update(1))
+	sim.Commit()
+	requirePrice(1)
+
+	// The reconciliation notices the external transaction
+	sendExternal(t, sim, key)
+	now = now.Add(time.Minute)
+	before := nonceResyncCounter().Count()
+	require.NoError(t, update(2))
+	sim.Commit()
+	requirePrice(2)
+	require.Equal(t, before+1, nonceResyncCounter().Count())
+
+	// Before the next reconciliation the stale nonce fails the send, and
+	// the following update is sent with the nonce of the chain
+	sendExternal(t, sim, key)
+	require.Error(t, update(3))
+	require.NoError(t, update(4))
+	sim.Commit()
+	requirePrice(4)
+
+	pending, err := sim.PendingNonceAt(context.Background(), opts.From)
+	require.NoError(t, err)
+	require.Equal(t, pending, nonces.nonce)
+}
+
+// staticNonces serves fixed nonces
+type staticNonces struct {
+	pending, latest uint64
+}
+
+func (s *staticNonces) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return s.pending, nil
+}
+
+func (s *staticNonces) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	return s.latest, nil
+}
+
+func TestNonceReconcile(t *testing.T) {
+	ctx := context.Background()
+	chain := &staticNonces{pending: 5, latest: 5}
+	now := time.Unix(1_700_000_000, 0)
+	nonces := newNonceManager(common.Address{1}, chain, time.Minute)
+	nonces.now = func() time.Time { return now }
+
+	// Nonces are handed out locally between reconciliations
+	for want := uint64(5); want < 8; want++ {
+		nonce, err := nonces.next(ctx)
+		require.NoError(t, err)
+		require.Equal(t, want, nonce)
+	}
+
+	// Transactions of the oracle that are still pending are kept
+	chain.pending, chain.latest = 8, 6
+	now = now.Add(time.Minute)
+	nonce, err := nonces.next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), nonce)
+
+	// Transactions of the oracle that were dropped are reused
+	chain.pending, chain.latest = 7, 7
+	now = now.Add(time.Minute)
+	before := nonceResyncCounter().Count()
+	nonce, err = nonces.next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nonce)
+	require.Equal(t, before+1, nonceResyncCounter().Count())
+
+	require.Nil(t, newNonceManager(common.Address{1}, chain, 0))
+}
@@ -0,0 +1,74 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errObserveOnly represents the error when a transaction is sent on an
+// observe-only channel
+var errObserveOnly = errors.New("channel is observe-only")
+
+// parseObserveOnly parses a list of channels of the form
+// `l1-base-fee,da-fee`
+func parseObserveOnly(spec string) (map[string]bool, error) {
+	channels := make(map[string]bool)
+	for _, channel := range strings.Split(spec, ",") {
+		channel = strings.TrimSpace(channel)
+		if channel == "" {
+			continue
+		}
+		switch channel {
+		case l1BaseFeeChannel, l2GasPriceChannel, daFeeChannel, overheadChannel, scalarChannel:
+		default:
+			return nil, fmt.Errorf("unknown channel %q", channel)
+		}
+		channels[channel] = true
+	}
+	return channels, nil
+}
+
+// observes returns true when the channel only reports what it would write
+func (c *Config) observes(channel string) bool {
+	return c.observeOnly[channel]
+}
+
+// observe reports the value that an observe-only channel would write in
+// place of building the transaction
+func observe(channel string, trace *decisionTrace, key string, value *big.Int) {
+	log.Info("Observe-only channel would update", "channel", channel, key, value)
+	trace.output(key, value)
+	trace.act(actionObserve, reasonObserveOnly, "observe-only")
+	observedGauge(channel).Update(value.Int64())
+}
+
+// observeOnly returns a backend that refuses to send the transactions of
+// an observe-only channel, so that nothing is written even if a code path
+// misses the check
+func observeOnly(channel string, backend DeployContractBackend) DeployContractBackend {
+	return &observedBackend{backendDecorator: decorate(backend), channel: channel, err: errObserveOnly}
+}
+
+// observedBackend refuses to send any transaction with err
+type observedBackend struct {
+	backendDecorator
+	channel string
+	err     error
+}
+
+func (b *observedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	log.Error("Refusing to send a transaction", "channel", b.channel, "hash", tx.Hash().Hex(), "reason", b.err)
+	return fmt.Errorf("%w: %s", b.err, b.channel)
+}
+
+func observedGauge(channel string) metrics.Gauge {
+	return metrics.GetOrRegisterGauge("observe_only/"+metricName(channel), ometrics.DefaultRegistry)
+}
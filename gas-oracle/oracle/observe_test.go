@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// buildRecorder counts the calls that building and sending a transaction
+// go through
+type buildRecorder struct {
+	DeployContractBackend
+	nonces int
+	sent   int
+}
+
+func (b *buildRecorder) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.nonces++
+	return b.DeployContractBackend.PendingNonceAt(ctx, account)
+}
+
+func (b *buildRecorder) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent++
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+func TestObserveOnlyNeverBuildsTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	observeOnly, err := parseObserveOnly("l1-base-fee")
+	require.NoError(t, err)
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		observeOnly:           observeOnly,
+	}
+	recorder := &buildRecorder{DeployContractBackend: sim}
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	update, err := wrapUpdateBaseFee(sim, recorder, cfg, channelGuards{trace: trace})
+	require.NoError(t, err)
+
+	tip := sim.Blockchain().CurrentHeader()
+	require.NoError(t, trace.wrap(update)())
+	sim.Commit()
+
+	require.Zero(t, recorder.nonces, "transaction built on an observe-only channel")
+	require.Zero(t, recorder.sent, "transaction sent on an observe-only channel")
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 0, l1BaseFee.Cmp(common.Big0))
+
+	// What would have been written is still reported
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionObserve, decision.Action)
+	require.Equal(t, tip.BaseFee, decision.Outputs["l1_base_fee"])
+	require.Equal(t, tip.BaseFee.Int64(), observedGauge(l1BaseFeeChannel).Value())
+}
+
+func TestObserveOnlyBackendRefusesToSend(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	backend := observeOnly(daFeeChannel, sim)
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)), &types.LegacyTx{
+		To:       &common.Address{},
+		Gas:      21000,
+		GasPrice: big.NewInt(1e9),
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, backend.SendTransaction(context.Background(), tx), errObserveOnly)
+}
+
+func TestParseObserveOnly(t *testing.T) {
+	channels, err := parseObserveOnly("")
+	require.NoError(t, err)
+	require.False(t, (&Config{observeOnly: channels}).observes(daFeeChannel))
+
+	channels, err = parseObserveOnly(" da-fee , l2-gas-price")
+	require.NoError(t, err)
+	cfg := &Config{observeOnly: channels}
+	require.True(t, cfg.observes(daFeeChannel))
+	require.True(t, cfg.observes(l2GasPriceChannel))
+	require.False(t, cfg.observes(l1BaseFeeChannel))
+
+	channels, err = parseObserveOnly("overhead,scalar")
+	require.NoError(t, err)
+	require.True(t, (&Config{observeOnly: channels}).observes(scalarChannel))
+
+	_, err = parseObserveOnly("charge")
+	require.Error(t, err)
+}
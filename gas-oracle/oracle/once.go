@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errCycleFailed represents the error when a channel failed its update in
+// a single cycle
+var errCycleFailed = errors.New("update cycle failed")
+
+// RunOnce runs a single read-compute-submit cycle of every enabled channel
+// in place of the loops, for setups that schedule the oracle externally. A
+// channel that skips its update because it is not needed succeeds. The
+// returned error names every channel that failed, including the ones whose
+// epoch was aborted or throttled, since nothing retries them.
+func (g *GasPriceOracle) RunOnce() error {
+	if err := g.prepare(); err != nil {
+		return err
+	}
+
+	// The L1 client prices with the token prices, which no loop refreshes
+	if len(g.config.tokenPriceSymbols) > 0 {
+		if err := g.tokenPricer.Refresh(); err != nil {
+			log.Warn("cannot refresh token prices", "message", err)
+		}
+	}
+	// nor the cached token price
+	if g.priceCache != nil {
+		if err := g.priceCache.poll(g.tokenPricer); err != nil {
+			log.Warn("cannot poll the token price", "message", err)
+		}
+	}
+
+	updates, err := g.channelUpdates()
+	if err != nil {
+		return err
+	}
+	return runCycle(updates)
+}
+
+// channelUpdates returns an epoch of every enabled channel
+func (g *GasPriceOracle) channelUpdates() (map[string]func() error, error) {
+	updates := make(map[string]func() error)
+	if g.config.enableL1BaseFee {
+		update, err := g.baseFeeUpdate()
+		if err != nil {
+			return nil, err
+		}
+		updates[l1BaseFeeChannel] = update
+	}
+	if g.config.enableDaFee {
+		update, err := g.daFeeUpdate()
+		if err != nil {
+			return nil, err
+		}
+		updates[daFeeChannel] = update
+	}
+	if g.config.enableL2GasPrice {
+		updates[l2GasPriceChannel] = g.gasPriceUpdate()
+	}
+	if g.config.enableOverhead {
+		update, err := g.feeParameterUpdate(g.config.overheadParameter())
+		if err != nil {
+			return nil, err
+		}
+		updates[overheadChannel] = update
+	}
+	if g.config.enableScalar {
+		update, err := g.feeParameterUpdate(g.config.scalarParameter())
+		if err != nil {
+			return nil, err
+		}
+		updates[scalarChannel] = update
+	}
+	return updates, nil
+}
+
+// runCycle runs every update once, in the order of the channel names, and
+// reports the channels that failed
+func runCycle(updates map[string]func() error) error {
+	channels := make([]string, 0, len(updates))
+	for channel := range updates {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	var failures []string
+	for _, channel := range channels {
+		if err := updates[channel](); err != nil {
+			log.Error("cannot update", "channel", channel, "message", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", channel, err))
+			continue
+		}
+		log.Info("Channel cycle finished", "channel", channel)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", errCycleFailed, strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCycle(t *testing.T) {
+	var runs []string
+	update := func(channel string, err error) func() error {
+		return func() error {
+			runs = append(runs, channel)
+			return err
+		}
+	}
+
+	// Every channel runs once, and the cycle succeeds when they all do
+	require.NoError(t, runCycle(map[string]func() error{
+		l2GasPriceChannel: update(l2GasPriceChannel, nil),
+		l1BaseFeeChannel:  update(l1BaseFeeChannel, nil),
+	}))
+	require.Equal(t, []string{l1BaseFeeChannel, l2GasPriceChannel}, runs)
+
+	// A failed channel does not keep the others from running, and the
+	// cycle fails naming it
+	runs = nil
+	err := runCycle(map[string]func() error{
+		daFeeChannel:      update(daFeeChannel, errors.New("no route")),
+		l1BaseFeeChannel:  update(l1BaseFeeChannel, errEpochAborted),
+		l2GasPriceChannel: update(l2GasPriceChannel, nil),
+	})
+	require.ErrorIs(t, err, errCycleFailed)
+	require.Contains(t, err.Error(), daFeeChannel)
+	require.Contains(t, err.Error(), l1BaseFeeChannel)
+	require.NotContains(t, err.Error(), l2GasPriceChannel)
+	require.Equal(t, []string{daFeeChannel, l1BaseFeeChannel, l2GasPriceChannel}, runs)
+}
+
+// unreachableL1 fails every header read
+type unreachableL1 struct {
+	bind.ContractTransactor
+}
+
+func (unreachableL1) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRunOnceUpdatesBaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, legacy, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	contract, err := bindings.NewBVMGasPriceOracle(addr, sim)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		l1ChainID:                   big.NewInt(1337),
+		l2ChainID:                   big.NewInt(1337),
+		privateKey:                  key,
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		enableL1BaseFee:             true,
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	history := newDecisionHistory(10)
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, history)
+	g := &GasPriceOracle{
+		config:         cfg,
+		contract:       contract,
+		l1Backend:      &syntheticL1{baseFees: []int64{5e9}},
+		l1RawBackend:   sim,
+		baseFeeBackend: sim,
+		state:          new(stateStore),
+		traces:         map[string]*decisionTrace{l1BaseFeeChannel: trace},
+		modes:          newModeReporter(),
+		baseFeeFreezer: newFreezer(l1BaseFeeChannel, 0),
+	}
+
+	// A single epoch writes the L1 base fee
+	require.NoError(t, g.RunOnce())
+	sim.Commit()
+	require.Len(t, history.series(l1BaseFeeChannel, time.Time{}, time.Time{}), 1)
+	decision, _ := trace.lastDecision()
+	require.Equal(t, actionUpdate, decision.Action)
+	baseFee, err := legacy.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5e9), baseFee)
+
+	// A cycle that cannot read L1 fails
+	g.l1Backend = unreachableL1{}
+	require.ErrorIs(t, g.RunOnce(), errCycleFailed)
+	require.Len(t, history.series(l1BaseFeeChannel, time.Time{}, time.Time{}), 2)
+}
@@ -0,0 +1,78 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// pendingReceipts records the updates that were sent and whose receipt was
+// not received yet, so that the ones still pending at shutdown can be
+// tracked by an operator. A nil pendingReceipts records nothing.
+type pendingReceipts struct {
+	mu  sync.Mutex
+	txs map[common.Hash]string
+}
+
+// newPendingReceipts creates the record of the pending receipts, or returns
+// nil when the receipts are not waited for
+func newPendingReceipts(waitForReceipt bool) *pendingReceipts {
+	if !waitForReceipt {
+		return nil
+	}
+	return &pendingReceipts{txs: make(map[common.Hash]string)}
+}
+
+// backend records the updates of the channel sent through the backend
+// until their receipt is fetched
+func (p *pendingReceipts) backend(channel string, backend DeployContractBackend) DeployContractBackend {
+	if p == nil {
+		return backend
+	}
+	return &pendingBackend{backendDecorator: decorate(backend), channel: channel, pending: p}
+}
+
+// snapshot returns the channel of every update whose receipt is pending by
+// transaction hash
+func (p *pendingReceipts) snapshot() map[common.Hash]string {
+	txs := make(map[common.Hash]string)
+	if p == nil {
+		return txs
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for hash, channel := range p.txs {
+		txs[hash] = channel
+	}
+	return txs
+}
+
+// pendingBackend records the transactions that it sends until their
+// receipt is fetched
+type pendingBackend struct {
+	backendDecorator
+	channel string
+	pending *pendingReceipts
+}
+
+func (b *pendingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.DeployContractBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.pending.mu.Lock()
+	defer b.pending.mu.Unlock()
+	b.pending.txs[tx.Hash()] = b.channel
+	return nil
+}
+
+func (b *pendingBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := b.DeployContractBackend.TransactionReceipt(ctx, hash)
+	if err == nil && receipt != nil {
+		b.pending.mu.Lock()
+		delete(b.pending.txs, hash)
+		b.pending.mu.Unlock()
+	}
+	return receipt, err
+}
@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/flags"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+)
+
+var (
+	// errStaleTokenPrice represents the error when the cached token price
+	// is older than the max age, or was never fetched, so that the update
+	// that needs it is skipped
+	errStaleTokenPrice = errors.New("token price too old")
+	// errInvalidTokenPriceMaxAge represents the error when the token price
+	// expires before the poller can refresh it
+	errInvalidTokenPriceMaxAge = errors.New("invalid token price max age")
+)
+
+// priceCache holds the token price ratio that the exchanges were last
+// polled for, so that the updates read it at their own cadence rather than
+// polling the exchanges themselves. A price older than maxAge is unusable.
+type priceCache struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu    sync.RWMutex
+	price float64
+	at    time.Time
+}
+
+// newPriceCache creates the cache of the token price, or returns nil when
+// maxAge is zero and the updates read the token price on demand
+func newPriceCache(maxAge time.Duration) *priceCache {
+	if maxAge <= 0 {
+		return nil
+	}
+	return &priceCache{maxAge: maxAge, now: time.Now}
+}
+
+// set caches the price, fetched at the time
+func (c *priceCache) set(price float64, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.price, c.at = price, at
+}
+
+// Get returns the cached price along with its age. ok is false when no
+// price was cached yet, or when it is older than the max age.
+func (c *priceCache) Get() (price float64, age time.Duration, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.at.IsZero() {
+		return 0, 0, false
+	}
+	age = c.now().Sub(c.at)
+	return c.price, age, age <= c.maxAge
+}
+
+// PriceRatio returns the cached price, or errStaleTokenPrice when it is
+// unusable. The age of the price is exported as the
+// `token_price/cache/age_seconds` gauge, and every read of an unusable
+// price is counted in `token_price/cache/stale`.
+func (c *priceCache) PriceRatio() (float64, error) {
+	price, age, ok := c.Get()
+	priceCacheAgeGauge().Update(int64(age.Seconds()))
+	if ok {
+		return price, nil
+	}
+	priceCacheStaleCounter().Inc(1)
+	if age == 0 {
+		return 0, fmt.Errorf("%w: not fetched yet", errStaleTokenPrice)
+	}
+	return 0, fmt.Errorf("%w: fetched %s ago, over %s", errStaleTokenPrice, age.Truncate(time.Second), c.maxAge)
+}
+
+// poll fetches the token price from the exchanges into the cache. A price
+// that the decay policy holds while the exchanges fail keeps the time that
+// it was last fetched at.
+func (c *priceCache) poll(pricer *tokenprice.Client) error {
+	price, err := pricer.Price()
+	if err != nil {
+		return err
+	}
+	c.set(price.Ratio, c.now().Add(-price.Stale))
+	return nil
+}
+
+// validateTokenPriceMaxAge makes sure that the cached token price outlives
+// the interval that it is polled at
+func (c *Config) validateTokenPriceMaxAge() error {
+	if c.tokenPriceMaxAgeSeconds == 0 || c.tokenPriceMaxAgeSeconds > c.tokenPricerUpdateFrequencySecond {
+		return nil
+	}
+	return fmt.Errorf("%w: --%s of %ds must exceed the --%s of %ds", errInvalidTokenPriceMaxAge,
+		flags.TokenPriceMaxAgeFlag.Name, c.tokenPriceMaxAgeSeconds,
+		flags.TokenPricerUpdateFrequencySecond.Name, c.tokenPricerUpdateFrequencySecond)
+}
+
+func priceCacheAgeGauge() metrics.Gauge {
+	return metrics.GetOrRegisterGauge("token_price/cache/age_seconds", ometrics.DefaultRegistry)
+}
+
+func priceCacheStaleCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("token_price/cache/stale", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceCache(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := newPriceCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	// An empty cache has no price to update with
+	_, _, ok := cache.Get()
+	require.False(t, ok)
+	stale := priceCacheStaleCounter().Count()
+	_, err := cache.PriceRatio()
+	require.ErrorIs(t, err, errStaleTokenPrice)
+	require.Equal(t, stale+1, priceCacheStaleCounter().Count())
+
+	// A fresh price is used, along with its age
+	cache.set(3000, now.Add(-10*time.Second))
+	price, age, ok := cache.Get()
+	require.True(t, ok)
+	require.Equal(t, 3000.0, price)
+	require.Equal(t, 10*time.Second, age)
+	ratio, err := cache.PriceRatio()
+	require.NoError(t, err)
+	require.Equal(t, 3000.0, ratio)
+	require.Equal(t, int64(10), priceCacheAgeGauge().Value())
+
+	// until it is older than the max age
+	now = now.Add(time.Minute)
+	price, age, ok = cache.Get()
+	require.False(t, ok)
+	require.Equal(t, 3000.0, price)
+	require.Equal(t, 70*time.Second, age)
+	_, err = cache.PriceRatio()
+	require.ErrorIs(t, err, errStaleTokenPrice)
+	require.Equal(t, stale+2, priceCacheStaleCounter().Count())
+	require.Equal(t, int64(70), priceCacheAgeGauge().Value())
+
+	// Without a max age there is no cache
+	require.Nil(t, newPriceCache(0))
+}
+
+func TestPriceCachePoll(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := newPriceCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	// The poller writes the price of the exchanges into the cache
+	pricer := tokenprice.NewClientWithBackend(staticPrices{"ETHUSDT": 1500, "BITUSDT": 0.5}, 0)
+	require.NoError(t, cache.poll(pricer))
+	price, age, ok := cache.Get()
+	require.True(t, ok)
+	require.Equal(t, 3000.0, price)
+	require.Zero(t, age)
+
+	// and leaves it alone when they fail
+	now = now.Add(time.Minute + time.Second)
+	require.Error(t, cache.poll(tokenprice.NewClientWithBackend(staticPrices{}, 0)))
+	_, _, ok = cache.Get()
+	require.False(t, ok)
+}
+
+func TestStaleTokenPriceSkipsUpdate(t *testing.T) {
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	err := trace.wrap(func() error {
+		return fmt.Errorf("cannot get gas price: %w", errStaleTokenPrice)
+	})()
+	require.True(t, errors.Is(err, errStaleTokenPrice))
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionSkip, decision.Action)
+	require.Equal(t, reasonStaleTokenPrice, decision.ReasonCode)
+}
+
+func TestValidateTokenPriceMaxAge(t *testing.T) {
+	cfg := &Config{tokenPricerUpdateFrequencySecond: 3}
+	require.NoError(t, cfg.validateTokenPriceMaxAge())
+	cfg.tokenPriceMaxAgeSeconds = 10
+	require.NoError(t, cfg.validateTokenPriceMaxAge())
+	cfg.tokenPriceMaxAgeSeconds = 3
+	require.ErrorIs(t, cfg.validateTokenPriceMaxAge(), errInvalidTokenPriceMaxAge)
+}
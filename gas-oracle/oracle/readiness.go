@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// readinessTimeout bounds the time an endpoint has to answer a readiness
+// check
+const readinessTimeout = 2 * time.Second
+
+// headerReader is an endpoint that readiness checks are made against
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// readiness decides whether the oracle is ready: every tracked channel
+// finished an epoch within the last staleness epochs, and every endpoint
+// answers. A staleness of zero only checks the endpoints, and a nil
+// readiness is always ready.
+type readiness struct {
+	staleness uint64
+	endpoints map[string]headerReader
+	now       func() time.Time
+
+	mu       sync.Mutex
+	channels map[string]*channelFreshness
+}
+
+// channelFreshness is when a channel last finished an epoch
+type channelFreshness struct {
+	epoch time.Duration
+	last  time.Time
+}
+
+// newReadiness creates the readiness of the oracle with the endpoints to
+// check by name
+func newReadiness(staleness uint64, endpoints map[string]headerReader) *readiness {
+	return &readiness{
+		staleness: staleness,
+		endpoints: endpoints,
+		now:       time.Now,
+		channels:  make(map[string]*channelFreshness),
+	}
+}
+
+// track starts checking the freshness of the channel, which gets the
+// staleness window from now on to finish its first epoch
+func (r *readiness) track(channel string, epoch time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.channels[channel] = &channelFreshness{epoch: epoch, last: r.now()}
+}
+
+// succeeded records that the channel finished an epoch. Loops that are not
+// tracked are ignored.
+func (r *readiness) succeeded(channel string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if freshness, ok := r.channels[channel]; ok {
+		freshness.last = r.now()
+	}
+}
+
+// check returns why each failed check failed by name, which is empty when
+// the oracle is ready
+func (r *readiness) check(ctx context.Context) map[string]string {
+	failed := make(map[string]string)
+	if r == nil {
+		return failed
+	}
+
+	r.mu.Lock()
+	if r.staleness > 0 {
+		now := r.now()
+		for channel, freshness := range r.channels {
+			window := time.Duration(r.staleness) * freshness.epoch
+			if age := now.Sub(freshness.last); age > window {
+				failed[channel] = fmt.Sprintf("no epoch finished for %s, over %d epochs", age.Truncate(time.Second), r.staleness)
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	for name, endpoint := range r.endpoints {
+		ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+		_, err := endpoint.HeaderByNumber(ctx, nil)
+		cancel()
+		if err != nil {
+			failed[name] = fmt.Sprintf("unreachable: %v", err)
+		}
+	}
+	return failed
+}
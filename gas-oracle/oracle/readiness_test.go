@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEndpoint answers the readiness checks with err
+type fakeEndpoint struct {
+	err error
+}
+
+func (e *fakeEndpoint) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &types.Header{Number: big.NewInt(1)}, nil
+}
+
+// readyz returns the status code and body of GET /readyz
+func readyz(t *testing.T, g *GasPriceOracle) (int, map[string]interface{}) {
+	t.Helper()
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	return rec.Code, body
+}
+
+func TestReadinessStaleness(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l1, l2 := new(fakeEndpoint), new(fakeEndpoint)
+	ready := newReadiness(3, map[string]headerReader{"l1-rpc": l1, "l2-rpc": l2})
+	ready.now = func() time.Time { return now }
+	ready.track(l1BaseFeeChannel, 10*time.Second)
+	ready.track(l2GasPriceChannel, time.Minute)
+	g := &GasPriceOracle{drainer: new(drainer), readiness: ready}
+
+	// A fresh process gets the staleness window to finish its first epoch
+	code, _ := readyz(t, g)
+	require.Equal(t, http.StatusOK, code)
+
+	now = now.Add(31 * time.Second)
+	code, body := readyz(t, g)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "not ready", body["status"])
+	failed := body["failed"].(map[string]interface{})
+	require.Len(t, failed, 1)
+	require.Contains(t, failed[l1BaseFeeChannel], "over 3 epochs")
+
+	// Finishing an epoch makes it ready again, other loops are ignored
+	ready.succeeded(l1BaseFeeChannel)
+	ready.succeeded("heartbeat")
+	code, _ = readyz(t, g)
+	require.Equal(t, http.StatusOK, code)
+
+	now = now.Add(3 * time.Minute)
+	ready.succeeded(l1BaseFeeChannel)
+	code, body = readyz(t, g)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Contains(t, body["failed"], l2GasPriceChannel)
+	require.NotContains(t, body["failed"], l1BaseFeeChannel)
+
+	// Without a staleness only the endpoints are checked
+	ready.staleness = 0
+	code, _ = readyz(t, g)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestReadinessEndpoints(t *testing.T) {
+	l1, l2 := new(fakeEndpoint), new(fakeEndpoint)
+	g := &GasPriceOracle{
+		drainer:   new(drainer),
+		readiness: newReadiness(3, map[string]headerReader{"l1-rpc": l1, "l2-rpc": l2}),
+	}
+	code, body := readyz(t, g)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ok", body["status"])
+
+	l2.err = errors.New("connection refused")
+	code, body = readyz(t, g)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, map[string]interface{}{"l2-rpc": "unreachable: connection refused"}, body["failed"])
+
+	// Draining takes precedence over the checks
+	g.Drain()
+	code, body = readyz(t, g)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "draining", body["status"])
+}
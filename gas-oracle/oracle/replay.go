@@ -0,0 +1,352 @@
+package oracle
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mantlenetworkio/mantle/gas-oracle/gasprices"
+)
+
+var (
+	// errInvalidReplay represents the error when the replay file cannot be
+	// read as samples
+	errInvalidReplay = errors.New("invalid replay file")
+	// errNoReplayChannel represents the error when a replay is run without
+	// a channel that it can replay
+	errNoReplayChannel = errors.New("no channel to replay")
+)
+
+// ReplaySample is a sample of the history that a replay feeds the oracle
+// with in place of the RPC endpoints
+type ReplaySample struct {
+	Time             time.Time
+	BaseFee          *big.Int
+	GasUsedPerSecond float64
+}
+
+// replayRatio is the token price ratio of a replay, whose base fees are
+// already in the L2 token
+type replayRatio struct{}
+
+func (replayRatio) PriceRatio() (float64, error) {
+	return 1, nil
+}
+
+// Replay computes what the L1 base fee and L2 gas price channels would have
+// decided over the samples of the replay file, and writes the decision of
+// every epoch to the replay output, or to w without one. The epochs are
+// timed by a clock that advances with the samples, and no transaction is
+// sent.
+func Replay(cfg *Config, w io.Writer) error {
+	file, err := os.Open(cfg.ReplayFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	samples, err := readReplaySamples(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.ReplayFile, err)
+	}
+
+	if cfg.ReplayOut != "" {
+		out, err := os.Create(cfg.ReplayOut)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+	return replay(cfg, samples, w)
+}
+
+// replay writes the decisions of the enabled channels over the samples to
+// w, in the order of their epochs
+func replay(cfg *Config, samples []ReplaySample, w io.Writer) error {
+	if !cfg.enableL1BaseFee && !cfg.enableL2GasPrice {
+		return fmt.Errorf("%w: enable the L1 base fee or the L2 gas price", errNoReplayChannel)
+	}
+	if len(samples) == 0 {
+		return fmt.Errorf("%w: no samples", errInvalidReplay)
+	}
+
+	var decisions []Decision
+	if cfg.enableL1BaseFee {
+		decisions = append(decisions, replayL1BaseFee(cfg, samples)...)
+	}
+	if cfg.enableL2GasPrice {
+		gasPrices, err := replayL2GasPrice(cfg, samples)
+		if err != nil {
+			return err
+		}
+		decisions = append(decisions, gasPrices...)
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].Time.Before(decisions[j].Time)
+	})
+
+	enc := json.NewEncoder(w)
+	for i := range decisions {
+		if err := enc.Encode(&decisions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayL1BaseFee decides the L1 base fee of every epoch. The tip mode
+// follows the last sample before the end of the epoch and the epoch mode the
+// average of the samples within it. The contract starts without a base fee.
+func replayL1BaseFee(cfg *Config, samples []ReplaySample) []Decision {
+	var decisions []Decision
+	current := new(big.Int)
+	window := newBaseFeeWindow(cfg.l1BaseFeeWindow)
+	length := time.Duration(cfg.l1BaseFeeEpochLengthSeconds) * time.Second
+	replayEpochs(samples, length, func(at time.Time, epoch []ReplaySample, last ReplaySample) {
+		baseFee := last.BaseFee
+		if cfg.l1BaseFeeMode == l1BaseFeeModeEpoch && len(epoch) > 0 {
+			sum := new(big.Int)
+			for _, sample := range epoch {
+				sum.Add(sum, sample.BaseFee)
+			}
+			baseFee = sum.Div(sum, big.NewInt(int64(len(epoch))))
+		}
+		decision := newReplayDecision(at, l1BaseFeeChannel)
+		decision.Inputs["current_l1_base_fee"] = current
+		decision.Inputs["l1_base_fee"] = baseFee
+		if window != nil {
+			baseFee = window.add(baseFee)
+			decision.Inputs["l1_base_fee_median"] = baseFee
+		}
+		baseFee = roundTo(baseFee, cfg.l1BaseFeeRoundTo)
+		decision.Outputs["l1_base_fee"] = baseFee
+
+		switch {
+		case !isDifferenceSignificant(current.Uint64(), baseFee.Uint64(), cfg.l1BaseFeeSignificanceFactor):
+			decision.Action, decision.ReasonCode = actionSkip, reasonBelowSignificance
+		case current.Cmp(baseFee) == 0:
+			decision.Action, decision.ReasonCode = actionSkip, reasonUnchanged
+		default:
+			decision.Action, decision.ReasonCode = actionUpdate, reasonThresholdMet
+			current = baseFee
+		}
+		decisions = append(decisions, decision)
+	})
+	return decisions
+}
+
+// replayL2GasPrice decides the L2 gas price of every epoch from the average
+// gas used per second of the samples within it. The gas price starts at the
+// floor price.
+func replayL2GasPrice(cfg *Config, samples []ReplaySample) ([]Decision, error) {
+	pricer, err := gasprices.NewGasPricer(
+		cfg.floorPrice,
+		cfg.floorPrice,
+		cfg.ceilingPrice,
+		replayRatio{},
+		func() float64 {
+			return float64(cfg.targetGasPerSecond)
+		},
+		cfg.maxPercentChangePerEpoch,
+	)
+	if err != nil {
+		return nil, err
+	}
+	smoother := newEMASmoother(l2GasPriceChannel, cfg.l2GasPriceEMAAlpha, nil)
+
+	var decisions []Decision
+	current := cfg.floorPrice
+	length := time.Duration(cfg.epochLengthSeconds) * time.Second
+	replayEpochs(samples, length, func(at time.Time, epoch []ReplaySample, last ReplaySample) {
+		if err != nil {
+			return
+		}
+		gasUsedPerSecond := last.GasUsedPerSecond
+		if len(epoch) > 0 {
+			gasUsedPerSecond = 0
+			for _, sample := range epoch {
+				gasUsedPerSecond += sample.GasUsedPerSecond
+			}
+			gasUsedPerSecond /= float64(len(epoch))
+		}
+		var computed uint64
+		computed, err = pricer.CompleteEpoch(gasUsedPerSecond)
+		if err != nil {
+			return
+		}
+		decision := newReplayDecision(at, l2GasPriceChannel)
+		decision.Inputs["current_gas_price"] = current
+		decision.Inputs["gas_used_per_second"] = gasUsedPerSecond
+		if smoother != nil {
+			decision.Inputs["raw_gas_price"] = computed
+		}
+		gasPrice := cfg.roundGasPrice(smoother.smooth(computed))
+		decision.Outputs["gas_price"] = gasPrice
+
+		switch {
+		case current == gasPrice:
+			decision.Action, decision.ReasonCode = actionSkip, reasonUnchanged
+		case !isDifferenceSignificant(current, gasPrice, cfg.l2GasPriceSignificanceFactorFor(current, gasPrice)):
+			decision.Action, decision.ReasonCode = actionSkip, reasonBelowSignificance
+		default:
+			decision.Action, decision.ReasonCode = actionUpdate, reasonThresholdMet
+			current = gasPrice
+		}
+		decisions = append(decisions, decision)
+	})
+	return decisions, err
+}
+
+func newReplayDecision(at time.Time, channel string) Decision {
+	return Decision{
+		Time:    at,
+		Channel: channel,
+		Inputs:  make(map[string]interface{}),
+		Outputs: make(map[string]interface{}),
+	}
+}
+
+// replayEpochs advances a clock from the first sample by epochs of the
+// length, and calls end at the end of every epoch that completes within the
+// samples with the samples of the epoch and the last sample before its end.
+// An epoch without samples holds the last sample.
+func replayEpochs(samples []ReplaySample, length time.Duration, end func(at time.Time, epoch []ReplaySample, last ReplaySample)) {
+	if length <= 0 || len(samples) == 0 {
+		return
+	}
+	last := samples[0]
+	i := 0
+	for boundary := samples[0].Time.Add(length); !boundary.After(samples[len(samples)-1].Time); boundary = boundary.Add(length) {
+		j := i
+		for j < len(samples) && samples[j].Time.Before(boundary) {
+			j++
+		}
+		if j > i {
+			last = samples[j-1]
+		}
+		end(boundary, samples[i:j], last)
+		i = j
+	}
+}
+
+// readReplaySamples reads the samples of a replay file, either JSON lines
+// of {"timestamp", "baseFee", "gasUsedPerSecond"} objects or CSV records of
+// timestamp,baseFee,gasUsedPerSecond with an optional header. A timestamp
+// is in Unix seconds or RFC 3339, and the samples are in the order of time.
+func readReplaySamples(r io.Reader) ([]ReplaySample, error) {
+	reader := bufio.NewReader(r)
+	var samples []ReplaySample
+	var err error
+	if isJSONLines(reader) {
+		samples, err = readReplayJSON(reader)
+	} else {
+		samples, err = readReplayCSV(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, sample := range samples {
+		if i > 0 && sample.Time.Before(samples[i-1].Time) {
+			return nil, fmt.Errorf("%w: sample %d at %s is before the one before it", errInvalidReplay, i+1, sample.Time.UTC().Format(time.RFC3339))
+		}
+	}
+	return samples, nil
+}
+
+// isJSONLines returns true when the first character of the file opens a
+// JSON object
+func isJSONLines(reader *bufio.Reader) bool {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return false
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b)) {
+			_ = reader.UnreadByte()
+			return b == '{'
+		}
+	}
+}
+
+func readReplayJSON(r io.Reader) ([]ReplaySample, error) {
+	var samples []ReplaySample
+	dec := json.NewDecoder(r)
+	for i := 1; ; i++ {
+		var record struct {
+			Timestamp        json.RawMessage `json:"timestamp"`
+			BaseFee          json.Number     `json:"baseFee"`
+			GasUsedPerSecond float64         `json:"gasUsedPerSecond"`
+		}
+		if err := dec.Decode(&record); errors.Is(err, io.EOF) {
+			return samples, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: sample %d: %v", errInvalidReplay, i, err)
+		}
+		timestamp := string(record.Timestamp)
+		if unquoted, err := strconv.Unquote(timestamp); err == nil {
+			timestamp = unquoted
+		}
+		sample, err := parseReplaySample(timestamp, record.BaseFee.String(), record.GasUsedPerSecond)
+		if err != nil {
+			return nil, fmt.Errorf("%w: sample %d: %v", errInvalidReplay, i, err)
+		}
+		samples = append(samples, sample)
+	}
+}
+
+func readReplayCSV(r io.Reader) ([]ReplaySample, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	var samples []ReplaySample
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidReplay, err)
+		}
+		line, _ := reader.FieldPos(0)
+		// The header names the columns
+		if len(samples) == 0 && strings.EqualFold(record[0], "timestamp") {
+			continue
+		}
+		gasUsedPerSecond, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid gas used per second %q", errInvalidReplay, line, record[2])
+		}
+		sample, err := parseReplaySample(record[0], record[1], gasUsedPerSecond)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", errInvalidReplay, line, err)
+		}
+		samples = append(samples, sample)
+	}
+}
+
+func parseReplaySample(timestamp, baseFee string, gasUsedPerSecond float64) (ReplaySample, error) {
+	var at time.Time
+	if seconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		at = time.Unix(seconds, 0)
+	} else if at, err = time.Parse(time.RFC3339, timestamp); err != nil {
+		return ReplaySample{}, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	fee, ok := new(big.Int).SetString(baseFee, 10)
+	if !ok || fee.Sign() < 0 {
+		return ReplaySample{}, fmt.Errorf("invalid base fee %q", baseFee)
+	}
+	if gasUsedPerSecond < 0 {
+		return ReplaySample{}, fmt.Errorf("negative gas used per second %v", gasUsedPerSecond)
+	}
+	return ReplaySample{Time: at, BaseFee: fee, GasUsedPerSecond: gasUsedPerSecond}, nil
+}
@@ -0,0 +1,161 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// replaySeries is a minute of samples every five seconds: the L2 runs at
+// twice its target, then at its target, then idle, while the L1 base fee
+// moves by 2% and then doubles
+const replaySeries = `timestamp,baseFee,gasUsedPerSecond
+1700000000,100,2000000
+1700000005,100,2000000
+1700000010,102,1000000
+1700000015,102,1000000
+1700000020,200,0
+1700000025,200,0
+1700000030,200,0
+`
+
+func replayConfig() *Config {
+	return &Config{
+		enableL1BaseFee:              true,
+		enableL2GasPrice:             true,
+		floorPrice:                   1_000,
+		targetGasPerSecond:           1_000_000,
+		maxPercentChangePerEpoch:     0.5,
+		epochLengthSeconds:           10,
+		l1BaseFeeEpochLengthSeconds:  10,
+		l2GasPriceSignificanceFactor: 0.05,
+		l1BaseFeeSignificanceFactor:  0.05,
+	}
+}
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+	cfg := replayConfig()
+	cfg.ReplayFile = filepath.Join(dir, "samples.csv")
+	require.NoError(t, os.WriteFile(cfg.ReplayFile, []byte(replaySeries), 0o600))
+
+	var out bytes.Buffer
+	require.NoError(t, Replay(cfg, &out))
+
+	type record struct {
+		Time       time.Time          `json:"time"`
+		Channel    string             `json:"channel"`
+		Outputs    map[string]float64 `json:"outputs"`
+		Action     string             `json:"action"`
+		ReasonCode string             `json:"reason_code"`
+	}
+	var records []record
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var r record
+		require.NoError(t, json.Unmarshal([]byte(line), &r), line)
+		records = append(records, r)
+	}
+
+	at := func(seconds int64) time.Time { return time.Unix(1_700_000_000+seconds, 0) }
+	expected := []struct {
+		time    time.Time
+		channel string
+		output  string
+		value   float64
+		action  string
+		reason  string
+	}{
+		// The L1 base fee follows the last sample of every epoch
+		{at(10), l1BaseFeeChannel, "l1_base_fee", 100, actionUpdate, reasonThresholdMet},
+		// The L2 gas price rises by the max change from the floor
+		{at(10), l2GasPriceChannel, "gas_price", 1_500, actionUpdate, reasonThresholdMet},
+		{at(20), l1BaseFeeChannel, "l1_base_fee", 102, actionSkip, reasonBelowSignificance},
+		{at(20), l2GasPriceChannel, "gas_price", 1_500, actionSkip, reasonUnchanged},
+		{at(30), l1BaseFeeChannel, "l1_base_fee", 200, actionUpdate, reasonThresholdMet},
+		// and falls by the max change, down to the floor
+		{at(30), l2GasPriceChannel, "gas_price", 1_000, actionUpdate, reasonThresholdMet},
+	}
+	require.Len(t, records, len(expected), out.String())
+	for i, e := range expected {
+		require.True(t, e.time.Equal(records[i].Time), "record %d at %s", i, records[i].Time)
+		require.Equal(t, e.channel, records[i].Channel, "record %d", i)
+		require.Equal(t, e.value, records[i].Outputs[e.output], "record %d", i)
+		require.Equal(t, e.action, records[i].Action, "record %d", i)
+		require.Equal(t, e.reason, records[i].ReasonCode, "record %d", i)
+	}
+
+	// The same series in JSON lines, written to the replay output
+	var lines []string
+	for _, row := range strings.Split(strings.TrimSpace(replaySeries), "\n")[1:] {
+		fields := strings.Split(row, ",")
+		lines = append(lines, `{"timestamp": `+fields[0]+`, "baseFee": "`+fields[1]+`", "gasUsedPerSecond": `+fields[2]+`}`)
+	}
+	cfg.ReplayFile = filepath.Join(dir, "samples.jsonl")
+	cfg.ReplayOut = filepath.Join(dir, "decisions.jsonl")
+	require.NoError(t, os.WriteFile(cfg.ReplayFile, []byte(strings.Join(lines, "\n")), 0o600))
+	var stdout bytes.Buffer
+	require.NoError(t, Replay(cfg, &stdout))
+	require.Zero(t, stdout.Len())
+	written, err := os.ReadFile(cfg.ReplayOut)
+	require.NoError(t, err)
+	require.Equal(t, out.String(), string(written))
+}
+
+func TestReplayEpochMode(t *testing.T) {
+	samples, err := readReplaySamples(strings.NewReader(replaySeries))
+	require.NoError(t, err)
+
+	// The epoch mode follows the average of the samples within the epoch
+	cfg := replayConfig()
+	cfg.enableL2GasPrice = false
+	cfg.l1BaseFeeMode = l1BaseFeeModeEpoch
+	cfg.l1BaseFeeEpochLengthSeconds = 15
+	var out bytes.Buffer
+	require.NoError(t, replay(cfg, samples, &out))
+	var decisions []Decision
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var decision Decision
+		require.NoError(t, dec.Decode(&decision))
+		decisions = append(decisions, decision)
+	}
+	require.Len(t, decisions, 2)
+	// (100 + 100 + 102) / 3
+	require.Equal(t, 100.0, decisions[0].Outputs["l1_base_fee"])
+	// (102 + 200 + 200) / 3
+	require.Equal(t, 167.0, decisions[1].Outputs["l1_base_fee"])
+}
+
+func TestReadReplaySamples(t *testing.T) {
+	// Timestamps in RFC 3339, and CSV without a header
+	samples, err := readReplaySamples(strings.NewReader("2023-11-14T22:13:20Z,30000000000,1.5e6\n2023-11-14T22:13:30Z,31000000000,0\n"))
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.True(t, time.Unix(1_700_000_000, 0).Equal(samples[0].Time))
+	require.Equal(t, "30000000000", samples[0].BaseFee.String())
+	require.Equal(t, 1.5e6, samples[0].GasUsedPerSecond)
+
+	for name, data := range map[string]string{
+		"unordered":              "1700000010,1,1\n1700000000,1,1\n",
+		"negative gas":           "1700000000,1,-1\n",
+		"invalid base fee":       "1700000000,1.5,1\n",
+		"invalid timestamp":      "yesterday,1,1\n",
+		"missing column":         "1700000000,1\n",
+		"invalid JSON":           `{"timestamp": 1700000000, "baseFee": 1,`,
+		"negative JSON base fee": `{"timestamp": 1700000000, "baseFee": -1, "gasUsedPerSecond": 1}`,
+	} {
+		_, err := readReplaySamples(strings.NewReader(data))
+		require.ErrorIs(t, err, errInvalidReplay, name)
+	}
+
+	// A replay needs a channel to replay
+	cfg := replayConfig()
+	cfg.enableL1BaseFee, cfg.enableL2GasPrice = false, false
+	require.ErrorIs(t, replay(cfg, samples, &bytes.Buffer{}), errNoReplayChannel)
+}
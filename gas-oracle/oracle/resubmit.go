@@ -0,0 +1,296 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// resubmitBumpPercent is how much more a replacement pays than the
+// transaction it replaces, which is the least that the nodes accept
+const resubmitBumpPercent = 10
+
+// maxMinedReplacements bounds the replaced updates remembered for their
+// receipts
+const maxMinedReplacements = 256
+
+// stuckTx is an update that was sent and whose nonce was not mined yet,
+// along with the replacements that were sent for it
+type stuckTx struct {
+	channel string
+	// backend is the backend that the update was sent through
+	backend DeployContractBackend
+	// attempts are the update and its replacements, the latest last
+	attempts []*types.Transaction
+	// sent is when the latest attempt was sent
+	sent time.Time
+}
+
+// resubmitter replaces the updates that are not mined within the timeout
+// with the same transaction at the same nonce, paying a gas price at least
+// 10% higher, until one of them is mined or the update was replaced
+// maxBumps times. An under-priced update would otherwise hold back every
+// later update of the signer. A nil resubmitter replaces nothing.
+type resubmitter struct {
+	cfg      *Config
+	timeout  time.Duration
+	maxBumps uint64
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[uint64]*stuckTx
+	// nonces are the nonces of every attempt by hash
+	nonces map[common.Hash]uint64
+	// mined are the hashes of the replacements that were mined by the hash
+	// of every attempt at their nonce
+	mined map[common.Hash]common.Hash
+}
+
+// newResubmitter creates the resubmitter, or returns nil when timeout is
+// zero
+func newResubmitter(cfg *Config, timeout time.Duration, maxBumps uint64) *resubmitter {
+	if timeout <= 0 {
+		return nil
+	}
+	return &resubmitter{
+		cfg:      cfg,
+		timeout:  timeout,
+		maxBumps: maxBumps,
+		now:      time.Now,
+		pending:  make(map[uint64]*stuckTx),
+		nonces:   make(map[common.Hash]uint64),
+		mined:    make(map[common.Hash]common.Hash),
+	}
+}
+
+// track records an update of the channel sent through the backend
+func (r *resubmitter) track(channel string, backend DeployContractBackend, tx *types.Transaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[tx.Nonce()] = &stuckTx{
+		channel:  channel,
+		backend:  backend,
+		attempts: []*types.Transaction{tx},
+		sent:     r.now(),
+	}
+	r.nonces[tx.Hash()] = tx.Nonce()
+}
+
+// forget stops replacing the update at the nonce
+func (r *resubmitter) forget(nonce uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stuck, ok := r.pending[nonce]; ok {
+		for _, tx := range stuck.attempts {
+			delete(r.nonces, tx.Hash())
+		}
+		delete(r.pending, nonce)
+	}
+}
+
+// receipt returns the receipt of whichever attempt at the nonce was mined
+func (r *resubmitter) receipt(ctx context.Context, nonce uint64) (*types.Receipt, error) {
+	r.mu.Lock()
+	stuck, ok := r.pending[nonce]
+	var attempts []*types.Transaction
+	if ok {
+		attempts = append(attempts, stuck.attempts...)
+	}
+	r.mu.Unlock()
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	for _, tx := range attempts {
+		receipt, err := stuck.backend.TransactionReceipt(ctx, tx.Hash())
+		if errors.Is(err, ethereum.NotFound) || (err == nil && receipt == nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(attempts) > 1 {
+			log.Info("Replaced update mined", "channel", stuck.channel, "nonce", nonce,
+				"hash", tx.Hash().Hex(), "bumps", len(attempts)-1)
+			r.remember(attempts, tx.Hash())
+		}
+		r.forget(nonce)
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// remember maps every attempt at a nonce to the attempt that was mined, so
+// that the receipt of any of them is the one of the mined attempt
+func (r *resubmitter) remember(attempts []*types.Transaction, mined common.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range attempts {
+		if len(r.mined) >= maxMinedReplacements {
+			for hash := range r.mined {
+				delete(r.mined, hash)
+				break
+			}
+		}
+		r.mined[tx.Hash()] = mined
+	}
+}
+
+// poll replaces the updates that were not mined within the timeout
+func (r *resubmitter) poll(ctx context.Context) error {
+	r.mu.Lock()
+	nonces := make([]uint64, 0, len(r.pending))
+	for nonce := range r.pending {
+		nonces = append(nonces, nonce)
+	}
+	r.mu.Unlock()
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	var failed error
+	for _, nonce := range nonces {
+		if _, err := r.receipt(ctx, nonce); err == nil || !errors.Is(err, ethereum.NotFound) {
+			if err != nil && failed == nil {
+				failed = err
+			}
+			continue
+		}
+		if err := r.bump(ctx, nonce); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// bump replaces the update at the nonce once it waited for the timeout
+func (r *resubmitter) bump(ctx context.Context, nonce uint64) error {
+	r.mu.Lock()
+	stuck, ok := r.pending[nonce]
+	if !ok || r.now().Sub(stuck.sent) < r.timeout {
+		r.mu.Unlock()
+		return nil
+	}
+	last := stuck.attempts[len(stuck.attempts)-1]
+	bumps := uint64(len(stuck.attempts) - 1)
+	r.mu.Unlock()
+
+	if bumps >= r.maxBumps {
+		log.Warn("Update not mined after the last replacement, giving up", "channel", stuck.channel,
+			"nonce", nonce, "hash", last.Hash().Hex(), "bumps", bumps)
+		resubmitGiveUpCounter(stuck.channel).Inc(1)
+		r.forget(nonce)
+		return nil
+	}
+	replacement, err := r.cfg.signTx(bumpGasPrice(last), r.cfg.l2ChainID)
+	if err != nil {
+		return err
+	}
+	if err := stuck.backend.SendTransaction(ctx, replacement); err != nil {
+		// The nonce was mined in the meantime
+		if failureCategory(err) == failureNonce {
+			r.forget(nonce)
+			return nil
+		}
+		return err
+	}
+	log.Warn("Update not mined, replacing it with a higher gas price", "channel", stuck.channel,
+		"nonce", nonce, "replaced", last.Hash().Hex(), "hash", replacement.Hash().Hex(),
+		"tx_gas_price", replacement.GasPrice(), "bump", bumps+1)
+	resubmitCounter(stuck.channel).Inc(1)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stuck.attempts = append(stuck.attempts, replacement)
+	stuck.sent = r.now()
+	r.nonces[replacement.Hash()] = nonce
+	return nil
+}
+
+// bumpGasPrice returns the transaction unsigned, with the gas price, or
+// the fee and tip caps, raised by resubmitBumpPercent rounding up
+func bumpGasPrice(tx *types.Transaction) *types.Transaction {
+	bump := func(price *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(price, big.NewInt(100+resubmitBumpPercent))
+		bumped.Add(bumped, big.NewInt(99))
+		bumped.Div(bumped, big.NewInt(100))
+		if bumped.Cmp(price) <= 0 {
+			bumped.Add(price, common.Big1)
+		}
+		return bumped
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  bump(tx.GasTipCap()),
+			GasFeeCap:  bump(tx.GasFeeCap()),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		})
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    tx.Nonce(),
+		GasPrice: bump(tx.GasPrice()),
+		Gas:      tx.Gas(),
+		To:       tx.To(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	})
+}
+
+// backend returns a backend that replaces the updates of the channel sent
+// through it
+func (r *resubmitter) backend(channel string, backend DeployContractBackend) DeployContractBackend {
+	if r == nil {
+		return backend
+	}
+	return &resubmitBackend{backendDecorator: decorate(backend), channel: channel, resubmitter: r}
+}
+
+// resubmitBackend records the updates that it sends, and returns the
+// receipt of whichever attempt was mined for any of them
+type resubmitBackend struct {
+	backendDecorator
+	channel     string
+	resubmitter *resubmitter
+}
+
+func (b *resubmitBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.DeployContractBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.resubmitter.track(b.channel, b.DeployContractBackend, tx)
+	return nil
+}
+
+func (b *resubmitBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.resubmitter.mu.Lock()
+	nonce, ok := b.resubmitter.nonces[hash]
+	if mined, replaced := b.resubmitter.mined[hash]; replaced {
+		hash = mined
+	}
+	b.resubmitter.mu.Unlock()
+	if !ok {
+		return b.DeployContractBackend.TransactionReceipt(ctx, hash)
+	}
+	return b.resubmitter.receipt(ctx, nonce)
+}
+
+func resubmitCounter(channel string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/resubmits/"+metricName(channel), ometrics.DefaultRegistry)
+}
+
+func resubmitGiveUpCounter(channel string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/resubmit_give_ups/"+metricName(channel), ometrics.DefaultRegistry)
+}
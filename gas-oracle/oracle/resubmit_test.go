@@ -0,0 +1,159 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// droppingBackend accepts the first drops transactions without sending
+// them, as if they were priced too low to ever be mined
+type droppingBackend struct {
+	DeployContractBackend
+	drops   int
+	dropped []*types.Transaction
+}
+
+func (b *droppingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if len(b.dropped) < b.drops {
+		b.dropped = append(b.dropped, tx)
+		return nil
+	}
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+func TestResubmitStuckUpdate(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	now := time.Unix(1_700_000_000, 0)
+	resubmit := newResubmitter(cfg, time.Minute, 3)
+	resubmit.now = func() time.Time { return now }
+	dropping := &droppingBackend{DeployContractBackend: sim, drops: 1}
+	backend := resubmit.backend(l2GasPriceChannel, dropping)
+	update, err := wrapUpdateL2GasPriceFn(backend, cfg, channelGuards{})
+	require.NoError(t, err)
+	requirePrice := func(price uint64) {
+		gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, price, gasPrice.Uint64())
+	}
+
+	// The first attempt is never mined
+	require.NoError(t, update(5))
+	sim.Commit()
+	requirePrice(0)
+	require.Len(t, dropping.dropped, 1)
+	stuck := dropping.dropped[0]
+
+	// and is left alone until the timeout
+	require.NoError(t, resubmit.poll(context.Background()))
+	require.Len(t, resubmit.pending[stuck.Nonce()].attempts, 1)
+
+	// after which it is replaced at the same nonce with a higher gas price
+	before := resubmitCounter(l2GasPriceChannel).Count()
+	now = now.Add(time.Minute)
+	require.NoError(t, resubmit.poll(context.Background()))
+	require.Equal(t, before+1, resubmitCounter(l2GasPriceChannel).Count())
+	attempts := resubmit.pending[stuck.Nonce()].attempts
+	require.Len(t, attempts, 2)
+	replacement := attempts[1]
+	require.Equal(t, stuck.Nonce(), replacement.Nonce())
+	require.Equal(t, stuck.Data(), replacement.Data())
+	minimum := new(big.Int).Mul(stuck.GasPrice(), big.NewInt(110))
+	require.True(t, new(big.Int).Mul(replacement.GasPrice(), big.NewInt(100)).Cmp(minimum) >= 0,
+		"replacement priced %v, replaced %v", replacement.GasPrice(), stuck.GasPrice())
+
+	// The replacement is mined, and is the receipt of the first attempt
+	sim.Commit()
+	requirePrice(5)
+	receipt, err := backend.TransactionReceipt(context.Background(), stuck.Hash())
+	require.NoError(t, err)
+	require.Equal(t, replacement.Hash(), receipt.TxHash)
+	require.Empty(t, resubmit.pending)
+
+	// and the next update is sent at the next nonce
+	require.NoError(t, update(6))
+	sim.Commit()
+	requirePrice(6)
+	receipt, err = backend.TransactionReceipt(context.Background(), stuck.Hash())
+	require.NoError(t, err)
+	require.Equal(t, replacement.Hash(), receipt.TxHash)
+}
+
+func TestResubmitGivesUp(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+	}
+	now := time.Unix(1_700_000_000, 0)
+	resubmit := newResubmitter(cfg, time.Minute, 2)
+	resubmit.now = func() time.Time { return now }
+	dropping := &droppingBackend{DeployContractBackend: sim, drops: 10}
+	update, err := wrapUpdateL2GasPriceFn(resubmit.backend(l2GasPriceChannel, dropping), cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update(5))
+
+	// Every replacement pays more than the one it replaces
+	for bump := 1; bump <= 2; bump++ {
+		now = now.Add(time.Minute)
+		require.NoError(t, resubmit.poll(context.Background()))
+		require.Len(t, dropping.dropped, bump+1)
+		require.Equal(t, 1, dropping.dropped[bump].GasPrice().Cmp(dropping.dropped[bump-1].GasPrice()))
+	}
+
+	// until the update was replaced the maximum number of times
+	before := resubmitGiveUpCounter(l2GasPriceChannel).Count()
+	now = now.Add(time.Minute)
+	require.NoError(t, resubmit.poll(context.Background()))
+	require.Len(t, dropping.dropped, 3)
+	require.Empty(t, resubmit.pending)
+	require.Equal(t, before+1, resubmitGiveUpCounter(l2GasPriceChannel).Count())
+}
+
+func TestBumpGasPrice(t *testing.T) {
+	to := common.Address{1}
+	legacy := bumpGasPrice(types.NewTransaction(3, to, big.NewInt(1), 21000, big.NewInt(1), []byte{1}))
+	require.Equal(t, uint64(3), legacy.Nonce())
+	require.Equal(t, big.NewInt(2), legacy.GasPrice())
+
+	dynamic := bumpGasPrice(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1_000),
+		GasFeeCap: big.NewInt(100_001),
+		Gas:       21000,
+		To:        &to,
+	}))
+	require.Equal(t, uint8(types.DynamicFeeTxType), dynamic.Type())
+	require.Equal(t, big.NewInt(1_100), dynamic.GasTipCap())
+	require.Equal(t, big.NewInt(110_002), dynamic.GasFeeCap())
+	require.Equal(t, big.NewInt(1337), dynamic.ChainId())
+}
@@ -0,0 +1,134 @@
+package oracle
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// reversalDamper notices a channel that flip-flops, writing values that
+// move up and down in turn. The directions of the last window writes are
+// kept, and every change of direction between consecutive writes counts as
+// a reversal. Once the reversals within the window reach the threshold, a
+// write that reverses the direction of the last one must clear the wider
+// factor. A zero threshold only reports the reversals. A nil
+// reversalDamper neither reports nor dampens.
+type reversalDamper struct {
+	channel   string
+	window    int
+	threshold int
+	factor    float64
+
+	mu         sync.Mutex
+	directions []int
+	engaged    bool
+}
+
+// newReversalDamper creates the damper of the channel, or returns nil when
+// the window is zero
+func newReversalDamper(channel string, window, threshold uint64, factor float64) *reversalDamper {
+	if window == 0 {
+		return nil
+	}
+	return &reversalDamper{
+		channel:   channel,
+		window:    int(window),
+		threshold: int(threshold),
+		factor:    factor,
+	}
+}
+
+// significanceFactor returns the factor that a write from current to next
+// uses in place of normal. The reversal factor is only used when it is
+// wider, and only for a write that reverses the last direction.
+func (r *reversalDamper) significanceFactor(normal float64, current, next *big.Int) float64 {
+	if r == nil {
+		return normal
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.engaged || r.factor <= normal || len(r.directions) == 0 {
+		return normal
+	}
+	if next.Cmp(current) == -r.directions[len(r.directions)-1] {
+		return r.factor
+	}
+	return normal
+}
+
+// wrote records that value was written over before
+func (r *reversalDamper) wrote(before, value *big.Int) {
+	direction := value.Cmp(before)
+	if r == nil || direction == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := len(r.directions); n > 0 && r.directions[n-1] != direction {
+		reversalCounter(r.channel).Inc(1)
+	}
+	r.directions = append(r.directions, direction)
+	if len(r.directions) > r.window {
+		r.directions = r.directions[len(r.directions)-r.window:]
+	}
+
+	reversals := r.reversals()
+	reversalWindowGauge(r.channel).Update(int64(reversals))
+	if r.threshold == 0 {
+		return
+	}
+	switch {
+	case !r.engaged && reversals >= r.threshold:
+		r.engaged = true
+		log.Warn("Channel flip-flopping, dampening reversals", "channel", r.channel,
+			"reversals", reversals, "window", r.window, "factor", r.factor)
+		reversalDampenedGauge(r.channel).Update(1)
+	case r.engaged && reversals < r.threshold:
+		r.engaged = false
+		log.Info("Channel steady, no longer dampening reversals", "channel", r.channel,
+			"reversals", reversals, "window", r.window)
+		reversalDampenedGauge(r.channel).Update(0)
+	}
+}
+
+// dampening returns true while reversals must clear the wider factor
+func (r *reversalDamper) dampening() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.engaged
+}
+
+// reversals counts the changes of direction within the window. It must be
+// called with mu held.
+func (r *reversalDamper) reversals() int {
+	count := 0
+	for i := 1; i < len(r.directions); i++ {
+		if r.directions[i] != r.directions[i-1] {
+			count++
+		}
+	}
+	return count
+}
+
+func reversalCounter(channel string) metrics.Counter {
+	name := "reversals/" + metricName(channel)
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
+
+func reversalWindowGauge(channel string) metrics.Gauge {
+	name := "reversals/" + metricName(channel) + "/window"
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
+
+func reversalDampenedGauge(channel string) metrics.Gauge {
+	name := "reversals/" + metricName(channel) + "/dampened"
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
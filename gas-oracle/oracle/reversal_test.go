@@ -0,0 +1,96 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReversalDamperEngagesOnAlternatingWrites(t *testing.T) {
+	r := newReversalDamper(daFeeChannel, 4, 2, 0.5)
+	before := reversalCounter(daFeeChannel).Count()
+
+	// Alternating writes reverse the direction every time
+	values := []int64{100, 200, 100, 200}
+	for i := 1; i < len(values); i++ {
+		require.False(t, r.dampening())
+		r.wrote(big.NewInt(values[i-1]), big.NewInt(values[i]))
+	}
+	require.True(t, r.dampening())
+	require.Equal(t, before+2, reversalCounter(daFeeChannel).Count())
+	require.Equal(t, int64(2), reversalWindowGauge(daFeeChannel).Value())
+	require.Equal(t, int64(1), reversalDampenedGauge(daFeeChannel).Value())
+
+	// Only a reversal must clear the wider factor
+	require.Equal(t, 0.5, r.significanceFactor(0.1, big.NewInt(200), big.NewInt(100)))
+	require.Equal(t, 0.1, r.significanceFactor(0.1, big.NewInt(200), big.NewInt(300)))
+	// A normal factor wider than the reversal one is kept
+	require.Equal(t, 0.8, r.significanceFactor(0.8, big.NewInt(200), big.NewInt(100)))
+
+	// Writes in a steady direction push the reversals out of the window
+	for _, value := range []int64{300, 400, 500} {
+		r.wrote(big.NewInt(value-100), big.NewInt(value))
+	}
+	require.False(t, r.dampening())
+	require.Equal(t, 0.1, r.significanceFactor(0.1, big.NewInt(500), big.NewInt(100)))
+	require.Equal(t, int64(0), reversalDampenedGauge(daFeeChannel).Value())
+
+	// Without a threshold the reversals are only reported
+	r = newReversalDamper(daFeeChannel, 4, 0, 0.5)
+	for i := 1; i < len(values); i++ {
+		r.wrote(big.NewInt(values[i-1]), big.NewInt(values[i]))
+	}
+	require.False(t, r.dampening())
+	require.Equal(t, int64(2), reversalWindowGauge(daFeeChannel).Value())
+
+	// Disabled without a window
+	require.Nil(t, newReversalDamper(daFeeChannel, 0, 2, 0.5))
+	var none *reversalDamper
+	none.wrote(big.NewInt(1), big.NewInt(2))
+	require.False(t, none.dampening())
+	require.Equal(t, 0.1, none.significanceFactor(0.1, big.NewInt(2), big.NewInt(1)))
+}
+
+func TestReversalDamperHoldsAlternatingBaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	l1 := &syntheticL1{baseFees: []int64{1e9, 2e9, 1e9, 2e9, 1e9, 5e8}}
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	reversals := newReversalDamper(l1BaseFeeChannel, 10, 2, 0.6)
+	update, err := wrapUpdateBaseFee(l1, sim, cfg, channelGuards{reversals: reversals})
+	require.NoError(t, err)
+
+	for _, step := range []struct {
+		tip  uint64
+		want int64
+	}{
+		{0, 1e9}, {1, 2e9}, {2, 1e9}, {3, 2e9},
+		// The reversals are dampened, the next drop is not significant
+		{4, 2e9},
+		// A drop that clears the reversal factor is still written
+		{5, 5e8},
+	} {
+		l1.tip = step.tip
+		require.NoError(t, update())
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(step.want), l1BaseFee, "tip %d", step.tip)
+	}
+	require.True(t, reversals.dampening())
+}
@@ -0,0 +1,33 @@
+package oracle
+
+import "math/big"
+
+// roundTo rounds the value to the nearest multiple of step, the halves
+// rounding up, so that noise below the step does not tell values apart. A
+// positive value never rounds down to zero, and a step of 0 or 1 leaves
+// the value as it is.
+func roundTo(value *big.Int, step uint64) *big.Int {
+	if step <= 1 || value == nil || value.Sign() <= 0 {
+		return value
+	}
+	s := new(big.Int).SetUint64(step)
+	quotient, remainder := new(big.Int).QuoRem(value, s, new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(s) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if quotient.Sign() == 0 {
+		quotient.SetInt64(1)
+	}
+	return quotient.Mul(quotient, s)
+}
+
+// roundGasPrice rounds the L2 gas price to --gas-price-round-to. Rounding up
+// and never rounding to zero must not push the price past --ceiling-price,
+// so the rounded price is clamped to the ceiling again.
+func (c *Config) roundGasPrice(price uint64) uint64 {
+	rounded := roundTo(new(big.Int).SetUint64(price), c.gasPriceRoundTo).Uint64()
+	if c.ceilingPrice != 0 && rounded > c.ceilingPrice {
+		return c.ceilingPrice
+	}
+	return rounded
+}
@@ -0,0 +1,128 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTo(t *testing.T) {
+	for _, test := range []struct {
+		value    int64
+		step     uint64
+		expected int64
+	}{
+		// Halves round up, the rest to the nearest multiple
+		{1_499_999, 1_000_000, 1_000_000},
+		{1_500_000, 1_000_000, 2_000_000},
+		{2_400_000, 1_000_000, 2_000_000},
+		{2_600_000, 1_000_000, 3_000_000},
+		{3_000_000, 1_000_000, 3_000_000},
+		// A positive value never rounds down to zero
+		{1, 1_000_000, 1_000_000},
+		{0, 1_000_000, 0},
+		// A step of 0 or 1 does not round
+		{1_234_567, 0, 1_234_567},
+		{1_234_567, 1, 1_234_567},
+	} {
+		value := big.NewInt(test.value)
+		require.Equal(t, big.NewInt(test.expected), roundTo(value, test.step), "%d to %d", test.value, test.step)
+		// The value that is rounded is left alone
+		require.Equal(t, big.NewInt(test.value), value)
+	}
+}
+
+func TestRoundGasPriceKeepsCeiling(t *testing.T) {
+	// A ceiling that is not a multiple of the step
+	cfg := &Config{gasPriceRoundTo: 1_000_000, ceilingPrice: 2_500_000}
+	require.Equal(t, uint64(2_000_000), cfg.roundGasPrice(2_400_000))
+	// A half that rounds up past the ceiling is clamped to it
+	require.Equal(t, uint64(2_500_000), cfg.roundGasPrice(2_500_000))
+
+	// as is a price that would not round down to zero
+	cfg.ceilingPrice = 400_000
+	require.Equal(t, uint64(400_000), cfg.roundGasPrice(1))
+
+	// Without a ceiling the price is only rounded
+	cfg.ceilingPrice = 0
+	require.Equal(t, uint64(3_000_000), cfg.roundGasPrice(2_500_000))
+}
+
+func TestRoundingSkipsEqualValues(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// Without a significance factor every change would be sent
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		gasPriceRoundTo:       1_000,
+		l1BaseFeeRoundTo:      1_000_000,
+	}
+
+	// The L2 gas price
+	recorder := &buildRecorder{DeployContractBackend: sim}
+	gasPriceTrace := newDecisionTrace(l2GasPriceChannel, nil, nil)
+	updateGasPrice, err := wrapUpdateL2GasPriceFn(recorder, cfg, channelGuards{trace: gasPriceTrace})
+	require.NoError(t, err)
+	epoch := func(gasPrice uint64) {
+		require.NoError(t, gasPriceTrace.wrap(func() error { return updateGasPrice(gasPrice) })())
+		sim.Commit()
+	}
+	epoch(100_400)
+	require.Equal(t, 1, recorder.sent)
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(100_000), gasPrice.Uint64())
+
+	// is not sent again for the noise that rounds to the same value
+	epoch(99_700)
+	require.Equal(t, 1, recorder.sent)
+	decision, _ := gasPriceTrace.lastDecision()
+	require.Equal(t, reasonUnchanged, decision.ReasonCode)
+
+	// while a change past the step is
+	epoch(100_600)
+	require.Equal(t, 2, recorder.sent)
+	gasPrice, err = gpo.GasPrice(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(101_000), gasPrice.Uint64())
+
+	// The rounded price does not cross the ceiling
+	cfg.ceilingPrice = 101_700
+	epoch(101_600)
+	require.Equal(t, 3, recorder.sent)
+	gasPrice, err = gpo.GasPrice(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(101_700), gasPrice.Uint64())
+
+	// The L1 base fee
+	recorder = &buildRecorder{DeployContractBackend: sim}
+	baseFeeTrace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	l1 := &syntheticL1{baseFees: []int64{1_000_400_000, 999_800_000}}
+	updateBaseFee, err := wrapUpdateBaseFee(l1, recorder, cfg, channelGuards{trace: baseFeeTrace})
+	require.NoError(t, err)
+	require.NoError(t, baseFeeTrace.wrap(updateBaseFee)())
+	sim.Commit()
+	require.Equal(t, 1, recorder.sent)
+	baseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1_000_000_000), baseFee)
+
+	l1.tip = 1
+	require.NoError(t, baseFeeTrace.wrap(updateBaseFee)())
+	require.Equal(t, 1, recorder.sent)
+	decision, _ = baseFeeTrace.lastDecision()
+	require.Equal(t, actionSkip, decision.Action)
+	require.Equal(t, reasonUnchanged, decision.ReasonCode)
+}
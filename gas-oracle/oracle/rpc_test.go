@@ -0,0 +1,88 @@
+package oracle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// fakeRPC is a JSON-RPC server that answers with canned results and
+// records the methods that were called on it. A result may be a
+// func([]json.RawMessage) (interface{}, error) to compute the response
+// from the request params.
+type fakeRPC struct {
+	*httptest.Server
+	mu      sync.Mutex
+	results map[string]interface{}
+	calls   []string
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+func newFakeRPC(results map[string]interface{}) *fakeRPC {
+	f := &fakeRPC{results: results}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeRPC) serve(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, req.Method)
+	result, ok := f.results[req.Method]
+	f.mu.Unlock()
+
+	res := rpcResponse{Version: "2.0", ID: req.ID}
+	switch {
+	case !ok:
+		res.Error = &rpcError{Code: -32601, Message: "method not found: " + req.Method}
+	default:
+		if fn, isFn := result.(func([]json.RawMessage) (interface{}, error)); isFn {
+			result, err := fn(req.Params)
+			if err != nil {
+				res.Error = &rpcError{Code: -32000, Message: err.Error()}
+			} else {
+				res.Result = result
+			}
+		} else {
+			res.Result = result
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// called returns the number of times that the method was called
+func (f *fakeRPC) called(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, call := range f.calls {
+		if call == method {
+			count++
+		}
+	}
+	return count
+}
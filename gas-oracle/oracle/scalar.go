@@ -0,0 +1,76 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// effectiveScalar returns the scalar that the L1 fee effectively applies
+// to the raw L1 base fee. The L1 fee is charged on the written L1 base fee
+// scaled by scalar / 10^decimals, and the written L1 base fee already
+// includes the token price ratio, so the effective scalar covers both.
+func effectiveScalar(l1BaseFee, rawL1BaseFee, scalar, decimals *big.Int) (float64, error) {
+	if rawL1BaseFee.Sign() == 0 {
+		return 0, errors.New("raw L1 base fee is zero")
+	}
+	num := new(big.Float).SetInt(new(big.Int).Mul(l1BaseFee, scalar))
+	denom := new(big.Float).SetInt(new(big.Int).Mul(
+		rawL1BaseFee,
+		new(big.Int).Exp(big.NewInt(10), decimals, nil),
+	))
+	value, _ := new(big.Float).Quo(num, denom).Float64()
+	return value, nil
+}
+
+// wrapReportEffectiveScalar returns a function that reads the values
+// written to the contract along with the raw L1 base fee, and exports the
+// effective scalar that they imply
+func wrapReportEffectiveScalar(l1Backend bind.ContractTransactor, l2Backend bind.ContractCaller, cfg *Config, store *stateStore) (func() error, error) {
+	contract, err := bindings.NewBVMGasPriceOracleCaller(cfg.gasPriceOracleAddress, l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		opts := &bind.CallOpts{Context: context.Background()}
+		l1BaseFee, err := contract.L1BaseFee(opts)
+		if err != nil {
+			return err
+		}
+		scalar, err := contract.Scalar(opts)
+		if err != nil {
+			return err
+		}
+		decimals, err := contract.Decimals(opts)
+		if err != nil {
+			return err
+		}
+		tip, err := l1Backend.HeaderByNumber(context.Background(), nil)
+		if err != nil {
+			return err
+		}
+		if tip.BaseFee == nil {
+			return errNoBaseFee
+		}
+		value, err := effectiveScalar(l1BaseFee, tip.BaseFee, scalar, decimals)
+		if err != nil {
+			return err
+		}
+
+		log.Debug("effective scalar", "l1BaseFee", l1BaseFee, "raw", tip.BaseFee,
+			"scalar", scalar, "decimals", decimals, "effective", value)
+		metrics.GetOrRegisterGaugeFloat64("l1_base_fee/effective_scalar", ometrics.DefaultRegistry).Update(value)
+		store.update(func(s *State) {
+			s.L1BaseFee = l1BaseFee
+			s.RawL1BaseFee = tip.BaseFee
+			s.EffectiveScalar = &value
+		})
+		return nil
+	}, nil
+}
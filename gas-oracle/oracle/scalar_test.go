@@ -0,0 +1,73 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveScalar(t *testing.T) {
+	// A written base fee of 4000x the raw one scaled by 1.5
+	value, err := effectiveScalar(big.NewInt(400_000), big.NewInt(100), big.NewInt(1_500_000), big.NewInt(6))
+	require.NoError(t, err)
+	require.InDelta(t, 6000, value, 1e-9)
+
+	_, err = effectiveScalar(big.NewInt(1), big.NewInt(0), big.NewInt(1), big.NewInt(0))
+	require.Error(t, err)
+}
+
+func TestReportEffectiveScalar(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	l2, _ := newSimulatedBackend(key)
+	defer l2.Close()
+	l1, _ := newSimulatedBackend(key)
+	defer l1.Close()
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, l2, opts.From)
+	require.NoError(t, err)
+	l2.Commit()
+
+	raw := l1.Blockchain().CurrentHeader().BaseFee
+	written := new(big.Int).Mul(raw, big.NewInt(4000))
+	_, err = gpo.SetL1BaseFee(opts, written)
+	require.NoError(t, err)
+	_, err = gpo.SetScalar(opts, big.NewInt(1_500_000))
+	require.NoError(t, err)
+	_, err = gpo.SetDecimals(opts, big.NewInt(6))
+	require.NoError(t, err)
+	l2.Commit()
+
+	store := new(stateStore)
+	report, err := wrapReportEffectiveScalar(l1, l2, &Config{gasPriceOracleAddress: addr}, store)
+	require.NoError(t, err)
+	require.NoError(t, report())
+
+	gauge := metrics.GetOrRegisterGaugeFloat64("l1_base_fee/effective_scalar", ometrics.DefaultRegistry)
+	require.InDelta(t, 6000, gauge.Value(), 1e-9)
+
+	state := store.snapshot()
+	require.InDelta(t, 6000, *state.EffectiveScalar, 1e-9)
+	require.Equal(t, 0, written.Cmp(state.L1BaseFee))
+	require.Equal(t, 0, raw.Cmp(state.RawL1BaseFee))
+
+	// The same values are served on /state
+	g := &GasPriceOracle{drainer: new(drainer), state: store}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var served map[string]interface{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&served))
+	require.InDelta(t, 6000, served["effective_scalar"], 1e-9)
+}
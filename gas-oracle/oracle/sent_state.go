@@ -0,0 +1,172 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// sentValue is the value that an update of a channel last sent, along with
+// the moving average of the values that the channel computed when it is
+// smoothed
+type sentValue struct {
+	Value *big.Int   `json:"value,omitempty"`
+	Time  *time.Time `json:"time,omitempty"`
+	EMA   *float64   `json:"ema,omitempty"`
+}
+
+// sentState persists the value that every channel last sent to a JSON file,
+// so that the first decision after a restart is made against it rather than
+// against the value on chain, which may not include an update still
+// pending. Afterwards the value on chain is the reference again. It also
+// persists the moving average of the smoothed channels. A missing or
+// corrupt file restores nothing, and a nil sentState persists nothing.
+type sentState struct {
+	path string
+	now  func() time.Time
+
+	mu       sync.Mutex
+	values   map[string]sentValue
+	restored map[string]bool
+}
+
+// loadSentState loads the values persisted at path, or returns nil when
+// the path is empty
+func loadSentState(path string) *sentState {
+	if path == "" {
+		return nil
+	}
+	s := &sentState{
+		path:     path,
+		now:      time.Now,
+		values:   make(map[string]sentValue),
+		restored: make(map[string]bool),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Info("No state file, reading the values on chain", "path", path)
+		return s
+	}
+	if err != nil {
+		log.Warn("Cannot read the state file, reading the values on chain", "path", path, "message", err)
+		return s
+	}
+	var values map[string]sentValue
+	if err := json.Unmarshal(data, &values); err != nil {
+		log.Warn("Corrupt state file, reading the values on chain", "path", path, "message", err)
+		return s
+	}
+	for channel, value := range values {
+		if value.Value != nil && value.Time != nil {
+			log.Info("Restored the last sent value", "channel", channel, "value", value.Value, "time", *value.Time)
+		}
+		if value.EMA != nil {
+			log.Info("Restored the moving average", "channel", channel, "ema", *value.EMA)
+		}
+		s.values[channel] = value
+	}
+	return s
+}
+
+// reference returns the value that the first decision of the channel after
+// a restart is made against, which is the value last sent before the
+// restart when there is one, and the value on chain otherwise
+func (s *sentState) reference(channel string, onChain *big.Int) *big.Int {
+	if s == nil {
+		return onChain
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.restored[channel] {
+		return onChain
+	}
+	s.restored[channel] = true
+	value, ok := s.values[channel]
+	if !ok || value.Value == nil {
+		return onChain
+	}
+	return value.Value
+}
+
+// sent persists the value that an update of the channel sent. The file is
+// rewritten atomically, and a failure is only logged since the update was
+// sent regardless.
+func (s *sentState) sent(channel string, value *big.Int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A value sent is the reference from now on
+	s.restored[channel] = true
+	now := s.now().UTC()
+	persisted := s.values[channel]
+	persisted.Value = new(big.Int).Set(value)
+	persisted.Time = &now
+	s.values[channel] = persisted
+	if err := s.write(); err != nil {
+		log.Warn("Cannot write the state file", "path", s.path, "message", err)
+	}
+}
+
+// ema returns the moving average of the channel persisted before a
+// restart, or false when there is none
+func (s *sentState) ema(channel string) (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[channel]
+	if !ok || value.EMA == nil {
+		return 0, false
+	}
+	return *value.EMA, true
+}
+
+// smoothed persists the moving average of the channel, the same way as sent
+func (s *sentState) smoothed(channel string, ema float64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	persisted := s.values[channel]
+	persisted.EMA = &ema
+	s.values[channel] = persisted
+	if err := s.write(); err != nil {
+		log.Warn("Cannot write the state file", "path", s.path, "message", err)
+	}
+}
+
+// write replaces the file with the values. It must be called with the lock
+// held.
+func (s *sentState) write() error {
+	data, err := json.MarshalIndent(s.values, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
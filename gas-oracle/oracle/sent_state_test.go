@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	onChain := big.NewInt(5)
+	require.Nil(t, loadSentState(""))
+	require.Equal(t, onChain, (*sentState)(nil).reference(l1BaseFeeChannel, onChain))
+
+	// A missing file restores nothing
+	s := loadSentState(path)
+	require.Equal(t, onChain, s.reference(l1BaseFeeChannel, onChain))
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	s.sent(l1BaseFeeChannel, big.NewInt(100))
+	s.sent(daFeeChannel, big.NewInt(7))
+
+	var persisted map[string]sentValue
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &persisted))
+	require.Equal(t, big.NewInt(100), persisted[l1BaseFeeChannel].Value)
+	require.True(t, now.Equal(*persisted[l1BaseFeeChannel].Time))
+
+	// A restart makes the first decision against the value last sent, and
+	// the later ones against the value on chain
+	s = loadSentState(path)
+	require.Equal(t, big.NewInt(100), s.reference(l1BaseFeeChannel, onChain))
+	require.Equal(t, onChain, s.reference(l1BaseFeeChannel, onChain))
+	require.Equal(t, big.NewInt(7), s.reference(daFeeChannel, onChain))
+	require.Equal(t, onChain, s.reference(l2GasPriceChannel, onChain))
+
+	// A corrupt file restores nothing
+	require.NoError(t, os.WriteFile(path, []byte(`{"l1-base-fee": `), 0o600))
+	s = loadSentState(path)
+	require.Equal(t, onChain, s.reference(l1BaseFeeChannel, onChain))
+}
+
+func TestRestartSuppressesRedundantUpdate(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		gasPriceSource:        gasPriceSourceFixed,
+		// The default of --l1-base-fee-significant-factor
+		l1BaseFeeSignificanceFactor: 0.10,
+	}
+	start := func(sent *sentState) (*buildRecorder, *decisionTrace) {
+		recorder := &buildRecorder{DeployContractBackend: sim}
+		trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+		update, err := wrapUpdateBaseFee(sim, recorder, cfg, channelGuards{trace: trace, sent: sent})
+		require.NoError(t, err)
+		require.NoError(t, trace.wrap(update)())
+		return recorder, trace
+	}
+
+	// The update is sent, and is still pending when the oracle restarts
+	recorder, _ := start(loadSentState(path))
+	require.Equal(t, 1, recorder.sent)
+
+	// so that without the state the restart sends it again
+	recorder, _ = start(nil)
+	require.Equal(t, 1, recorder.sent)
+
+	// while with the state the restart knows that it was sent
+	recorder, trace := start(loadSentState(path))
+	require.Zero(t, recorder.sent, "redundant update sent after a restart")
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.Equal(t, actionSkip, decision.Action)
+	require.Equal(t, reasonBelowSignificance, decision.ReasonCode)
+	require.Contains(t, decision.Inputs, "last_sent_l1_base_fee")
+}
@@ -0,0 +1,300 @@
+package oracle
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// RegisterHandlers registers the operational HTTP endpoints of the
+// GasPriceOracle on the given mux
+func (g *GasPriceOracle) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/drain", g.handleDrain)
+	mux.HandleFunc("/healthz", g.handleHealthz)
+	mux.HandleFunc("/readyz", g.handleReadyz)
+	mux.HandleFunc("/freeze", g.handleFreeze)
+	mux.HandleFunc("/state", g.handleState)
+	mux.HandleFunc("/series", g.handleSeries)
+	mux.HandleFunc("/da-fee-model", g.handleDAFeeModel)
+	mux.HandleFunc("/budget", g.handleBudget)
+	mux.HandleFunc("/drop-limit", g.handleDropLimit)
+	if g.config != nil && g.config.Pprof {
+		log.Info("Serving the pprof endpoints", "path", "/debug/pprof/")
+		ometrics.Pprof(mux)
+	}
+}
+
+// handleDrain puts the oracle into the draining state
+func (g *GasPriceOracle) handleDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeStatus(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	g.Drain()
+	writeStatus(w, http.StatusAccepted, "draining")
+}
+
+// handleHealthz reports the oracle as healthy until it is fully drained,
+// at which point it is ready to be terminated
+func (g *GasPriceOracle) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if g.drainer.isDrained() {
+		writeStatus(w, http.StatusServiceUnavailable, "drained")
+		return
+	}
+	writeStatus(w, http.StatusOK, "ok")
+}
+
+// handleReadyz reports the oracle as not ready as soon as it is draining,
+// or while a readiness check fails along with why it failed
+func (g *GasPriceOracle) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if g.drainer.isDraining() {
+		writeStatus(w, http.StatusServiceUnavailable, "draining")
+		return
+	}
+	if failed := g.readiness.check(r.Context()); len(failed) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status": "not ready",
+			"failed": failed,
+		})
+		return
+	}
+	writeStatus(w, http.StatusOK, "ok")
+}
+
+// handleFreeze holds the L1 base fee flat. POST freezes it for the
+// duration given by the duration query parameter, e.g. ?duration=30m,
+// DELETE lifts the freeze and GET reports whether it is frozen.
+func (g *GasPriceOracle) handleFreeze(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		duration, err := time.ParseDuration(r.URL.Query().Get("duration"))
+		if err != nil || duration <= 0 {
+			writeStatus(w, http.StatusBadRequest, "invalid duration")
+			return
+		}
+		g.FreezeBaseFee(duration)
+	case http.MethodDelete:
+		g.ThawBaseFee()
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		writeStatus(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	until, frozen := g.baseFeeFreezer.frozenUntil()
+	if !frozen {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "thawed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status": "frozen",
+		"until":  until.UTC().Format(time.RFC3339),
+	})
+}
+
+// handleBudget reports the spend within the window of the spend budget,
+// DELETE resumes the updates that it paused
+func (g *GasPriceOracle) handleBudget(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodDelete:
+		g.ResumeSpending()
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeStatus(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if g.budget == nil {
+		writeStatus(w, http.StatusNotFound, "no spend budget")
+		return
+	}
+
+	spent, paused := g.budget.status()
+	status := "active"
+	if paused {
+		status = "paused"
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status": status,
+		"spent":  spent.String(),
+		"budget": g.budget.budget.String(),
+		"window": g.budget.window.String(),
+	})
+}
+
+// handleDropLimit reports the highest value within the period of every
+// channel with a drop limit, DELETE resumes the decreases that it paused
+func (g *GasPriceOracle) handleDropLimit(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodDelete:
+		g.ResumeDecreases()
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeStatus(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	channels := make(map[string]map[string]string)
+	for channel, drops := range g.drops {
+		if drops == nil {
+			continue
+		}
+		peak, paused := drops.status()
+		status := "active"
+		if paused {
+			status = "paused"
+		}
+		channels[channel] = map[string]string{
+			"status": status,
+			"peak":   peak.String(),
+			"period": drops.period.String(),
+		}
+	}
+	if len(channels) == 0 {
+		writeStatus(w, http.StatusNotFound, "no drop limit")
+		return
+	}
+	writeJSON(w, http.StatusOK, channels)
+}
+
+// handleDAFeeModel reports the DA fee model in use, or switches it with
+// e.g. POST /da-fee-model?model=calldata. Switching to the expression model
+// uses the expression parameter, or the configured expression without it.
+func (g *GasPriceOracle) handleDAFeeModel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		query := r.URL.Query()
+		expression := query.Get("expression")
+		var symbols []string
+		if g.config != nil {
+			if expression == "" {
+				expression = g.config.daFeeExpression
+			}
+			symbols = g.config.tokenPriceSymbols
+		}
+		if query.Get("model") == "" {
+			writeStatus(w, http.StatusBadRequest, "missing model")
+			return
+		}
+		model, err := newDAFeeModel(query.Get("model"), expression, symbols)
+		if err != nil {
+			writeStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if g.daFeeModel == nil {
+			writeStatus(w, http.StatusNotFound, "no DA fee model")
+			return
+		}
+		g.daFeeModel.set(model)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeStatus(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	model := g.daFeeModel.current()
+	body := map[string]string{"model": model.Name()}
+	if expr, ok := model.(*expressionModel); ok {
+		body["expression"] = expr.source
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// handleState reports the latest values computed by the oracle
+func (g *GasPriceOracle) handleState(w http.ResponseWriter, r *http.Request) {
+	state := g.state.snapshot()
+	for channel, trace := range g.traces {
+		decision, ok := trace.lastDecision()
+		if !ok {
+			continue
+		}
+		if state.Blocks == nil {
+			state.Blocks = make(map[string]Blocks)
+		}
+		state.Blocks[channel] = Blocks{L1: decision.L1Block, L2: decision.L2Block}
+		if state.Decisions == nil {
+			state.Decisions = make(map[string]Decision)
+		}
+		state.Decisions[channel] = decision
+		if values, ok := trace.channelState(); ok {
+			if state.Channels == nil {
+				state.Channels = make(map[string]ChannelState)
+			}
+			state.Channels[channel] = values
+		}
+		if g.config.observes(channel) {
+			if state.Observed == nil {
+				state.Observed = make(map[string]Decision)
+			}
+			state.Observed[channel] = decision
+		}
+	}
+	if g.tokenPricer != nil {
+		if ratio, at := g.tokenPricer.Latest(); !at.IsZero() {
+			state.TokenPrice = &TokenPrice{Ratio: ratio, Time: at}
+		}
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleSeries returns the decisions of a channel over a time range, e.g.
+// ?channel=l2-gas-price&from=2023-01-01T00:00:00Z&to=2023-01-02T00:00:00Z.
+// Times are RFC3339 or unix seconds, and a missing end leaves it open.
+func (g *GasPriceOracle) handleSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeStatus(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	query := r.URL.Query()
+	channel := query.Get("channel")
+	switch channel {
+	case l1BaseFeeChannel, l2GasPriceChannel, daFeeChannel, overheadChannel, scalarChannel:
+	default:
+		writeStatus(w, http.StatusBadRequest, "invalid channel")
+		return
+	}
+	from, err := parseSeriesTime(query.Get("from"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	to, err := parseSeriesTime(query.Get("to"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	writeJSON(w, http.StatusOK, g.history.series(channel, from, to))
+}
+
+// parseSeriesTime parses an RFC3339 time or unix seconds, an empty value
+// is the zero time
+func parseSeriesTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func writeStatus(w http.ResponseWriter, code int, status string) {
+	writeJSON(w, code, map[string]string{"status": status})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("cannot write response", "message", err)
+	}
+}
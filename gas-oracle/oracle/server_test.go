@@ -0,0 +1,29 @@
+package oracle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPprofOnlyWhenEnabled(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		expect int
+	}{
+		{nil, http.StatusNotFound},
+		{[]string{"--pprof"}, http.StatusOK},
+	} {
+		g := &GasPriceOracle{config: NewConfig(newTestContext(t, test.args...))}
+		mux := http.NewServeMux()
+		g.RegisterHandlers(mux)
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != test.expect {
+				t.Fatalf("%v: GET %s: expected %d, got %d", test.args, path, test.expect, rec.Code)
+			}
+		}
+	}
+}
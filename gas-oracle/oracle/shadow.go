@@ -0,0 +1,81 @@
+package oracle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// shadowOracles sends the updates of a channel to the gas price oracle
+// contracts listed after the first --gas-price-oracle-address, such as a
+// shadow or a backup of the primary. The values are read from the primary
+// only, and the same value is written to every contract in turn. A nil
+// shadowOracles sends nothing.
+type shadowOracles struct {
+	channel   string
+	backend   DeployContractBackend
+	contracts []shadowOracle
+}
+
+type shadowOracle struct {
+	address  common.Address
+	contract *bindings.BVMGasPriceOracle
+}
+
+// newShadowOracles creates the shadow contracts of the channel, or returns
+// nil when only the primary contract is configured
+func newShadowOracles(channel string, cfg *Config, backend DeployContractBackend) (*shadowOracles, error) {
+	if len(cfg.shadowGasPriceOracleAddresses) == 0 {
+		return nil, nil
+	}
+	s := &shadowOracles{channel: channel, backend: backend}
+	for _, address := range cfg.shadowGasPriceOracleAddresses {
+		contract, err := bindings.NewBVMGasPriceOracle(address, backend)
+		if err != nil {
+			return nil, err
+		}
+		s.contracts = append(s.contracts, shadowOracle{address: address, contract: contract})
+	}
+	return s, nil
+}
+
+// send builds the update of every shadow contract with build and sends it.
+// A failure is logged and counted for its contract, and does not keep the
+// update from the other ones.
+func (s *shadowOracles) send(ctx context.Context, build func(*bindings.BVMGasPriceOracle) (*types.Transaction, error)) {
+	if s == nil {
+		return
+	}
+	for _, shadow := range s.contracts {
+		tx, err := build(shadow.contract)
+		if err == nil {
+			err = s.backend.SendTransaction(ctx, tx)
+		}
+		reportOracleSend(s.channel, shadow.address, err)
+		if err != nil {
+			log.Error("cannot update shadow gas price oracle", "channel", s.channel,
+				"address", shadow.address.Hex(), "message", err)
+			continue
+		}
+		log.Info("shadow gas price oracle transaction sent", "channel", s.channel,
+			"address", shadow.address.Hex(), "hash", tx.Hash().Hex())
+	}
+}
+
+// reportOracleSend counts the updates of the channel sent to the contract
+// in `oracles/<address>/<channel>/sent` and the ones that failed in
+// `oracles/<address>/<channel>/failed`
+func reportOracleSend(channel string, address common.Address, err error) {
+	name := "oracles/" + strings.ToLower(address.Hex()) + "/" + channel
+	if err != nil {
+		metrics.GetOrRegisterCounter(name+"/failed", ometrics.DefaultRegistry).Inc(1)
+		return
+	}
+	metrics.GetOrRegisterCounter(name+"/sent", ometrics.DefaultRegistry).Inc(1)
+}
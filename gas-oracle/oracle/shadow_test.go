@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// failingOracle fails the transactions sent to one of the contracts
+type failingOracle struct {
+	DeployContractBackend
+	address common.Address
+}
+
+func (b *failingOracle) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if tx.To() != nil && *tx.To() == b.address {
+		return errors.New("connection refused")
+	}
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+func oracleSendCount(channel string, address common.Address, outcome string) int64 {
+	name := "oracles/" + strings.ToLower(address.Hex()) + "/" + channel + "/" + outcome
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry).Count()
+}
+
+func TestShadowOracles(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	primary, _, primaryGPO, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	shadow, _, shadowGPO, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:                    key,
+		l2ChainID:                     big.NewInt(1337),
+		gasPriceOracleAddress:         primary,
+		shadowGasPriceOracleAddresses: []common.Address{shadow},
+		gasPrice:                      big.NewInt(1_000_000_000),
+	}
+	gasPrices := func() (uint64, uint64) {
+		primaryPrice, err := primaryGPO.GasPrice(&bind.CallOpts{})
+		require.NoError(t, err)
+		shadowPrice, err := shadowGPO.GasPrice(&bind.CallOpts{})
+		require.NoError(t, err)
+		return primaryPrice.Uint64(), shadowPrice.Uint64()
+	}
+
+	// Both contracts receive the update
+	update, err := wrapUpdateL2GasPriceFn(sim, cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update(100))
+	sim.Commit()
+	primaryPrice, shadowPrice := gasPrices()
+	require.Equal(t, uint64(100), primaryPrice)
+	require.Equal(t, uint64(100), shadowPrice)
+
+	// A failure of the primary does not keep the update from the shadow
+	failures := oracleSendCount(l2GasPriceChannel, primary, "failed")
+	sent := oracleSendCount(l2GasPriceChannel, shadow, "sent")
+	update, err = wrapUpdateL2GasPriceFn(&failingOracle{DeployContractBackend: sim, address: primary},
+		cfg, channelGuards{})
+	require.NoError(t, err)
+	require.Error(t, update(200))
+	sim.Commit()
+	primaryPrice, shadowPrice = gasPrices()
+	require.Equal(t, uint64(100), primaryPrice)
+	require.Equal(t, uint64(200), shadowPrice)
+	require.Equal(t, failures+1, oracleSendCount(l2GasPriceChannel, primary, "failed"))
+	require.Equal(t, sent+1, oracleSendCount(l2GasPriceChannel, shadow, "sent"))
+
+	// and neither does a failure of the shadow keep it from the primary
+	failures = oracleSendCount(l2GasPriceChannel, shadow, "failed")
+	update, err = wrapUpdateL2GasPriceFn(&failingOracle{DeployContractBackend: sim, address: shadow},
+		cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update(300))
+	sim.Commit()
+	primaryPrice, shadowPrice = gasPrices()
+	require.Equal(t, uint64(300), primaryPrice)
+	require.Equal(t, uint64(200), shadowPrice)
+	require.Equal(t, failures+1, oracleSendCount(l2GasPriceChannel, shadow, "failed"))
+}
@@ -0,0 +1,161 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// referenceFeed serves trusted values of the channels, for example from
+// another oracle, as a JSON object of channel names to decimal values:
+//
+//	{"l1-base-fee": "1000000000", "da-fee": "2000"}
+//
+// A nil referenceFeed serves nothing.
+type referenceFeed struct {
+	url    string
+	client *http.Client
+}
+
+// newReferenceFeed creates the feed of the url, or returns nil when the
+// url is empty
+func newReferenceFeed(url string) *referenceFeed {
+	if url == "" {
+		return nil
+	}
+	return &referenceFeed{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// values fetches the reference value of every channel that the feed knows
+func (f *referenceFeed) values(ctx context.Context) (map[string]*big.Int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reference feed returned %s", res.Status)
+	}
+
+	var raw map[string]string
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("cannot decode reference feed: %w", err)
+	}
+	values := make(map[string]*big.Int, len(raw))
+	for channel, value := range raw {
+		v, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid reference value of %s: %q", channel, value)
+		}
+		values[channel] = v
+	}
+	return values, nil
+}
+
+// Shutdown stops the oracle gracefully. It drains the updates in flight and
+// cancels the root context so that no new epoch is scheduled, waiting up to
+// the shutdown timeout for the updates in flight, and their receipts when
+// they are waited for, to finish. It then compares the value that every
+// enabled channel left on chain with the reference feed before stopping, so
+// that an instance going down does not silently leave the chain mispriced.
+func (g *GasPriceOracle) Shutdown() {
+	timeout := time.Duration(g.config.shutdownTimeoutSeconds) * time.Second
+	log.Info("Shutting down Gas Price Oracle", "timeout", timeout)
+	g.Drain()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	deadline := time.Now().Add(timeout)
+	for !g.drainer.isDrained() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !g.drainer.isDrained() {
+		log.Warn("Updates still in flight at shutdown", "timeout", timeout)
+		// The nonce of a pending transaction holds back the next run, so
+		// it is left for an operator to track
+		for hash, channel := range g.pending.snapshot() {
+			log.Warn("Receipt still pending at shutdown", "channel", channel, "hash", hash.Hex())
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	g.reconcile(ctx)
+	g.Stop()
+}
+
+// reconcile alerts on every enabled channel whose value on chain diverges
+// from the reference by more than the tolerance. It returns the channels
+// that diverge.
+func (g *GasPriceOracle) reconcile(ctx context.Context) []string {
+	if g.reference == nil {
+		return nil
+	}
+	references, err := g.reference.values(ctx)
+	if err != nil {
+		log.Error("Cannot read the reference feed at shutdown", "message", err)
+		return nil
+	}
+
+	channels := make([]string, 0)
+	for channel := range g.config.channelModes() {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	var diverged []string
+	for _, channel := range channels {
+		reference, ok := references[channel]
+		if !ok {
+			log.Warn("No reference value at shutdown", "channel", channel)
+			continue
+		}
+		onChain, err := g.onChainValue(ctx, channel)
+		if err != nil {
+			log.Error("Cannot read the on-chain value at shutdown", "channel", channel, "message", err)
+			continue
+		}
+		if !isDifferenceSignificant(reference.Uint64(), onChain.Uint64(), g.config.shutdownDivergenceTolerance) {
+			log.Info("On-chain value matches the reference at shutdown", "channel", channel,
+				"on-chain", onChain, "reference", reference)
+			continue
+		}
+		log.Error("ALERT: on-chain value diverges from the reference at shutdown", "channel", channel,
+			"on-chain", onChain, "reference", reference, "tolerance", g.config.shutdownDivergenceTolerance)
+		shutdownDivergedCounter(channel).Inc(1)
+		diverged = append(diverged, channel)
+	}
+	return diverged
+}
+
+// onChainValue reads the value of the channel from the contract
+func (g *GasPriceOracle) onChainValue(ctx context.Context, channel string) (*big.Int, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	switch channel {
+	case l1BaseFeeChannel:
+		return g.contract.L1BaseFee(opts)
+	case l2GasPriceChannel:
+		return g.contract.GasPrice(opts)
+	case daFeeChannel:
+		return g.contract.DaGasPrice(opts)
+	default:
+		return nil, fmt.Errorf("unknown channel %s", channel)
+	}
+}
+
+func shutdownDivergedCounter(channel string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("shutdown/"+metricName(channel)+"/diverged", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,166 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownAlertsOnDivergence(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, legacy, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+	_, err = legacy.SetL1BaseFee(opts, big.NewInt(1000))
+	require.NoError(t, err)
+	_, err = legacy.SetGasPrice(opts, big.NewInt(100))
+	require.NoError(t, err)
+	sim.Commit()
+	contract, err := bindings.NewBVMGasPriceOracle(addr, sim)
+	require.NoError(t, err)
+
+	// The L1 base fee is within the tolerance of its reference while the
+	// L2 gas price is twice as high
+	reference := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			l1BaseFeeChannel:  "1050",
+			l2GasPriceChannel: "200",
+		})
+	}))
+	defer reference.Close()
+
+	g := &GasPriceOracle{
+		config: &Config{
+			enableL1BaseFee:             true,
+			enableL2GasPrice:            true,
+			shutdownDivergenceTolerance: 0.1,
+			shutdownTimeoutSeconds:      1,
+		},
+		contract:  contract,
+		drainer:   new(drainer),
+		stop:      make(chan struct{}),
+		reference: newReferenceFeed(reference.URL),
+	}
+	baseFeeAlerts := shutdownDivergedCounter(l1BaseFeeChannel).Count()
+	gasPriceAlerts := shutdownDivergedCounter(l2GasPriceChannel).Count()
+
+	g.Shutdown()
+	g.Wait()
+	require.True(t, g.drainer.isDraining())
+	require.Equal(t, baseFeeAlerts, shutdownDivergedCounter(l1BaseFeeChannel).Count())
+	require.Equal(t, gasPriceAlerts+1, shutdownDivergedCounter(l2GasPriceChannel).Count())
+}
+
+func TestReconcileWithoutReference(t *testing.T) {
+	g := &GasPriceOracle{config: &Config{enableL2GasPrice: true}}
+	require.Empty(t, g.reconcile(context.Background()))
+
+	// A channel that the feed does not know is skipped, and an invalid feed
+	// reconciles nothing
+	for _, body := range []string{`{}`, `{"l2-gas-price": "lots"}`, `[]`} {
+		body := body
+		feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		g.reference = newReferenceFeed(feed.URL)
+		require.Empty(t, g.reconcile(context.Background()), body)
+		feed.Close()
+	}
+}
+
+func TestShutdownMidEpoch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &GasPriceOracle{
+		config:  &Config{shutdownTimeoutSeconds: 5},
+		ctx:     ctx,
+		cancel:  cancel,
+		stop:    make(chan struct{}),
+		drainer: new(drainer),
+	}
+
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go g.loop("test", 5*time.Millisecond, func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	})
+	<-started
+
+	// The root context is cancelled while the epoch is in flight, which is
+	// waited for
+	done := make(chan struct{})
+	go func() {
+		g.Shutdown()
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return g.ctx.Err() != nil }, time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("shut down with an update in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	g.Wait()
+	require.True(t, g.drainer.isDrained())
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls), "update started after the shutdown")
+}
+
+func TestShutdownReportsPendingReceipts(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	require.Nil(t, newPendingReceipts(false))
+
+	pending := newPendingReceipts(true)
+	backend := pending.backend(l1BaseFeeChannel, sim)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)), &types.LegacyTx{
+		To:       &common.Address{},
+		Gas:      21000,
+		GasPrice: big.NewInt(1e9),
+	})
+	require.NoError(t, err)
+	require.NoError(t, backend.SendTransaction(context.Background(), tx))
+	require.Equal(t, map[common.Hash]string{tx.Hash(): l1BaseFeeChannel}, pending.snapshot())
+
+	// The hash of an update still in flight is logged on timeout
+	g := &GasPriceOracle{
+		config:  &Config{shutdownTimeoutSeconds: 1},
+		stop:    make(chan struct{}),
+		drainer: new(drainer),
+		pending: pending,
+	}
+	g.drainer.begin()
+	records, restore := captureLogs("Receipt still pending at shutdown")
+	g.Shutdown()
+	restore()
+	require.Len(t, *records, 1)
+	require.Equal(t, l1BaseFeeChannel, (*records)[0]["channel"])
+	require.Equal(t, tx.Hash().Hex(), (*records)[0]["hash"])
+
+	// and is forgotten once its receipt is fetched
+	sim.Commit()
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	require.Empty(t, pending.snapshot())
+}
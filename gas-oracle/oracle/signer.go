@@ -0,0 +1,148 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// errAmbiguousSigner represents the error when more than one of a
+	// private key, an external signer and a KMS key are configured
+	errAmbiguousSigner = errors.New("more than one of a private key, a signer endpoint and a KMS key are configured")
+	// errInvalidSignerAddress represents the error when the account of the
+	// external signer is missing or is not an address
+	errInvalidSignerAddress = errors.New("invalid signer address")
+	// errUnknownSignerAccount represents the error when the external signer
+	// does not manage the configured account
+	errUnknownSignerAccount = errors.New("account unknown to the signer")
+)
+
+// txSigner signs the transactions of an account whose private key is held
+// outside of the process
+type txSigner interface {
+	// address returns the address of the account
+	address() common.Address
+	// signTx signs the transaction for the chain
+	signTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// externalSigner signs the transactions of an account through an external
+// signer such as clef, so that the private key never enters the process
+type externalSigner struct {
+	signer  *external.ExternalSigner
+	account accounts.Account
+}
+
+// dialExternalSigner connects to the external signer at the endpoint and
+// checks that it manages the account
+func dialExternalSigner(endpoint string, address common.Address) (*externalSigner, error) {
+	signer, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to the signer: %w", err)
+	}
+	account := accounts.Account{Address: address}
+	if !signer.Contains(account) {
+		return nil, fmt.Errorf("%w: %s", errUnknownSignerAccount, address.Hex())
+	}
+	log.Info("Signing through the external signer", "endpoint", endpoint, "address", address.Hex())
+	return &externalSigner{signer: signer, account: account}, nil
+}
+
+func (s *externalSigner) address() common.Address {
+	return s.account.Address
+}
+
+func (s *externalSigner) signTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.signer.SignTx(s.account, tx, chainID)
+}
+
+// validateSigner makes sure that the transactions are signed by a single
+// one of the private key, an external signer and a KMS key, and that the
+// account of the external signer is an address
+func (c *Config) validateSigner() error {
+	configured := 0
+	for _, set := range []bool{c.privateKey != nil, c.signerEndpoint != "", c.kmsKeyID != ""} {
+		if set {
+			configured++
+		}
+	}
+	if configured > 1 {
+		return errAmbiguousSigner
+	}
+	if c.signerEndpoint != "" && !common.IsHexAddress(c.signerAddress) {
+		return fmt.Errorf("%w: %q", errInvalidSignerAddress, c.signerAddress)
+	}
+	if c.kmsKeyID != "" && c.kmsRegion == "" {
+		return errNoKMSRegion
+	}
+	return nil
+}
+
+// hasSigner returns true when the transactions can be signed
+func (c *Config) hasSigner() bool {
+	return c.signer != nil || c.privateKey != nil
+}
+
+// from returns the address that the transactions are signed by
+func (c *Config) from() common.Address {
+	if c.signer != nil {
+		return c.signer.address()
+	}
+	return crypto.PubkeyToAddress(c.privateKey.PublicKey)
+}
+
+// signTx signs the transaction for the chain, through the external signer
+// or the KMS key when there is one and with the private key otherwise
+func (c *Config) signTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if c.signer != nil {
+		return c.signer.signTx(tx, chainID)
+	}
+	if c.privateKey == nil {
+		return nil, errNoPrivateKey
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), c.privateKey)
+}
+
+// signerFn returns the function that signs the transactions of the signer
+// for the chain
+func (c *Config) signerFn(chainID *big.Int) bind.SignerFn {
+	from := c.from()
+	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != from {
+			return nil, bind.ErrNotAuthorized
+		}
+		return c.signTx(tx, chainID)
+	}
+}
+
+// transactor returns the options that the updates are built with and
+// signed by on L2
+func (c *Config) transactor() (*bind.TransactOpts, error) {
+	if !c.hasSigner() {
+		return nil, errNoPrivateKey
+	}
+	if c.l2ChainID == nil {
+		return nil, errNoChainID
+	}
+	return newTransactor(c.from(), c.signerFn(c.l2ChainID)), nil
+}
+
+// newTransactor returns the options of transactions sent from the address
+// and signed by sign, whichever signer it is backed by
+func newTransactor(from common.Address, sign bind.SignerFn) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    from,
+		Signer:  sign,
+		Context: context.Background(),
+	}
+}
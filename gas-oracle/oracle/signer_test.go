@@ -0,0 +1,114 @@
+package oracle
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSigner serves the account API of clef for a single key
+type fakeSigner struct {
+	key    *ecdsa.PrivateKey
+	signed int32
+}
+
+func (f *fakeSigner) Version() string { return "6.0.0" }
+
+func (f *fakeSigner) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}
+}
+
+func (f *fakeSigner) SignTransaction(args apitypes.SendTxArgs) (*signedTransaction, error) {
+	tx, err := types.SignTx(args.ToTransaction(), types.LatestSignerForChainID((*big.Int)(args.ChainID)), f.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&f.signed, 1)
+	return &signedTransaction{Raw: raw, Tx: tx}, nil
+}
+
+type signedTransaction struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// serveFakeSigner serves the fake signer over HTTP and returns its endpoint
+func serveFakeSigner(t *testing.T, signer *fakeSigner) string {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", signer))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func TestExternalSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	fake := &fakeSigner{key: key}
+	signer, err := dialExternalSigner(serveFakeSigner(t, fake), opts.From)
+	require.NoError(t, err)
+
+	// The oracle holds no key, the owner signs through the signer
+	cfg := &Config{
+		signer:                signer,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		gasPriceSource:        gasPriceSourceFixed,
+	}
+	require.Equal(t, opts.From, cfg.from())
+	update, err := wrapUpdateBaseFee(sim, sim, cfg, channelGuards{})
+	require.NoError(t, err)
+	require.NoError(t, update())
+	sim.Commit()
+
+	require.EqualValues(t, 1, atomic.LoadInt32(&fake.signed))
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.NotZero(t, l1BaseFee.Sign(), "base fee not updated")
+
+	// The signer must manage the account
+	other, _ := crypto.GenerateKey()
+	_, err = dialExternalSigner(serveFakeSigner(t, fake), crypto.PubkeyToAddress(other.PublicKey))
+	require.ErrorIs(t, err, errUnknownSignerAccount)
+}
+
+func TestValidateSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	require.NoError(t, (&Config{privateKey: key}).validateSigner())
+	require.NoError(t, (&Config{signerEndpoint: "http://localhost:8550", signerAddress: address}).validateSigner())
+	require.ErrorIs(t, (&Config{privateKey: key, signerEndpoint: "http://localhost:8550", signerAddress: address}).validateSigner(), errAmbiguousSigner)
+	require.ErrorIs(t, (&Config{signerEndpoint: "http://localhost:8550"}).validateSigner(), errInvalidSignerAddress)
+	require.ErrorIs(t, (&Config{signerEndpoint: "http://localhost:8550", signerAddress: "0x1234"}).validateSigner(), errInvalidSignerAddress)
+
+	require.NoError(t, (&Config{kmsKeyID: "alias/gas-oracle", kmsRegion: "eu-west-1"}).validateSigner())
+	require.ErrorIs(t, (&Config{kmsKeyID: "alias/gas-oracle"}).validateSigner(), errNoKMSRegion)
+	require.ErrorIs(t, (&Config{privateKey: key, kmsKeyID: "alias/gas-oracle", kmsRegion: "eu-west-1"}).validateSigner(), errAmbiguousSigner)
+	require.ErrorIs(t, (&Config{signerEndpoint: "http://localhost:8550", signerAddress: address, kmsKeyID: "alias/gas-oracle", kmsRegion: "eu-west-1"}).validateSigner(), errAmbiguousSigner)
+}
@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errSimulatedRevert represents the error when an update reverts once
+// simulated, so that it is not sent
+var errSimulatedRevert = errors.New("update reverts")
+
+// simulateBackend calls every transaction against the latest block before
+// sending it, and does not send the ones that revert, so that an update
+// that would revert on chain, for instance when the signer is not the owner
+// of the contract, spends no gas
+type simulateBackend struct {
+	backendDecorator
+	channel string
+}
+
+// simulateFirst returns a backend that simulates the updates of the channel
+// before sending them
+func simulateFirst(channel string, backend DeployContractBackend) DeployContractBackend {
+	return &simulateBackend{backendDecorator: decorate(backend), channel: channel}
+}
+
+func (b *simulateBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return err
+	}
+	// The fees are left out, whether the update reverts does not depend on
+	// them and the price checks of the nodes differ between calls
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if _, err := b.CallContract(ctx, msg, nil); err != nil {
+		if !isRevert(err) {
+			return fmt.Errorf("cannot simulate update: %w", err)
+		}
+		reason := revertReason(err)
+		log.Error("Update reverts, not sending it", "channel", b.channel, "hash", tx.Hash().Hex(),
+			"nonce", tx.Nonce(), "reason", reason)
+		simulatedRevertCounter(b.channel).Inc(1)
+		return fmt.Errorf("%w: %s", errSimulatedRevert, reason)
+	}
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+// isRevert returns true when a call failed because it reverted, rather
+// than because it could not be made
+func isRevert(err error) bool {
+	var dataErr rpc.DataError
+	return errors.As(err, &dataErr) || strings.Contains(err.Error(), "execution reverted")
+}
+
+// revertReason decodes the reason that a call reverted with, or returns the
+// error when it carries none
+func revertReason(err error) string {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if encoded, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(encoded); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+					return reason
+				}
+			}
+		}
+	}
+	return err.Error()
+}
+
+func simulatedRevertCounter(channel string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/simulated_reverts/"+metricName(channel), ometrics.DefaultRegistry)
+}
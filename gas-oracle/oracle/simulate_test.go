@@ -0,0 +1,73 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateBeforeSend(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	// The signer owns one contract and not the other
+	owned, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	notOwned, _, _, err := bindings.DeployGasPriceOracle(opts, sim, common.Address{1})
+	require.NoError(t, err)
+	sim.Commit()
+
+	data, err := updateCalldata(l1BaseFeeChannel)
+	require.NoError(t, err)
+	gasPrice, err := sim.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	update := func(nonce uint64, to common.Address) *types.Transaction {
+		tx, err := opts.Signer(opts.From, types.NewTransaction(nonce, to, new(big.Int), 100_000, gasPrice, data))
+		require.NoError(t, err)
+		return tx
+	}
+	recorder := &buildRecorder{DeployContractBackend: sim}
+	backend := simulateFirst(l1BaseFeeChannel, recorder)
+
+	// An update that reverts is never broadcast
+	reverts := simulatedRevertCounter(l1BaseFeeChannel).Count()
+	err = backend.SendTransaction(context.Background(), update(2, notOwned))
+	require.ErrorIs(t, err, errSimulatedRevert)
+	require.Contains(t, err.Error(), "Ownable: caller is not the owner")
+	require.Equal(t, failureReverted, failureCategory(err))
+	require.Zero(t, recorder.sent)
+	require.Equal(t, reverts+1, simulatedRevertCounter(l1BaseFeeChannel).Count())
+
+	// while one that succeeds is
+	require.NoError(t, backend.SendTransaction(context.Background(), update(2, owned)))
+	require.Equal(t, 1, recorder.sent)
+	sim.Commit()
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, representativeValue, l1BaseFee)
+}
+
+func TestRevertReason(t *testing.T) {
+	// Error(string) with the reason "not owner"
+	data := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000009" +
+		"6e6f74206f776e65720000000000000000000000000000000000000000000000"
+	require.Equal(t, "not owner", revertReason(&dataError{data: data}))
+	require.Equal(t, "execution reverted", revertReason(&dataError{data: "0x"}))
+}
+
+// dataError is an RPC error that carries data
+type dataError struct {
+	data string
+}
+
+func (e *dataError) Error() string          { return "execution reverted" }
+func (e *dataError) ErrorData() interface{} { return e.data }
@@ -0,0 +1,89 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errInvalidEMAAlpha represents the error when the smoothing factor of the
+// moving average is not within [0, 1]
+var errInvalidEMAAlpha = errors.New("invalid EMA alpha")
+
+// emaSmoother smooths the values computed for a channel with an exponential
+// moving average before they are checked for significance. For the computed
+// values x_0, x_1, ... the smoothed values are
+//
+//	s_0 = x_0
+//	s_t = alpha * x_t + (1 - alpha) * s_{t-1}
+//
+// so that a value computed k epochs ago weighs alpha * (1 - alpha)^k. A
+// step from a to b is followed after k epochs by b - (b - a) * (1 - alpha)^k,
+// and noise of variance v has variance alpha / (2 - alpha) * v once smoothed.
+// An alpha of 1 does not smooth, the closer it is to 0 the smoother the
+// values. The moving average is persisted to the state file when there is
+// one, in which case s_0 is the moving average before the restart. A nil
+// emaSmoother does not smooth.
+type emaSmoother struct {
+	channel string
+	alpha   float64
+	state   *sentState
+
+	mu     sync.Mutex
+	ema    float64
+	primed bool
+}
+
+// validateEMAAlpha makes sure that the smoothing factor of the L2 gas price
+// is a weight
+func (c *Config) validateEMAAlpha() error {
+	if c.l2GasPriceEMAAlpha < 0 || c.l2GasPriceEMAAlpha > 1 || math.IsNaN(c.l2GasPriceEMAAlpha) {
+		return fmt.Errorf("%w: %v", errInvalidEMAAlpha, c.l2GasPriceEMAAlpha)
+	}
+	return nil
+}
+
+// newEMASmoother creates the smoother of the channel, or returns nil when
+// alpha is zero
+func newEMASmoother(channel string, alpha float64, state *sentState) *emaSmoother {
+	if alpha == 0 {
+		return nil
+	}
+	s := &emaSmoother{channel: channel, alpha: alpha, state: state}
+	s.ema, s.primed = state.ema(channel)
+	return s
+}
+
+// smooth returns the moving average once the value is added to it
+func (s *emaSmoother) smooth(value uint64) uint64 {
+	if s == nil {
+		return value
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.primed {
+		s.ema = s.alpha*float64(value) + (1-s.alpha)*s.ema
+	} else {
+		s.ema = float64(value)
+		s.primed = true
+	}
+	s.state.smoothed(s.channel, s.ema)
+	return uint64(math.Round(s.ema))
+}
+
+// wrap smooths the values that update is called with, recording the value
+// computed before smoothing in the trace
+func (s *emaSmoother) wrap(update func(uint64) error, trace *decisionTrace) func(uint64) error {
+	if s == nil {
+		return update
+	}
+	return func(value uint64) error {
+		smoothed := s.smooth(value)
+		log.Debug("smoothed computed value", "channel", s.channel, "raw", value, "smoothed", smoothed)
+		trace.input("raw_"+metricName(s.channel), value)
+		return update(smoothed)
+	}
+}
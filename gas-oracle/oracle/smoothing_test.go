@@ -0,0 +1,121 @@
+package oracle
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// smoothSeries returns the smoothed values of the series
+func smoothSeries(s *emaSmoother, raw []uint64) []uint64 {
+	smoothed := make([]uint64, len(raw))
+	for i, value := range raw {
+		smoothed[i] = s.smooth(value)
+	}
+	return smoothed
+}
+
+func variance(series []uint64) float64 {
+	var mean float64
+	for _, value := range series {
+		mean += float64(value)
+	}
+	mean /= float64(len(series))
+	var v float64
+	for _, value := range series {
+		v += (float64(value) - mean) * (float64(value) - mean)
+	}
+	return v / float64(len(series))
+}
+
+func TestEMAStep(t *testing.T) {
+	raw := make([]uint64, 20)
+	for i := range raw {
+		raw[i] = 1000
+		if i >= 5 {
+			raw[i] = 2000
+		}
+	}
+	smoothed := smoothSeries(newEMASmoother(l2GasPriceChannel, 0.5, nil), raw)
+
+	// The smoothed value closes half of the remaining gap every epoch
+	require.Equal(t, raw[:5], smoothed[:5])
+	for k := 1; k < 15; k++ {
+		expected := 2000 - 1000*math.Pow(0.5, float64(k))
+		require.Equal(t, uint64(math.Round(expected)), smoothed[4+k], "epoch %d after the step", k)
+		require.LessOrEqual(t, smoothed[4+k], raw[4+k])
+	}
+
+	// An alpha of 1 does not smooth
+	require.Equal(t, raw, smoothSeries(newEMASmoother(l2GasPriceChannel, 1, nil), raw))
+}
+
+func TestEMANoisy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	raw := make([]uint64, 2000)
+	for i := range raw {
+		raw[i] = uint64(1e9 + rng.NormFloat64()*1e8)
+	}
+	smoothed := smoothSeries(newEMASmoother(l2GasPriceChannel, 0.2, nil), raw)
+
+	// The variance shrinks by alpha / (2 - alpha) around the same level
+	ratio := variance(smoothed[100:]) / variance(raw[100:])
+	require.InDelta(t, 0.2/1.8, ratio, 0.03)
+	var rawSum, smoothedSum float64
+	for i := 100; i < len(raw); i++ {
+		rawSum += float64(raw[i])
+		smoothedSum += float64(smoothed[i])
+	}
+	require.InEpsilon(t, rawSum, smoothedSum, 0.01)
+}
+
+func TestEMADisabled(t *testing.T) {
+	s := newEMASmoother(l2GasPriceChannel, 0, nil)
+	require.Nil(t, s)
+	require.Equal(t, uint64(42), s.smooth(42))
+
+	var got uint64
+	update := s.wrap(func(value uint64) error {
+		got = value
+		return nil
+	}, nil)
+	require.NoError(t, update(7))
+	require.Equal(t, uint64(7), got)
+}
+
+func TestEMASurvivesRestart(t *testing.T) {
+	raw := []uint64{1000, 3000, 1000, 3000, 2000, 2000}
+	uninterrupted := smoothSeries(newEMASmoother(l2GasPriceChannel, 0.3, nil), raw)
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	before := smoothSeries(newEMASmoother(l2GasPriceChannel, 0.3, loadSentState(path)), raw[:3])
+	after := smoothSeries(newEMASmoother(l2GasPriceChannel, 0.3, loadSentState(path)), raw[3:])
+	require.Equal(t, uninterrupted, append(before, after...))
+
+	// The trace records the value before smoothing
+	trace := newDecisionTrace(l2GasPriceChannel, nil, nil)
+	var got uint64
+	update := newEMASmoother(l2GasPriceChannel, 0.5, loadSentState(path)).wrap(func(value uint64) error {
+		got = value
+		return nil
+	}, trace)
+	require.NoError(t, trace.wrap(func() error { return update(4000) })())
+	ema, ok := loadSentState(path).ema(l2GasPriceChannel)
+	require.True(t, ok)
+	require.Equal(t, uint64(math.Round(ema)), got)
+	decision, ok := trace.lastDecision()
+	require.True(t, ok)
+	require.EqualValues(t, 4000, decision.Inputs["raw_l2_gas_price"])
+}
+
+func TestValidateEMAAlpha(t *testing.T) {
+	for _, alpha := range []float64{0, 0.3, 1} {
+		require.NoError(t, (&Config{l2GasPriceEMAAlpha: alpha}).validateEMAAlpha(), alpha)
+	}
+	for _, alpha := range []float64{-0.1, 1.5, math.NaN()} {
+		require.ErrorIs(t, (&Config{l2GasPriceEMAAlpha: alpha}).validateEMAAlpha(), errInvalidEMAAlpha, alpha)
+	}
+}
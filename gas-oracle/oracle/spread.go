@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errNoRealizedFee represents the error when none of the recent L2 blocks
+// report a base fee
+var errNoRealizedFee = errors.New("no base fee found on recent blocks")
+
+// FeeHistoryReader reads the fee history of recent blocks
+type FeeHistoryReader interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// realizedBaseFee returns the average base fee of the most recent blocks.
+// Blocks without a base fee are left out of the average.
+func realizedBaseFee(ctx context.Context, reader FeeHistoryReader, blocks uint64) (*big.Int, error) {
+	history, err := reader.FeeHistory(ctx, blocks, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return averageBaseFee(history)
+}
+
+// averageBaseFee returns the average base fee of the blocks in the fee
+// history. Blocks without a base fee are left out of the average.
+func averageBaseFee(history *ethereum.FeeHistory) (*big.Int, error) {
+	sum := new(big.Int)
+	count := int64(0)
+	// The last entry is the base fee of the next block, which is not
+	// realized yet
+	for i, baseFee := range history.BaseFee {
+		if i >= len(history.GasUsedRatio) {
+			break
+		}
+		if baseFee == nil || baseFee.Sign() == 0 {
+			continue
+		}
+		sum.Add(sum, baseFee)
+		count++
+	}
+	if count == 0 {
+		return nil, errNoRealizedFee
+	}
+	return sum.Div(sum, big.NewInt(count)), nil
+}
+
+// gasPriceSpread returns how much the gas price is above the realized base
+// fee, relative to the realized base fee. A negative spread means that the
+// gas price is below what blocks realize.
+func gasPriceSpread(gasPrice, realized *big.Int) float64 {
+	diff := new(big.Float).SetInt(new(big.Int).Sub(gasPrice, realized))
+	spread, _ := new(big.Float).Quo(diff, new(big.Float).SetInt(realized)).Float64()
+	return spread
+}
+
+// reportSpread exports the spread between the gas price that was set and
+// the base fee realized by recent L2 blocks
+func reportSpread(ctx context.Context, reader FeeHistoryReader, blocks uint64, gasPrice *big.Int) error {
+	realized, err := realizedBaseFee(ctx, reader, blocks)
+	if err != nil {
+		return err
+	}
+	spread := gasPriceSpread(gasPrice, realized)
+	log.Debug("gas price spread", "l2_gas_price", gasPrice, "realized", realized, "spread", spread)
+	metrics.GetOrRegisterGauge("l2_gas_price/realized_base_fee", ometrics.DefaultRegistry).Update(realized.Int64())
+	metrics.GetOrRegisterGaugeFloat64("l2_gas_price/spread", ometrics.DefaultRegistry).Update(spread)
+	return nil
+}
@@ -0,0 +1,74 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeeHistory serves a series of realized base fees, oldest first
+type fakeFeeHistory struct {
+	baseFees []int64
+	err      error
+}
+
+func (f *fakeFeeHistory) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	fees := f.baseFees
+	if uint64(len(fees)) > blockCount {
+		fees = fees[uint64(len(fees))-blockCount:]
+	}
+	history := &ethereum.FeeHistory{OldestBlock: big.NewInt(100)}
+	for _, fee := range fees {
+		history.BaseFee = append(history.BaseFee, big.NewInt(fee))
+		history.GasUsedRatio = append(history.GasUsedRatio, 0.5)
+	}
+	// The base fee of the next block is always reported too
+	history.BaseFee = append(history.BaseFee, big.NewInt(1_000_000))
+	return history, nil
+}
+
+func TestReportSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseFees []int64
+		blocks   uint64
+		gasPrice int64
+		realized int64
+		spread   float64
+	}{
+		{name: "overpriced", baseFees: []int64{100, 200, 300}, blocks: 3, gasPrice: 300, realized: 200, spread: 0.5},
+		{name: "underpriced", baseFees: []int64{400, 400}, blocks: 2, gasPrice: 300, realized: 400, spread: -0.25},
+		{name: "on target", baseFees: []int64{250}, blocks: 1, gasPrice: 250, realized: 250, spread: 0},
+		{name: "only recent blocks", baseFees: []int64{1000, 100, 100}, blocks: 2, gasPrice: 150, realized: 100, spread: 0.5},
+		{name: "blocks without base fee", baseFees: []int64{0, 100, 0, 300}, blocks: 4, gasPrice: 100, realized: 200, spread: -0.5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &fakeFeeHistory{baseFees: tc.baseFees}
+			require.NoError(t, reportSpread(context.Background(), reader, tc.blocks, big.NewInt(tc.gasPrice)))
+
+			realized := metrics.GetOrRegisterGauge("l2_gas_price/realized_base_fee", ometrics.DefaultRegistry)
+			require.Equal(t, tc.realized, realized.Value())
+			spread := metrics.GetOrRegisterGaugeFloat64("l2_gas_price/spread", ometrics.DefaultRegistry)
+			require.InDelta(t, tc.spread, spread.Value(), 1e-9)
+		})
+	}
+}
+
+func TestReportSpreadWithoutRealizedFee(t *testing.T) {
+	err := reportSpread(context.Background(), &fakeFeeHistory{baseFees: []int64{0, 0}}, 2, big.NewInt(1))
+	require.ErrorIs(t, err, errNoRealizedFee)
+
+	err = reportSpread(context.Background(), &fakeFeeHistory{err: errors.New("unavailable")}, 2, big.NewInt(1))
+	require.Error(t, err)
+}
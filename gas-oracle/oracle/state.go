@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"math/big"
+	"sync"
+	"time"
+)
+
+// State is the latest view of the values computed by the oracle, served
+// on /state
+type State struct {
+	// L1BaseFee is the L1 base fee that was last written
+	L1BaseFee *big.Int `json:"l1_base_fee,omitempty"`
+	// RawL1BaseFee is the L1 base fee that L1BaseFee was derived from
+	RawL1BaseFee *big.Int `json:"raw_l1_base_fee,omitempty"`
+	// EffectiveScalar is the scalar that is effectively applied to the
+	// raw L1 base fee by the written values
+	EffectiveScalar *float64 `json:"effective_scalar,omitempty"`
+	// Observed is the last decision of every observe-only channel
+	Observed map[string]Decision `json:"observed,omitempty"`
+	// Blocks are the blocks that the last decision of every channel was
+	// computed at
+	Blocks map[string]Blocks `json:"blocks,omitempty"`
+	// Decisions are the last decision of every channel
+	Decisions map[string]Decision `json:"decisions,omitempty"`
+	// Channels are the values that every channel last computed and sent
+	Channels map[string]ChannelState `json:"channels,omitempty"`
+	// TokenPrice is the ETH/BIT price ratio that was last fetched
+	TokenPrice *TokenPrice `json:"token_price,omitempty"`
+}
+
+// ChannelState is the value that a channel last computed and the value
+// that it last sent, each with the time of the epoch that produced it
+type ChannelState struct {
+	// Epoch is the number of epochs that the channel ran
+	Epoch      uint64     `json:"epoch"`
+	Computed   *big.Int   `json:"computed,omitempty"`
+	ComputedAt *time.Time `json:"computed_at,omitempty"`
+	Sent       *big.Int   `json:"sent,omitempty"`
+	SentAt     *time.Time `json:"sent_at,omitempty"`
+}
+
+// observe counts the epoch of the decision, and keeps the values that it
+// computed and sent. The computed value is read from the inputs of the
+// decision first, as its output may have been clamped before being written.
+func (c *ChannelState) observe(decision *Decision) {
+	c.Epoch++
+	key := channelValues[decision.Channel].computed
+	at := decision.Time
+	computed, ok := bigValue(decision.Inputs[key])
+	if !ok {
+		computed, ok = bigValue(decision.Outputs[key])
+	}
+	if ok {
+		c.Computed, c.ComputedAt = computed, &at
+	}
+	if decision.Action != actionUpdate {
+		return
+	}
+	if sent, ok := bigValue(decision.Outputs[key]); ok {
+		c.Sent, c.SentAt = sent, &at
+	}
+}
+
+// bigValue converts a value recorded by a decision to an integer
+func bigValue(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, false
+		}
+		return new(big.Int).Set(v), true
+	case uint64:
+		return new(big.Int).SetUint64(v), true
+	default:
+		return nil, false
+	}
+}
+
+// TokenPrice is a price ratio along with when it was fetched
+type TokenPrice struct {
+	Ratio float64   `json:"ratio"`
+	Time  time.Time `json:"time"`
+}
+
+// Blocks are the numbers of the L1 and L2 blocks that the inputs of a
+// decision were read at
+type Blocks struct {
+	L1 uint64 `json:"l1,omitempty"`
+	L2 uint64 `json:"l2,omitempty"`
+}
+
+// stateStore guards the State of the oracle
+type stateStore struct {
+	mu    sync.RWMutex
+	state State
+}
+
+// update applies fn to the state
+func (s *stateStore) update(fn func(*State)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.state)
+}
+
+// snapshot returns a copy of the state
+func (s *stateStore) snapshot() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/mantlenetworkio/mantle/gas-oracle/tokenprice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateServesChannelValues(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		// The default of --significant-factor
+		l2GasPriceSignificanceFactor: 0.05,
+	}
+	trace := newDecisionTrace(l2GasPriceChannel, nil, nil)
+	update, err := wrapUpdateL2GasPriceFn(sim, cfg, channelGuards{trace: trace})
+	require.NoError(t, err)
+	tokenPricer := tokenprice.NewClientWithBackend(staticPrices{"ETHUSDT": 1500, "BITUSDT": 0.5}, 0)
+	g := &GasPriceOracle{
+		config:      &Config{},
+		drainer:     new(drainer),
+		state:       new(stateStore),
+		traces:      map[string]*decisionTrace{l2GasPriceChannel: trace},
+		tokenPricer: tokenPricer,
+	}
+	mux := http.NewServeMux()
+	g.RegisterHandlers(mux)
+	fetch := func() (State, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var state State
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+		return state, raw
+	}
+
+	// Nothing is served before the first epoch
+	state, _ := fetch()
+	require.Empty(t, state.Channels)
+	require.Nil(t, state.TokenPrice)
+
+	// The first epoch computes a value and sends it
+	require.NoError(t, trace.wrap(func() error { return update(1000) })())
+	sim.Commit()
+	_, err = tokenPricer.PriceRatio()
+	require.NoError(t, err)
+	state, raw := fetch()
+	values := state.Channels[l2GasPriceChannel]
+	require.Equal(t, uint64(1), values.Epoch)
+	require.Equal(t, big.NewInt(1000), values.Computed)
+	require.Equal(t, big.NewInt(1000), values.Sent)
+	require.NotNil(t, values.ComputedAt)
+	require.Equal(t, *values.ComputedAt, *values.SentAt)
+	require.Equal(t, 3000.0, state.TokenPrice.Ratio)
+	require.False(t, state.TokenPrice.Time.IsZero())
+	channel := raw["channels"].(map[string]interface{})[l2GasPriceChannel].(map[string]interface{})
+	for _, field := range []string{"epoch", "computed", "computed_at", "sent", "sent_at"} {
+		require.Contains(t, channel, field)
+	}
+	require.Contains(t, raw["token_price"], "ratio")
+	require.Contains(t, raw["token_price"], "time")
+	sentAt := *values.SentAt
+
+	// The next epoch computes a value too close to send, which leaves the
+	// sent value as it was
+	trace.now = func() time.Time { return sentAt.Add(time.Minute) }
+	require.NoError(t, trace.wrap(func() error { return update(1010) })())
+	state, _ = fetch()
+	values = state.Channels[l2GasPriceChannel]
+	require.Equal(t, uint64(2), values.Epoch)
+	require.Equal(t, big.NewInt(1010), values.Computed)
+	require.Equal(t, sentAt.Add(time.Minute), *values.ComputedAt)
+	require.Equal(t, big.NewInt(1000), values.Sent)
+	require.Equal(t, sentAt, *values.SentAt)
+}
+
+// staticPrices quotes a fixed price for every pair
+type staticPrices map[string]float64
+
+func (p staticPrices) GetPrice(ctx context.Context, pair string) (float64, error) {
+	return p[pair], nil
+}
@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// stuckDetector notices a contract that acknowledges writes without
+// changing its state, for example behind a proxy that misroutes them. Every
+// write records the value read before it and the value written. When the
+// next read still returns the value from before the write, the write is
+// counted as stale, and once threshold consecutive writes are stale the
+// channel is reported as stuck. A nil stuckDetector detects nothing.
+type stuckDetector struct {
+	channel   string
+	threshold int
+
+	mu      sync.Mutex
+	before  *big.Int
+	written *big.Int
+	streak  int
+}
+
+// newStuckDetector creates the detector of the channel, or returns nil when
+// the threshold is zero
+func newStuckDetector(channel string, threshold uint64) *stuckDetector {
+	if threshold == 0 {
+		return nil
+	}
+	return &stuckDetector{channel: channel, threshold: int(threshold)}
+}
+
+// wrote records that value was written over before
+func (s *stuckDetector) wrote(before, value *big.Int) {
+	if s == nil || before.Cmp(value) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.before = new(big.Int).Set(before)
+	s.written = new(big.Int).Set(value)
+}
+
+// observed checks the value read back from the contract against the last
+// write
+func (s *stuckDetector) observed(current *big.Int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.written == nil {
+		return
+	}
+	stale := current.Cmp(s.before) == 0
+	s.before, s.written = nil, nil
+	if !stale {
+		if s.streak >= s.threshold {
+			log.Info("Contract state changing again", "channel", s.channel)
+		}
+		s.streak = 0
+		stuckGauge(s.channel).Update(0)
+		return
+	}
+
+	s.streak++
+	staleWriteCounter(s.channel).Inc(1)
+	if s.streak >= s.threshold {
+		log.Error("Writes are not changing the contract state, the contract or a proxy may be misrouting them",
+			"channel", s.channel, "consecutive", s.streak, "value", current)
+		stuckGauge(s.channel).Update(1)
+	} else {
+		log.Warn("Write did not change the contract state", "channel", s.channel,
+			"consecutive", s.streak, "value", current)
+	}
+}
+
+// stuck returns true when the channel is reported as stuck
+func (s *stuckDetector) stuck() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streak >= s.threshold
+}
+
+func stuckGauge(channel string) metrics.Gauge {
+	name := "stuck/" + metricName(channel)
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
+
+func staleWriteCounter(channel string) metrics.Counter {
+	name := "stuck/" + metricName(channel) + "/stale_writes"
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
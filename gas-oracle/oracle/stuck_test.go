@@ -0,0 +1,82 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStuckContractDetected(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	l1, _ := newSimulatedBackend(key)
+	// A contract that acknowledges every write but keeps returning the
+	// same L1 base fee
+	l2 := newFakeRPC(map[string]interface{}{
+		"eth_call":                common.BigToHash(big.NewInt(1)).Hex(),
+		"eth_gasPrice":            "0x1",
+		"eth_getTransactionCount": "0x0",
+		"eth_estimateGas":         "0x5208",
+		"eth_getCode":             "0x01",
+		"eth_sendRawTransaction":  common.Hash{}.Hex(),
+		"eth_getBlockByNumber":    &types.Header{Difficulty: common.Big0, Number: big.NewInt(1)},
+	})
+	defer l2.Close()
+	l2Client, err := ethclient.Dial(l2.URL)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		l1BaseFeeSignificanceFactor: 0.1,
+	}
+	stuck := newStuckDetector(l1BaseFeeChannel, 3)
+	update, err := wrapUpdateBaseFee(l1, l2Client, cfg, channelGuards{stuck: stuck})
+	require.NoError(t, err)
+
+	// The first write cannot be checked yet, the next ones are stale
+	for i := 0; i < 3; i++ {
+		require.NoError(t, update())
+		require.False(t, stuck.stuck())
+	}
+	require.NoError(t, update())
+	require.True(t, stuck.stuck())
+	require.Equal(t, int64(1), stuckGauge(l1BaseFeeChannel).Value())
+	require.Equal(t, 4, l2.called("eth_sendRawTransaction"))
+}
+
+func TestStuckDetectorResets(t *testing.T) {
+	s := newStuckDetector(daFeeChannel, 2)
+	for i := 0; i < 2; i++ {
+		s.wrote(big.NewInt(1), big.NewInt(2))
+		s.observed(big.NewInt(1))
+	}
+	require.True(t, s.stuck())
+
+	// A write that lands clears the report
+	s.wrote(big.NewInt(1), big.NewInt(2))
+	s.observed(big.NewInt(2))
+	require.False(t, s.stuck())
+	require.Equal(t, int64(0), stuckGauge(daFeeChannel).Value())
+
+	// Reads without a write in between are not checked
+	s.observed(big.NewInt(2))
+	s.observed(big.NewInt(2))
+	require.False(t, s.stuck())
+
+	// A write of the value in place is not an intended change
+	s.wrote(big.NewInt(2), big.NewInt(2))
+	s.observed(big.NewInt(2))
+	require.False(t, s.stuck())
+
+	// Disabled without a threshold
+	require.Nil(t, newStuckDetector(daFeeChannel, 0))
+	var none *stuckDetector
+	none.wrote(big.NewInt(1), big.NewInt(2))
+	none.observed(big.NewInt(1))
+	require.False(t, none.stuck())
+}
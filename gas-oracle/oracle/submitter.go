@@ -0,0 +1,164 @@
+package oracle
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// submitter is the queue that the channels share to send their updates.
+// At most maxInflight updates are sent at once, the others wait and are
+// dequeued by the priority of their channel, then in the order that they
+// arrived. A nil submitter sends right away.
+type submitter struct {
+	maxInflight int
+	priorities  map[string]int
+
+	mu       sync.Mutex
+	inflight int
+	seq      uint64
+	waiting  submitQueue
+}
+
+// newSubmitter creates the shared submitter, or returns nil when
+// maxInflight is zero
+func newSubmitter(maxInflight uint64, priorities map[string]int) *submitter {
+	if maxInflight == 0 {
+		return nil
+	}
+	return &submitter{
+		maxInflight: int(maxInflight),
+		priorities:  priorities,
+	}
+}
+
+// submit runs send once the channel gets its turn
+func (s *submitter) submit(channel string, send func() error) error {
+	if s == nil {
+		return send()
+	}
+	s.acquire(channel)
+	defer s.release()
+	return send()
+}
+
+func (s *submitter) acquire(channel string) {
+	s.mu.Lock()
+	if s.inflight < s.maxInflight && s.waiting.Len() == 0 {
+		s.inflight++
+		s.mu.Unlock()
+		return
+	}
+	s.seq++
+	entry := &submitEntry{
+		priority: s.priorities[channel],
+		seq:      s.seq,
+		ready:    make(chan struct{}),
+	}
+	heap.Push(&s.waiting, entry)
+	s.mu.Unlock()
+	<-entry.ready
+}
+
+// release hands the slot over to the next update in line
+func (s *submitter) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiting.Len() > 0 {
+		close(heap.Pop(&s.waiting).(*submitEntry).ready)
+		return
+	}
+	s.inflight--
+}
+
+// queued returns the number of updates waiting for their turn
+func (s *submitter) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiting.Len()
+}
+
+// backend returns a backend that sends the updates of the channel through
+// the submitter
+func (s *submitter) backend(channel string, backend DeployContractBackend) DeployContractBackend {
+	if s == nil {
+		return backend
+	}
+	return &submittedBackend{backendDecorator: decorate(backend), channel: channel, submitter: s}
+}
+
+type submitEntry struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+}
+
+// submitQueue is a heap of the waiting updates, the highest priority first
+type submitQueue []*submitEntry
+
+func (q submitQueue) Len() int { return len(q) }
+
+func (q submitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q submitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *submitQueue) Push(x interface{}) { *q = append(*q, x.(*submitEntry)) }
+
+func (q *submitQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// submittedBackend sends the transactions of a channel through the shared
+// submitter
+type submittedBackend struct {
+	backendDecorator
+	channel   string
+	submitter *submitter
+}
+
+func (b *submittedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.submitter.submit(b.channel, func() error {
+		return b.DeployContractBackend.SendTransaction(ctx, tx)
+	})
+}
+
+// parseChannelPriorities parses priorities of the form
+// `l1-base-fee=2,da-fee=0`. Channels that are left out have priority 0.
+func parseChannelPriorities(spec string) (map[string]int, error) {
+	priorities := make(map[string]int)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid channel priority %q", field)
+		}
+		channel := strings.TrimSpace(parts[0])
+		switch channel {
+		case l1BaseFeeChannel, l2GasPriceChannel, daFeeChannel, overheadChannel, scalarChannel:
+		default:
+			return nil, fmt.Errorf("unknown channel %q", channel)
+		}
+		priority, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority of %s: %w", channel, err)
+		}
+		priorities[channel] = priority
+	}
+	return priorities, nil
+}
@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitterDequeuesByPriority(t *testing.T) {
+	s := newSubmitter(1, map[string]int{
+		l1BaseFeeChannel:  2,
+		l2GasPriceChannel: 1,
+	})
+
+	// Hold the only slot while the updates queue up
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go func() {
+		_ = s.submit(l2GasPriceChannel, func() error {
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for i, channel := range []string{daFeeChannel, l2GasPriceChannel, daFeeChannel, l1BaseFeeChannel} {
+		wg.Add(1)
+		go func(channel string) {
+			defer wg.Done()
+			_ = s.submit(channel, func() error {
+				mu.Lock()
+				order = append(order, channel)
+				mu.Unlock()
+				return nil
+			})
+		}(channel)
+		// Queue the updates in a known order
+		require.Eventually(t, func() bool { return s.queued() == i+1 }, time.Second, time.Millisecond)
+	}
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, []string{l1BaseFeeChannel, l2GasPriceChannel, daFeeChannel, daFeeChannel}, order)
+	require.Equal(t, 0, s.inflight)
+}
+
+func TestSubmitterInflightCap(t *testing.T) {
+	s := newSubmitter(2, nil)
+
+	var mu sync.Mutex
+	inflight, peak := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = s.submit(daFeeChannel, func() error {
+				mu.Lock()
+				inflight++
+				if inflight > peak {
+					peak = inflight
+				}
+				mu.Unlock()
+				time.Sleep(5 * time.Millisecond)
+				mu.Lock()
+				inflight--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	require.LessOrEqual(t, peak, 2)
+
+	// Without a cap updates are sent right away
+	require.Nil(t, newSubmitter(0, nil))
+	var none *submitter
+	require.NoError(t, none.submit(daFeeChannel, func() error { return nil }))
+}
+
+func TestParseChannelPriorities(t *testing.T) {
+	priorities, err := parseChannelPriorities("l1-base-fee=2, da-fee=-1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{l1BaseFeeChannel: 2, daFeeChannel: -1}, priorities)
+
+	priorities, err = parseChannelPriorities("overhead=1,scalar=0")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{overheadChannel: 1, scalarChannel: 0}, priorities)
+
+	priorities, err = parseChannelPriorities("")
+	require.NoError(t, err)
+	require.Empty(t, priorities)
+
+	for _, spec := range []string{"l1-base-fee", "l1-base-fee=high", "token-price=1"} {
+		_, err := parseChannelPriorities(spec)
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,120 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errThrottled represents the error when an update is held back because
+// too many transactions of the signer are pending
+var errThrottled = errors.New("update throttled, too many pending transactions")
+
+// pendingThrottle holds back the updates of the channels while more than
+// maxPending transactions of the signer are pending, since sending more
+// only piles them up in the mempool. The updates that are held back are
+// coalesced: every epoch computes its value again, so once the backlog
+// clears only the latest value of a channel is sent. A nil pendingThrottle
+// never holds back.
+type pendingThrottle struct {
+	signer     common.Address
+	reader     NonceReader
+	maxPending uint64
+
+	mu        sync.Mutex
+	throttled bool
+	held      map[string]int
+}
+
+// newPendingThrottle creates the throttle of the signer, or returns nil
+// when maxPending is zero
+func newPendingThrottle(signer common.Address, reader NonceReader, maxPending uint64) *pendingThrottle {
+	if maxPending == 0 {
+		return nil
+	}
+	return &pendingThrottle{
+		signer:     signer,
+		reader:     reader,
+		maxPending: maxPending,
+		held:       make(map[string]int),
+	}
+}
+
+// check returns errThrottled when the update of the channel must be held
+// back
+func (t *pendingThrottle) check(ctx context.Context, channel string) error {
+	pending, err := t.reader.PendingNonceAt(ctx, t.signer)
+	if err != nil {
+		return err
+	}
+	latest, err := t.reader.NonceAt(ctx, t.signer, nil)
+	if err != nil {
+		return err
+	}
+	var count uint64
+	if pending > latest {
+		count = pending - latest
+	}
+	pendingTransactionsGauge().Update(int64(count))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if count <= t.maxPending {
+		if t.throttled {
+			log.Info("Pending transactions cleared, resuming updates", "signer", t.signer.Hex(),
+				"pending", count, "coalesced", t.held)
+			t.throttled = false
+			t.held = make(map[string]int)
+		}
+		return nil
+	}
+
+	if !t.throttled {
+		log.Warn("Too many pending transactions, holding back updates", "signer", t.signer.Hex(),
+			"pending", count, "max", t.maxPending)
+		t.throttled = true
+	}
+	t.held[channel]++
+	throttledCounter(channel).Inc(1)
+	return fmt.Errorf("%w: %d pending", errThrottled, count)
+}
+
+// backend returns a backend that holds back the transactions of the
+// channel while the throttle is engaged
+func (t *pendingThrottle) backend(channel string, backend DeployContractBackend) DeployContractBackend {
+	if t == nil {
+		return backend
+	}
+	return &throttledBackend{backendDecorator: decorate(backend), channel: channel, throttle: t}
+}
+
+// throttledBackend checks the pending transactions of the signer before
+// sending
+type throttledBackend struct {
+	backendDecorator
+	channel  string
+	throttle *pendingThrottle
+}
+
+func (b *throttledBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.throttle.check(ctx, b.channel); err != nil {
+		return err
+	}
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+func pendingTransactionsGauge() metrics.Gauge {
+	return metrics.GetOrRegisterGauge("throttle/pending", ometrics.DefaultRegistry)
+}
+
+func throttledCounter(channel string) metrics.Counter {
+	name := "throttle/" + metricName(channel) + "/throttled"
+	return metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,96 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingThrottle(t *testing.T) {
+	ctx := context.Background()
+	chain := &staticNonces{pending: 3, latest: 3}
+	throttle := newPendingThrottle(common.Address{1}, chain, 2)
+
+	// The pending count grows up to the threshold
+	for pending := uint64(3); pending <= 5; pending++ {
+		chain.pending = pending
+		require.NoError(t, throttle.check(ctx, daFeeChannel))
+	}
+	require.Equal(t, int64(2), pendingTransactionsGauge().Value())
+
+	// Beyond it the updates are held back
+	before := throttledCounter(daFeeChannel).Count()
+	for pending := uint64(6); pending <= 8; pending++ {
+		chain.pending = pending
+		require.ErrorIs(t, throttle.check(ctx, daFeeChannel), errThrottled)
+	}
+	require.Equal(t, before+3, throttledCounter(daFeeChannel).Count())
+	require.Equal(t, int64(5), pendingTransactionsGauge().Value())
+
+	// They resume once the backlog is mined
+	chain.latest = 7
+	require.NoError(t, throttle.check(ctx, daFeeChannel))
+
+	require.Nil(t, newPendingThrottle(common.Address{1}, chain, 0))
+}
+
+func TestPendingThrottleCoalescesBaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	l1 := &syntheticL1{baseFees: []int64{1e9, 2e9, 3e9, 4e9}}
+	cfg := &Config{
+		privateKey:                  key,
+		l2ChainID:                   big.NewInt(1337),
+		gasPriceOracleAddress:       addr,
+		gasPrice:                    big.NewInt(784637584),
+		l1BaseFeeSignificanceFactor: 0.01,
+	}
+	chain := &staticNonces{}
+	throttle := newPendingThrottle(opts.From, chain, 1)
+	recorder := &buildRecorder{DeployContractBackend: sim}
+	trace := newDecisionTrace(l1BaseFeeChannel, nil, nil)
+	update, err := wrapUpdateBaseFee(l1, throttle.backend(l1BaseFeeChannel, recorder), cfg, channelGuards{trace: trace})
+	require.NoError(t, err)
+	update = trace.wrap(update)
+
+	requireBaseFee := func(want int64) {
+		t.Helper()
+		sim.Commit()
+		l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(want), l1BaseFee)
+	}
+
+	require.NoError(t, update())
+	requireBaseFee(1e9)
+
+	// The updates of a growing backlog are held back
+	chain.pending, chain.latest = 10, 8
+	for tip := uint64(1); tip <= 2; tip++ {
+		l1.tip = tip
+		require.ErrorIs(t, update(), errThrottled)
+		decision, ok := trace.lastDecision()
+		require.True(t, ok)
+		require.Equal(t, actionThrottled, decision.Action)
+		requireBaseFee(1e9)
+	}
+	require.Equal(t, 1, recorder.sent)
+
+	// Once it clears, only the latest value is sent
+	chain.latest = 10
+	l1.tip = 3
+	require.NoError(t, update())
+	requireBaseFee(4e9)
+	require.Equal(t, 2, recorder.sent)
+}
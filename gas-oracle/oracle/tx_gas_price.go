@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+const (
+	// gasPriceSourceNode uses the gas price suggested by the node
+	gasPriceSourceNode = "node"
+	// gasPriceSourceHistory uses the base fee of the next block plus a
+	// percentile of the tips paid in recent blocks
+	gasPriceSourceHistory = "history"
+	// gasPriceSourceFixed uses the configured transaction gas price
+	gasPriceSourceFixed = "fixed"
+)
+
+var (
+	// errUnknownGasPriceSource represents the error when the gas price
+	// source is not one of the known sources
+	errUnknownGasPriceSource = errors.New("unknown gas price source")
+	// errNoFeeHistory represents the error when the history source is used
+	// with a backend that cannot read the fee history
+	errNoFeeHistory = errors.New("backend does not support fee history")
+)
+
+// validateGasPriceSource checks that the configured gas price source can
+// be used
+func (c *Config) validateGasPriceSource() error {
+	switch c.gasPriceSource {
+	case gasPriceSourceNode:
+	case gasPriceSourceHistory:
+		if c.gasPriceHistoryPercentile < 0 || c.gasPriceHistoryPercentile > 100 {
+			return fmt.Errorf("gas price history percentile must be between 0 and 100, got %v", c.gasPriceHistoryPercentile)
+		}
+		if c.gasPriceHistoryBlocks == 0 {
+			return errors.New("gas price history blocks must be set")
+		}
+	case gasPriceSourceFixed:
+		if c.gasPrice == nil {
+			return errors.New("the fixed gas price source requires a transaction gas price")
+		}
+	default:
+		return fmt.Errorf("%w: %s", errUnknownGasPriceSource, c.gasPriceSource)
+	}
+	return nil
+}
+
+// txGasPrice returns the gas price to send update transactions with,
+// according to the configured gas price source. Update transactions are
+// legacy transactions, so a single gas price covers both the base fee and
+// the tip.
+func txGasPrice(ctx context.Context, backend bind.ContractTransactor, cfg *Config) (*big.Int, error) {
+	switch cfg.gasPriceSource {
+	case gasPriceSourceFixed:
+		return cfg.gasPrice, nil
+	case gasPriceSourceHistory:
+		reader, ok := backend.(FeeHistoryReader)
+		if !ok {
+			return nil, errNoFeeHistory
+		}
+		return historicalGasPrice(ctx, reader, cfg.gasPriceHistoryBlocks, cfg.gasPriceHistoryPercentile)
+	case gasPriceSourceNode:
+		return backend.SuggestGasPrice(ctx)
+	case "":
+		// Without a source the configured gas price is used if it is set,
+		// otherwise the node suggestion
+		if cfg.gasPrice != nil {
+			return cfg.gasPrice, nil
+		}
+		return backend.SuggestGasPrice(ctx)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownGasPriceSource, cfg.gasPriceSource)
+	}
+}
+
+// historicalGasPrice returns the base fee of the next block plus the
+// average of the tips paid at the percentile in recent blocks
+func historicalGasPrice(ctx context.Context, reader FeeHistoryReader, blocks uint64, percentile float64) (*big.Int, error) {
+	history, err := reader.FeeHistory(ctx, blocks, nil, []float64{percentile})
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errNoRealizedFee
+	}
+
+	tips := new(big.Int)
+	count := int64(0)
+	for _, reward := range history.Reward {
+		if len(reward) == 0 || reward[0] == nil {
+			continue
+		}
+		tips.Add(tips, reward[0])
+		count++
+	}
+	if count > 0 {
+		tips.Div(tips, big.NewInt(count))
+	}
+
+	// The last base fee is the one of the next block
+	next := history.BaseFee[len(history.BaseFee)-1]
+	if next == nil {
+		return nil, errNoRealizedFee
+	}
+	return tips.Add(tips, next), nil
+}
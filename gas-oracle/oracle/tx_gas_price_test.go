@@ -0,0 +1,156 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+func newGasPriceRPC(t *testing.T) (*ethclient.Client, *fakeRPC) {
+	rpc := newFakeRPC(map[string]interface{}{
+		"eth_gasPrice": "0x3b9aca00", // 1 gwei
+		"eth_feeHistory": func(params []json.RawMessage) (interface{}, error) {
+			return map[string]interface{}{
+				"oldestBlock":   "0x10",
+				"baseFeePerGas": []string{"0x64", "0xc8", "0x12c"}, // 100, 200, next 300
+				"gasUsedRatio":  []float64{0.5, 0.5},
+				"reward":        [][]string{{"0xa"}, {"0x1e"}}, // 10, 30
+			}, nil
+		},
+	})
+	t.Cleanup(rpc.Close)
+	client, err := ethclient.Dial(rpc.URL)
+	require.NoError(t, err)
+	return client, rpc
+}
+
+func TestTxGasPriceSources(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *Config
+		expect int64
+		method string
+	}{
+		{
+			name:   "node",
+			cfg:    &Config{gasPriceSource: gasPriceSourceNode, gasPrice: big.NewInt(5)},
+			expect: 1_000_000_000,
+			method: "eth_gasPrice",
+		},
+		{
+			name: "history",
+			cfg: &Config{gasPriceSource: gasPriceSourceHistory, gasPriceHistoryBlocks: 2,
+				gasPriceHistoryPercentile: 50},
+			// next base fee plus the average tip
+			expect: 300 + 20,
+			method: "eth_feeHistory",
+		},
+		{
+			name:   "fixed",
+			cfg:    &Config{gasPriceSource: gasPriceSourceFixed, gasPrice: big.NewInt(5)},
+			expect: 5,
+		},
+		{
+			name:   "unset with gas price",
+			cfg:    &Config{gasPrice: big.NewInt(7)},
+			expect: 7,
+		},
+		{
+			name:   "unset without gas price",
+			cfg:    &Config{},
+			expect: 1_000_000_000,
+			method: "eth_gasPrice",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, rpc := newGasPriceRPC(t)
+			if tc.cfg.gasPriceSource != "" {
+				require.NoError(t, tc.cfg.validateGasPriceSource())
+			}
+
+			gasPrice, err := txGasPrice(context.Background(), client, tc.cfg)
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, gasPrice.Int64())
+			if tc.method != "" {
+				require.Equal(t, 1, rpc.called(tc.method))
+			} else {
+				require.Equal(t, 0, rpc.called("eth_gasPrice")+rpc.called("eth_feeHistory"))
+			}
+		})
+	}
+}
+
+func TestTxGasPriceHistoryThroughDecorators(t *testing.T) {
+	client, _ := newGasPriceRPC(t)
+	cfg := &Config{gasPriceSource: gasPriceSourceHistory, gasPriceHistoryBlocks: 2, gasPriceHistoryPercentile: 50}
+
+	// Every decorator of the submission path reads the fee history of the
+	// backend that it wraps
+	decorators := map[string]func(DeployContractBackend) DeployContractBackend{
+		"simulate": func(b DeployContractBackend) DeployContractBackend { return simulateFirst(l2GasPriceChannel, b) },
+		"resubmit": func(b DeployContractBackend) DeployContractBackend {
+			return newResubmitter(cfg, time.Minute, 1).backend(l2GasPriceChannel, b)
+		},
+		"throttle": func(b DeployContractBackend) DeployContractBackend {
+			return newPendingThrottle(common.Address{}, client, 1).backend(l2GasPriceChannel, b)
+		},
+		"nonce": func(b DeployContractBackend) DeployContractBackend {
+			return newNonceManager(common.Address{}, client, 0).backend(b)
+		},
+		"deposit":      func(b DeployContractBackend) DeployContractBackend { return newDepositBackend(b, client, cfg) },
+		"observe-only": func(b DeployContractBackend) DeployContractBackend { return observeOnly(l2GasPriceChannel, b) },
+		"dry-run":      func(b DeployContractBackend) DeployContractBackend { return dryRunOnly(l2GasPriceChannel, b) },
+		"budget": func(b DeployContractBackend) DeployContractBackend {
+			return newSpendBudget(big.NewInt(1), time.Hour).backend(b)
+		},
+		"queue": func(b DeployContractBackend) DeployContractBackend {
+			return newSubmitter(1, nil).backend(l2GasPriceChannel, b)
+		},
+		"submissions": func(b DeployContractBackend) DeployContractBackend {
+			return newSubmissionTracker(time.Minute).backend(l2GasPriceChannel, b)
+		},
+		"heartbeat": func(b DeployContractBackend) DeployContractBackend {
+			return newHeartbeat(client, cfg, 0, new(big.Int)).track(b)
+		},
+		"pending": func(b DeployContractBackend) DeployContractBackend {
+			return newPendingReceipts(true).backend(l2GasPriceChannel, b)
+		},
+	}
+	var chained DeployContractBackend = client
+	for name, decorate := range decorators {
+		gasPrice, err := txGasPrice(context.Background(), decorate(client), cfg)
+		require.NoError(t, err, name)
+		require.Equal(t, int64(320), gasPrice.Int64(), name)
+		chained = decorate(chained)
+	}
+	gasPrice, err := txGasPrice(context.Background(), chained, cfg)
+	require.NoError(t, err)
+	require.Equal(t, int64(320), gasPrice.Int64())
+}
+
+func TestValidateGasPriceSource(t *testing.T) {
+	require.ErrorIs(t, (&Config{gasPriceSource: "oracle"}).validateGasPriceSource(), errUnknownGasPriceSource)
+	require.Error(t, (&Config{gasPriceSource: gasPriceSourceFixed}).validateGasPriceSource())
+	require.Error(t, (&Config{gasPriceSource: gasPriceSourceHistory, gasPriceHistoryBlocks: 10,
+		gasPriceHistoryPercentile: 101}).validateGasPriceSource())
+}
+
+func TestGasPriceSourceDefault(t *testing.T) {
+	cfg := NewConfig(newTestContext(t))
+	require.Equal(t, gasPriceSourceNode, cfg.gasPriceSource)
+
+	cfg = NewConfig(newTestContext(t, "--transaction-gas-price", "1000"))
+	require.Equal(t, gasPriceSourceFixed, cfg.gasPriceSource)
+
+	cfg = NewConfig(newTestContext(t, "--transaction-gas-price", "1000", "--gas-price-source", "history"))
+	require.Equal(t, gasPriceSourceHistory, cfg.gasPriceSource)
+	require.NoError(t, cfg.validateGasPriceSource())
+}
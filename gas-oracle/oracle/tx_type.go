@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// txTypeLegacy sends the updates as legacy transactions priced by the
+	// gas price source
+	txTypeLegacy = "legacy"
+	// txTypeDynamic sends the updates as EIP-1559 transactions
+	txTypeDynamic = "dynamic"
+	// txTypeAuto sends the updates as EIP-1559 transactions when the chain
+	// has a base fee and as legacy transactions otherwise
+	txTypeAuto = "auto"
+)
+
+var (
+	// errUnknownTxType represents the error when the transaction type is
+	// not one of the known types
+	errUnknownTxType = errors.New("unknown transaction type")
+	// errTipAboveFeeCap represents the error when the configured tip cap
+	// is above the configured fee cap
+	errTipAboveFeeCap = errors.New("max priority fee per gas above the max fee per gas")
+)
+
+// validateTxType checks that the configured transaction type is known and
+// that the configured fee caps are consistent
+func (c *Config) validateTxType() error {
+	switch c.txType {
+	case "", txTypeLegacy, txTypeDynamic, txTypeAuto:
+	default:
+		return fmt.Errorf("%w: %s", errUnknownTxType, c.txType)
+	}
+	if c.txMaxFeePerGas != nil && c.txMaxPriorityFeePerGas != nil && c.txMaxPriorityFeePerGas.Cmp(c.txMaxFeePerGas) > 0 {
+		return fmt.Errorf("%w: %s > %s", errTipAboveFeeCap, c.txMaxPriorityFeePerGas, c.txMaxFeePerGas)
+	}
+	return nil
+}
+
+// txFees sets the fees of the next update transaction on opts according to
+// the configured transaction type. The opts are reused across epochs, so
+// the fields of the other type are cleared.
+func txFees(ctx context.Context, backend bind.ContractTransactor, cfg *Config, opts *bind.TransactOpts) error {
+	var baseFee *big.Int
+	switch cfg.txType {
+	case "", txTypeLegacy:
+	case txTypeDynamic, txTypeAuto:
+		tip, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		baseFee = tip.BaseFee
+		if baseFee == nil && cfg.txType == txTypeDynamic {
+			return errNoBaseFee
+		}
+	default:
+		return fmt.Errorf("%w: %s", errUnknownTxType, cfg.txType)
+	}
+
+	if baseFee == nil {
+		gasPrice, err := txGasPrice(ctx, backend, cfg)
+		if err != nil {
+			return err
+		}
+		opts.GasPrice, opts.GasTipCap, opts.GasFeeCap = gasPrice, nil, nil
+		return nil
+	}
+
+	gasTipCap, gasFeeCap, err := dynamicFees(ctx, backend, cfg, baseFee)
+	if err != nil {
+		return err
+	}
+	log.Trace("pricing update as dynamic fee transaction", "tip-cap", gasTipCap, "fee-cap", gasFeeCap)
+	opts.GasPrice, opts.GasTipCap, opts.GasFeeCap = nil, gasTipCap, gasFeeCap
+	return nil
+}
+
+// dynamicFees returns the tip cap and the fee cap of an EIP-1559 update
+// transaction. The configured caps take precedence, otherwise the tip is
+// suggested by the node and the fee cap leaves room for the base fee to
+// double before the transaction is priced out. The tip never exceeds the
+// fee cap.
+func dynamicFees(ctx context.Context, backend bind.ContractTransactor, cfg *Config, baseFee *big.Int) (*big.Int, *big.Int, error) {
+	gasTipCap := cfg.txMaxPriorityFeePerGas
+	if gasTipCap == nil {
+		var err error
+		if gasTipCap, err = backend.SuggestGasTipCap(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	gasFeeCap := cfg.txMaxFeePerGas
+	if gasFeeCap == nil {
+		gasFeeCap = new(big.Int).Add(gasTipCap, new(big.Int).Mul(baseFee, big.NewInt(2)))
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasTipCap = gasFeeCap
+	}
+	return new(big.Int).Set(gasTipCap), new(big.Int).Set(gasFeeCap), nil
+}
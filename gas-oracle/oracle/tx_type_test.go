@@ -0,0 +1,166 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+// txTypeRecorder records the type of the sent transactions. A legacy
+// recorder serves headers without a base fee, as a chain before EIP-1559
+// would.
+type txTypeRecorder struct {
+	DeployContractBackend
+	legacy bool
+	sent   []uint8
+}
+
+func (b *txTypeRecorder) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := b.DeployContractBackend.HeaderByNumber(ctx, number)
+	if err != nil || !b.legacy {
+		return header, err
+	}
+	header = types.CopyHeader(header)
+	header.BaseFee = nil
+	return header, nil
+}
+
+func (b *txTypeRecorder) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx.Type())
+	return b.DeployContractBackend.SendTransaction(ctx, tx)
+}
+
+// sendBaseFeeUpdate sends an L1 base fee update with the transaction type
+// through the recorder and returns the type of the sent transaction
+func sendBaseFeeUpdate(t *testing.T, txType string, legacy bool) (uint8, error) {
+	t.Helper()
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(784637584),
+		gasPriceSource:        gasPriceSourceFixed,
+		txType:                txType,
+	}
+	require.NoError(t, cfg.validateTxType())
+	recorder := &txTypeRecorder{DeployContractBackend: sim, legacy: legacy}
+	update, err := wrapUpdateBaseFee(&syntheticL1{baseFees: []int64{2e9}}, recorder, cfg, channelGuards{})
+	require.NoError(t, err)
+	if err := update(); err != nil {
+		return 0, err
+	}
+	sim.Commit()
+
+	l1BaseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2e9), l1BaseFee)
+	require.Len(t, recorder.sent, 1)
+	return recorder.sent[0], nil
+}
+
+func TestTxTypeAuto(t *testing.T) {
+	// A chain with a base fee is sent EIP-1559 transactions
+	txType, err := sendBaseFeeUpdate(t, txTypeAuto, false)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.DynamicFeeTxType), txType)
+
+	// A legacy-only chain falls back to legacy transactions
+	txType, err = sendBaseFeeUpdate(t, txTypeAuto, true)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.LegacyTxType), txType)
+}
+
+func TestTxTypeExplicit(t *testing.T) {
+	txType, err := sendBaseFeeUpdate(t, txTypeLegacy, false)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.LegacyTxType), txType)
+
+	txType, err = sendBaseFeeUpdate(t, txTypeDynamic, false)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.DynamicFeeTxType), txType)
+
+	// Without a base fee the dynamic type cannot be priced
+	_, err = sendBaseFeeUpdate(t, txTypeDynamic, true)
+	require.ErrorIs(t, err, errNoBaseFee)
+}
+
+func TestValidateTxType(t *testing.T) {
+	require.ErrorIs(t, (&Config{txType: "blob"}).validateTxType(), errUnknownTxType)
+	require.NoError(t, (&Config{}).validateTxType())
+
+	cfg := NewConfig(newTestContext(t))
+	require.Equal(t, txTypeLegacy, cfg.txType)
+	cfg = NewConfig(newTestContext(t, "--tx-type", "auto"))
+	require.Equal(t, txTypeAuto, cfg.txType)
+}
+
+func TestTxFeesFromSameConfig(t *testing.T) {
+	ctx := context.Background()
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	cfg := &Config{
+		gasPrice:               big.NewInt(784637584),
+		gasPriceSource:         gasPriceSourceFixed,
+		txType:                 txTypeAuto,
+		txMaxFeePerGas:         big.NewInt(50e9),
+		txMaxPriorityFeePerGas: big.NewInt(2e9),
+	}
+	require.NoError(t, cfg.validateTxType())
+
+	// A chain with a base fee gets the configured caps
+	opts := &bind.TransactOpts{}
+	require.NoError(t, txFees(ctx, &txTypeRecorder{DeployContractBackend: sim}, cfg, opts))
+	require.Nil(t, opts.GasPrice)
+	require.Equal(t, big.NewInt(2e9), opts.GasTipCap)
+	require.Equal(t, big.NewInt(50e9), opts.GasFeeCap)
+
+	// and a legacy-only chain the gas price, reusing the same opts
+	require.NoError(t, txFees(ctx, &txTypeRecorder{DeployContractBackend: sim, legacy: true}, cfg, opts))
+	require.Equal(t, big.NewInt(784637584), opts.GasPrice)
+	require.Nil(t, opts.GasTipCap)
+	require.Nil(t, opts.GasFeeCap)
+
+	// Without caps the tip is suggested by the node and the fee cap
+	// follows the base fee
+	cfg.txMaxFeePerGas, cfg.txMaxPriorityFeePerGas = nil, nil
+	require.NoError(t, txFees(ctx, sim, cfg, opts))
+	tip, err := sim.SuggestGasTipCap(ctx)
+	require.NoError(t, err)
+	header, err := sim.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, tip, opts.GasTipCap)
+	require.Equal(t, new(big.Int).Add(tip, new(big.Int).Mul(header.BaseFee, big.NewInt(2))), opts.GasFeeCap)
+
+	// A suggested tip never exceeds the configured fee cap
+	cfg.txMaxFeePerGas = big.NewInt(0)
+	require.NoError(t, txFees(ctx, sim, cfg, opts))
+	require.Equal(t, big.NewInt(0), opts.GasTipCap)
+	require.Equal(t, big.NewInt(0), opts.GasFeeCap)
+}
+
+func TestValidateTxFeeCaps(t *testing.T) {
+	cfg := NewConfig(newTestContext(t, "--tx-max-fee-per-gas", "1000", "--tx-max-priority-fee-per-gas", "10"))
+	require.Equal(t, big.NewInt(1000), cfg.txMaxFeePerGas)
+	require.Equal(t, big.NewInt(10), cfg.txMaxPriorityFeePerGas)
+	require.NoError(t, cfg.validateTxType())
+
+	cfg = NewConfig(newTestContext(t))
+	require.Nil(t, cfg.txMaxFeePerGas)
+	require.Nil(t, cfg.txMaxPriorityFeePerGas)
+
+	cfg = NewConfig(newTestContext(t, "--tx-max-fee-per-gas", "10", "--tx-max-priority-fee-per-gas", "1000"))
+	require.ErrorIs(t, cfg.validateTxType(), errTipAboveFeeCap)
+}
@@ -0,0 +1,78 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// updateMethods maps every channel to the method of the
+// `BVM_GasPriceOracle` that its update transaction calls
+var updateMethods = map[string]string{
+	l1BaseFeeChannel:  "setL1BaseFee",
+	l2GasPriceChannel: "setGasPrice",
+	daFeeChannel:      "setDAGasPrice",
+	overheadChannel:   "setOverhead",
+	scalarChannel:     "setScalar",
+}
+
+// representativeValue is the value written by the calldata of an estimate.
+// A nonzero value prices in the storage write of a real update.
+var representativeValue = big.NewInt(1_000_000_000)
+
+// updateCalldata returns calldata representative of the update
+// transaction of the channel
+func updateCalldata(channel string) ([]byte, error) {
+	method, ok := updateMethods[channel]
+	if !ok {
+		return nil, fmt.Errorf("unknown channel %q", channel)
+	}
+	parsed, err := bindings.BVMGasPriceOracleMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack(method, representativeValue)
+}
+
+// estimateUpdateGas estimates the gas used by the update transaction of the
+// channel when sent by from
+func estimateUpdateGas(ctx context.Context, backend bind.ContractTransactor, from, to common.Address, channel string) (uint64, error) {
+	data, err := updateCalldata(channel)
+	if err != nil {
+		return 0, err
+	}
+	return backend.EstimateGas(ctx, ethereum.CallMsg{
+		From: from,
+		To:   &to,
+		Data: data,
+	})
+}
+
+// reportUpdateGas estimates the gas used by the update transaction of every
+// channel and exports it as the gauge `update_gas/<channel>`, so that the
+// cost of running a channel can be budgeted. A failed estimate is logged and
+// leaves the gauge unset.
+func reportUpdateGas(ctx context.Context, backends map[string]bind.ContractTransactor, from, to common.Address) {
+	for channel, backend := range backends {
+		gas, err := estimateUpdateGas(ctx, backend, from, to, channel)
+		if err != nil {
+			log.Warn("cannot estimate update gas", "channel", channel, "message", err)
+			continue
+		}
+		log.Info("Estimated update gas", "channel", channel, "gas", gas)
+		updateGasGauge(channel).Update(int64(gas))
+	}
+}
+
+func updateGasGauge(channel string) metrics.Gauge {
+	name := "update_gas/" + metricName(channel)
+	return metrics.GetOrRegisterGauge(name, ometrics.DefaultRegistry)
+}
@@ -0,0 +1,68 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportUpdateGasPerChannel(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// The legacy contract has no DA fee, its estimate comes from a fake node
+	da := newFakeRPC(map[string]interface{}{
+		"eth_estimateGas": "0x7530",
+	})
+	defer da.Close()
+	daClient, err := ethclient.Dial(da.URL)
+	require.NoError(t, err)
+
+	reportUpdateGas(context.Background(), map[string]bind.ContractTransactor{
+		l1BaseFeeChannel:  sim,
+		l2GasPriceChannel: sim,
+		daFeeChannel:      daClient,
+	}, opts.From, addr)
+
+	for _, channel := range []string{l1BaseFeeChannel, l2GasPriceChannel} {
+		gas := updateGasGauge(channel).Value()
+		require.Greater(t, gas, int64(21000), "no estimate for %s", channel)
+		require.Less(t, gas, int64(100000), "estimate for %s out of range", channel)
+	}
+	require.Equal(t, int64(30000), updateGasGauge(daFeeChannel).Value())
+	require.Equal(t, 1, da.called("eth_estimateGas"))
+}
+
+func TestReportUpdateGasFailure(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	node := newFakeRPC(map[string]interface{}{
+		"eth_estimateGas": func(params []json.RawMessage) (interface{}, error) {
+			return nil, errors.New("execution reverted")
+		},
+	})
+	defer node.Close()
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(t, err)
+
+	// A failed estimate leaves the gauge as it was
+	updateGasGauge(daFeeChannel).Update(42)
+	reportUpdateGas(context.Background(), map[string]bind.ContractTransactor{
+		daFeeChannel: client,
+	}, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(key.PublicKey))
+	require.Equal(t, int64(42), updateGasGauge(daFeeChannel).Value())
+
+	_, err = updateCalldata("unknown")
+	require.Error(t, err)
+}
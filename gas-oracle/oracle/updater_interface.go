@@ -12,25 +12,37 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
 	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
 	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
 )
 
-var (
-	txSendCounter           = metrics.NewRegisteredCounter("tx/send", ometrics.DefaultRegistry)
-	txNotSignificantCounter = metrics.NewRegisteredCounter("tx/not_significant", ometrics.DefaultRegistry)
-	gasPriceGauge           = metrics.NewRegisteredGauge("gas_price", ometrics.DefaultRegistry)
-	txConfTimer             = metrics.NewRegisteredTimer("tx/confirmed", ometrics.DefaultRegistry)
-	txSendTimer             = metrics.NewRegisteredTimer("tx/send", ometrics.DefaultRegistry)
-)
+// The metrics are created when they are first updated, once the metrics
+// are enabled by the command line or the config file
+func txSendCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/send", ometrics.DefaultRegistry)
+}
+
+func txNotSignificantCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("tx/not_significant", ometrics.DefaultRegistry)
+}
+
+func txConfTimer() metrics.Timer {
+	return metrics.GetOrRegisterTimer("tx/confirmed", ometrics.DefaultRegistry)
+}
+
+// txSendTimer is named apart from txSendCounter, which takes "tx/send"
+func txSendTimer() metrics.Timer {
+	return metrics.GetOrRegisterTimer("tx/send_duration", ometrics.DefaultRegistry)
+}
 
 // getLatestBlockNumberFn is used by the GasPriceUpdater
 // to get the latest block number. The outer function binds the
 // inner function to a `bind.ContractBackend` which is implemented
 // by the `ethclient.Client`
-func wrapGetLatestBlockNumberFn(backend bind.ContractBackend) func() (uint64, error) {
+func wrapGetLatestBlockNumberFn(backend bind.ContractBackend, deadline *inputDeadline) func() (uint64, error) {
 	return func() (uint64, error) {
-		tip, err := backend.HeaderByNumber(context.Background(), nil)
+		tip, err := backend.HeaderByNumber(deadline.context(), nil)
 		if err != nil {
 			return 0, err
 		}
@@ -41,9 +53,9 @@ func wrapGetLatestBlockNumberFn(backend bind.ContractBackend) func() (uint64, er
 // wrapGetGasUsedByBlock is used by the GasPriceUpdater to get
 // the amount of gas used by a particular block. This is used to
 // track gas usage over time
-func wrapGetGasUsedByBlock(backend bind.ContractBackend) func(*big.Int) (uint64, error) {
+func wrapGetGasUsedByBlock(backend bind.ContractBackend, deadline *inputDeadline) func(*big.Int) (uint64, error) {
 	return func(number *big.Int) (uint64, error) {
-		block, err := backend.HeaderByNumber(context.Background(), number)
+		block, err := backend.HeaderByNumber(deadline.context(), number)
 		if err != nil {
 			return 0, err
 		}
@@ -51,6 +63,12 @@ func wrapGetGasUsedByBlock(backend bind.ContractBackend) func(*big.Int) (uint64,
 	}
 }
 
+// l2GasPricer computes and writes the L2 gas price once per epoch
+type l2GasPricer interface {
+	UpdateGasPrice() error
+	GetGasPrice() uint64
+}
+
 // DeployContractBackend represents the union of the
 // DeployBackend and the ContractBackend
 type DeployContractBackend interface {
@@ -58,19 +76,34 @@ type DeployContractBackend interface {
 	bind.ContractBackend
 }
 
+// backendDecorator is embedded by the backends that decorate another one.
+// Along with the methods of the DeployContractBackend it forwards the fee
+// history, which the gas price source of history reads through every
+// decorator.
+type backendDecorator struct {
+	DeployContractBackend
+}
+
+// decorate returns the backendDecorator of backend
+func decorate(backend DeployContractBackend) backendDecorator {
+	return backendDecorator{DeployContractBackend: backend}
+}
+
+// FeeHistory forwards to the backend when it can read the fee history
+func (b backendDecorator) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	reader, ok := b.DeployContractBackend.(FeeHistoryReader)
+	if !ok {
+		return nil, errNoFeeHistory
+	}
+	return reader.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
 // updateL2GasPriceFn is used by the GasPriceUpdater
 // to update the L2 gas price
 // perhaps this should take an options struct along with the backend?
 // how can this continue to be decomposed?
-func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(uint64) error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
-
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
+func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config, guards channelGuards) (func(uint64) error, error) {
+	opts, err := cfg.transactor()
 	if err != nil {
 		return nil, err
 	}
@@ -85,75 +118,128 @@ func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(ui
 	if err != nil {
 		return nil, err
 	}
+	shadows, err := newShadowOracles(l2GasPriceChannel, cfg, backend)
+	if err != nil {
+		return nil, err
+	}
 
 	return func(updatedGasPrice uint64) error {
-		log.Trace("UpdateL2GasPriceFn", "gas-price", updatedGasPrice)
-		if cfg.gasPrice == nil {
-			// Set the gas price manually to use legacy transactions
-			gasPrice, err := backend.SuggestGasPrice(context.Background())
-			if err != nil {
-				log.Error("cannot fetch gas price", "message", err)
-				return err
-			}
-			log.Trace("fetched L2 tx.gasPrice", "gas-price", gasPrice)
-			opts.GasPrice = gasPrice
-		} else {
-			// Allow a configurable gas price to be set
-			opts.GasPrice = cfg.gasPrice
+		log.Trace("UpdateL2GasPriceFn", "l2_gas_price", updatedGasPrice)
+		updatedGasPrice = cfg.roundGasPrice(updatedGasPrice)
+		// Set the fees manually according to the transaction type
+		// The update is built and sent under the context of the loop
+		opts.Context = guards.deadline.epoch()
+		if err := txFees(opts.Context, backend, cfg, opts); err != nil {
+			log.Error("cannot fetch gas price", "message", err)
+			return err
 		}
+		log.Trace("fetched L2 tx fees", "tx_gas_price", opts.GasPrice, "tip-cap", opts.GasTipCap)
 
 		// Query the current L2 gas price
+		l2Block, err := headNumber(guards.deadline.context(), backend)
+		if err != nil {
+			log.Error("cannot fetch the L2 head", "message", err)
+			return err
+		}
+		guards.trace.l2Block(l2Block)
 		currentPrice, err := contract.GasPrice(&bind.CallOpts{
-			Context: context.Background(),
+			Context:     guards.deadline.context(),
+			BlockNumber: l2Block,
 		})
 		if err != nil {
 			log.Error("cannot fetch current gas price", "message", err)
 			return err
 		}
 
+		guards.stuck.observed(currentPrice)
+		reportCurrent(l2GasPriceChannel, currentPrice)
+		guards.drops.observed(currentPrice)
+		guards.trace.input("current_gas_price", currentPrice)
+		guards.trace.output("gas_price", updatedGasPrice)
+		reference := guards.sent.reference(l2GasPriceChannel, currentPrice)
+		if reference != currentPrice {
+			guards.trace.input("last_sent_gas_price", reference)
+		}
+
 		// no need to update when they are the same
-		if currentPrice.Uint64() == updatedGasPrice {
-			log.Info("gas price did not change", "gas-price", updatedGasPrice)
-			txNotSignificantCounter.Inc(1)
+		if reference.Uint64() == updatedGasPrice {
+			log.Info("gas price did not change", "l2_gas_price", updatedGasPrice)
+			txNotSignificantCounter().Inc(1)
+			guards.trace.act(actionSkip, reasonUnchanged, "not changed")
 			return nil
 		}
 
 		// Only update the gas price when it must be changed by at least
 		// a paramaterizable amount.
-		if !isDifferenceSignificant(currentPrice.Uint64(), updatedGasPrice, cfg.l2GasPriceSignificanceFactor) {
-			log.Info("gas price did not significantly change", "min-factor", cfg.l2GasPriceSignificanceFactor,
-				"current-price", currentPrice, "next-price", updatedGasPrice)
-			txNotSignificantCounter.Inc(1)
+		factor := guards.emergency.significanceFactor(cfg.l2GasPriceSignificanceFactorFor(reference.Uint64(), updatedGasPrice))
+		factor = guards.reversals.significanceFactor(factor, currentPrice, new(big.Int).SetUint64(updatedGasPrice))
+		if !isDifferenceSignificant(reference.Uint64(), updatedGasPrice, factor) {
+			log.Info("gas price did not significantly change", "min-factor", factor,
+				"current-price", reference, "next-price", updatedGasPrice)
+			txNotSignificantCounter().Inc(1)
+			guards.trace.act(actionSkip, reasonBelowSignificance, "not significant")
 			return nil
 		}
 
-		// Set the gas price by sending a transaction
-		tx, err := contract.SetGasPrice(opts, new(big.Int).SetUint64(updatedGasPrice))
-		if err != nil {
+		// A channel that was just enabled may ease from a stale value
+		target := new(big.Int).SetUint64(updatedGasPrice)
+		updatedGasPrice = guards.grace.next(currentPrice, new(big.Int).SetUint64(updatedGasPrice)).Uint64()
+		guards.trace.output("gas_price", updatedGasPrice)
+		if cfg.observes(l2GasPriceChannel) {
+			observe(l2GasPriceChannel, guards.trace, "gas_price", new(big.Int).SetUint64(updatedGasPrice))
+			return nil
+		}
+		if err := guards.drops.check(currentPrice, new(big.Int).SetUint64(updatedGasPrice)); err != nil {
 			return err
 		}
+		if guards.deadline.exceeded() {
+			return errEpochAborted
+		}
+		if cfg.dryRun {
+			return dryRun(l2GasPriceChannel, guards.trace, cfg.gasPriceOracleAddress, opts, "setGasPrice", "gas_price", new(big.Int).SetUint64(updatedGasPrice))
+		}
 
-		log.Debug("updating L2 gas price", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
-			"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+		// Set the gas price by sending a transaction
 		pre := time.Now()
-		if err := backend.SendTransaction(context.Background(), tx); err != nil {
+		tx, err := contract.SetGasPrice(opts, new(big.Int).SetUint64(updatedGasPrice))
+		if err == nil {
+			log.Debug("updating L2 gas price", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
+				"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+			if err = backend.SendTransaction(opts.Context, tx); err != nil {
+				reportSendFailure(l2GasPriceChannel, err)
+			}
+		}
+		reportOracleSend(l2GasPriceChannel, cfg.gasPriceOracleAddress, err)
+		// The shadow contracts are updated whether or not the primary was
+		shadows.send(opts.Context, func(shadow *bindings.BVMGasPriceOracle) (*types.Transaction, error) {
+			return shadow.SetGasPrice(opts, new(big.Int).SetUint64(updatedGasPrice))
+		})
+		if err != nil {
 			return err
 		}
-		txSendTimer.Update(time.Since(pre))
-		log.Info("L2 gas price transaction sent", "hash", tx.Hash().Hex())
+		txSendTimer().Update(time.Since(pre))
+		log.Info("L2 gas price transaction sent", "epoch", guards.trace.epoch(), "hash", tx.Hash().Hex(), "l2_gas_price", updatedGasPrice,
+			"l2-block", l2Block)
+		reportBlocks(l2GasPriceChannel, nil, l2Block)
 
-		gasPriceGauge.Update(int64(updatedGasPrice))
-		txSendCounter.Inc(1)
+		reportWritten(l2GasPriceChannel, new(big.Int).SetUint64(updatedGasPrice))
+		guards.sent.sent(l2GasPriceChannel, new(big.Int).SetUint64(updatedGasPrice))
+		txSendCounter().Inc(1)
+		guards.trace.output("tx_hash", tx.Hash().Hex())
+		guards.trace.act(actionUpdate, updateReason(target, new(big.Int).SetUint64(updatedGasPrice)), "")
+		guards.emergency.updated()
+		guards.stuck.wrote(currentPrice, new(big.Int).SetUint64(updatedGasPrice))
+		guards.reversals.wrote(currentPrice, new(big.Int).SetUint64(updatedGasPrice))
 
 		if cfg.waitForReceipt {
 			// Keep track of the time it takes to confirm the transaction
 			pre := time.Now()
 			// Wait for the receipt
-			receipt, err := waitForReceipt(backend, tx)
+			receipt, err := waitForReceipt(opts.Context, backend, tx, cfg.receiptBackoff)
 			if err != nil {
 				return err
 			}
-			txConfTimer.Update(time.Since(pre))
+			txConfTimer().Update(time.Since(pre))
 
 			log.Info("L2 gas price transaction confirmed", "hash", tx.Hash().Hex(),
 				"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
@@ -173,13 +259,30 @@ func isDifferenceSignificant(a, b uint64, c float64) bool {
 	return c <= factor
 }
 
-// Wait for the receipt by polling the backend
-func waitForReceipt(backend DeployContractBackend, tx *types.Transaction) (*types.Receipt, error) {
-	t := time.NewTicker(300 * time.Millisecond)
-	receipt := new(types.Receipt)
-	var err error
-	for range t.C {
-		receipt, err = backend.TransactionReceipt(context.Background(), tx.Hash())
+// l2GasPriceSignificanceFactorFor returns the factor that a change of the
+// gas price from current to next must clear. An increase uses the up factor
+// and a decrease the down factor, when they are set, so that the price can
+// follow spikes quickly and ease back down slowly.
+func (c *Config) l2GasPriceSignificanceFactorFor(current, next uint64) float64 {
+	switch {
+	case next > current && c.l2GasPriceSignificanceFactorUp != nil:
+		return *c.l2GasPriceSignificanceFactorUp
+	case next < current && c.l2GasPriceSignificanceFactorDown != nil:
+		return *c.l2GasPriceSignificanceFactorDown
+	}
+	return c.l2GasPriceSignificanceFactor
+}
+
+// Wait for the receipt by polling the backend until the context is done
+func waitForReceipt(ctx context.Context, backend DeployContractBackend, tx *types.Transaction, policy backoff.Policy) (*types.Receipt, error) {
+	b := policy.New()
+	for {
+		select {
+		case <-time.After(b.Next()):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
 		if errors.Is(err, ethereum.NotFound) {
 			continue
 		}
@@ -187,11 +290,9 @@ func waitForReceipt(backend DeployContractBackend, tx *types.Transaction) (*type
 			return nil, err
 		}
 		if receipt != nil {
-			t.Stop()
-			break
+			return receipt, nil
 		}
 	}
-	return receipt, nil
 }
 
 func max(a, b uint64) uint64 {
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/mantlenetworkio/mantle/gas-oracle/bindings"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWrapGetLatestBlockNumberFn(t *testing.T) {
@@ -21,7 +22,7 @@ func TestWrapGetLatestBlockNumberFn(t *testing.T) {
 	sim, db := newSimulatedBackend(key)
 	chain := sim.Blockchain()
 
-	getLatest := wrapGetLatestBlockNumberFn(sim)
+	getLatest := wrapGetLatestBlockNumberFn(sim, nil)
 
 	// Generate a valid chain of 10 blocks
 	blocks, _ := core.GenerateChain(chain.Config(), chain.CurrentBlock(), chain.Engine(), db, 10, nil)
@@ -69,7 +70,7 @@ func TestWrapUpdateL2GasPriceFn(t *testing.T) {
 		gasPrice:              big.NewInt(783460975),
 	}
 
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, channelGuards{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -110,7 +111,7 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 		// the new gas price must change be 50% for it to actually update
 		l2GasPriceSignificanceFactor: 0.5,
 	}
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, channelGuards{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -167,6 +168,65 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 	tryUpdate(1, true)
 }
 
+func TestWrapUpdateL2GasPriceFnDirectionalSignificance(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// Increases must clear 10% and decreases 50%, in place of the 5% of
+	// the symmetric factor
+	up, down := 0.1, 0.5
+	cfg := &Config{
+		privateKey:                       key,
+		l2ChainID:                        big.NewInt(1337),
+		gasPriceOracleAddress:            addr,
+		gasPrice:                         big.NewInt(1_000_000_000),
+		l2GasPriceSignificanceFactor:     0.05,
+		l2GasPriceSignificanceFactorUp:   &up,
+		l2GasPriceSignificanceFactorDown: &down,
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, channelGuards{})
+	require.NoError(t, err)
+	update := func(price, expected uint64) {
+		require.NoError(t, updateL2GasPriceFn(price))
+		sim.Commit()
+		gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, expected, gasPrice.Uint64(), "update to %d", price)
+	}
+
+	update(1_000_000, 1_000_000)
+	// An increase of 9.9% is below the up factor
+	update(1_110_000, 1_000_000)
+	// while one of 10.7% is just above it
+	update(1_120_000, 1_120_000)
+	// A decrease of 37.5% would clear the symmetric factor, but not the
+	// down factor
+	update(700_000, 1_120_000)
+	// while one of 55% does
+	update(500_000, 500_000)
+}
+
+func TestL2GasPriceSignificanceFactorFor(t *testing.T) {
+	cfg := &Config{l2GasPriceSignificanceFactor: 0.05}
+	// Without the directional factors both directions use the symmetric one
+	require.Equal(t, 0.05, cfg.l2GasPriceSignificanceFactorFor(100, 200))
+	require.Equal(t, 0.05, cfg.l2GasPriceSignificanceFactorFor(200, 100))
+
+	// Each directional factor overrides its direction only
+	up := 0.01
+	cfg.l2GasPriceSignificanceFactorUp = &up
+	require.Equal(t, 0.01, cfg.l2GasPriceSignificanceFactorFor(100, 200))
+	require.Equal(t, 0.05, cfg.l2GasPriceSignificanceFactorFor(200, 100))
+	down := 0.0
+	cfg.l2GasPriceSignificanceFactorDown = &down
+	require.Equal(t, 0.0, cfg.l2GasPriceSignificanceFactorFor(200, 100))
+	require.Equal(t, 0.05, cfg.l2GasPriceSignificanceFactorFor(100, 100))
+}
+
 func TestIsDifferenceSignificant(t *testing.T) {
 	tests := []struct {
 		name   string
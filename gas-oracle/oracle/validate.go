@@ -0,0 +1,150 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mantlenetworkio/mantle/gas-oracle/flags"
+)
+
+// errInvalidConfig represents the error when the configuration has one or
+// more problems
+var errInvalidConfig = errors.New("invalid configuration")
+
+// configError lists every problem of a configuration. It wraps
+// errInvalidConfig along with the errors of the checks that return one.
+type configError struct {
+	problems []string
+	errs     []error
+}
+
+func (e *configError) Error() string {
+	return fmt.Sprintf("%s:\n  %s", errInvalidConfig, strings.Join(e.problems, "\n  "))
+}
+
+func (e *configError) Unwrap() []error {
+	return append([]error{errInvalidConfig}, e.errs...)
+}
+
+// ValidateConfig checks the numeric ranges of the options and the
+// invariants between them, so that a misconfiguration fails at startup.
+// The options that NewConfig could not parse are reported along with them.
+// NewGasPriceOracle validates its config with it. The returned error lists
+// every problem found rather than the first one.
+func ValidateConfig(cfg *Config) error {
+	var problems []string
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	// The options that NewConfig could not parse come first
+	for _, err := range cfg.optionErrs {
+		problems = append(problems, err.Error())
+		errs = append(errs, err)
+	}
+
+	check(cfg.floorPrice >= 1, "--%s must be at least 1, got %d", flags.FloorPriceFlag.Name, cfg.floorPrice)
+	check(cfg.ceilingPrice == 0 || cfg.ceilingPrice >= cfg.floorPrice, "--%s of %d is below the --%s of %d",
+		flags.CeilingPriceFlag.Name, cfg.ceilingPrice, flags.FloorPriceFlag.Name, cfg.floorPrice)
+	check(cfg.maxPercentChangePerEpoch > 0 && !math.IsInf(cfg.maxPercentChangePerEpoch, 0),
+		"--%s must be positive, got %v", flags.MaxPercentChangePerEpochFlag.Name, cfg.maxPercentChangePerEpoch)
+
+	for _, factor := range []struct {
+		name  string
+		value float64
+	}{
+		{flags.L2GasPriceSignificanceFactorFlag.Name, cfg.l2GasPriceSignificanceFactor},
+		{flags.L1BaseFeeSignificanceFactorFlag.Name, cfg.l1BaseFeeSignificanceFactor},
+		{flags.DaFeeSignificanceFactorFlag.Name, cfg.daFeeSignificanceFactor},
+		{flags.OverheadSignificanceFactorFlag.Name, cfg.overheadSignificanceFactor},
+		{flags.ScalarSignificanceFactorFlag.Name, cfg.scalarSignificanceFactor},
+		{flags.EmergencySignificanceFactorFlag.Name, cfg.emergencySignificanceFactor},
+		{flags.ReversalSignificanceFactorFlag.Name, cfg.reversalSignificanceFactor},
+	} {
+		check(factor.value >= 0, "--%s must not be negative, got %v", factor.name, factor.value)
+	}
+	if up := cfg.l2GasPriceSignificanceFactorUp; up != nil {
+		check(*up >= 0, "--%s must not be negative, got %v", flags.L2GasPriceSignificanceFactorUpFlag.Name, *up)
+	}
+	if down := cfg.l2GasPriceSignificanceFactorDown; down != nil {
+		check(*down >= 0, "--%s must not be negative, got %v", flags.L2GasPriceSignificanceFactorDownFlag.Name, *down)
+	}
+
+	// The epochs of the enabled channels must have a length
+	if cfg.enableL2GasPrice {
+		check(cfg.epochLengthSeconds >= 1, "--%s must be at least 1", flags.EpochLengthSecondsFlag.Name)
+		check(cfg.averageBlockGasLimitPerEpoch >= 1, "--%s must be at least 1", flags.AverageBlockGasLimitPerEpochFlag.Name)
+	}
+	if cfg.enableL1BaseFee {
+		check(cfg.l1BaseFeeEpochLengthSeconds >= 1, "--%s must be at least 1", flags.L1BaseFeeEpochLengthSecondsFlag.Name)
+	}
+	if cfg.enableDaFee {
+		check(cfg.daFeeEpochLengthSeconds >= 1, "--%s must be at least 1", flags.DaFeeEpochLengthSecondsFlag.Name)
+	}
+	if cfg.enableOverhead {
+		check(cfg.overheadEpochLengthSeconds >= 1, "--%s must be at least 1", flags.OverheadEpochLengthSecondsFlag.Name)
+	}
+	if cfg.enableScalar {
+		check(cfg.scalarEpochLengthSeconds >= 1, "--%s must be at least 1", flags.ScalarEpochLengthSecondsFlag.Name)
+		// A zero scalar would waive the L1 fee altogether
+		check(cfg.scalar >= 1, "--%s must be at least 1", flags.ScalarFlag.Name)
+	}
+
+	if cfg.alertWebhookURL != "" {
+		check(cfg.alertFailureThreshold >= 1, "--%s must be at least 1", flags.AlertFailureThresholdFlag.Name)
+	}
+
+	names := make([]string, 0, len(cfg.addressFlags))
+	for name := range cfg.addressFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addresses := []string{cfg.addressFlags[name]}
+		if name == flags.GasPriceOracleAddressFlag.Name {
+			addresses = strings.Split(addresses[0], ",")
+		}
+		seen := make(map[common.Address]bool)
+		for _, address := range addresses {
+			address = strings.TrimSpace(address)
+			// An empty address is left to the checks of the inputs that need it
+			check(address == "" || common.IsHexAddress(address), "--%s is not an address: %q", name, address)
+			if address != "" && common.IsHexAddress(address) {
+				check(!seen[common.HexToAddress(address)], "--%s lists %s more than once", name, address)
+				seen[common.HexToAddress(address)] = true
+			}
+		}
+	}
+
+	for _, validate := range []func() error{
+		cfg.validateGasPriceSource,
+		cfg.validateTxType,
+		cfg.validateSubmissionPath,
+		cfg.validateL1BaseFeeMode,
+		cfg.validateDaFeeBounds,
+		cfg.validateInclusionTime,
+		cfg.validateTokenPriceCredentials,
+		cfg.validateSpendBudget,
+		cfg.validateChannelInputs,
+		cfg.validateEMAAlpha,
+		cfg.validateSigner,
+		cfg.validateTokenPriceMaxAge,
+	} {
+		if err := validate(); err != nil {
+			problems = append(problems, err.Error())
+			errs = append(errs, err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &configError{problems: problems, errs: errs}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	// The L2 gas price channel with every input that it needs
+	valid := []string{
+		"--enable-l2-gas-price",
+		"--layer-two-http-url", "http://127.0.0.1:8545",
+		"--gas-price-oracle-address", "0x420000000000000000000000000000000000000F",
+	}
+	require.NoError(t, ValidateConfig(NewConfig(newTestContext(t, valid...))))
+
+	for _, test := range []struct {
+		name    string
+		args    []string
+		problem string
+	}{
+		{"floor above ceiling", []string{"--floor-price", "10", "--ceiling-price", "5"},
+			"--ceiling-price of 5 is below the --floor-price of 10"},
+		{"zero floor", []string{"--floor-price", "0"},
+			"--floor-price must be at least 1"},
+		{"zero max change", []string{"--max-percent-change-per-epoch", "0"},
+			"--max-percent-change-per-epoch must be positive"},
+		{"negative max change", []string{"--max-percent-change-per-epoch", "-0.1"},
+			"--max-percent-change-per-epoch must be positive"},
+		{"zero epoch length", []string{"--epoch-length-seconds", "0"},
+			"--epoch-length-seconds must be at least 1"},
+		{"zero block gas limit", []string{"--average-block-gas-limit-per-epoch", "0"},
+			"--average-block-gas-limit-per-epoch must be at least 1"},
+		{"negative significance factor", []string{"--significant-factor", "-0.05"},
+			"--significant-factor must not be negative"},
+		{"negative L1 base fee significance factor", []string{"--l1-base-fee-significant-factor", "-1"},
+			"--l1-base-fee-significant-factor must not be negative"},
+		{"negative up significance factor", []string{"--significant-factor-up", "-0.1"},
+			"--significant-factor-up must not be negative"},
+		{"invalid gas price oracle address", []string{"--gas-price-oracle-address", "0x1234"},
+			`--gas-price-oracle-address is not an address: "0x1234"`},
+		{"invalid shadow gas price oracle address", []string{"--gas-price-oracle-address", "0x420000000000000000000000000000000000000F,0x1234"},
+			`--gas-price-oracle-address is not an address: "0x1234"`},
+		{"repeated gas price oracle address", []string{"--gas-price-oracle-address",
+			"0x420000000000000000000000000000000000000F, 0x420000000000000000000000000000000000000f"},
+			"--gas-price-oracle-address lists 0x420000000000000000000000000000000000000f more than once"},
+		{"invalid deposit portal address", []string{"--deposit-portal-address", "portal"},
+			`--deposit-portal-address is not an address: "portal"`},
+		{"DA fee without its contract", []string{"--enable-da-gas-price", "--ethereum-http-url", "http://127.0.0.1:8546",
+			"--da-fee-contract-address", ""},
+			"the DA fee contract address (--da-fee-contract-address)"},
+		{"L1 base fee without an epoch length", []string{"--enable-l1-base-fee", "--ethereum-http-url", "http://127.0.0.1:8546",
+			"--l1-base-fee-epoch-length-seconds", "0"},
+			"--l1-base-fee-epoch-length-seconds must be at least 1"},
+		{"scalar without a target", []string{"--enable-scalar"},
+			"--scalar must be at least 1"},
+		{"unknown observe-only channel", []string{"--observe-only", "l2-gas-pric"},
+			`--observe-only: unknown channel "l2-gas-pric"`},
+		{"unknown channel priority", []string{"--channel-priority", "l3-gas-price=1"},
+			`--channel-priority: unknown channel "l3-gas-price"`},
+		{"unknown grace mode", []string{"--enable-grace", "da-fee=slow"},
+			`--enable-grace: unknown grace mode of da-fee: "slow"`},
+		{"invalid receipt backoff", []string{"--receipt-backoff", "multiplier=0"},
+			"--receipt-backoff: "},
+		{"invalid private key", []string{"--private-key", "0x1234"},
+			"--private-key: "},
+		{"alert without a threshold", []string{"--alert-webhook-url", "http://127.0.0.1:9000", "--alert-failure-threshold", "0"},
+			"--alert-failure-threshold must be at least 1"},
+	} {
+		err := ValidateConfig(NewConfig(newTestContext(t, append(valid, test.args...)...)))
+		require.ErrorIs(t, err, errInvalidConfig, test.name)
+		require.Contains(t, err.Error(), test.problem, test.name)
+	}
+
+	// Every problem is reported, not only the first one
+	err := ValidateConfig(NewConfig(newTestContext(t, append(valid,
+		"--floor-price", "10",
+		"--ceiling-price", "5",
+		"--significant-factor", "-1",
+		"--enable-da-gas-price",
+		"--da-fee-contract-address", "",
+	)...)))
+	require.ErrorIs(t, err, errInvalidConfig)
+	problems := strings.Split(err.Error(), "\n")[1:]
+	require.Len(t, problems, 3, err.Error())
+	require.Contains(t, problems[0], "--ceiling-price")
+	require.Contains(t, problems[1], "--significant-factor")
+	require.Contains(t, problems[2], "--da-fee-contract-address")
+	// and the errors of the checks are kept
+	require.ErrorIs(t, err, errMissingChannelInput)
+
+	// The oracle is only created from a valid config
+	_, err = NewGasPriceOracle(NewConfig(newTestContext(t, append(valid, "--floor-price", "0")...)))
+	require.ErrorIs(t, err, errInvalidConfig)
+	require.Contains(t, err.Error(), "--floor-price must be at least 1")
+
+	// A typo in the observe-only channels refuses to start rather than
+	// writing every channel
+	_, err = NewGasPriceOracle(NewConfig(newTestContext(t, append(valid, "--observe-only", "l2-gas-price,da-fe")...)))
+	require.ErrorIs(t, err, errInvalidConfig)
+	require.Contains(t, err.Error(), `--observe-only: unknown channel "da-fe"`)
+}
@@ -0,0 +1,166 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WatchOptions configure Watch
+type WatchOptions struct {
+	// URL is the address of the metrics server of the oracle
+	URL string
+	// Interval is how often the summary is refreshed
+	Interval time.Duration
+	// Username, Password and Token authenticate with the metrics server
+	// when it requires it
+	Username string
+	Password string
+	Token    string
+}
+
+// channelValues are the keys of the decision values that the summary shows
+// for every channel: the value on chain and the value computed
+var channelValues = map[string]struct{ onChain, computed string }{
+	l1BaseFeeChannel:  {"current_l1_base_fee", "l1_base_fee"},
+	l2GasPriceChannel: {"current_gas_price", "gas_price"},
+	daFeeChannel:      {"current_da_fee", "da_fee"},
+	overheadChannel:   {"current_overhead", "overhead"},
+	scalarChannel:     {"current_scalar", "scalar"},
+}
+
+// Watch renders a summary of the state of a running oracle to w, refreshed
+// every interval until the context is done. A failed refresh is shown in
+// place of the summary and retried.
+func Watch(ctx context.Context, opts WatchOptions, w io.Writer) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		state, err := fetchState(ctx, client, opts)
+		// Clear the screen and move to its top left corner
+		fmt.Fprint(w, "\033[H\033[2J")
+		if err != nil {
+			fmt.Fprintf(w, "gas-oracle  %s  %s\n\ncannot read the state: %v\n",
+				opts.URL, time.Now().UTC().Format(time.RFC3339), err)
+		} else {
+			renderState(w, opts.URL, state, time.Now())
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// fetchState reads the state served on /state
+func fetchState(ctx context.Context, client *http.Client, opts WatchOptions) (State, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(opts.URL, "/")+"/state", nil)
+	if err != nil {
+		return State{}, err
+	}
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	} else if opts.Username != "" || opts.Password != "" {
+		req.SetBasicAuth(opts.Username, opts.Password)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return State{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return State{}, fmt.Errorf("state returned %s", res.Status)
+	}
+
+	var state State
+	dec := json.NewDecoder(res.Body)
+	// Keep the values exact, they do not fit a float64
+	dec.UseNumber()
+	if err := dec.Decode(&state); err != nil {
+		return State{}, fmt.Errorf("cannot decode the state: %w", err)
+	}
+	return state, nil
+}
+
+// renderState writes a single-screen summary of the state: a line per
+// channel with its values, the age of its last decision and that decision
+func renderState(w io.Writer, url string, state State, now time.Time) {
+	fmt.Fprintf(w, "gas-oracle  %s  %s\n\n", url, now.UTC().Format(time.RFC3339))
+
+	channels := make([]string, 0, len(state.Decisions))
+	for channel := range state.Decisions {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANNEL\tON CHAIN\tCOMPUTED\tAGE\tACTION\tREASON\tL1 BLOCK\tL2 BLOCK")
+	for _, channel := range channels {
+		decision := state.Decisions[channel]
+		keys := channelValues[channel]
+		reason := decision.ReasonCode
+		if decision.Reason != "" {
+			reason += ": " + decision.Reason
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			channel,
+			decisionValue(decision, keys.onChain),
+			decisionValue(decision, keys.computed),
+			now.Sub(decision.Time).Round(time.Second),
+			decision.Action,
+			reason,
+			blockNumber(decision.L1Block),
+			blockNumber(decision.L2Block))
+	}
+	if len(channels) == 0 {
+		fmt.Fprintln(tw, "no decisions yet")
+	}
+	tw.Flush()
+
+	if state.L1BaseFee != nil {
+		fmt.Fprintf(w, "\nL1 base fee %s", state.L1BaseFee)
+		if state.RawL1BaseFee != nil {
+			fmt.Fprintf(w, " from %s", state.RawL1BaseFee)
+		}
+		if state.EffectiveScalar != nil {
+			fmt.Fprintf(w, ", effective scalar %.4f", *state.EffectiveScalar)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(state.Observed) > 0 {
+		observed := make([]string, 0, len(state.Observed))
+		for channel := range state.Observed {
+			observed = append(observed, channel)
+		}
+		sort.Strings(observed)
+		fmt.Fprintf(w, "\nObserve-only: %s\n", strings.Join(observed, ", "))
+	}
+}
+
+// decisionValue returns the value of the key in the outputs of the
+// decision, or else in its inputs
+func decisionValue(decision Decision, key string) string {
+	if value, ok := decision.Outputs[key]; ok {
+		return fmt.Sprint(value)
+	}
+	if value, ok := decision.Inputs[key]; ok {
+		return fmt.Sprint(value)
+	}
+	return "-"
+}
+
+func blockNumber(number uint64) string {
+	if number == 0 {
+		return "-"
+	}
+	return fmt.Sprint(number)
+}
@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleState = `{
+  "l1_base_fee": 30000000000,
+  "raw_l1_base_fee": 25000000000,
+  "effective_scalar": 1.2,
+  "observed": {"da-fee": {"time": "2023-05-01T11:59:00Z", "channel": "da-fee", "inputs": {}, "outputs": {}, "action": "observe", "reason_code": "observe_only"}},
+  "blocks": {"l1-base-fee": {"l1": 17000000, "l2": 420}, "l2-gas-price": {"l2": 421}},
+  "decisions": {
+    "l2-gas-price": {
+      "time": "2023-05-01T11:59:55Z",
+      "channel": "l2-gas-price",
+      "l2_block": 421,
+      "inputs": {"current_gas_price": 1000000},
+      "outputs": {"gas_price": 1000000},
+      "action": "skip",
+      "reason_code": "unchanged",
+      "reason": "not changed"
+    },
+    "l1-base-fee": {
+      "time": "2023-05-01T11:59:48Z",
+      "channel": "l1-base-fee",
+      "l1_block": 17000000,
+      "l2_block": 420,
+      "inputs": {"current_l1_base_fee": 123456789012345678901, "l1_base_fee": 123456789012345678999},
+      "outputs": {"l1_base_fee": 30000000000, "tx_hash": "0x01"},
+      "action": "update",
+      "reason_code": "clamped"
+    },
+    "da-fee": {
+      "time": "2023-05-01T11:59:00Z",
+      "channel": "da-fee",
+      "inputs": {},
+      "outputs": {},
+      "action": "error",
+      "reason_code": "failed",
+      "reason": "connection refused"
+    }
+  }
+}`
+
+func TestRenderState(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(sampleState))
+	})
+	server := httptest.NewServer(ometrics.Authenticate(mux, "", "", "secret"))
+	defer server.Close()
+
+	// The token is required
+	client := &http.Client{}
+	_, err := fetchState(context.Background(), client, WatchOptions{URL: server.URL})
+	require.Error(t, err)
+	state, err := fetchState(context.Background(), client, WatchOptions{URL: server.URL + "/", Token: "secret"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	renderState(&buf, "http://oracle:6060", state, time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, strings.Join([]string{
+		"gas-oracle  http://oracle:6060  2023-05-01T12:00:00Z",
+		"",
+		"CHANNEL       ON CHAIN               COMPUTED     AGE   ACTION  REASON                      L1 BLOCK  L2 BLOCK",
+		"da-fee        -                      -            1m0s  error   failed: connection refused  -         -",
+		"l1-base-fee   123456789012345678901  30000000000  12s   update  clamped                     17000000  420",
+		"l2-gas-price  1000000                1000000      5s    skip    unchanged: not changed      -         421",
+		"",
+		"L1 base fee 30000000000 from 25000000000, effective scalar 1.2000",
+		"",
+		"Observe-only: da-fee",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestRenderEmptyState(t *testing.T) {
+	var buf bytes.Buffer
+	renderState(&buf, "http://oracle:6060", State{}, time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+	require.Contains(t, buf.String(), "no decisions yet")
+}
+
+func TestWatchRefreshes(t *testing.T) {
+	requests := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		_, _ = w.Write([]byte(sampleState))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	var buf bytes.Buffer
+	go func() {
+		done <- Watch(ctx, WatchOptions{URL: server.URL, Interval: 10 * time.Millisecond}, &buf)
+	}()
+	<-requests
+	<-requests
+	cancel()
+	require.NoError(t, <-done)
+	require.Contains(t, buf.String(), "l1-base-fee")
+}
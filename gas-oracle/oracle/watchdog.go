@@ -0,0 +1,150 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// watchdog restarts the loops that stopped making progress. A loop is
+// stalled when it has not finished an iteration within its interval plus
+// the timeout, for example because an update is blocked on a call that no
+// deadline covers. A goroutine cannot be killed, so the context of the
+// stalled loop is cancelled, which unblocks the calls that honor it, and a
+// fresh loop takes over once the stalled one has returned. Two generations
+// of a loop never run at once, as they share the state of their channel and
+// must not both send an update. A nil watchdog runs the loops unsupervised.
+type watchdog struct {
+	timeout time.Duration
+	now     func() time.Time
+
+	mu    sync.Mutex
+	loops map[string]*watchedLoop
+}
+
+// watchedLoop is a loop under supervision
+type watchedLoop struct {
+	parent     context.Context
+	interval   time.Duration
+	start      func(ctx context.Context, progress func())
+	cancel     context.CancelFunc
+	done       chan struct{}
+	restarting bool
+	generation int
+	last       time.Time
+}
+
+// newWatchdog creates a watchdog, or returns nil when the timeout is zero
+func newWatchdog(timeout time.Duration) *watchdog {
+	if timeout == 0 {
+		return nil
+	}
+	return &watchdog{
+		timeout: timeout,
+		now:     time.Now,
+		loops:   make(map[string]*watchedLoop),
+	}
+}
+
+// supervise runs the loop until the parent context is done, restarting it
+// whenever it stalls. The loop must call progress after every iteration
+// and return once its context is done.
+func (w *watchdog) supervise(parent context.Context, name string, interval time.Duration, start func(ctx context.Context, progress func())) {
+	if w == nil {
+		start(parent, func() {})
+		return
+	}
+
+	w.mu.Lock()
+	l := &watchedLoop{parent: parent, interval: interval, start: start}
+	w.loops[name] = l
+	w.launch(name, l)
+	w.mu.Unlock()
+
+	<-parent.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	l.cancel()
+	delete(w.loops, name)
+}
+
+// launch starts a new generation of the loop. The progress of the former
+// generations is ignored. It must be called with the lock held.
+func (w *watchdog) launch(name string, l *watchedLoop) {
+	ctx, cancel := context.WithCancel(l.parent)
+	l.cancel = cancel
+	l.generation++
+	l.last = w.now()
+
+	done := make(chan struct{})
+	l.done = done
+	generation := l.generation
+	go func() {
+		defer close(done)
+		l.start(ctx, func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if l.generation == generation {
+				l.last = w.now()
+			}
+		})
+	}()
+}
+
+// relaunch starts a new generation of the loop once the cancelled one has
+// returned, unless the loop is no longer supervised by then
+func (w *watchdog) relaunch(name string, l *watchedLoop, done <-chan struct{}) {
+	<-done
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	l.restarting = false
+	if l.parent.Err() != nil || w.loops[name] != l {
+		return
+	}
+	log.Info("Stalled loop returned, restarting", "loop", name)
+	w.launch(name, l)
+}
+
+// check cancels the stalled loops and restarts each one once it returns
+func (w *watchdog) check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	for name, l := range w.loops {
+		stalled := now.Sub(l.last)
+		if l.restarting || stalled <= l.interval+w.timeout {
+			continue
+		}
+		log.Error("Loop stalled, cancelling it", "loop", name, "since", l.last, "stalled", stalled)
+		watchdogRestartCounter(name).Inc(1)
+		l.cancel()
+		l.restarting = true
+		go w.relaunch(name, l, l.done)
+	}
+}
+
+// run checks the loops until the context is done
+func (w *watchdog) run(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	ticker := time.NewTicker(w.timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.check()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func watchdogRestartCounter(name string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("watchdog/"+metricName(name)+"/restarts", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdogRestartsStalledLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Unix(1_700_000_000, 0)
+	w := newWatchdog(time.Minute)
+	w.now = func() time.Time { return now }
+	advance := func(d time.Duration) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		now = now.Add(d)
+	}
+	deadline := newInputDeadline(0)
+	g := &GasPriceOracle{ctx: ctx, drainer: new(drainer), watchdog: w,
+		deadlines: map[string]*inputDeadline{"stalled": deadline}}
+
+	// The first update wedges until it is released, and tells whether its
+	// epoch was cancelled by then
+	release := make(chan struct{})
+	var calls, cancelled int32
+	update := func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+			if deadline.epoch().Err() != nil {
+				atomic.StoreInt32(&cancelled, 1)
+			}
+		}
+		return nil
+	}
+	go g.loop("stalled", 10*time.Millisecond, update)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+
+	// A loop within its interval plus the timeout is left alone
+	before := watchdogRestartCounter("stalled").Count()
+	advance(time.Minute)
+	w.check()
+	require.Equal(t, before, watchdogRestartCounter("stalled").Count())
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Past it the loop is cancelled, but not restarted while it still runs
+	advance(time.Second)
+	w.check()
+	require.Equal(t, before+1, watchdogRestartCounter("stalled").Count())
+	require.Never(t, func() bool { return atomic.LoadInt32(&calls) > 1 }, 50*time.Millisecond, time.Millisecond)
+	advance(time.Minute)
+	w.check()
+	require.Equal(t, before+1, watchdogRestartCounter("stalled").Count())
+
+	// Once it returns the loop is restarted and updates again
+	close(release)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) > 2 }, time.Second, time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&cancelled), "stalled epoch not cancelled")
+
+	// The restarted loop makes progress, so it is not restarted again
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.loops["stalled"].last.Equal(now)
+	}, time.Second, time.Millisecond)
+	advance(time.Minute)
+	w.check()
+	require.Equal(t, before+1, watchdogRestartCounter("stalled").Count())
+
+	require.Nil(t, newWatchdog(0))
+}
+
+func TestWatchdogDisabledRunsLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	g := &GasPriceOracle{ctx: ctx, drainer: new(drainer)}
+	done := make(chan struct{})
+	go func() {
+		g.loop("unsupervised", time.Millisecond, func() error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		})
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) > 0 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
@@ -0,0 +1,90 @@
+package tokenprice
+
+import (
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// Adaptive is the policy that adapts how often the price ratio is polled
+// to its volatility. A poll that moves the ratio by more than Threshold
+// halves the interval, down to Min, and a poll that does not doubles it,
+// up to Max. The zero value disables the policy, so that the ratio is
+// polled at the update frequency.
+type Adaptive struct {
+	// Min is the shortest interval, used while the ratio is volatile
+	Min time.Duration
+	// Max is the longest interval, used while the ratio is stable
+	Max time.Duration
+	// Threshold is the relative change of the ratio between two polls
+	// above which it is considered volatile
+	Threshold float64
+}
+
+// Enabled returns true when the polling interval adapts to volatility
+func (a Adaptive) Enabled() bool {
+	return a.Min > 0 && a.Max >= a.Min
+}
+
+// Next returns the interval that follows current after a poll that moved
+// the ratio by change, relative to the previous ratio
+func (a Adaptive) Next(current time.Duration, change float64) time.Duration {
+	if math.Abs(change) > a.Threshold {
+		return a.bound(current / 2)
+	}
+	return a.bound(current * 2)
+}
+
+func (a Adaptive) bound(interval time.Duration) time.Duration {
+	if interval < a.Min {
+		return a.Min
+	}
+	if interval > a.Max {
+		return a.Max
+	}
+	return interval
+}
+
+// SetAdaptive sets the policy that adapts the polling interval of the
+// price ratio to its volatility. Polling starts at the update frequency,
+// within the bounds of the policy.
+func (c *Client) SetAdaptive(adaptive Adaptive) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.adaptive = adaptive
+	c.interval = adaptive.bound(c.frequency)
+}
+
+// Interval returns how long the price ratio is cached for before it is
+// polled again
+func (c *Client) Interval() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pollInterval()
+}
+
+// pollInterval is Interval for the callers that hold the lock
+func (c *Client) pollInterval() time.Duration {
+	if !c.adaptive.Enabled() {
+		return c.frequency
+	}
+	return c.interval
+}
+
+// adapt moves the polling interval after a poll returned ratio. The caller
+// holds the lock.
+func (c *Client) adapt(previous, ratio float64) {
+	if !c.adaptive.Enabled() || previous <= 0 {
+		return
+	}
+	change := (ratio - previous) / previous
+	next := c.adaptive.Next(c.interval, change)
+	if next != c.interval {
+		log.Debug("Token price polling interval adapted", "change", change, "interval", next)
+	}
+	c.interval = next
+	metrics.GetOrRegisterGauge("token_price/poll_interval_ms", ometrics.DefaultRegistry).Update(next.Milliseconds())
+}
@@ -0,0 +1,94 @@
+package tokenprice
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptivePollingInterval(t *testing.T) {
+	metrics.Enabled = true
+	var down int32
+	prices := map[string]string{"ETHUSDT": "2000", "BITUSDT": "0.5"}
+	srv := newBybitServer(prices, &down)
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	client := NewClient(srv.URL, 60)
+	client.now = func() time.Time { return now }
+	client.SetAdaptive(Adaptive{
+		Min:       10 * time.Second,
+		Max:       4 * time.Minute,
+		Threshold: 0.01,
+	})
+	require.Equal(t, time.Minute, client.Interval())
+
+	_, err := client.Price()
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, client.Interval())
+
+	// Within the interval the cached ratio is served
+	prices["ETHUSDT"] = "2500"
+	now = now.Add(30 * time.Second)
+	price, err := client.Price()
+	require.NoError(t, err)
+	require.Equal(t, 4000.0, price.Ratio)
+
+	// A volatile price shortens the interval down to the minimum
+	for i, want := range []time.Duration{30 * time.Second, 15 * time.Second, 10 * time.Second, 10 * time.Second} {
+		prices["ETHUSDT"] = strconv.Itoa(2500 + 100*i)
+		now = now.Add(client.Interval())
+		_, err := client.Price()
+		require.NoError(t, err)
+		require.Equal(t, want, client.Interval(), "poll %d", i)
+	}
+
+	// A calm price lengthens it up to the maximum
+	for i, want := range []time.Duration{20 * time.Second, 40 * time.Second, 80 * time.Second, 160 * time.Second, 4 * time.Minute, 4 * time.Minute} {
+		now = now.Add(client.Interval())
+		_, err := client.Price()
+		require.NoError(t, err)
+		require.Equal(t, want, client.Interval(), "poll %d", i)
+	}
+	gauge := metrics.GetOrRegisterGauge("token_price/poll_interval_ms", ometrics.DefaultRegistry)
+	require.Equal(t, (4 * time.Minute).Milliseconds(), gauge.Value())
+}
+
+func TestAdaptiveDisabled(t *testing.T) {
+	client := NewClient("http://localhost", 60)
+	client.SetAdaptive(Adaptive{})
+	require.Equal(t, time.Minute, client.Interval())
+	client.adapt(4000, 8000)
+	require.Equal(t, time.Minute, client.Interval())
+}
+
+func TestAdaptiveIntervalConcurrent(t *testing.T) {
+	client := NewClientWithBackend(new(jumpBackend), 0)
+	client.SetAdaptive(Adaptive{Min: time.Nanosecond, Max: time.Microsecond, Threshold: 0.01})
+
+	// The interval is read by the other loops while a poll adapts it
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := client.Price()
+				require.NoError(t, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				interval := client.Interval()
+				require.True(t, interval >= time.Nanosecond && interval <= time.Microsecond, interval)
+			}
+		}()
+	}
+	wg.Wait()
+}
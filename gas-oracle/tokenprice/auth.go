@@ -0,0 +1,59 @@
+package tokenprice
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// recvWindow is how many milliseconds the exchange accepts a signed request
+// for after its timestamp
+const recvWindow = "5000"
+
+// Credentials are the API key and secret of an exchange account, whose
+// rate limits are higher than the ones of the public endpoints
+type Credentials struct {
+	Key    string
+	Secret string
+}
+
+// SetCredentials signs every request of the price source with the
+// credentials. Without credentials the requests are sent unsigned to the
+// public endpoints. Only the Bybit backend signs its requests.
+func (c *Client) SetCredentials(credentials Credentials) {
+	if backend, ok := c.backend.(signer); ok {
+		backend.setCredentials(credentials)
+	}
+}
+
+// signer is a backend whose requests can be signed with credentials
+type signer interface {
+	setCredentials(credentials Credentials)
+}
+
+func (b *BybitBackend) setCredentials(credentials Credentials) {
+	b.credentials = credentials
+}
+
+// sign adds the authentication headers of the exchange to a request with
+// the query, when credentials are set. The signature is the HMAC-SHA256,
+// keyed by the secret, of the timestamp, the API key, the receive window
+// and the query string.
+func (b *BybitBackend) sign(request *resty.Request, query url.Values) {
+	if b.credentials.Key == "" {
+		return
+	}
+	timestamp := strconv.FormatInt(b.now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(b.credentials.Secret))
+	mac.Write([]byte(timestamp + b.credentials.Key + recvWindow + query.Encode()))
+	request.SetHeaders(map[string]string{
+		"X-BAPI-API-KEY":     b.credentials.Key,
+		"X-BAPI-TIMESTAMP":   timestamp,
+		"X-BAPI-RECV-WINDOW": recvWindow,
+		"X-BAPI-SIGN":        hex.EncodeToString(mac.Sum(nil)),
+	})
+}
@@ -0,0 +1,46 @@
+package tokenprice
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedRequests(t *testing.T) {
+	var headers http.Header
+	var query string
+	prices := newBybitServer(map[string]string{"ETHUSDT": "2000"}, new(int32))
+	defer prices.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		query = r.URL.RawQuery
+		prices.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, 0)
+	client.backend.(*BybitBackend).now = func() time.Time { return time.UnixMilli(1_700_000_000_123) }
+	client.SetCredentials(Credentials{Key: "key", Secret: "secret"})
+	_, err := client.Query("ETHUSDT")
+	require.NoError(t, err)
+
+	require.Equal(t, "symbol=ETHUSDT", query)
+	require.Equal(t, "key", headers.Get("X-BAPI-API-KEY"))
+	require.Equal(t, "1700000000123", headers.Get("X-BAPI-TIMESTAMP"))
+	require.Equal(t, recvWindow, headers.Get("X-BAPI-RECV-WINDOW"))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000123" + "key" + recvWindow + query))
+	require.Equal(t, hex.EncodeToString(mac.Sum(nil)), headers.Get("X-BAPI-SIGN"))
+
+	// Without credentials the requests are unsigned
+	_, err = NewClient(srv.URL, 0).Query("ETHUSDT")
+	require.NoError(t, err)
+	require.Empty(t, headers.Get("X-BAPI-API-KEY"))
+	require.Empty(t, headers.Get("X-BAPI-SIGN"))
+}
@@ -0,0 +1,55 @@
+package tokenprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// BackendBybit reads the prices from the Bybit spot ticker
+	BackendBybit = "bybit"
+	// BackendBinance reads the prices from the Binance spot ticker
+	BackendBinance = "binance"
+)
+
+// ErrUnknownBackend represents the error when the backend name is not one
+// of the known backends
+var ErrUnknownBackend = errors.New("unknown token price backend")
+
+// Backend is an exchange that quotes the price of a pair, e.g. ETHUSDT
+type Backend interface {
+	GetPrice(ctx context.Context, pair string) (float64, error)
+}
+
+// NewBackend creates the backend of the name, bybit or binance, at the
+// remote HTTP url
+func NewBackend(name, url string) (Backend, error) {
+	switch name {
+	case BackendBybit:
+		return NewBybitBackend(url), nil
+	case BackendBinance:
+		return NewBinanceBackend(url), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, name)
+	}
+}
+
+// newHTTPClient creates the HTTP client of a backend, which fails the
+// responses with an error status
+func newHTTPClient(url string) *resty.Client {
+	client := resty.New()
+	client.SetHostURL(url)
+	client.OnAfterResponse(func(c *resty.Client, r *resty.Response) error {
+		statusCode := r.StatusCode()
+		if statusCode >= 400 {
+			method := r.Request.Method
+			url := r.Request.URL
+			return fmt.Errorf("%d cannot %s %s: %w", statusCode, method, url, errHTTPError)
+		}
+		return nil
+	})
+	return client
+}
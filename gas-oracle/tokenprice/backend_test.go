@@ -0,0 +1,94 @@
+package tokenprice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newCannedServer serves the canned body of the symbol at the path
+func newCannedServer(t *testing.T, path string, bodies map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, path, r.URL.Path)
+		body, ok := bodies[r.URL.Query().Get("symbol")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestBackends(t *testing.T) {
+	ctx := context.Background()
+	bybit := newCannedServer(t, "/spot/quote/v1/ticker/price", map[string]string{
+		"ETHUSDT": `{"retCode":0,"retMsg":"OK","result":{"symbol":"ETHUSDT","price":"2000.5"}}`,
+		"BITUSDT": `{"retCode":0,"retMsg":"OK","result":{"symbol":"BITUSDT","price":"0.5"}}`,
+	})
+	defer bybit.Close()
+	binance := newCannedServer(t, "/api/v3/ticker/price", map[string]string{
+		"ETHUSDT": `{"symbol":"ETHUSDT","price":"2000.50000000"}`,
+		"BITUSDT": `{"symbol":"BITUSDT","price":"0.50000000"}`,
+	})
+	defer binance.Close()
+
+	for _, test := range []struct {
+		name string
+		url  string
+	}{
+		{BackendBybit, bybit.URL},
+		{BackendBinance, binance.URL},
+	} {
+		backend, err := NewBackend(test.name, test.url)
+		require.NoError(t, err)
+		price, err := backend.GetPrice(ctx, "ETHUSDT")
+		require.NoError(t, err, test.name)
+		require.Equal(t, 2000.5, price, test.name)
+		_, err = backend.GetPrice(ctx, "NOPE")
+		require.ErrorIs(t, err, errHTTPError, test.name)
+
+		// The client computes the ratio the same way whatever the backend
+		client := NewClientWithBackend(backend, 0)
+		client.SetStrict(true)
+		ratio, err := client.PriceRatio()
+		require.NoError(t, err, test.name)
+		require.Equal(t, 4001.0, ratio, test.name)
+	}
+
+	_, err := NewBackend("kraken", "")
+	require.ErrorIs(t, err, ErrUnknownBackend)
+}
+
+func TestBinanceStrict(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		body   string
+		strict string
+		lax    string
+	}{
+		{`{"symbol":"BTCUSDT","price":"2000"}`, `symbol is "BTCUSDT"`, ""},
+		{`{"price":"2000"}`, "symbol is not a string", ""},
+		{`{"symbol":"ETHUSDT","lastPrice":"2000"}`, "missing price", "missing price"},
+		{`{"symbol":"ETHUSDT","price":2000}`, "price is not a string", "price is not a string"},
+		{`{"symbol":"ETHUSDT","price":"n/a"}`, "price is not numeric", "price is not numeric"},
+	} {
+		srv := newCannedServer(t, "/api/v3/ticker/price", map[string]string{"ETHUSDT": test.body})
+		backend := NewBinanceBackend(srv.URL)
+		_, err := backend.GetPrice(ctx, "ETHUSDT")
+		if test.lax == "" {
+			require.NoError(t, err, test.body)
+		} else {
+			require.ErrorContains(t, err, test.lax, test.body)
+		}
+		backend.SetStrict(true)
+		_, err = backend.GetPrice(ctx, "ETHUSDT")
+		require.ErrorIs(t, err, errMalformedResponse, test.body)
+		require.ErrorContains(t, err, test.strict, test.body)
+		srv.Close()
+	}
+}
@@ -0,0 +1,140 @@
+package tokenprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errNotFetched represents the error when a symbol has no price yet
+var errNotFetched = errors.New("token price not fetched yet")
+
+// Quote is the cached price of a single symbol
+type Quote struct {
+	Symbol string
+	Price  float64
+	// Updated is when the price was last fetched successfully
+	Updated time.Time
+	// Age is how long ago the price was fetched
+	Age time.Duration
+}
+
+// basket holds the cached prices of the tracked symbols. Every symbol is
+// refreshed on its own, so that a failing symbol does not hold back the
+// others.
+type basket struct {
+	mu      sync.Mutex
+	symbols []string
+	quotes  map[string]Quote
+}
+
+// SetSymbols sets the symbols that are tracked by Refresh, e.g. ETHUSDT
+// and MNTUSDT
+func (c *Client) SetSymbols(symbols ...string) {
+	c.basket.mu.Lock()
+	defer c.basket.mu.Unlock()
+	c.basket.symbols = append([]string(nil), symbols...)
+}
+
+// Symbols returns the tracked symbols
+func (c *Client) Symbols() []string {
+	c.basket.mu.Lock()
+	defer c.basket.mu.Unlock()
+	return append([]string(nil), c.basket.symbols...)
+}
+
+// Quote returns the price of the symbol, fetching it when the cached price
+// is older than the update frequency. When fetching fails the last price
+// is returned along with the error, so that the caller can decide whether
+// its age is acceptable.
+func (c *Client) Quote(symbol string) (Quote, error) {
+	now := c.now()
+	if quote, ok := c.cachedQuote(symbol, now); ok && quote.Age < c.frequency {
+		return quote, nil
+	}
+	ctx, cancel := c.tick()
+	defer cancel()
+	return c.refreshQuote(ctx, symbol)
+}
+
+// Refresh fetches the prices of all of the tracked symbols concurrently.
+// The symbols that fail keep their last price and are reported in the
+// returned error.
+func (c *Client) Refresh() error {
+	symbols := c.Symbols()
+	ctx, cancel := c.tick()
+	defer cancel()
+	errs := make([]error, len(symbols))
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			_, errs[i] = c.refreshQuote(ctx, symbol)
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", symbols[i], err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot refresh token prices: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// refreshQuote fetches the price of the symbol and caches it
+func (c *Client) refreshQuote(ctx context.Context, symbol string) (Quote, error) {
+	value, err := c.queryQuote(ctx, symbol)
+	now := c.now()
+	if err == nil {
+		quote := Quote{Symbol: symbol, Price: value, Updated: now}
+		c.basket.mu.Lock()
+		c.basket.quotes[symbol] = quote
+		c.basket.mu.Unlock()
+		metrics.GetOrRegisterGaugeFloat64("token_price/"+strings.ToLower(symbol), ometrics.DefaultRegistry).Update(value)
+		return quote, nil
+	}
+
+	quote, ok := c.cachedQuote(symbol, now)
+	if !ok {
+		return Quote{}, fmt.Errorf("%w: %s: %v", errNotFetched, symbol, err)
+	}
+	log.Warn("Cannot refresh token price", "symbol", symbol, "age", quote.Age, "message", err)
+	return quote, err
+}
+
+// queryQuote fetches the price of the symbol from the price source
+func (c *Client) queryQuote(ctx context.Context, symbol string) (float64, error) {
+	price, err := c.query(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	value, _ := price.Float64()
+	if value <= 0 {
+		return 0, fmt.Errorf("invalid %s price", symbol)
+	}
+	return value, nil
+}
+
+func (c *Client) cachedQuote(symbol string, now time.Time) (Quote, bool) {
+	c.basket.mu.Lock()
+	defer c.basket.mu.Unlock()
+	quote, ok := c.basket.quotes[symbol]
+	if !ok {
+		return Quote{}, false
+	}
+	quote.Age = now.Sub(quote.Updated)
+	return quote, true
+}
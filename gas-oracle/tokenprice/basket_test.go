@@ -0,0 +1,137 @@
+package tokenprice
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// symbolServer serves a price per symbol, each of which can be taken down
+// on its own, and counts the requests per symbol
+type symbolServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	prices   map[string]string
+	down     map[string]bool
+	requests map[string]int
+}
+
+func newSymbolServer(prices map[string]string) *symbolServer {
+	s := &symbolServer{
+		prices:   prices,
+		down:     make(map[string]bool),
+		requests: make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbol")
+		s.mu.Lock()
+		s.requests[symbol]++
+		down := s.down[symbol]
+		price := s.prices[symbol]
+		s.mu.Unlock()
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"retCode": 0,
+			"result":  map[string]string{"symbol": symbol, "price": price},
+		})
+	}))
+	return s
+}
+
+func (s *symbolServer) set(symbol, price string, down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[symbol] = price
+	s.down[symbol] = down
+}
+
+func (s *symbolServer) count(symbol string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[symbol]
+}
+
+func TestBasketIndependentRefresh(t *testing.T) {
+	srv := newSymbolServer(map[string]string{"ETHUSDT": "2000", "MNTUSDT": "0.5"})
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	client := NewClient(srv.URL, 60)
+	client.now = func() time.Time { return now }
+	client.SetSymbols("ETHUSDT", "MNTUSDT")
+
+	require.NoError(t, client.Refresh())
+	eth, err := client.Quote("ETHUSDT")
+	require.NoError(t, err)
+	require.Equal(t, 2000.0, eth.Price)
+	mnt, err := client.Quote("MNTUSDT")
+	require.NoError(t, err)
+	require.Equal(t, 0.5, mnt.Price)
+	// Fresh quotes come from the cache
+	require.Equal(t, 1, srv.count("ETHUSDT"))
+	require.Equal(t, 1, srv.count("MNTUSDT"))
+
+	// A failing symbol keeps its last price while the others refresh
+	now = now.Add(2 * time.Minute)
+	srv.set("ETHUSDT", "2100", false)
+	srv.set("MNTUSDT", "0.6", true)
+	require.Error(t, client.Refresh())
+
+	eth, err = client.Quote("ETHUSDT")
+	require.NoError(t, err)
+	require.Equal(t, 2100.0, eth.Price)
+	require.Equal(t, time.Duration(0), eth.Age)
+
+	mnt, err = client.Quote("MNTUSDT")
+	require.Error(t, err)
+	require.Equal(t, 0.5, mnt.Price)
+	require.Equal(t, 2*time.Minute, mnt.Age)
+
+	// Reading one symbol does not refresh the other
+	now = now.Add(2 * time.Minute)
+	srv.set("MNTUSDT", "0.6", false)
+	ethRequests := srv.count("ETHUSDT")
+	mnt, err = client.Quote("MNTUSDT")
+	require.NoError(t, err)
+	require.Equal(t, 0.6, mnt.Price)
+	require.Equal(t, ethRequests, srv.count("ETHUSDT"))
+
+	eth, err = client.Quote("ETHUSDT")
+	require.NoError(t, err)
+	require.Equal(t, ethRequests+1, srv.count("ETHUSDT"))
+	require.Equal(t, time.Duration(0), eth.Age)
+}
+
+func TestBasketSingleSymbol(t *testing.T) {
+	srv := newSymbolServer(map[string]string{"ETHUSDT": "2000", "BITUSDT": "0.5"})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, 60)
+	client.SetSymbols("ETHUSDT")
+	require.NoError(t, client.Refresh())
+	require.Equal(t, 0, srv.count("BITUSDT"))
+
+	// The ratio is unaffected by the tracked symbols
+	ratio, err := client.PriceRatio()
+	require.NoError(t, err)
+	require.Equal(t, 4000.0, ratio)
+}
+
+func TestQuoteNotFetched(t *testing.T) {
+	srv := newSymbolServer(map[string]string{})
+	defer srv.Close()
+	srv.set("MNTUSDT", "", true)
+
+	client := NewClient(srv.URL, 60)
+	_, err := client.Quote("MNTUSDT")
+	require.ErrorIs(t, err, errNotFetched)
+}
@@ -0,0 +1,84 @@
+package tokenprice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// BinanceBackend reads the prices from the Binance spot ticker
+type BinanceBackend struct {
+	client *resty.Client
+	strict bool
+}
+
+// NewBinanceBackend creates the backend of the Binance API at the remote
+// HTTP url
+func NewBinanceBackend(url string) *BinanceBackend {
+	return &BinanceBackend{client: newHTTPClient(url)}
+}
+
+// SetStrict validates every response against the ticker schema
+func (b *BinanceBackend) SetStrict(strict bool) {
+	b.strict = strict
+}
+
+// GetPrice returns the price of the pair
+func (b *BinanceBackend) GetPrice(ctx context.Context, pair string) (float64, error) {
+	response, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParam("symbol", pair).
+		Get("/api/v3/ticker/price")
+	if err != nil {
+		return 0, fmt.Errorf("cannot fetch token price result: %w", err)
+	}
+	price, err := parseBinanceTicker(response.Body(), pair, b.strict)
+	if err != nil {
+		if b.strict {
+			malformedCounter().Inc(1)
+			return 0, fmt.Errorf("%w: %s: %v", errMalformedResponse, pair, err)
+		}
+		return 0, err
+	}
+	value, _ := price.Float64()
+	return value, nil
+}
+
+// parseBinanceTicker parses a ticker response of Binance for the pair. The
+// response looks like
+//
+//	{"symbol": "ETHUSDT", "price": "1234.5"}
+//
+// and in strict mode the symbol must be the pair.
+func parseBinanceTicker(body []byte, pair string, strict bool) (*big.Float, error) {
+	var ticker map[string]json.RawMessage
+	if err := json.Unmarshal(body, &ticker); err != nil {
+		return nil, fmt.Errorf("not an object: %v", err)
+	}
+	if strict {
+		var got string
+		if err := json.Unmarshal(ticker["symbol"], &got); err != nil {
+			return nil, fmt.Errorf("symbol is not a string: %s", ticker["symbol"])
+		}
+		if got != pair {
+			return nil, fmt.Errorf("symbol is %q", got)
+		}
+	}
+	raw, ok := ticker["price"]
+	if !ok {
+		return nil, errors.New("missing price")
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("price is not a string: %s", raw)
+	}
+	price, ok := new(big.Float).SetString(value)
+	if !ok || price.IsInf() {
+		return nil, fmt.Errorf("price is not numeric: %q", value)
+	}
+	return price, nil
+}
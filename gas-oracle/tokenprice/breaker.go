@@ -0,0 +1,68 @@
+package tokenprice
+
+import (
+	"math"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// Breaker is the policy that rejects a price ratio which jumped
+// implausibly from the last accepted one, e.g. after a bad tick on a thin
+// order book. A rejected ratio is replaced by the last accepted one. After
+// ResetAfter consecutive rejections the breaker resets and accepts the new
+// ratio, so that a genuine large move still goes through. The zero value
+// disables the policy.
+type Breaker struct {
+	// MaxDeviation is the largest change from the last accepted ratio, in
+	// percent, that is accepted
+	MaxDeviation float64
+	// ResetAfter is the number of consecutive rejections after which the
+	// new ratio is accepted, 0 never accepts it
+	ResetAfter int
+}
+
+// Enabled returns true when the ratios are checked for jumps
+func (b Breaker) Enabled() bool {
+	return b.MaxDeviation > 0
+}
+
+// Deviation returns the change from previous to ratio in percent
+func (b Breaker) Deviation(previous, ratio float64) float64 {
+	return math.Abs(ratio-previous) / previous * 100
+}
+
+// SetBreaker sets the policy that rejects implausible jumps of the price
+// ratio
+func (c *Client) SetBreaker(breaker Breaker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.breaker = breaker
+	c.rejections = 0
+}
+
+// accept returns true when the ratio may replace the last accepted ratio.
+// A rejection is logged and counted in `token_price/breaker/rejections`.
+// The caller holds the lock.
+func (c *Client) accept(ratio float64) bool {
+	if !c.breaker.Enabled() || c.lastRatio <= 0 {
+		return true
+	}
+	deviation := c.breaker.Deviation(c.lastRatio, ratio)
+	if deviation <= c.breaker.MaxDeviation {
+		c.rejections = 0
+		return true
+	}
+	c.rejections++
+	if c.breaker.ResetAfter > 0 && c.rejections >= c.breaker.ResetAfter {
+		log.Warn("Token price breaker reset, accepting the price", "ratio", ratio,
+			"last", c.lastRatio, "deviation", deviation, "rejections", c.rejections)
+		c.rejections = 0
+		return true
+	}
+	log.Warn("Token price rejected by the breaker, keeping the last price", "ratio", ratio,
+		"last", c.lastRatio, "deviation", deviation, "max", c.breaker.MaxDeviation, "rejections", c.rejections)
+	metrics.GetOrRegisterCounter("token_price/breaker/rejections", ometrics.DefaultRegistry).Inc(1)
+	return false
+}
@@ -0,0 +1,143 @@
+package tokenprice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// pairBackend quotes the prices of the pairs
+type pairBackend map[string]float64
+
+func (p pairBackend) GetPrice(ctx context.Context, pair string) (float64, error) {
+	return p[pair], nil
+}
+
+func TestBreaker(t *testing.T) {
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+	rejections := metrics.GetOrRegisterCounter("token_price/breaker/rejections", ometrics.DefaultRegistry)
+	rejected := rejections.Count()
+
+	now := time.Unix(1_700_000_000, 0)
+	prices := pairBackend{"ETHUSDT": 2000, "BITUSDT": 0.5}
+	client := NewClientWithBackend(prices, 60)
+	client.now = func() time.Time { return now }
+	client.SetBreaker(Breaker{MaxDeviation: 50, ResetAfter: 3})
+	tick := func() float64 {
+		now = now.Add(time.Minute)
+		ratio, err := client.PriceRatio()
+		require.NoError(t, err)
+		return ratio
+	}
+
+	require.Equal(t, 4000.0, tick())
+
+	// Normal movement goes through
+	prices["ETHUSDT"] = 2400
+	require.Equal(t, 4800.0, tick())
+	prices["ETHUSDT"] = 1500
+	require.Equal(t, 3000.0, tick())
+	require.Equal(t, rejected, rejections.Count())
+
+	// A jump keeps the last price
+	prices["ETHUSDT"] = 15000
+	require.Equal(t, 3000.0, tick())
+	require.Equal(t, rejected+1, rejections.Count())
+	latest, _ := client.Latest()
+	require.Equal(t, 3000.0, latest)
+	// and a single bad tick is forgotten once the price is back
+	prices["ETHUSDT"] = 1500
+	require.Equal(t, 3000.0, tick())
+	require.Equal(t, 0, client.rejections)
+
+	// A move that lasts goes through after the consecutive rejections
+	prices["ETHUSDT"] = 15000
+	require.Equal(t, 3000.0, tick())
+	require.Equal(t, 3000.0, tick())
+	require.Equal(t, 30000.0, tick())
+	require.Equal(t, rejected+3, rejections.Count())
+	require.Equal(t, 30000.0, tick())
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	prices := pairBackend{"ETHUSDT": 2000, "BITUSDT": 0.5}
+	client := NewClientWithBackend(prices, 60)
+	client.now = func() time.Time { return now }
+
+	_, err := client.PriceRatio()
+	require.NoError(t, err)
+	prices["ETHUSDT"] = 20000
+	now = now.Add(time.Minute)
+	ratio, err := client.PriceRatio()
+	require.NoError(t, err)
+	require.Equal(t, 40000.0, ratio)
+
+	// A breaker that never resets holds the last price
+	client.SetBreaker(Breaker{MaxDeviation: 10})
+	prices["ETHUSDT"] = 2000
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Minute)
+		ratio, err = client.PriceRatio()
+		require.NoError(t, err)
+		require.Equal(t, 40000.0, ratio)
+	}
+}
+
+// jumpBackend quotes a first ETH price and then a much higher one
+type jumpBackend struct {
+	calls int32
+}
+
+func (b *jumpBackend) GetPrice(ctx context.Context, pair string) (float64, error) {
+	if pair == "BITUSDT" {
+		return 0.5, nil
+	}
+	if atomic.AddInt32(&b.calls, 1) == 1 {
+		return 2000, nil
+	}
+	return 15000, nil
+}
+
+func TestBreakerConcurrent(t *testing.T) {
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+	rejections := metrics.GetOrRegisterCounter("token_price/breaker/rejections", ometrics.DefaultRegistry)
+	rejected := rejections.Count()
+
+	// Every call polls, as the channels do once their cache expires
+	client := NewClientWithBackend(new(jumpBackend), 0)
+	client.SetBreaker(Breaker{MaxDeviation: 50, ResetAfter: 5})
+	_, err := client.PriceRatio()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := client.PriceRatio()
+				require.NoError(t, err)
+				// The state endpoint reads the latest ratio meanwhile
+				_, at := client.Latest()
+				require.False(t, at.IsZero())
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The jump is rejected exactly until the breaker resets, however the
+	// calls interleave
+	require.Equal(t, rejected+4, rejections.Count())
+	ratio, err := client.PriceRatio()
+	require.NoError(t, err)
+	require.Equal(t, 30000.0, ratio)
+}
@@ -0,0 +1,77 @@
+package tokenprice
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// BybitBackend reads the prices from the Bybit spot ticker
+type BybitBackend struct {
+	client *resty.Client
+	strict bool
+	now    func() time.Time
+	// credentials sign the requests when set
+	credentials Credentials
+}
+
+// NewBybitBackend creates the backend of the Bybit API at the remote HTTP
+// url
+func NewBybitBackend(url string) *BybitBackend {
+	return &BybitBackend{client: newHTTPClient(url), now: time.Now}
+}
+
+type TokenPrice struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+type Result struct {
+	RetCode int
+	Result  TokenPrice
+}
+
+// SetStrict validates every response against the ticker schema
+func (b *BybitBackend) SetStrict(strict bool) {
+	b.strict = strict
+}
+
+// GetPrice returns the price of the pair
+func (b *BybitBackend) GetPrice(ctx context.Context, pair string) (float64, error) {
+	query := url.Values{"symbol": {pair}}
+	request := b.client.R().SetContext(ctx).SetQueryParamsFromValues(query)
+	b.sign(request, query)
+	// The strict parser reads the raw body
+	if !b.strict {
+		request.SetResult(&Result{})
+	}
+	response, err := request.Get("/spot/quote/v1/ticker/price")
+	if err != nil {
+		return 0, fmt.Errorf("cannot fetch token price result: %w", err)
+	}
+	if b.strict {
+		price, err := parseTicker(response.Body(), pair)
+		if err != nil {
+			return 0, err
+		}
+		value, _ := price.Float64()
+		return value, nil
+	}
+	result, ok := response.Result().(*Result)
+	if !ok {
+		return 0, fmt.Errorf("cannot parse result")
+	}
+	if result.Result.Price == "" {
+		return 0, fmt.Errorf("empty price")
+	}
+	bigPrice, ok := big.NewFloat(0).SetString(result.Result.Price)
+	if !ok {
+		return 0, fmt.Errorf("cannot parse price %q", result.Result.Price)
+	}
+	value, _ := bigPrice.Float64()
+	return value, nil
+}
@@ -0,0 +1,57 @@
+package tokenprice
+
+import (
+	"math"
+	"time"
+)
+
+// Decay is the policy applied to the last known price ratio while the
+// price source is failing. The ratio is held, with a conservative margin
+// that grows with the duration of the outage, until MaxStale is exceeded
+// after which no ratio is returned at all. The zero value disables the
+// policy, so that a failing price source is an error right away.
+type Decay struct {
+	// MaxStale is the longest outage that the last ratio is used for
+	MaxStale time.Duration
+	// MarginPerMinute is the fraction added to the margin for every
+	// minute of outage
+	MarginPerMinute float64
+}
+
+// Enabled returns true when the last ratio may be used during an outage
+func (d Decay) Enabled() bool {
+	return d.MaxStale > 0
+}
+
+// Margin returns the conservative margin to apply to a ratio that is
+// stale by the given duration. A margin of 0.1 raises the ratio by 10%,
+// which makes fees priced through the ratio more expensive rather than
+// cheaper while the actual price is unknown.
+func (d Decay) Margin(stale time.Duration) float64 {
+	return d.MarginPerMinute * stale.Minutes()
+}
+
+// Confidence goes from 1 for a fresh ratio down to 0 for a ratio that is
+// stale by MaxStale
+func (d Decay) Confidence(stale time.Duration) float64 {
+	if !d.Enabled() {
+		if stale > 0 {
+			return 0
+		}
+		return 1
+	}
+	return math.Max(0, 1-float64(stale)/float64(d.MaxStale))
+}
+
+// Price is a price ratio along with how much it can be trusted
+type Price struct {
+	// Ratio is the ETH/BIT price ratio, including Margin
+	Ratio float64
+	// Stale is how long the price source has been failing for
+	Stale time.Duration
+	// Margin is the conservative margin included in Ratio
+	Margin float64
+	// Confidence is 1 for a fresh ratio and decreases towards 0 the
+	// longer the price source is failing
+	Confidence float64
+}
@@ -0,0 +1,110 @@
+package tokenprice
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBybitServer serves the bybit ticker price endpoint. While down is
+// set every request fails.
+func newBybitServer(prices map[string]string, down *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		symbol := r.URL.Query().Get("symbol")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"retCode": 0,
+			"result":  map[string]string{"symbol": symbol, "price": prices[symbol]},
+		})
+	}))
+}
+
+func TestPriceDecayDuringOutage(t *testing.T) {
+	var down int32
+	srv := newBybitServer(map[string]string{"ETHUSDT": "2000", "BITUSDT": "0.5"}, &down)
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	client := NewClient(srv.URL, 60)
+	client.now = func() time.Time { return now }
+	client.SetDecay(Decay{
+		MaxStale:        5 * time.Minute,
+		MarginPerMinute: 0.02,
+	})
+
+	price, err := client.Price()
+	require.NoError(t, err)
+	require.Equal(t, 4000.0, price.Ratio)
+	require.Equal(t, 1.0, price.Confidence)
+
+	atomic.StoreInt32(&down, 1)
+
+	// Every epoch of the outage widens the margin and lowers the confidence
+	lastMargin := 0.0
+	lastConfidence := 1.0
+	for minute := 1; minute <= 5; minute++ {
+		now = now.Add(time.Minute)
+		price, err := client.Price()
+		require.NoError(t, err, "minute %d", minute)
+		require.Greater(t, price.Margin, lastMargin)
+		require.Less(t, price.Confidence, lastConfidence)
+		require.InDelta(t, 0.02*float64(minute), price.Margin, 1e-9)
+		require.InDelta(t, 4000*(1+price.Margin), price.Ratio, 1e-9)
+		lastMargin = price.Margin
+		lastConfidence = price.Confidence
+	}
+	require.InDelta(t, 0, lastConfidence, 1e-9)
+
+	// Past the max staleness the price is not used anymore
+	now = now.Add(time.Minute)
+	_, err = client.Price()
+	require.Error(t, err)
+	_, err = client.PriceRatio()
+	require.Error(t, err)
+
+	// Once the source recovers the fresh price is used without a margin
+	atomic.StoreInt32(&down, 0)
+	now = now.Add(time.Minute)
+	price, err = client.Price()
+	require.NoError(t, err)
+	require.Equal(t, 4000.0, price.Ratio)
+	require.Equal(t, 0.0, price.Margin)
+}
+
+func TestPriceDecayDisabled(t *testing.T) {
+	var down int32
+	srv := newBybitServer(map[string]string{"ETHUSDT": "2000", "BITUSDT": "0.5"}, &down)
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	client := NewClient(srv.URL, 60)
+	client.now = func() time.Time { return now }
+
+	_, err := client.PriceRatio()
+	require.NoError(t, err)
+
+	// Without a decay policy a failing source is an error right away
+	atomic.StoreInt32(&down, 1)
+	now = now.Add(time.Minute)
+	_, err = client.PriceRatio()
+	require.Error(t, err)
+}
+
+func TestDecayConfidence(t *testing.T) {
+	d := Decay{MaxStale: 10 * time.Minute, MarginPerMinute: 0.01}
+	require.Equal(t, 1.0, d.Confidence(0))
+	require.InDelta(t, 0.5, d.Confidence(5*time.Minute), 1e-9)
+	require.Equal(t, 0.0, d.Confidence(20*time.Minute))
+	require.True(t, math.Abs(d.Margin(30*time.Second)-0.005) < 1e-9)
+	require.False(t, Decay{}.Enabled())
+}
@@ -0,0 +1,135 @@
+package tokenprice
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Limiter spaces the requests to the exchanges evenly at a rate, holding
+// them back rather than dropping them. A single Limiter is shared by all of
+// the backends so that their requests add up to the rate, the retries
+// included. A nil Limiter does not limit.
+type Limiter struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu sync.Mutex
+	// next is the earliest time that the next request may be sent at
+	next time.Time
+}
+
+// NewLimiter creates a Limiter of rate requests per second, or returns nil
+// when the rate is not positive
+func NewLimiter(rate float64) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &Limiter{interval: time.Duration(float64(time.Second) / rate), now: time.Now}
+}
+
+// Wait blocks until a request may be sent, or returns the error of the
+// context when it is done first
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := l.now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := slot.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the slot back when no later request took one since
+		l.mu.Lock()
+		if l.next.Equal(slot.Add(l.interval)) {
+			l.next = slot
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// SetLimiter holds every request of the price source back to the rate of
+// the limiter
+func (c *Client) SetLimiter(limiter *Limiter) {
+	if backend, ok := c.backend.(limited); ok {
+		backend.setLimiter(limiter)
+	}
+}
+
+// limited is a backend whose requests can be rate limited
+type limited interface {
+	setLimiter(limiter *Limiter)
+}
+
+func (b *BybitBackend) setLimiter(limiter *Limiter) {
+	setLimitTransport(b.client, limiter)
+}
+
+func (b *BinanceBackend) setLimiter(limiter *Limiter) {
+	setLimitTransport(b.client, limiter)
+}
+
+func (m *MedianPricer) setLimiter(limiter *Limiter) {
+	for _, backend := range m.backends {
+		if backend, ok := backend.(limited); ok {
+			backend.setLimiter(limiter)
+		}
+	}
+}
+
+// setLimitTransport limits the requests of the client, replacing the limit
+// that was set before. The limit is applied below the retries so that every
+// attempt waits for its turn.
+func setLimitTransport(client *resty.Client, limiter *Limiter) {
+	transport := client.GetClient().Transport
+	retry, retried := transport.(*retryTransport)
+	if retried {
+		transport = retry.next
+	}
+	if limit, ok := transport.(*limitTransport); ok {
+		transport = limit.next
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if limiter != nil {
+		transport = &limitTransport{next: transport, limiter: limiter}
+	}
+	if retried {
+		retry.next = transport
+		return
+	}
+	client.SetTransport(transport)
+}
+
+// limitTransport is a RoundTripper that waits for the limiter before every
+// request
+type limitTransport struct {
+	next    http.RoundTripper
+	limiter *Limiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
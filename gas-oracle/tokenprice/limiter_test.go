@@ -0,0 +1,79 @@
+package tokenprice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterSmoothsBursts(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"symbol":"ETHUSDT","price":"2000.5"}`))
+	}))
+	defer server.Close()
+
+	// Two backends share the limiter, retries included
+	limiter := NewLimiter(50)
+	backends := []*BinanceBackend{NewBinanceBackend(server.URL), NewBinanceBackend(server.URL)}
+	backends[0].setRetry(testRetry)
+	for _, backend := range backends {
+		backend.setLimiter(limiter)
+	}
+	backends[1].setRetry(testRetry)
+
+	// A burst of requests is spread out at the rate instead of being sent
+	// at once
+	const requests = 6
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(backend *BinanceBackend) {
+			defer wg.Done()
+			_, err := backend.GetPrice(context.Background(), "ETHUSDT")
+			require.NoError(t, err)
+		}(backends[i%2])
+	}
+	wg.Wait()
+
+	require.Len(t, arrivals, requests)
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+	// The requests are 20ms apart, less some scheduling slack
+	require.GreaterOrEqual(t, arrivals[requests-1].Sub(arrivals[0]), (requests-1)*15*time.Millisecond)
+}
+
+func TestLimiterWaitCancel(t *testing.T) {
+	limiter := NewLimiter(1)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	// A request that cannot wait for its turn gives it back
+	next := limiter.next
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+	require.Equal(t, next, limiter.next)
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	require.Nil(t, NewLimiter(0))
+	var limiter *Limiter
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	// and setting it removes the limit of a backend
+	backend := NewBinanceBackend("http://127.0.0.1")
+	transport := backend.client.GetClient().Transport
+	backend.setLimiter(NewLimiter(10))
+	require.IsType(t, &limitTransport{}, backend.client.GetClient().Transport)
+	backend.setLimiter(nil)
+	require.Same(t, transport, backend.client.GetClient().Transport)
+}
@@ -0,0 +1,131 @@
+package tokenprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// AggregateMedian combines the prices of the backends into their median
+	AggregateMedian = "median"
+	// AggregateMean combines the prices of the backends into their mean
+	AggregateMean = "mean"
+)
+
+var (
+	// ErrNoQuorum represents the error when fewer backends than the quorum
+	// returned a price
+	ErrNoQuorum = errors.New("token price quorum not met")
+	// ErrUnknownAggregation represents the error when the aggregation is not
+	// median or mean
+	ErrUnknownAggregation = errors.New("unknown token price aggregation")
+)
+
+// MedianPricer is a Backend that queries several backends at once and
+// combines their prices, so that one exchange quoting a bad price does not
+// move the ratio. The backends that fail are discarded, and the price is
+// only returned when at least a quorum of them succeeded.
+type MedianPricer struct {
+	backends []Backend
+	quorum   int
+	mean     bool
+}
+
+// NewMedianPricer creates a MedianPricer over the backends, which needs the
+// quorum of them to return a price. The prices are combined by aggregation,
+// median or mean.
+func NewMedianPricer(backends []Backend, quorum int, aggregation string) (*MedianPricer, error) {
+	if quorum < 1 || quorum > len(backends) {
+		return nil, fmt.Errorf("%w: quorum of %d out of %d backends", ErrNoQuorum, quorum, len(backends))
+	}
+	m := &MedianPricer{
+		backends: backends,
+		quorum:   quorum,
+	}
+	switch aggregation {
+	case AggregateMedian:
+	case AggregateMean:
+		m.mean = true
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAggregation, aggregation)
+	}
+	return m, nil
+}
+
+// GetPrice queries the pair from every backend concurrently and returns the
+// median, or the mean, of the prices of the backends that succeeded
+func (m *MedianPricer) GetPrice(ctx context.Context, pair string) (float64, error) {
+	prices := make([]float64, len(m.backends))
+	errs := make([]error, len(m.backends))
+	var wg sync.WaitGroup
+	for i, backend := range m.backends {
+		wg.Add(1)
+		go func(i int, backend Backend) {
+			defer wg.Done()
+			prices[i], errs[i] = backend.GetPrice(ctx, pair)
+		}(i, backend)
+	}
+	wg.Wait()
+
+	healthy := make([]float64, 0, len(m.backends))
+	var lastErr error
+	for i, err := range errs {
+		if err != nil {
+			log.Warn("Discarding token price backend", "backend", i, "pair", pair, "message", err)
+			lastErr = err
+			continue
+		}
+		healthy = append(healthy, prices[i])
+	}
+	if len(healthy) < m.quorum {
+		return 0, fmt.Errorf("%w: %d of %d backends priced %s, need %d: %v",
+			ErrNoQuorum, len(healthy), len(m.backends), pair, m.quorum, lastErr)
+	}
+	if m.mean {
+		return mean(healthy), nil
+	}
+	return median(healthy), nil
+}
+
+// SetStrict sets the strict mode of the backends that support it
+func (m *MedianPricer) SetStrict(strict bool) {
+	for _, backend := range m.backends {
+		if backend, ok := backend.(interface{ SetStrict(bool) }); ok {
+			backend.SetStrict(strict)
+		}
+	}
+}
+
+// setCredentials sets the credentials of the backends that sign their
+// requests
+func (m *MedianPricer) setCredentials(credentials Credentials) {
+	for _, backend := range m.backends {
+		if backend, ok := backend.(signer); ok {
+			backend.setCredentials(credentials)
+		}
+	}
+}
+
+// median returns the middle of the values, or the mean of the two middle
+// values for an even count. The values are sorted in place.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
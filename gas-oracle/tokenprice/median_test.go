@@ -0,0 +1,104 @@
+package tokenprice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fixedBackend quotes the same price, or fails, for every pair
+type fixedBackend struct {
+	price float64
+	err   error
+}
+
+func (f fixedBackend) GetPrice(ctx context.Context, pair string) (float64, error) {
+	return f.price, f.err
+}
+
+func TestMedianPricer(t *testing.T) {
+	ctx := context.Background()
+	down := fixedBackend{err: errors.New("exchange down")}
+
+	for _, test := range []struct {
+		name        string
+		backends    []Backend
+		quorum      int
+		aggregation string
+		price       float64
+		err         error
+	}{
+		{
+			name:        "all agree",
+			backends:    []Backend{fixedBackend{price: 2000}, fixedBackend{price: 2000}, fixedBackend{price: 2000}},
+			quorum:      3,
+			aggregation: AggregateMedian,
+			price:       2000,
+		},
+		{
+			name:        "outlier ignored by the median",
+			backends:    []Backend{fixedBackend{price: 2000}, fixedBackend{price: 20}, fixedBackend{price: 2002}},
+			quorum:      2,
+			aggregation: AggregateMedian,
+			price:       2000,
+		},
+		{
+			name:        "outlier moves the mean",
+			backends:    []Backend{fixedBackend{price: 2000}, fixedBackend{price: 20}, fixedBackend{price: 2002}},
+			quorum:      2,
+			aggregation: AggregateMean,
+			price:       1340.6666666666667,
+		},
+		{
+			name:        "even count takes the middle mean",
+			backends:    []Backend{fixedBackend{price: 2000}, fixedBackend{price: 2004}},
+			quorum:      1,
+			aggregation: AggregateMedian,
+			price:       2002,
+		},
+		{
+			name:        "failing backend discarded",
+			backends:    []Backend{fixedBackend{price: 2000}, down, fixedBackend{price: 2004}},
+			quorum:      2,
+			aggregation: AggregateMedian,
+			price:       2002,
+		},
+		{
+			name:        "quorum not met",
+			backends:    []Backend{fixedBackend{price: 2000}, down, down},
+			quorum:      2,
+			aggregation: AggregateMedian,
+			err:         ErrNoQuorum,
+		},
+	} {
+		pricer, err := NewMedianPricer(test.backends, test.quorum, test.aggregation)
+		require.NoError(t, err, test.name)
+		price, err := pricer.GetPrice(ctx, "ETHUSDT")
+		if test.err != nil {
+			require.ErrorIs(t, err, test.err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.price, price, test.name)
+	}
+}
+
+func TestNewMedianPricer(t *testing.T) {
+	backends := []Backend{fixedBackend{price: 1}, fixedBackend{price: 2}}
+
+	_, err := NewMedianPricer(backends, 0, AggregateMedian)
+	require.ErrorIs(t, err, ErrNoQuorum)
+	_, err = NewMedianPricer(backends, 3, AggregateMedian)
+	require.ErrorIs(t, err, ErrNoQuorum)
+	_, err = NewMedianPricer(backends, 2, "mode")
+	require.ErrorIs(t, err, ErrUnknownAggregation)
+
+	// The client computes the ratio from the combined prices
+	pricer, err := NewMedianPricer([]Backend{fixedBackend{price: 4}, fixedBackend{price: 2}, fixedBackend{price: 400}}, 2, AggregateMedian)
+	require.NoError(t, err)
+	ratio, err := NewClientWithBackend(pricer, 0).PriceRatio()
+	require.NoError(t, err)
+	require.Equal(t, 1.0, ratio)
+}
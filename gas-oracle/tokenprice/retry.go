@@ -0,0 +1,130 @@
+package tokenprice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/go-resty/resty/v2"
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// Retry is how the requests to the exchanges are retried. A request that
+// fails with a network error, a 429 or a 5xx is retried up to MaxRetries
+// times, waiting the delays of Policy in between. Deadline bounds every
+// tick, all of its requests and retries included, 0 leaves it unbounded.
+type Retry struct {
+	Policy     backoff.Policy
+	MaxRetries int
+	Deadline   time.Duration
+}
+
+// SetRetry retries the failed requests of the backends according to retry
+func (c *Client) SetRetry(retry Retry) {
+	c.deadline = retry.Deadline
+	if backend, ok := c.backend.(retrier); ok {
+		backend.setRetry(retry)
+	}
+}
+
+// retrier is a backend whose requests can be retried
+type retrier interface {
+	setRetry(retry Retry)
+}
+
+func (b *BybitBackend) setRetry(retry Retry) {
+	setRetryTransport(b.client, retry)
+}
+
+func (b *BinanceBackend) setRetry(retry Retry) {
+	setRetryTransport(b.client, retry)
+}
+
+func (m *MedianPricer) setRetry(retry Retry) {
+	for _, backend := range m.backends {
+		if backend, ok := backend.(retrier); ok {
+			backend.setRetry(retry)
+		}
+	}
+}
+
+// tick returns the context of a tick, which is done once the deadline
+// passes
+func (c *Client) tick() (context.Context, context.CancelFunc) {
+	if c.deadline <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.deadline)
+}
+
+// setRetryTransport retries the requests of the client, replacing the
+// retries that were set before
+func setRetryTransport(client *resty.Client, retry Retry) {
+	next := client.GetClient().Transport
+	if transport, ok := next.(*retryTransport); ok {
+		next = transport.next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.SetTransport(&retryTransport{next: next, retry: retry})
+}
+
+// retryTransport is a RoundTripper that retries the requests that failed
+// transiently. The waits between the attempts end early when the context
+// of the request is done, so that a retried request never outlives it.
+type retryTransport struct {
+	next  http.RoundTripper
+	retry Retry
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A request with a body that cannot be rewound is sent once
+	if req.Body != nil && req.GetBody == nil {
+		return t.next.RoundTrip(req)
+	}
+	delays := t.retry.Policy.New()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := t.next.RoundTrip(req)
+		if !retryable(resp, err) || attempt >= t.retry.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		reason := "network error"
+		if err == nil {
+			reason = resp.Status
+			resp.Body.Close()
+		}
+		delay := delays.Next()
+		log.Debug("Retrying token price request", "url", req.URL.Path, "attempt", attempt+1,
+			"delay", delay, "reason", reason, "message", err)
+		metrics.GetOrRegisterCounter("token_price/retries", ometrics.DefaultRegistry).Inc(1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryable returns true for the failures that may pass on their own: the
+// network errors, rate limiting and the server errors
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
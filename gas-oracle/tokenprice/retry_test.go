@@ -0,0 +1,94 @@
+package tokenprice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mantlenetworkio/mantle/gas-oracle/backoff"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer fails the first requests with the statuses in order and
+// answers the next ones with a Binance ticker
+func newFlakyServer(statuses ...int) (*httptest.Server, *int32) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(atomic.AddInt32(&attempts, 1)) - 1
+		if attempt < len(statuses) {
+			w.WriteHeader(statuses[attempt])
+			return
+		}
+		_, _ = w.Write([]byte(`{"symbol":"ETHUSDT","price":"2000.5"}`))
+	}))
+	return server, &attempts
+}
+
+var testRetry = Retry{
+	Policy:     backoff.Policy{Initial: time.Millisecond, Max: 4 * time.Millisecond, Multiplier: 2, Jitter: 0.2},
+	MaxRetries: 3,
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		name     string
+		statuses []int
+		attempts int32
+		ok       bool
+	}{
+		{"success after two 503s", []int{503, 503}, 3, true},
+		{"rate limited", []int{429}, 2, true},
+		{"retries exhausted", []int{500, 502, 503, 504, 500}, 4, false},
+		{"client error not retried", []int{400}, 1, false},
+	} {
+		server, attempts := newFlakyServer(test.statuses...)
+		backend := NewBinanceBackend(server.URL)
+		backend.setRetry(testRetry)
+		price, err := backend.GetPrice(ctx, "ETHUSDT")
+		server.Close()
+		require.Equal(t, test.attempts, atomic.LoadInt32(attempts), test.name)
+		if !test.ok {
+			require.ErrorIs(t, err, errHTTPError, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		require.Equal(t, 2000.5, price, test.name)
+	}
+
+	// Without retries a 503 fails the request at once
+	server, attempts := newFlakyServer(503)
+	defer server.Close()
+	_, err := NewBinanceBackend(server.URL).GetPrice(ctx, "ETHUSDT")
+	require.ErrorIs(t, err, errHTTPError)
+	require.Equal(t, int32(1), atomic.LoadInt32(attempts))
+}
+
+func TestRetryCancel(t *testing.T) {
+	server, attempts := newFlakyServer(503, 503, 503)
+	defer server.Close()
+
+	// A cancelled context ends the wait before the next attempt
+	backend := NewBinanceBackend(server.URL)
+	backend.setRetry(Retry{Policy: backoff.Constant(time.Hour), MaxRetries: 3})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := backend.GetPrice(ctx, "ETHUSDT")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 10*time.Second)
+	require.Equal(t, int32(1), atomic.LoadInt32(attempts))
+
+	// and so does the deadline of a tick of the client
+	atomic.StoreInt32(attempts, 0)
+	client := NewClientWithBackend(NewBinanceBackend(server.URL), 0)
+	client.SetRetry(Retry{Policy: backoff.Constant(time.Hour), MaxRetries: 3, Deadline: 50 * time.Millisecond})
+	start = time.Now()
+	_, err = client.Query("ETHUSDT")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 10*time.Second)
+}
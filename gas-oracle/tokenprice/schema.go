@@ -0,0 +1,111 @@
+package tokenprice
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	ometrics "github.com/mantlenetworkio/mantle/gas-oracle/metrics"
+)
+
+// errMalformedResponse represents the error when the price source responds
+// with a shape that does not match the ticker schema
+var errMalformedResponse = errors.New("malformed token price response")
+
+// SetStrict validates every response of the price source against the ticker
+// schema, so that a change of the response shape fails loudly instead of
+// being coerced into a default price
+func (c *Client) SetStrict(strict bool) {
+	if backend, ok := c.backend.(interface{ SetStrict(bool) }); ok {
+		backend.SetStrict(strict)
+	}
+}
+
+// parseTicker parses a ticker response of the price source for the symbol.
+// The response must look like
+//
+//	{"retCode": 0, "result": {"symbol": "ETHUSDT", "price": "1234.5"}}
+//
+// where every field is required and of the type shown.
+func parseTicker(body []byte, symbol string) (*big.Float, error) {
+	price, err := parseTickerFields(body, symbol)
+	if err != nil {
+		malformedCounter().Inc(1)
+		return nil, fmt.Errorf("%w: %s: %v", errMalformedResponse, symbol, err)
+	}
+	return price, nil
+}
+
+func parseTickerFields(body []byte, symbol string) (*big.Float, error) {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("not an object: %v", err)
+	}
+
+	raw, ok := response["retCode"]
+	if !ok {
+		return nil, errors.New("missing retCode")
+	}
+	var retCode json.Number
+	if err := decodeNumber(raw, &retCode); err != nil {
+		return nil, fmt.Errorf("retCode is not a number: %s", raw)
+	}
+	if retCode.String() != "0" {
+		return nil, fmt.Errorf("retCode is %s: %s", retCode, response["retMsg"])
+	}
+
+	raw, ok = response["result"]
+	if !ok {
+		return nil, errors.New("missing result")
+	}
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &result); err != nil || result == nil {
+		return nil, fmt.Errorf("result is not an object: %s", raw)
+	}
+
+	if _, ok := result["symbol"]; !ok {
+		return nil, errors.New("missing result.symbol")
+	}
+	var got string
+	if err := json.Unmarshal(result["symbol"], &got); err != nil {
+		return nil, fmt.Errorf("result.symbol is not a string: %s", result["symbol"])
+	}
+	if got != symbol {
+		return nil, fmt.Errorf("result.symbol is %q", got)
+	}
+
+	if _, ok := result["price"]; !ok {
+		return nil, errors.New("missing result.price")
+	}
+	var value string
+	if err := json.Unmarshal(result["price"], &value); err != nil {
+		return nil, fmt.Errorf("result.price is not a string: %s", result["price"])
+	}
+	price, ok := new(big.Float).SetString(value)
+	if !ok || price.IsInf() {
+		return nil, fmt.Errorf("result.price is not numeric: %q", value)
+	}
+	return price, nil
+}
+
+func decodeNumber(raw json.RawMessage, n *json.Number) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	number, ok := v.(json.Number)
+	if !ok {
+		return errors.New("not a number")
+	}
+	*n = number
+	return nil
+}
+
+func malformedCounter() metrics.Counter {
+	return metrics.GetOrRegisterCounter("token_price/malformed", ometrics.DefaultRegistry)
+}
@@ -0,0 +1,69 @@
+package tokenprice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictRejectsMalformedResponses(t *testing.T) {
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"not json", `<html>maintenance</html>`, "not an object"},
+		{"missing retCode", `{"result":{"symbol":"ETHUSDT","price":"2000"}}`, "missing retCode"},
+		{"retCode string", `{"retCode":"0","result":{"symbol":"ETHUSDT","price":"2000"}}`, "retCode is not a number"},
+		{"retCode failure", `{"retCode":10001,"retMsg":"params error","result":{}}`, "retCode is 10001"},
+		{"renamed result", `{"retCode":0,"data":{"symbol":"ETHUSDT","price":"2000"}}`, "missing result"},
+		{"result list", `{"retCode":0,"result":[{"symbol":"ETHUSDT","price":"2000"}]}`, "result is not an object"},
+		{"other symbol", `{"retCode":0,"result":{"symbol":"BTCUSDT","price":"2000"}}`, `result.symbol is "BTCUSDT"`},
+		{"renamed price", `{"retCode":0,"result":{"symbol":"ETHUSDT","lastPrice":"2000"}}`, "missing result.price"},
+		{"numeric price", `{"retCode":0,"result":{"symbol":"ETHUSDT","price":2000}}`, "result.price is not a string"},
+		{"text price", `{"retCode":0,"result":{"symbol":"ETHUSDT","price":"n/a"}}`, "result.price is not numeric"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL, 0)
+			client.SetStrict(true)
+			before := malformedCounter().Count()
+			_, err := client.Query("ETHUSDT")
+			require.ErrorIs(t, err, errMalformedResponse)
+			require.Contains(t, err.Error(), tt.err)
+			require.Equal(t, before+1, malformedCounter().Count())
+		})
+	}
+}
+
+func TestStrictAcceptsTicker(t *testing.T) {
+	srv := newBybitServer(map[string]string{"ETHUSDT": "2000.5"}, new(int32))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, 0)
+	client.SetStrict(true)
+	price, err := client.Query("ETHUSDT")
+	require.NoError(t, err)
+	value, _ := price.Float64()
+	require.Equal(t, 2000.5, value)
+}
+
+func TestLenientRejectsUnparsablePrice(t *testing.T) {
+	srv := newBybitServer(map[string]string{"ETHUSDT": "n/a"}, new(int32))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, 0).Query("ETHUSDT")
+	require.Error(t, err)
+}
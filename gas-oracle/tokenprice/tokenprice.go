@@ -1,84 +1,154 @@
 package tokenprice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
-	"github.com/go-resty/resty/v2"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var errHTTPError = errors.New("http error")
 
-// NewClient create a new Client given a remote HTTP url and update frequency
+// NewClient create a new Client of the Bybit backend given a remote HTTP url
+// and update frequency
 func NewClient(url string, frequency uint64) *Client {
-	client := resty.New()
-	client.SetHostURL(url)
-	client.OnAfterResponse(func(c *resty.Client, r *resty.Response) error {
-		statusCode := r.StatusCode()
-		if statusCode >= 400 {
-			method := r.Request.Method
-			url := r.Request.URL
-			return fmt.Errorf("%d cannot %s %s: %w", statusCode, method, url, errHTTPError)
-		}
-		return nil
-	})
+	return NewClientWithBackend(NewBybitBackend(url), frequency)
+}
 
+// NewClientWithBackend creates a new Client of the backend given an update
+// frequency
+func NewClientWithBackend(backend Backend, frequency uint64) *Client {
 	return &Client{
-		client:    client,
+		backend:   backend,
 		frequency: time.Duration(frequency) * time.Second,
+		now:       time.Now,
+		basket:    &basket{quotes: make(map[string]Quote)},
 	}
 }
 
-// Client is an HTTP based TokenPriceClient
+// SetDecay sets the policy used for the last price ratio while the price
+// source is failing
+func (c *Client) SetDecay(decay Decay) {
+	c.decay = decay
+}
+
+// RatioReader reads the ETH/BIT price ratio that the fees are converted at
+type RatioReader interface {
+	PriceRatio() (float64, error)
+}
+
+// Client is a TokenPriceClient that reads the prices from a Backend. It is
+// shared by the channels, which call Price concurrently.
 type Client struct {
-	client     *resty.Client
-	frequency  time.Duration
+	backend   Backend
+	frequency time.Duration
+	decay     Decay
+	adaptive  Adaptive
+	now       func() time.Time
+	basket    *basket
+	deadline  time.Duration
+	breaker   Breaker
+
+	// mu guards the state that Price reads and writes, so that a ratio is
+	// fetched, checked and cached by one caller at a time
+	mu         sync.Mutex
 	lastRatio  float64
 	lastUpdate time.Time
+	interval   time.Duration
+	rejections int
+	latest     float64
+	latestTime time.Time
 }
 
-type TokenPrice struct {
-	Symbol string `json:"symbol"`
-	Price  string `json:"price"`
+// Latest returns the last price ratio that was fetched from the price
+// source and when it was fetched, without querying the source. The time is
+// zero until a ratio is fetched. A fetch in progress is waited for.
+func (c *Client) Latest() (float64, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.latest, c.latestTime
 }
 
-type Result struct {
-	RetCode int
-	Result  TokenPrice
+// Query returns the price of the symbol from the backend
+func (c *Client) Query(symbol string) (*big.Float, error) {
+	ctx, cancel := c.tick()
+	defer cancel()
+	return c.query(ctx, symbol)
 }
 
-func (c *Client) Query(symbol string) (*big.Float, error) {
-	response, err := c.client.R().
-		SetResult(&Result{}).
-		SetQueryParams(map[string]string{
-			"symbol": symbol,
-		}).
-		Get("/spot/quote/v1/ticker/price")
+// query returns the price of the symbol from the backend within the tick
+// of ctx
+func (c *Client) query(ctx context.Context, symbol string) (*big.Float, error) {
+	price, err := c.backend.GetPrice(ctx, symbol)
 	if err != nil {
-		return nil, fmt.Errorf("cannot fetch token price result: %w", err)
+		return nil, err
 	}
-	result, ok := response.Result().(*Result)
-	if !ok {
-		return nil, fmt.Errorf("cannot parse result")
-	}
-	if result.Result.Price == "" {
-		return nil, fmt.Errorf("empty price")
-	}
-	bigPrice, _ := big.NewFloat(0).SetString(result.Result.Price)
-	return bigPrice, nil
+	return big.NewFloat(price), nil
 }
 
+// PriceRatio returns the ETH/BIT price ratio
 func (c *Client) PriceRatio() (float64, error) {
-	if time.Now().Sub(c.lastUpdate) < c.frequency {
-		return c.lastRatio, nil
+	price, err := c.Price()
+	if err != nil {
+		return 0, err
+	}
+	return price.Ratio, nil
+}
+
+// Price returns the ETH/BIT price ratio. While the price source is failing
+// the last ratio is held according to the decay policy, and a ratio that
+// the breaker rejects is replaced by the last accepted one.
+func (c *Client) Price() (Price, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if now.Sub(c.lastUpdate) < c.pollInterval() {
+		return Price{Ratio: c.lastRatio, Confidence: 1}, nil
+	}
+	ratio, err := c.queryRatio()
+	if err == nil {
+		if !c.accept(ratio) {
+			c.lastUpdate = now
+			return Price{Ratio: c.lastRatio, Confidence: 1}, nil
+		}
+		c.adapt(c.lastRatio, ratio)
+		c.lastUpdate = now
+		c.lastRatio = ratio
+		c.latest, c.latestTime = ratio, now
+		return Price{Ratio: ratio, Confidence: 1}, nil
 	}
-	ethPrice, err := c.Query("ETHUSDT")
+	if c.lastUpdate.IsZero() || !c.decay.Enabled() {
+		return Price{}, err
+	}
+	stale := now.Sub(c.lastUpdate)
+	if stale > c.decay.MaxStale {
+		return Price{}, fmt.Errorf("token price stale for %s: %w", stale, err)
+	}
+	margin := c.decay.Margin(stale)
+	price := Price{
+		Ratio:      c.lastRatio * (1 + margin),
+		Stale:      stale,
+		Margin:     margin,
+		Confidence: c.decay.Confidence(stale),
+	}
+	log.Warn("Using stale token price", "stale", stale, "margin", margin,
+		"confidence", price.Confidence, "message", err)
+	return price, nil
+}
+
+// queryRatio fetches the ETH/BIT price ratio from the price source
+func (c *Client) queryRatio() (float64, error) {
+	ctx, cancel := c.tick()
+	defer cancel()
+	ethPrice, err := c.query(ctx, "ETHUSDT")
 	if err != nil {
 		return 0, err
 	}
-	bitPrice, err := c.Query("BITUSDT")
+	bitPrice, err := c.query(ctx, "BITUSDT")
 	if err != nil {
 		return 0, err
 	}
@@ -90,7 +160,5 @@ func (c *Client) PriceRatio() (float64, error) {
 		return 0, fmt.Errorf("invalid bit Price")
 	}
 	ratio, _ := ethPrice.Quo(ethPrice, bitPrice).Float64()
-	c.lastUpdate = time.Now()
-	c.lastRatio = ratio
-	return c.lastRatio, nil
+	return ratio, nil
 }
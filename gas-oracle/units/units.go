@@ -0,0 +1,161 @@
+// Package units converts amounts of gas prices and fees between units
+// exactly. Amounts are held in wei, the smallest unit, so that converting
+// them never loses precision, and every conversion that cannot be exact
+// either fails or says how it rounds.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Unit is a denomination of the native token, by the power of ten of wei
+// that it is worth
+type Unit uint
+
+const (
+	Wei   Unit = 0
+	Gwei  Unit = 9
+	Ether Unit = 18
+)
+
+func (u Unit) String() string {
+	switch u {
+	case Wei:
+		return "wei"
+	case Gwei:
+		return "gwei"
+	case Ether:
+		return "ether"
+	default:
+		return fmt.Sprintf("1e%d wei", uint(u))
+	}
+}
+
+// factor returns the wei worth one unit
+func (u Unit) factor() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(u)), nil)
+}
+
+var (
+	// ErrPrecision is returned when an amount cannot be held in wei
+	// without dropping a fraction of a wei
+	ErrPrecision = errors.New("amount is not a whole number of wei")
+	// ErrRange is returned when an amount does not fit the requested type
+	ErrRange = errors.New("amount out of range")
+)
+
+// Amount is an amount of the native token, or a price per unit of gas,
+// held exactly in wei. The zero value is zero wei.
+type Amount struct {
+	wei *big.Int
+}
+
+// FromWei returns the amount of the wei
+func FromWei(wei *big.Int) Amount {
+	if wei == nil {
+		return Amount{}
+	}
+	return Amount{wei: new(big.Int).Set(wei)}
+}
+
+// FromUint64 returns the amount of the value in the unit
+func FromUint64(value uint64, unit Unit) Amount {
+	return Amount{wei: new(big.Int).Mul(new(big.Int).SetUint64(value), unit.factor())}
+}
+
+// Parse parses a non-negative decimal amount in the unit, e.g. "1.5" gwei.
+// A fraction of a wei fails with ErrPrecision.
+func Parse(value string, unit Unit) (Amount, error) {
+	value = strings.TrimSpace(value)
+	whole, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		whole, fraction = value[:i], value[i+1:]
+	}
+	if whole == "" && fraction == "" || strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(unit) {
+		return Amount{}, fmt.Errorf("%w: %s %s", ErrPrecision, value, unit)
+	}
+	digits := whole + fraction + strings.Repeat("0", int(unit)-len(fraction))
+	wei, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+	return Amount{wei: wei}, nil
+}
+
+// Wei returns the amount in wei
+func (a Amount) Wei() *big.Int {
+	if a.wei == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.wei)
+}
+
+// Uint64 returns the amount in wei, failing with ErrRange when it does not
+// fit
+func (a Amount) Uint64() (uint64, error) {
+	wei := a.Wei()
+	if !wei.IsUint64() {
+		return 0, fmt.Errorf("%w: %s wei", ErrRange, wei)
+	}
+	return wei.Uint64(), nil
+}
+
+// Floor returns the whole number of the unit in the amount, dropping the
+// remainder
+func (a Amount) Floor(unit Unit) *big.Int {
+	return new(big.Int).Quo(a.Wei(), unit.factor())
+}
+
+// In formats the amount as an exact decimal in the unit, e.g. "1.5"
+func (a Amount) In(unit Unit) string {
+	whole, remainder := new(big.Int).QuoRem(a.Wei(), unit.factor(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return whole.String()
+	}
+	fraction := fmt.Sprintf("%0*s", int(unit), remainder.String())
+	return whole.String() + "." + strings.TrimRight(fraction, "0")
+}
+
+func (a Amount) String() string {
+	return a.In(Wei) + " wei"
+}
+
+// Cmp compares the amounts like big.Int.Cmp
+func (a Amount) Cmp(b Amount) int {
+	return a.Wei().Cmp(b.Wei())
+}
+
+// Add returns the sum of the amounts
+func (a Amount) Add(b Amount) Amount {
+	return Amount{wei: new(big.Int).Add(a.Wei(), b.Wei())}
+}
+
+// Mul returns the amount times n, e.g. the cost of an amount of gas at a
+// price
+func (a Amount) Mul(n uint64) Amount {
+	return Amount{wei: new(big.Int).Mul(a.Wei(), new(big.Int).SetUint64(n))}
+}
+
+// Scale converts the amount into another token at the ratio of their
+// prices. The product is computed exactly and only rounded down to a whole
+// wei at the end.
+func (a Amount) Scale(ratio float64) (Amount, error) {
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) || ratio < 0 {
+		return Amount{}, fmt.Errorf("%w: price ratio %v", ErrRange, ratio)
+	}
+	// A float64 holds 53 significant bits, so the product of an amount of
+	// up to 256 bits is exact at this precision
+	const prec = 256 + 53
+	product := new(big.Float).SetPrec(prec).SetInt(a.Wei())
+	product.Mul(product, new(big.Float).SetPrec(prec).SetFloat64(ratio))
+	wei, _ := product.Int(nil)
+	return Amount{wei: wei}, nil
+}
@@ -0,0 +1,90 @@
+package units
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversions(t *testing.T) {
+	require.Equal(t, big.NewInt(1_500_000_000), FromUint64(1, Gwei).Add(FromUint64(500_000_000, Wei)).Wei())
+	require.Equal(t, "1.5", FromUint64(1_500_000_000, Wei).In(Gwei))
+	require.Equal(t, "0.0000000015", FromUint64(1_500_000_000, Wei).In(Ether))
+	require.Equal(t, big.NewInt(1), FromUint64(1_999_999_999, Wei).Floor(Gwei))
+
+	for _, tc := range []struct {
+		value string
+		unit  Unit
+		wei   string
+	}{
+		{"1.5", Gwei, "1500000000"},
+		{"0.000000001", Gwei, "1"},
+		{".25", Ether, "250000000000000000"},
+		{"7", Wei, "7"},
+		{"2.000", Wei, "2"},
+	} {
+		amount, err := Parse(tc.value, tc.unit)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.wei, amount.Wei().String(), tc.value)
+	}
+	for _, value := range []string{"", ".", "-1", "1e9", "one", "1.2.3"} {
+		_, err := Parse(value, Gwei)
+		require.Error(t, err, value)
+	}
+}
+
+func TestSubWeiIsRejected(t *testing.T) {
+	_, err := Parse("0.0000000001", Gwei)
+	require.ErrorIs(t, err, ErrPrecision)
+	_, err = Parse("0.5", Wei)
+	require.ErrorIs(t, err, ErrPrecision)
+}
+
+func TestExtremesKeepPrecision(t *testing.T) {
+	// The largest uint64 of ether does not fit a uint64 of wei, and
+	// survives the round trip
+	amount := FromUint64(math.MaxUint64, Ether)
+	require.Equal(t, new(big.Int).SetUint64(math.MaxUint64), amount.Floor(Ether))
+	_, err := amount.Uint64()
+	require.ErrorIs(t, err, ErrRange)
+	wei, err := FromUint64(math.MaxUint64, Wei).Uint64()
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), wei)
+
+	// as does the largest uint256, through every unit
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	for _, unit := range []Unit{Wei, Gwei, Ether} {
+		parsed, err := Parse(FromWei(maxUint256).In(unit), unit)
+		require.NoError(t, err, unit)
+		require.Equal(t, 0, parsed.Cmp(FromWei(maxUint256)), unit)
+	}
+}
+
+func TestScale(t *testing.T) {
+	// The product is exact, unlike a float64 of the amount
+	amount := FromWei(new(big.Int).Add(big.NewInt(1e18), big.NewInt(1)))
+	scaled, err := amount.Scale(1.5)
+	require.NoError(t, err)
+	require.Equal(t, "1500000000000000001", scaled.Wei().String())
+
+	// and the fraction of the ratio is kept, only the sub-wei remainder
+	// is rounded down
+	scaled, err = FromUint64(30, Gwei).Scale(4000.25)
+	require.NoError(t, err)
+	require.Equal(t, "120007.5", scaled.In(Gwei))
+	scaled, err = FromUint64(3, Wei).Scale(0.5)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), scaled.Wei())
+
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	scaled, err = FromWei(maxUint256).Scale(1)
+	require.NoError(t, err)
+	require.Equal(t, maxUint256, scaled.Wei())
+
+	for _, ratio := range []float64{-1, math.NaN(), math.Inf(1)} {
+		_, err := amount.Scale(ratio)
+		require.ErrorIs(t, err, ErrRange)
+	}
+}
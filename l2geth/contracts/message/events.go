@@ -0,0 +1,32 @@
+package message
+
+import (
+	"github.com/mantlenetworkio/mantle/l2geth/accounts/abi"
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+)
+
+// contractAbis are the ABIs of the package by the name of their contract
+var contractAbis = map[string]func() *abi.ABI{
+	"L2CrossDomainMessenger": messageAbi,
+	"CrossDomainMessenger":   relayAbi,
+	"StateCommitmentChain":   sccAbi,
+}
+
+// EventTopics returns the topic0, the hash of the event signature, of
+// every event of the ABIs of the package, by contract name and event name,
+// so that log filters can be built without per-contract boilerplate. A
+// contract whose ABI has no event is left out.
+func EventTopics() map[string]map[string]common.Hash {
+	topics := make(map[string]map[string]common.Hash)
+	for contract, contractAbi := range contractAbis {
+		events := contractAbi().Events
+		if len(events) == 0 {
+			continue
+		}
+		topics[contract] = make(map[string]common.Hash, len(events))
+		for name, event := range events {
+			topics[contract][name] = event.ID()
+		}
+	}
+	return topics
+}
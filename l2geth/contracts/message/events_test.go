@@ -0,0 +1,36 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/mantlenetworkio/mantle/l2geth/crypto"
+)
+
+func TestEventTopics(t *testing.T) {
+	topics := EventTopics()
+
+	messenger, ok := topics["L2CrossDomainMessenger"]
+	if !ok {
+		t.Fatal("no events of the L2CrossDomainMessenger")
+	}
+	for name, signature := range map[string]string{
+		"RelayedMessage":       "RelayedMessage(bytes32)",
+		"FailedRelayedMessage": "FailedRelayedMessage(bytes32)",
+		"SentMessage":          "SentMessage(address,address,bytes,uint256,uint256)",
+	} {
+		want := crypto.Keccak256Hash([]byte(signature))
+		if topic, ok := messenger[name]; !ok || topic != want {
+			t.Errorf("topic0 of %s: got %s, want %s", name, topic.Hex(), want.Hex())
+		}
+	}
+	// 0x4641df4a962071e12719d8c8c8e5ac7fc4d97b927346a3d7a335b1f7517e133c is
+	// the topic0 of RelayedMessage(bytes32) as emitted on chain
+	if got := messenger["RelayedMessage"].Hex(); got != "0x4641df4a962071e12719d8c8c8e5ac7fc4d97b927346a3d7a335b1f7517e133c" {
+		t.Errorf("unexpected RelayedMessage topic0 %s", got)
+	}
+
+	// The ABIs without events are left out
+	if _, ok := topics["StateCommitmentChain"]; ok {
+		t.Error("StateCommitmentChain has no events")
+	}
+}
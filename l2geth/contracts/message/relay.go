@@ -0,0 +1,84 @@
+package message
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/mantlenetworkio/mantle/l2geth/accounts/abi"
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/crypto"
+)
+
+var relayABI *abi.ABI
+
+const (
+	// relayJsondata is the versioned relayMessage of the cross domain
+	// messenger, whose selector is 0xd764ad0b
+	relayJsondata = "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_nonce\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"_sender\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_target\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"_value\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_minGasLimit\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_message\",\"type\":\"bytes\"}],\"name\":\"relayMessage\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"}]"
+)
+
+func relayAbi() *abi.ABI {
+	if relayABI != nil {
+		return relayABI
+	}
+	rABI, err := abi.JSON(strings.NewReader(relayJsondata))
+	if err != nil {
+		panic(err)
+	}
+	relayABI = &rABI
+	return relayABI
+}
+
+// EncodeRelayMessage returns the calldata of
+// relayMessage(uint256,address,address,uint256,uint256,bytes), including
+// its 4-byte selector, as the cross domain messenger expects it
+func EncodeRelayMessage(nonce *big.Int, sender, target common.Address, value, minGasLimit *big.Int, message []byte) ([]byte, error) {
+	relayABI = relayAbi()
+	return relayABI.Pack("relayMessage", nonce, sender, target, value, minGasLimit, message)
+}
+
+// DecodeVersionedNonce splits a message nonce into the nonce and the
+// version that are packed into its lower 240 and upper 16 bits
+func DecodeVersionedNonce(versioned *big.Int) (*big.Int, uint16) {
+	nonce := new(big.Int).And(versioned, new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 240), common.Big1))
+	version := new(big.Int).Rsh(versioned, 240)
+	return nonce, uint16(version.Uint64())
+}
+
+// HashCrossDomainMessage returns the hash that keys the message in the
+// successfulMessages and failedMessages of the messenger, as the version
+// packed into the nonce hashes it
+func HashCrossDomainMessage(nonce *big.Int, sender, target common.Address, value, gasLimit *big.Int, message []byte) (common.Hash, error) {
+	_, version := DecodeVersionedNonce(nonce)
+	switch version {
+	case 0:
+		return HashCrossDomainMessageV0(target, sender, message, nonce)
+	case 1:
+		return HashCrossDomainMessageV1(nonce, sender, target, value, gasLimit, message)
+	default:
+		return common.Hash{}, fmt.Errorf("unknown message version %d", version)
+	}
+}
+
+// HashCrossDomainMessageV0 returns the hash of a legacy message, the
+// keccak256 of its relayMessage(address,address,bytes,uint256) calldata,
+// which carries neither a value nor a gas limit
+func HashCrossDomainMessageV0(target, sender common.Address, message []byte, nonce *big.Int) (common.Hash, error) {
+	messageABI = messageAbi()
+	calldata, err := messageABI.Pack("relayMessage", target, sender, message, nonce)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(calldata), nil
+}
+
+// HashCrossDomainMessageV1 returns the hash of a version 1 message, the
+// keccak256 of its versioned relayMessage calldata
+func HashCrossDomainMessageV1(nonce *big.Int, sender, target common.Address, value, gasLimit *big.Int, message []byte) (common.Hash, error) {
+	calldata, err := EncodeRelayMessage(nonce, sender, target, value, gasLimit, message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(calldata), nil
+}
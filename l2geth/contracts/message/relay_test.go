@@ -0,0 +1,130 @@
+package message
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
+	"github.com/mantlenetworkio/mantle/l2geth/crypto"
+)
+
+func TestEncodeRelayMessage(t *testing.T) {
+	// The calldata of relayMessage of a version 1 message with nonce 5,
+	// laid out word by word as the Solidity ABI encoder does
+	want := "0xd764ad0b" + strings.Join([]string{
+		"0001000000000000000000000000000000000000000000000000000000000005",
+		"0000000000000000000000001111111111111111111111111111111111111111",
+		"0000000000000000000000002222222222222222222222222222222222222222",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000030d40",
+		"00000000000000000000000000000000000000000000000000000000000000c0",
+		"0000000000000000000000000000000000000000000000000000000000000004",
+		"deadbeef00000000000000000000000000000000000000000000000000000000",
+	}, "")
+
+	nonce := new(big.Int).Lsh(big.NewInt(1), 240)
+	nonce.Add(nonce, big.NewInt(5))
+	calldata, err := EncodeRelayMessage(
+		nonce,
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		big.NewInt(0),
+		big.NewInt(200000),
+		[]byte{0xde, 0xad, 0xbe, 0xef},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexutil.Encode(calldata); got != want {
+		t.Fatalf("calldata mismatch\n got %s\nwant %s", got, want)
+	}
+
+	selector := crypto.Keccak256([]byte("relayMessage(uint256,address,address,uint256,uint256,bytes)"))[:4]
+	if !bytes.Equal(calldata[:4], selector) {
+		t.Fatalf("selector mismatch: got %x, want %x", calldata[:4], selector)
+	}
+}
+
+func TestDecodeVersionedNonce(t *testing.T) {
+	versioned := new(big.Int).Lsh(big.NewInt(1), 240)
+	versioned.Add(versioned, big.NewInt(42))
+	nonce, version := DecodeVersionedNonce(versioned)
+	if version != 1 || nonce.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("got nonce %d version %d, want nonce 42 version 1", nonce, version)
+	}
+
+	nonce, version = DecodeVersionedNonce(big.NewInt(14))
+	if version != 0 || nonce.Cmp(big.NewInt(14)) != 0 {
+		t.Fatalf("got nonce %d version %d, want nonce 14 version 0", nonce, version)
+	}
+}
+
+func TestHashCrossDomainMessage(t *testing.T) {
+	// A legacy message, as relayed in TestUnpacket, is keyed by the hash of
+	// its relayMessage calldata, which the v0 hash must rebuild exactly
+	relayed, err := hexutil.Decode("0xcbd4ece9000000000000000000000000deaddeaddeaddeaddeaddeaddeaddeaddead2222000000000000000000000000d9e2f450525079e1e29fb23bc7caca6f61f8fd4a0000000000000000000000000000000000000000000000000000000000000080000000000000000000000000000000000000000000000000000000000000000e0000000000000000000000000000000000000000000000000000000000000024f523f40d00000000000000000000000000000000000000000000000000000000000003e800000000000000000000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &Data{}
+	if err := data.UnPackData(relayed); err != nil {
+		t.Fatal(err)
+	}
+	want := common.HexToHash("0x0f95007d4d0cc9a5687d93c7740e9d5d80f7773fbe7e1bf133fa98cb2cf36114")
+	if hash := crypto.Keccak256Hash(relayed); hash != want {
+		t.Fatalf("fixture hash mismatch: got %s, want %s", hash.Hex(), want.Hex())
+	}
+	// The value and the gas limit are not part of the v0 hash
+	hash, err := HashCrossDomainMessage(data.MessageNonce, data.Sender, data.Target, big.NewInt(7), big.NewInt(100000), data.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != want {
+		t.Fatalf("v0 hash mismatch: got %s, want %s", hash.Hex(), want.Hex())
+	}
+
+	// The version bit of the nonce selects the v1 hash, the keccak256 of the
+	// versioned relayMessage calldata of the same message, with a gas limit
+	// of 200000, laid out word by word as the Solidity ABI encoder does
+	v1 := "0xd764ad0b" + strings.Join([]string{
+		"000100000000000000000000000000000000000000000000000000000000000e",
+		"000000000000000000000000d9e2f450525079e1e29fb23bc7caca6f61f8fd4a",
+		"000000000000000000000000deaddeaddeaddeaddeaddeaddeaddeaddead2222",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000030d40",
+		"00000000000000000000000000000000000000000000000000000000000000c0",
+		"0000000000000000000000000000000000000000000000000000000000000024",
+		"f523f40d00000000000000000000000000000000000000000000000000000000",
+		"000003e800000000000000000000000000000000000000000000000000000000",
+	}, "")
+	wantV1 := common.HexToHash("0x92a4425b5053f5764efbc6a55e76cbbbd1d713e6c066d5af31b4b32bf20d44cf")
+	if hash := crypto.Keccak256Hash(hexutil.MustDecode(v1)); hash != wantV1 {
+		t.Fatalf("v1 fixture hash mismatch: got %s, want %s", hash.Hex(), wantV1.Hex())
+	}
+	nonce := new(big.Int).Lsh(big.NewInt(1), 240)
+	nonce.Add(nonce, data.MessageNonce)
+	hash, err = HashCrossDomainMessage(nonce, data.Sender, data.Target, big.NewInt(0), big.NewInt(200000), data.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != wantV1 {
+		t.Fatalf("v1 hash mismatch: got %s, want %s", hash.Hex(), wantV1.Hex())
+	}
+	// and unlike v0 it covers the value and the gas limit
+	hash, err = HashCrossDomainMessage(nonce, data.Sender, data.Target, big.NewInt(0), big.NewInt(100000), data.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == wantV1 {
+		t.Fatal("v1 hash ignores the gas limit")
+	}
+
+	// Unknown versions are refused
+	nonce = new(big.Int).Lsh(big.NewInt(2), 240)
+	if _, err := HashCrossDomainMessage(nonce, data.Sender, data.Target, big.NewInt(0), big.NewInt(0), data.Message); err == nil {
+		t.Fatal("expected an error for message version 2")
+	}
+}